@@ -0,0 +1,179 @@
+package account
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/gin-gonic/gin"
+)
+
+// Size of the pages of listed transactions when none is requested, and the
+// largest one that can be requested.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type accountController struct {
+	accountService port.AccountService
+}
+
+func newAccountController(accountService port.AccountService) *accountController {
+	return &accountController{
+		accountService: accountService,
+	}
+}
+
+type createAccountRequest struct {
+	Name string `json:"name"`
+}
+
+func (ac *accountController) createAccount(c *gin.Context) {
+	var request createAccountRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Code:    entity.FieldErrorInvalidJSON,
+			Message: "the request body is not a valid account",
+		}}})
+		return
+	}
+
+	if strings.TrimSpace(request.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Field:   "name",
+			Code:    entity.FieldErrorRequired,
+			Message: "name is required",
+		}}})
+		return
+	}
+
+	account, err := ac.accountService.CreateAccount(c, request.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error creating account": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, account)
+}
+
+func (ac *accountController) getAccount(c *gin.Context) {
+	accountID := c.Param("account_id")
+
+	account, err := ac.accountService.GetAccount(c, accountID)
+	if !checkAccountError(c, accountID, err, "Error getting account") {
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// getBalance gets the points held by an account, from its ledger.
+func (ac *accountController) getBalance(c *gin.Context) {
+	accountID := c.Param("account_id")
+
+	balance, err := ac.accountService.GetBalance(c, accountID)
+	if !checkAccountError(c, accountID, err, "Error getting account balance") {
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
+
+type transactionListResponse struct {
+	Transactions []entity.LedgerTransaction `json:"transactions"`
+	NextCursor   string                     `json:"nextCursor,omitempty"`
+}
+
+// listTransactions lists the ledger transactions of an account a page at a
+// time, newest first.
+func (ac *accountController) listTransactions(c *gin.Context) {
+	accountID := c.Param("account_id")
+
+	limit := defaultPageSize
+	if value := c.Query("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+				Field:   "limit",
+				Code:    entity.FieldErrorInvalidFormat,
+				Message: fmt.Sprintf("limit must be a whole number between 1 and %d", maxPageSize),
+			}}})
+			return
+		}
+	}
+
+	page, err := ac.accountService.ListTransactions(c, accountID, c.Query("cursor"), limit)
+	if errors.Is(err, entity.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Field:   "cursor",
+			Code:    entity.FieldErrorInvalidFormat,
+			Message: "cursor must be the nextCursor of a page of transactions",
+		}}})
+		return
+	}
+	if !checkAccountError(c, accountID, err, "Error listing account transactions") {
+		return
+	}
+
+	c.JSON(http.StatusOK, transactionListResponse{Transactions: page.Transactions, NextCursor: page.NextCursor})
+}
+
+type redemptionRequest struct {
+	Points      int64  `json:"points"`
+	Description string `json:"description"`
+}
+
+// redeemPoints spends points of an account, debiting them from its ledger.
+func (ac *accountController) redeemPoints(c *gin.Context) {
+	accountID := c.Param("account_id")
+
+	var request redemptionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Code:    entity.FieldErrorInvalidJSON,
+			Message: "the request body is not a valid redemption",
+		}}})
+		return
+	}
+
+	if request.Points <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Field:   "points",
+			Code:    entity.FieldErrorInvalidFormat,
+			Message: "points must be a whole number greater than zero",
+		}}})
+		return
+	}
+
+	transaction, err := ac.accountService.Redeem(c, accountID, request.Points, request.Description)
+	if errors.Is(err, entity.ErrInsufficientPoints) {
+		c.JSON(http.StatusConflict, gin.H{"The account doesn't have enough points to redeem": request.Points})
+		return
+	}
+	if !checkAccountError(c, accountID, err, "Error redeeming points") {
+		return
+	}
+
+	c.JSON(http.StatusCreated, transaction)
+}
+
+// checkAccountError writes the error response if there is an error, and
+// returns whether there was none.
+func checkAccountError(c *gin.Context, accountID string, err error, message string) bool {
+	if errors.Is(err, entity.ErrAccountNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"Account not found for that id": accountID})
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{message: err.Error()})
+		return false
+	}
+
+	return true
+}
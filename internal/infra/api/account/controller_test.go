@@ -0,0 +1,363 @@
+package account
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAccount(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+
+		requestBody string
+
+		wantStatusCode int
+		wantAccount    entity.Account
+	}{
+		{
+			name: "should create an account",
+
+			requestBody: `{"name": "Jane"}`,
+
+			wantStatusCode: http.StatusCreated,
+			wantAccount:    entity.Account{ID: "1", Name: "Jane", CreatedAt: createdAt},
+		},
+		{
+			name: "should fail due missing name",
+
+			requestBody: `{"name": " "}`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due invalid JSON",
+
+			requestBody: `{"name":`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.AccountService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		RegisterRoutes(router.Group("/accounts"), mockService)
+
+		if tc.wantStatusCode == http.StatusCreated {
+			mockService.On(
+				"CreateAccount",
+				mock.Anything, /* context.Context */
+				tc.wantAccount.Name,
+			).Return(tc.wantAccount, nil).Once()
+		}
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Post(fmt.Sprintf("%s/accounts", server.URL), "application/json", bytes.NewBufferString(tc.requestBody))
+			if err != nil {
+				t.Fatalf("CreateAccount() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("CreateAccount() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			if tc.wantStatusCode == http.StatusCreated {
+				var got entity.Account
+				if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+					t.Fatalf("CreateAccount() = Decoding error %v", err)
+				}
+
+				if got != tc.wantAccount {
+					t.Errorf("CreateAccount() = %v, want %v", got, tc.wantAccount)
+				}
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetBalance(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		balance    entity.AccountBalance
+		balanceErr error
+
+		wantStatusCode int
+	}{
+		{
+			name: "should get the balance of the account",
+
+			balance: entity.AccountBalance{AccountID: "1", Balance: 28},
+
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "should fail due unknown account",
+
+			balanceErr: entity.ErrAccountNotFound,
+
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.AccountService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		RegisterRoutes(router.Group("/accounts"), mockService)
+
+		mockService.On(
+			"GetBalance",
+			mock.Anything, /* context.Context */
+			"1",
+		).Return(tc.balance, tc.balanceErr).Once()
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Get(fmt.Sprintf("%s/accounts/1/balance", server.URL))
+			if err != nil {
+				t.Fatalf("GetBalance() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("GetBalance() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			if tc.wantStatusCode == http.StatusOK {
+				var got entity.AccountBalance
+				if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+					t.Fatalf("GetBalance() = Decoding error %v", err)
+				}
+
+				if got != tc.balance {
+					t.Errorf("GetBalance() = %v, want %v", got, tc.balance)
+				}
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListTransactions(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	transaction := entity.NewLedgerTransaction("tx", "1", entity.TransactionTypeEarning, "receipt", 28, createdAt)
+
+	testCases := []struct {
+		name string
+
+		rawQuery string
+
+		wantCursor string
+		wantLimit  int
+		page       entity.LedgerPage
+		pageErr    error
+
+		wantStatusCode int
+		wantResponse   transactionListResponse
+	}{
+		{
+			name: "should list the first page with the default size",
+
+			wantLimit: defaultPageSize,
+			page:      entity.LedgerPage{Transactions: []entity.LedgerTransaction{transaction}, NextCursor: "next"},
+
+			wantStatusCode: http.StatusOK,
+			wantResponse:   transactionListResponse{Transactions: []entity.LedgerTransaction{transaction}, NextCursor: "next"},
+		},
+		{
+			name: "should list the next page with the requested size",
+
+			rawQuery: "cursor=next&limit=5",
+
+			wantCursor: "next",
+			wantLimit:  5,
+			page:       entity.LedgerPage{Transactions: []entity.LedgerTransaction{}},
+
+			wantStatusCode: http.StatusOK,
+			wantResponse:   transactionListResponse{Transactions: []entity.LedgerTransaction{}},
+		},
+		{
+			name: "should fail due invalid limit",
+
+			rawQuery: "limit=1000",
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due invalid cursor",
+
+			rawQuery: "cursor=bad",
+
+			wantCursor: "bad",
+			wantLimit:  defaultPageSize,
+			pageErr:    entity.ErrInvalidCursor,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due unknown account",
+
+			wantLimit: defaultPageSize,
+			pageErr:   entity.ErrAccountNotFound,
+
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.AccountService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		RegisterRoutes(router.Group("/accounts"), mockService)
+
+		if tc.wantLimit != 0 {
+			mockService.On(
+				"ListTransactions",
+				mock.Anything, /* context.Context */
+				"1",
+				tc.wantCursor,
+				tc.wantLimit,
+			).Return(tc.page, tc.pageErr).Once()
+		}
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Get(fmt.Sprintf("%s/accounts/1/transactions?%s", server.URL, tc.rawQuery))
+			if err != nil {
+				t.Fatalf("ListTransactions() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("ListTransactions() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			if tc.wantStatusCode == http.StatusOK {
+				var got transactionListResponse
+				if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+					t.Fatalf("ListTransactions() = Decoding error %v", err)
+				}
+
+				if !reflect.DeepEqual(got, tc.wantResponse) {
+					t.Errorf("ListTransactions() = %v, want %v", got, tc.wantResponse)
+				}
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRedeemPoints(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	transaction := entity.NewLedgerTransaction("tx", "1", entity.TransactionTypeRedemption, "", -10, createdAt)
+
+	testCases := []struct {
+		name string
+
+		requestBody string
+
+		redeemErr error
+
+		wantStatusCode int
+	}{
+		{
+			name: "should redeem the points",
+
+			requestBody: `{"points": 10, "description": "coffee"}`,
+
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name: "should fail due insufficient points",
+
+			requestBody: `{"points": 10, "description": "coffee"}`,
+			redeemErr:   entity.ErrInsufficientPoints,
+
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name: "should fail due points not greater than zero",
+
+			requestBody: `{"points": 0}`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.AccountService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		RegisterRoutes(router.Group("/accounts"), mockService)
+
+		if tc.wantStatusCode != http.StatusBadRequest {
+			mockService.On(
+				"Redeem",
+				mock.Anything, /* context.Context */
+				"1",
+				int64(10),
+				"coffee",
+			).Return(transaction, tc.redeemErr).Once()
+		}
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Post(fmt.Sprintf("%s/accounts/1/redemptions", server.URL), "application/json", bytes.NewBufferString(tc.requestBody))
+			if err != nil {
+				t.Fatalf("RedeemPoints() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("RedeemPoints() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
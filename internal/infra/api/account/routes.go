@@ -0,0 +1,16 @@
+package account
+
+import (
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, accountService port.AccountService) {
+	controller := newAccountController(accountService)
+
+	router.POST("", controller.createAccount)
+	router.GET("/:account_id", controller.getAccount)
+	router.GET("/:account_id/balance", controller.getBalance)
+	router.GET("/:account_id/transactions", controller.listTransactions)
+	router.POST("/:account_id/redemptions", controller.redeemPoints)
+}
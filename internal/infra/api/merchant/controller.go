@@ -0,0 +1,206 @@
+package merchant
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/gin-gonic/gin"
+)
+
+type merchantController struct {
+	merchantService port.MerchantService
+}
+
+func newMerchantController(merchantService port.MerchantService) *merchantController {
+	return &merchantController{
+		merchantService: merchantService,
+	}
+}
+
+type merchantRequest struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+type aliasRequest struct {
+	Alias string `json:"alias"`
+}
+
+type merchantListResponse struct {
+	Merchants []entity.Merchant `json:"merchants"`
+}
+
+// createMerchant registers a merchant with its name and aliases, assigning it
+// the stored receipts of its retailer names.
+func (mc *merchantController) createMerchant(c *gin.Context) {
+	var request merchantRequest
+	if !bindRequest(c, &request, "merchant") {
+		return
+	}
+
+	fieldErrors := checkRetailerName(nil, "name", request.Name)
+	for i, alias := range request.Aliases {
+		fieldErrors = checkRetailerName(fieldErrors, fmt.Sprintf("aliases[%d]", i), alias)
+	}
+	if fieldErrors != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": fieldErrors})
+		return
+	}
+
+	merchant, err := mc.merchantService.CreateMerchant(c, request.Name, request.Aliases)
+	if errors.Is(err, entity.ErrMerchantAliasTaken) {
+		c.JSON(http.StatusConflict, gin.H{"The name or an alias is already of another merchant": request})
+		return
+	}
+	if !checkMerchantError(c, "", err, "Error creating merchant") {
+		return
+	}
+
+	c.JSON(http.StatusCreated, merchant)
+}
+
+func (mc *merchantController) listMerchants(c *gin.Context) {
+	merchants, err := mc.merchantService.ListMerchants(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error listing merchants": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, merchantListResponse{Merchants: merchants})
+}
+
+func (mc *merchantController) getMerchant(c *gin.Context) {
+	merchantID := c.Param("merchant_id")
+
+	merchant, err := mc.merchantService.GetMerchant(c, merchantID)
+	if !checkMerchantError(c, merchantID, err, "Error getting merchant") {
+		return
+	}
+
+	c.JSON(http.StatusOK, merchant)
+}
+
+// renameMerchant changes the name of a merchant, leaving its aliases as they are.
+func (mc *merchantController) renameMerchant(c *gin.Context) {
+	merchantID := c.Param("merchant_id")
+
+	var request merchantRequest
+	if !bindRequest(c, &request, "merchant") {
+		return
+	}
+
+	if fieldErrors := checkRetailerName(nil, "name", request.Name); fieldErrors != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": fieldErrors})
+		return
+	}
+
+	merchant, err := mc.merchantService.RenameMerchant(c, merchantID, request.Name)
+	if !checkMerchantError(c, merchantID, err, "Error renaming merchant") {
+		return
+	}
+
+	c.JSON(http.StatusOK, merchant)
+}
+
+// deleteMerchant deletes a merchant with its aliases, unassigning its receipts.
+func (mc *merchantController) deleteMerchant(c *gin.Context) {
+	merchantID := c.Param("merchant_id")
+
+	err := mc.merchantService.DeleteMerchant(c, merchantID)
+	if !checkMerchantError(c, merchantID, err, "Error deleting merchant") {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// addAlias adds a retailer name to a merchant, assigning it the stored
+// receipts of the retailer.
+func (mc *merchantController) addAlias(c *gin.Context) {
+	merchantID := c.Param("merchant_id")
+
+	var request aliasRequest
+	if !bindRequest(c, &request, "alias") {
+		return
+	}
+
+	if fieldErrors := checkRetailerName(nil, "alias", request.Alias); fieldErrors != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": fieldErrors})
+		return
+	}
+
+	merchant, err := mc.merchantService.AddAlias(c, merchantID, request.Alias)
+	if errors.Is(err, entity.ErrMerchantAliasTaken) {
+		c.JSON(http.StatusConflict, gin.H{"The alias is already of another merchant": request.Alias})
+		return
+	}
+	if !checkMerchantError(c, merchantID, err, "Error adding merchant alias") {
+		return
+	}
+
+	c.JSON(http.StatusCreated, merchant)
+}
+
+// deleteAlias removes a retailer name from a merchant, matched by its
+// normalized name, and unassigns the receipts of the retailer.
+func (mc *merchantController) deleteAlias(c *gin.Context) {
+	merchantID := c.Param("merchant_id")
+	alias := c.Param("alias")
+
+	merchant, err := mc.merchantService.DeleteAlias(c, merchantID, alias)
+	if errors.Is(err, entity.ErrMerchantAliasNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"Alias not found for that merchant": alias})
+		return
+	}
+	if !checkMerchantError(c, merchantID, err, "Error deleting merchant alias") {
+		return
+	}
+
+	c.JSON(http.StatusOK, merchant)
+}
+
+// bindRequest decodes the JSON body of the request. If it's invalid it writes
+// the error response and returns false.
+func bindRequest(c *gin.Context, request any, kind string) bool {
+	if err := c.ShouldBindJSON(request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Code:    entity.FieldErrorInvalidJSON,
+			Message: fmt.Sprintf("the request body is not a valid %s", kind),
+		}}})
+		return false
+	}
+
+	return true
+}
+
+// checkRetailerName adds the error of a name that would match no retailer, as
+// it has no letters or digits, to the field errors.
+func checkRetailerName(fieldErrors []entity.FieldError, field, name string) []entity.FieldError {
+	if entity.NormalizeRetailer(name) != "" {
+		return fieldErrors
+	}
+
+	return append(fieldErrors, entity.FieldError{
+		Field:   field,
+		Code:    entity.FieldErrorRequired,
+		Message: field + " must have letters or digits",
+	})
+}
+
+// checkMerchantError writes the error response if there is an error, and
+// returns whether there was none.
+func checkMerchantError(c *gin.Context, merchantID string, err error, message string) bool {
+	if errors.Is(err, entity.ErrMerchantNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"Merchant not found for that id": merchantID})
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{message: err.Error()})
+		return false
+	}
+
+	return true
+}
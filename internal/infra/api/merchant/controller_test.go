@@ -0,0 +1,334 @@
+package merchant
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateMerchant(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	merchant := entity.Merchant{
+		ID:        "1",
+		Name:      "M&M Corner Market",
+		Aliases:   []string{"M&M Corner Market", "m&m corner mkt"},
+		CreatedAt: createdAt,
+	}
+
+	testCases := []struct {
+		name string
+
+		requestBody string
+
+		wantCreated    bool
+		createErr      error
+		wantStatusCode int
+	}{
+		{
+			name: "should create a merchant with its aliases",
+
+			requestBody: `{"name": "M&M Corner Market", "aliases": ["m&m corner mkt"]}`,
+
+			wantCreated:    true,
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name: "should fail due alias of another merchant",
+
+			requestBody: `{"name": "M&M Corner Market", "aliases": ["m&m corner mkt"]}`,
+
+			wantCreated:    true,
+			createErr:      entity.ErrMerchantAliasTaken,
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name: "should fail due name without letters or digits",
+
+			requestBody: `{"name": " & "}`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due empty alias",
+
+			requestBody: `{"name": "M&M Corner Market", "aliases": [""]}`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due invalid JSON",
+
+			requestBody: `{"name":`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.MerchantService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		RegisterRoutes(router.Group("/merchants"), mockService)
+
+		if tc.wantCreated {
+			mockService.On(
+				"CreateMerchant",
+				mock.Anything, /* context.Context */
+				"M&M Corner Market",
+				[]string{"m&m corner mkt"},
+			).Return(merchant, tc.createErr).Once()
+		}
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Post(fmt.Sprintf("%s/merchants", server.URL), "application/json", bytes.NewBufferString(tc.requestBody))
+			if err != nil {
+				t.Fatalf("CreateMerchant() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("CreateMerchant() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			if tc.wantStatusCode == http.StatusCreated {
+				var got entity.Merchant
+				if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+					t.Fatalf("CreateMerchant() = Decoding error %v", err)
+				}
+
+				if !reflect.DeepEqual(got, merchant) {
+					t.Errorf("CreateMerchant() = %v, want %v", got, merchant)
+				}
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddAlias(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		requestBody string
+
+		wantAdded      bool
+		addErr         error
+		wantStatusCode int
+	}{
+		{
+			name: "should add the alias",
+
+			requestBody: `{"alias": "m&m corner mkt"}`,
+
+			wantAdded:      true,
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name: "should fail due alias of another merchant",
+
+			requestBody: `{"alias": "m&m corner mkt"}`,
+
+			wantAdded:      true,
+			addErr:         entity.ErrMerchantAliasTaken,
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name: "should fail due unknown merchant",
+
+			requestBody: `{"alias": "m&m corner mkt"}`,
+
+			wantAdded:      true,
+			addErr:         entity.ErrMerchantNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "should fail due alias without letters or digits",
+
+			requestBody: `{"alias": "--"}`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.MerchantService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		RegisterRoutes(router.Group("/merchants"), mockService)
+
+		if tc.wantAdded {
+			mockService.On(
+				"AddAlias",
+				mock.Anything, /* context.Context */
+				"1",
+				"m&m corner mkt",
+			).Return(entity.Merchant{ID: "1"}, tc.addErr).Once()
+		}
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Post(fmt.Sprintf("%s/merchants/1/aliases", server.URL), "application/json", bytes.NewBufferString(tc.requestBody))
+			if err != nil {
+				t.Fatalf("AddAlias() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("AddAlias() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteAlias(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		deleteErr error
+
+		wantStatusCode int
+	}{
+		{
+			name: "should delete the alias",
+
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "should fail due alias not of the merchant",
+
+			deleteErr: entity.ErrMerchantAliasNotFound,
+
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.MerchantService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		RegisterRoutes(router.Group("/merchants"), mockService)
+
+		mockService.On(
+			"DeleteAlias",
+			mock.Anything, /* context.Context */
+			"1",
+			"m&m corner mkt",
+		).Return(entity.Merchant{ID: "1"}, tc.deleteErr).Once()
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			request, err := http.NewRequest(http.MethodDelete,
+				fmt.Sprintf("%s/merchants/1/aliases/%s", server.URL, url.PathEscape("m&m corner mkt")), nil)
+			if err != nil {
+				t.Fatalf("DeleteAlias() = error %v", err)
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatalf("DeleteAlias() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("DeleteAlias() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteMerchant(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		deleteErr error
+
+		wantStatusCode int
+	}{
+		{
+			name: "should delete the merchant",
+
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "should fail due unknown merchant",
+
+			deleteErr: entity.ErrMerchantNotFound,
+
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.MerchantService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		RegisterRoutes(router.Group("/merchants"), mockService)
+
+		mockService.On(
+			"DeleteMerchant",
+			mock.Anything, /* context.Context */
+			"1",
+		).Return(tc.deleteErr).Once()
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/merchants/1", server.URL), nil)
+			if err != nil {
+				t.Fatalf("DeleteMerchant() = error %v", err)
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatalf("DeleteMerchant() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("DeleteMerchant() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package merchant
+
+import (
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, merchantService port.MerchantService) {
+	controller := newMerchantController(merchantService)
+
+	router.POST("", controller.createMerchant)
+	router.GET("", controller.listMerchants)
+	router.GET("/:merchant_id", controller.getMerchant)
+	router.PATCH("/:merchant_id", controller.renameMerchant)
+	router.DELETE("/:merchant_id", controller.deleteMerchant)
+	router.POST("/:merchant_id/aliases", controller.addAlias)
+	router.DELETE("/:merchant_id/aliases/:alias", controller.deleteAlias)
+}
@@ -0,0 +1,178 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/gin-gonic/gin"
+)
+
+type productController struct {
+	productService port.ProductService
+}
+
+func newProductController(productService port.ProductService) *productController {
+	return &productController{
+		productService: productService,
+	}
+}
+
+// productRequest is the definition of a product, without the fields set by
+// the service.
+type productRequest struct {
+	Name     string   `json:"name"`
+	Brand    string   `json:"brand"`
+	Category string   `json:"category"`
+	Keywords []string `json:"keywords"`
+}
+
+type matchRequest struct {
+	ShortDescription string `json:"shortDescription"`
+}
+
+type productListResponse struct {
+	Products []entity.Product `json:"products"`
+}
+
+// createProduct adds a product to the catalogue, which is matched to the
+// items of the receipts submitted from then on.
+func (pc *productController) createProduct(c *gin.Context) {
+	product, ok := bindProduct(c)
+	if !ok {
+		return
+	}
+
+	product, err := pc.productService.CreateProduct(c, product)
+	if !checkProductError(c, "", err, "Error creating product") {
+		return
+	}
+
+	c.JSON(http.StatusCreated, product)
+}
+
+func (pc *productController) listProducts(c *gin.Context) {
+	products, err := pc.productService.ListProducts(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error listing products": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, productListResponse{Products: products})
+}
+
+func (pc *productController) getProduct(c *gin.Context) {
+	productID := c.Param("product_id")
+
+	product, err := pc.productService.GetProduct(c, productID)
+	if !checkProductError(c, productID, err, "Error getting product") {
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+// updateProduct replaces the definition of a product. The items already
+// stored keep the products they matched.
+func (pc *productController) updateProduct(c *gin.Context) {
+	productID := c.Param("product_id")
+
+	product, ok := bindProduct(c)
+	if !ok {
+		return
+	}
+
+	product, err := pc.productService.UpdateProduct(c, productID, product)
+	if !checkProductError(c, productID, err, "Error updating product") {
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+func (pc *productController) deleteProduct(c *gin.Context) {
+	productID := c.Param("product_id")
+
+	err := pc.productService.DeleteProduct(c, productID)
+	if !checkProductError(c, productID, err, "Error deleting product") {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// matchProduct returns the product an item with the short description would
+// match, so the catalogue can be checked before receipts are submitted.
+func (pc *productController) matchProduct(c *gin.Context) {
+	var request matchRequest
+	if !bindRequest(c, &request, "item") {
+		return
+	}
+
+	products, err := pc.productService.MatchProducts(c, []entity.Item{{ShortDescription: request.ShortDescription}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error matching product": err.Error()})
+		return
+	}
+
+	if products[0].ID == "" {
+		c.JSON(http.StatusNotFound, gin.H{"No product matches the description": request.ShortDescription})
+		return
+	}
+
+	c.JSON(http.StatusOK, products[0])
+}
+
+// bindProduct decodes and validates the product of the request. If it's
+// invalid it writes the error response and returns false.
+func bindProduct(c *gin.Context) (entity.Product, bool) {
+	var request productRequest
+	if !bindRequest(c, &request, "product") {
+		return entity.Product{}, false
+	}
+
+	product := entity.Product{
+		Name:     request.Name,
+		Brand:    request.Brand,
+		Category: request.Category,
+		Keywords: request.Keywords,
+	}
+
+	if fieldErrors := product.Validate(); fieldErrors != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": fieldErrors})
+		return entity.Product{}, false
+	}
+
+	return product, true
+}
+
+// bindRequest decodes the JSON body of the request. If it's invalid it writes
+// the error response and returns false.
+func bindRequest(c *gin.Context, request any, kind string) bool {
+	if err := c.ShouldBindJSON(request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Code:    entity.FieldErrorInvalidJSON,
+			Message: fmt.Sprintf("the request body is not a valid %s", kind),
+		}}})
+		return false
+	}
+
+	return true
+}
+
+// checkProductError writes the error response if there is an error, and
+// returns whether there was none.
+func checkProductError(c *gin.Context, productID string, err error, message string) bool {
+	if errors.Is(err, entity.ErrProductNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"Product not found for that id": productID})
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{message: err.Error()})
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,239 @@
+package product
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateProduct(t *testing.T) {
+	definition := entity.Product{
+		Name:     "Mountain Dew",
+		Brand:    "PepsiCo",
+		Category: "soda",
+		Keywords: []string{"Mtn Dew"},
+	}
+
+	product := definition
+	product.ID = "1234567890"
+	product.CreatedAt = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+
+		requestBody string
+
+		wantCreated    bool
+		wantStatusCode int
+	}{
+		{
+			name: "should create a product",
+
+			requestBody: `{"name": "Mountain Dew", "brand": "PepsiCo", "category": "soda", "keywords": ["Mtn Dew"]}`,
+
+			wantCreated:    true,
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name: "should fail due keyword without words",
+
+			requestBody: `{"name": "Mountain Dew", "keywords": ["  -  "]}`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due invalid JSON",
+
+			requestBody: `{"name":`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.ProductService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		RegisterRoutes(router.Group("/products"), mockService)
+
+		if tc.wantCreated {
+			mockService.On(
+				"CreateProduct",
+				mock.Anything, /* context.Context */
+				definition,
+			).Return(product, nil).Once()
+		}
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Post(fmt.Sprintf("%s/products", server.URL), "application/json", bytes.NewBufferString(tc.requestBody))
+			if err != nil {
+				t.Fatalf("CreateProduct() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("CreateProduct() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			if tc.wantStatusCode == http.StatusCreated {
+				var got entity.Product
+				if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+					t.Fatalf("CreateProduct() = Decoding error %v", err)
+				}
+
+				if !reflect.DeepEqual(got, product) {
+					t.Errorf("CreateProduct() = %v, want %v", got, product)
+				}
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMatchProduct(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		product entity.Product
+
+		wantStatusCode int
+	}{
+		{
+			name: "should return the matching product",
+
+			product: entity.Product{ID: "1", Name: "Mountain Dew"},
+
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "should fail due no matching product",
+
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.ProductService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		RegisterRoutes(router.Group("/products"), mockService)
+
+		mockService.On(
+			"MatchProducts",
+			mock.Anything, /* context.Context */
+			[]entity.Item{{ShortDescription: "MTN DEW 12PK"}},
+		).Return([]entity.Product{tc.product}, nil).Once()
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Post(fmt.Sprintf("%s/products/match", server.URL), "application/json", bytes.NewBufferString(`{"shortDescription": "MTN DEW 12PK"}`))
+			if err != nil {
+				t.Fatalf("MatchProduct() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("MatchProduct() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			if tc.wantStatusCode == http.StatusOK {
+				var got entity.Product
+				if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+					t.Fatalf("MatchProduct() = Decoding error %v", err)
+				}
+
+				if got.ID != tc.product.ID {
+					t.Errorf("MatchProduct() = %v, want %v", got.ID, tc.product.ID)
+				}
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteProduct(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		deleteErr error
+
+		wantStatusCode int
+	}{
+		{
+			name: "should delete a product",
+
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "should fail due unknown product",
+
+			deleteErr: entity.ErrProductNotFound,
+
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.ProductService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		RegisterRoutes(router.Group("/products"), mockService)
+
+		mockService.On(
+			"DeleteProduct",
+			mock.Anything, /* context.Context */
+			"1",
+		).Return(tc.deleteErr).Once()
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/products/1", server.URL), nil)
+			if err != nil {
+				t.Fatalf("DeleteProduct() = error %v", err)
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatalf("DeleteProduct() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("DeleteProduct() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
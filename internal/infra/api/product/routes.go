@@ -0,0 +1,17 @@
+package product
+
+import (
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, productService port.ProductService) {
+	controller := newProductController(productService)
+
+	router.POST("", controller.createProduct)
+	router.GET("", controller.listProducts)
+	router.POST("/match", controller.matchProduct)
+	router.GET("/:product_id", controller.getProduct)
+	router.PUT("/:product_id", controller.updateProduct)
+	router.DELETE("/:product_id", controller.deleteProduct)
+}
@@ -0,0 +1,160 @@
+package promotion
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/gin-gonic/gin"
+)
+
+type promotionController struct {
+	promotionService port.PromotionService
+}
+
+func newPromotionController(promotionService port.PromotionService) *promotionController {
+	return &promotionController{
+		promotionService: promotionService,
+	}
+}
+
+// promotionRequest is the definition of a promotion, without the fields set
+// by the service.
+type promotionRequest struct {
+	Name       string  `json:"name"`
+	MerchantID string  `json:"merchantId"`
+	StartDate  string  `json:"startDate"`
+	EndDate    string  `json:"endDate"`
+	Multiplier float64 `json:"multiplier"`
+	Bonus      int64   `json:"bonus"`
+	MaxPoints  int64   `json:"maxPoints"`
+	Stacking   string  `json:"stacking"`
+}
+
+type promotionListResponse struct {
+	Promotions []entity.Promotion `json:"promotions"`
+}
+
+func (r promotionRequest) promotion() entity.Promotion {
+	return entity.Promotion{
+		Name:       r.Name,
+		MerchantID: r.MerchantID,
+		StartDate:  r.StartDate,
+		EndDate:    r.EndDate,
+		Multiplier: r.Multiplier,
+		Bonus:      r.Bonus,
+		MaxPoints:  r.MaxPoints,
+		Stacking:   r.Stacking,
+	}
+}
+
+// createPromotion creates a promotion, which applies to the receipts scored
+// from then on.
+func (pc *promotionController) createPromotion(c *gin.Context) {
+	promotion, ok := bindPromotion(c)
+	if !ok {
+		return
+	}
+
+	promotion, err := pc.promotionService.CreatePromotion(c, promotion)
+	if !checkPromotionError(c, "", err, "Error creating promotion") {
+		return
+	}
+
+	c.JSON(http.StatusCreated, promotion)
+}
+
+func (pc *promotionController) listPromotions(c *gin.Context) {
+	promotions, err := pc.promotionService.ListPromotions(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error listing promotions": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, promotionListResponse{Promotions: promotions})
+}
+
+func (pc *promotionController) getPromotion(c *gin.Context) {
+	promotionID := c.Param("promotion_id")
+
+	promotion, err := pc.promotionService.GetPromotion(c, promotionID)
+	if !checkPromotionError(c, promotionID, err, "Error getting promotion") {
+		return
+	}
+
+	c.JSON(http.StatusOK, promotion)
+}
+
+// updatePromotion replaces the definition of a promotion. The points of the
+// receipts already scored aren't changed.
+func (pc *promotionController) updatePromotion(c *gin.Context) {
+	promotionID := c.Param("promotion_id")
+
+	promotion, ok := bindPromotion(c)
+	if !ok {
+		return
+	}
+
+	promotion, err := pc.promotionService.UpdatePromotion(c, promotionID, promotion)
+	if !checkPromotionError(c, promotionID, err, "Error updating promotion") {
+		return
+	}
+
+	c.JSON(http.StatusOK, promotion)
+}
+
+func (pc *promotionController) deletePromotion(c *gin.Context) {
+	promotionID := c.Param("promotion_id")
+
+	err := pc.promotionService.DeletePromotion(c, promotionID)
+	if !checkPromotionError(c, promotionID, err, "Error deleting promotion") {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// bindPromotion decodes and validates the promotion of the request. If it's
+// invalid it writes the error response and returns false.
+func bindPromotion(c *gin.Context) (entity.Promotion, bool) {
+	var request promotionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Code:    entity.FieldErrorInvalidJSON,
+			Message: "the request body is not a valid promotion",
+		}}})
+		return entity.Promotion{}, false
+	}
+
+	promotion := request.promotion()
+	if fieldErrors := promotion.Validate(); fieldErrors != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": fieldErrors})
+		return entity.Promotion{}, false
+	}
+
+	return promotion, true
+}
+
+// checkPromotionError writes the error response if there is an error, and
+// returns whether there was none.
+func checkPromotionError(c *gin.Context, promotionID string, err error, message string) bool {
+	if errors.Is(err, entity.ErrPromotionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"Promotion not found for that id": promotionID})
+		return false
+	}
+	if errors.Is(err, entity.ErrMerchantNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Field:   "merchantId",
+			Code:    entity.FieldErrorInvalidValue,
+			Message: "merchantId must be the id of a merchant",
+		}}})
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{message: err.Error()})
+		return false
+	}
+
+	return true
+}
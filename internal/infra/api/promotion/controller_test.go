@@ -0,0 +1,253 @@
+package promotion
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreatePromotion(t *testing.T) {
+	definition := entity.Promotion{
+		Name:       "Double points at Target",
+		MerchantID: "1",
+		StartDate:  "2024-03-01",
+		EndDate:    "2024-03-07",
+		Multiplier: 2,
+		MaxPoints:  500,
+	}
+
+	promotion := definition
+	promotion.ID = "1234567890"
+	promotion.Stacking = entity.PromotionStackingCombine
+	promotion.CreatedAt = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+
+		requestBody string
+
+		wantCreated    bool
+		createErr      error
+		wantStatusCode int
+	}{
+		{
+			name: "should create a promotion",
+
+			requestBody: `{"name": "Double points at Target", "merchantId": "1", "startDate": "2024-03-01", "endDate": "2024-03-07", "multiplier": 2, "maxPoints": 500}`,
+
+			wantCreated:    true,
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name: "should fail due unknown merchant",
+
+			requestBody: `{"name": "Double points at Target", "merchantId": "1", "startDate": "2024-03-01", "endDate": "2024-03-07", "multiplier": 2, "maxPoints": 500}`,
+
+			wantCreated:    true,
+			createErr:      entity.ErrMerchantNotFound,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due invalid promotion",
+
+			requestBody: `{"name": "Double points at Target", "startDate": "2024-03-07", "endDate": "2024-03-01", "multiplier": 2}`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due invalid JSON",
+
+			requestBody: `{"name":`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.PromotionService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		RegisterRoutes(router.Group("/promotions"), mockService)
+
+		if tc.wantCreated {
+			mockService.On(
+				"CreatePromotion",
+				mock.Anything, /* context.Context */
+				definition,
+			).Return(promotion, tc.createErr).Once()
+		}
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Post(fmt.Sprintf("%s/promotions", server.URL), "application/json", bytes.NewBufferString(tc.requestBody))
+			if err != nil {
+				t.Fatalf("CreatePromotion() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("CreatePromotion() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			if tc.wantStatusCode == http.StatusCreated {
+				var got entity.Promotion
+				if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+					t.Fatalf("CreatePromotion() = Decoding error %v", err)
+				}
+
+				if !reflect.DeepEqual(got, promotion) {
+					t.Errorf("CreatePromotion() = %v, want %v", got, promotion)
+				}
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdatePromotion(t *testing.T) {
+	definition := entity.Promotion{Name: "New partner", Bonus: 100, Stacking: entity.PromotionStackingExclusive}
+
+	testCases := []struct {
+		name string
+
+		updateErr error
+
+		wantStatusCode int
+	}{
+		{
+			name: "should update a promotion",
+
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "should fail due unknown promotion",
+
+			updateErr: entity.ErrPromotionNotFound,
+
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.PromotionService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		RegisterRoutes(router.Group("/promotions"), mockService)
+
+		mockService.On(
+			"UpdatePromotion",
+			mock.Anything, /* context.Context */
+			"1",
+			definition,
+		).Return(entity.Promotion{ID: "1"}, tc.updateErr).Once()
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			request, err := http.NewRequest(
+				http.MethodPut,
+				fmt.Sprintf("%s/promotions/1", server.URL),
+				bytes.NewBufferString(`{"name": "New partner", "bonus": 100, "stacking": "exclusive"}`),
+			)
+			if err != nil {
+				t.Fatalf("UpdatePromotion() = error %v", err)
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatalf("UpdatePromotion() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("UpdatePromotion() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeletePromotion(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		deleteErr error
+
+		wantStatusCode int
+	}{
+		{
+			name: "should delete a promotion",
+
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "should fail due unknown promotion",
+
+			deleteErr: entity.ErrPromotionNotFound,
+
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.PromotionService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		RegisterRoutes(router.Group("/promotions"), mockService)
+
+		mockService.On(
+			"DeletePromotion",
+			mock.Anything, /* context.Context */
+			"1",
+		).Return(tc.deleteErr).Once()
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/promotions/1", server.URL), nil)
+			if err != nil {
+				t.Fatalf("DeletePromotion() = error %v", err)
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatalf("DeletePromotion() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("DeletePromotion() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
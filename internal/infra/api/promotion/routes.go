@@ -0,0 +1,16 @@
+package promotion
+
+import (
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, promotionService port.PromotionService) {
+	controller := newPromotionController(promotionService)
+
+	router.POST("", controller.createPromotion)
+	router.GET("", controller.listPromotions)
+	router.GET("/:promotion_id", controller.getPromotion)
+	router.PUT("/:promotion_id", controller.updatePromotion)
+	router.DELETE("/:promotion_id", controller.deletePromotion)
+}
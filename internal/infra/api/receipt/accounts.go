@@ -0,0 +1,81 @@
+package receipt
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// accountIDParam is the query parameter with the loyalty account the
+// submitted receipts are attached to.
+const accountIDParam = "accountId"
+
+// submissionAccount gets the loyalty account the receipts of the request are
+// submitted to, which is empty if there is none or the accounts are disabled.
+// If the account doesn't exist it writes the error response and returns false.
+func (rc *receiptController) submissionAccount(c *gin.Context) (string, bool) {
+	accountID := c.Query(accountIDParam)
+	if accountID == "" || rc.accountService == nil {
+		return "", true
+	}
+
+	_, err := rc.accountService.GetAccount(c, accountID)
+	if errors.Is(err, entity.ErrAccountNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"Account not found for that id": accountID})
+		return "", false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error getting account": err.Error()})
+		return "", false
+	}
+
+	return accountID, true
+}
+
+// settleReceiptPoints credits the points of a scored receipt to its account,
+// or none if its fraud screening doesn't award them or they expired.
+func (rc *receiptController) settleReceiptPoints(ctx context.Context, record entity.ReceiptRecord, points entity.ReceiptPoints) {
+	if rc.accountService == nil || record.AccountID == "" || !points.Scored() {
+		return
+	}
+
+	awarded := points.Points
+
+	if rc.expiryService != nil {
+		validity, err := rc.expiryService.GetPointsValidity(ctx, record.Receipt, points, rc.now().UTC())
+		if err != nil {
+			log.Printf("Error getting validity of receipt %s to settle its points: %v", record.ID, err)
+			return
+		}
+		awarded = validity.ValidPoints
+	}
+
+	if rc.fraudService != nil {
+		screening, ok, err := rc.fraudService.GetScreening(ctx, record.ID)
+		if err != nil {
+			log.Printf("Error getting screening of receipt %s to settle its points: %v", record.ID, err)
+			return
+		}
+		if ok && !screening.Awarded() {
+			awarded = 0
+		}
+	}
+
+	rc.settle(ctx, record, awarded)
+}
+
+// settle brings the points credited to the account of a receipt to the given
+// points. The receipt is already stored, so failures are only logged.
+func (rc *receiptController) settle(ctx context.Context, record entity.ReceiptRecord, points int64) {
+	if rc.accountService == nil || record.AccountID == "" {
+		return
+	}
+
+	if err := rc.accountService.SettleReceiptPoints(ctx, record.AccountID, record.ID, points); err != nil {
+		log.Printf("Error settling points of receipt %s to account %s: %v", record.ID, record.AccountID, err)
+	}
+}
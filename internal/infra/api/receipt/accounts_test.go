@@ -0,0 +1,255 @@
+package receipt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateReceiptWithAccount(t *testing.T) {
+	submittedAt := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	receipt := entity.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-01",
+		PurchaseTime: "13:01",
+		Items: []entity.Item{
+			{
+				ShortDescription: "Mountain Dew 12PK",
+				Price:            entity.MustParseMoney("6.49"),
+			},
+		},
+		Total: entity.MustParseMoney("6.49"),
+	}
+
+	testCases := []struct {
+		name string
+
+		accountErr  error
+		screening   entity.FraudScreening
+		validPoints int64
+
+		wantSettledPoints int64
+		wantStatusCode    int
+	}{
+		{
+			name: "should credit the points of the receipt to its account",
+
+			screening:   entity.FraudScreening{Status: entity.ScreeningStatusClear},
+			validPoints: 28,
+
+			wantSettledPoints: 28,
+			wantStatusCode:    http.StatusOK,
+		},
+		{
+			name: "should credit no points if they expired",
+
+			screening: entity.FraudScreening{Status: entity.ScreeningStatusClear},
+
+			wantSettledPoints: 0,
+			wantStatusCode:    http.StatusOK,
+		},
+		{
+			name: "should credit no points for a rejected receipt",
+
+			screening:   entity.FraudScreening{Status: entity.ScreeningStatusRejected, DuplicateOf: "first"},
+			validPoints: 28,
+
+			wantSettledPoints: 0,
+			wantStatusCode:    http.StatusOK,
+		},
+		{
+			name: "should fail due unknown account",
+
+			accountErr: entity.ErrAccountNotFound,
+
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.ReceiptService{}
+		mockRepository := &mocks.ReceiptRepository{}
+		mockFraudService := &mocks.FraudService{}
+		mockAccountService := &mocks.AccountService{}
+		mockExpiryService := &mocks.ExpiryService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		controller := newReceiptController(
+			mockService,
+			mockRepository,
+			WithEagerScoring(),
+			WithFraudScreening(mockFraudService),
+			WithAccounts(mockAccountService),
+			WithPointsExpiry(mockExpiryService),
+		)
+		controller.now = func() time.Time { return submittedAt }
+
+		record := entity.ReceiptRecord{ID: "1234567890", Receipt: receipt, SubmittedAt: submittedAt, AccountID: "1"}
+		breakdown := entity.PointsBreakdown{Points: 28, RuleSetVersion: "1"}
+		points := entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1", ScoredAt: &submittedAt, Breakdown: &breakdown}
+
+		mockAccountService.On(
+			"GetAccount",
+			mock.Anything, /* context.Context */
+			"1",
+		).Return(entity.Account{ID: "1"}, tc.accountErr).Once()
+
+		if tc.wantStatusCode == http.StatusOK {
+			mockService.On("ValidateReceipt", mock.Anything /* context.Context */, receipt).Return(nil).Once()
+			mockService.On("CreateReceiptID", mock.Anything /* context.Context */).Return("1234567890").Once()
+			mockRepository.On("SaveReceipt", mock.Anything /* context.Context */, record).Return(nil).Once()
+			mockFraudService.On("ScreenReceipt", mock.Anything /* context.Context */, record).Return(tc.screening, nil).Once()
+
+			mockService.On(
+				"GetRecordPointsBreakdown",
+				mock.Anything, /* context.Context */
+				record,
+				"",
+			).Return(breakdown, nil).Once()
+
+			mockRepository.On(
+				"SaveReceiptPoints",
+				mock.Anything, /* context.Context */
+				"1234567890",
+				points,
+			).Return(nil).Once()
+
+			mockFraudService.On(
+				"GetScreening",
+				mock.Anything, /* context.Context */
+				"1234567890",
+			).Return(tc.screening, true, nil).Once()
+
+			mockExpiryService.On(
+				"GetPointsValidity",
+				mock.Anything, /* context.Context */
+				receipt,
+				points,
+				submittedAt,
+			).Return(entity.PointsValidity{ValidPoints: tc.validPoints}, nil).Once()
+
+			mockAccountService.On(
+				"SettleReceiptPoints",
+				mock.Anything, /* context.Context */
+				"1",
+				"1234567890",
+				tc.wantSettledPoints,
+			).Return(nil).Once()
+		}
+
+		router.POST("/process", controller.createReceipt)
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			requestBody, err := json.Marshal(&receipt)
+			if err != nil {
+				t.Fatalf("CreateReceipt() = Marshaling error %v", err)
+			}
+
+			response, err := http.Post(fmt.Sprintf("%s/process?accountId=1", server.URL), "application/json", bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Fatalf("CreateReceipt() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("CreateReceipt() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockService.AssertExpectations(t)
+			mockRepository.AssertExpectations(t)
+			mockFraudService.AssertExpectations(t)
+			mockAccountService.AssertExpectations(t)
+			mockExpiryService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteReceiptWithAccount(t *testing.T) {
+	mockReceiptID := "1234567890"
+
+	mockService := &mocks.ReceiptService{}
+	mockRepository := &mocks.ReceiptRepository{}
+	mockAuditRepository := &mocks.ReceiptAuditRepository{}
+	mockAccountService := &mocks.AccountService{}
+
+	// Create a new router for tests.
+	router := gin.Default()
+	gin.SetMode(gin.TestMode)
+
+	controller := newReceiptController(
+		mockService,
+		mockRepository,
+		WithAuditLog(mockAuditRepository),
+		WithAccounts(mockAccountService),
+	)
+
+	mockRepository.On(
+		"GetReceiptByID",
+		mock.Anything, /* context.Context */
+		mockReceiptID,
+	).Return(entity.ReceiptRecord{ID: mockReceiptID, AccountID: "1"}, nil).Once()
+
+	mockRepository.On(
+		"GetReceiptPoints",
+		mock.Anything, /* context.Context */
+		mockReceiptID,
+	).Return(entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28}, nil).Once()
+
+	mockAuditRepository.On(
+		"DeleteReceipt",
+		mock.Anything, /* context.Context */
+		mockReceiptID,
+		mock.Anything, /* entity.ReceiptAuditEntry */
+	).Return(nil).Once()
+
+	// The points of the deleted receipt are taken back from the account.
+	mockAccountService.On(
+		"SettleReceiptPoints",
+		mock.Anything, /* context.Context */
+		"1",
+		mockReceiptID,
+		int64(0),
+	).Return(nil).Once()
+
+	router.DELETE("/:receipt_id", controller.deleteReceipt)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%s", server.URL, mockReceiptID), nil)
+	if err != nil {
+		t.Fatalf("DeleteReceipt() = error %v", err)
+	}
+	request.Header.Set(actorHeader, "support")
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("DeleteReceipt() = error %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusNoContent {
+		t.Errorf("DeleteReceipt() = %v, want %v", response.StatusCode, http.StatusNoContent)
+	}
+
+	mockRepository.AssertExpectations(t)
+	mockAuditRepository.AssertExpectations(t)
+	mockAccountService.AssertExpectations(t)
+}
@@ -0,0 +1,339 @@
+package receipt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// Headers identifying who changes a receipt and why, recorded in its audit log.
+const (
+	actorHeader        = "X-Actor"
+	changeReasonHeader = "X-Change-Reason"
+)
+
+type receiptHistoryResponse struct {
+	ReceiptID string                     `json:"receiptId"`
+	History   []entity.ReceiptAuditEntry `json:"history"`
+}
+
+// replaceReceipt replaces a stored receipt with the one in the body.
+func (rc *receiptController) replaceReceipt(c *gin.Context) {
+	rc.amendReceipt(c, func(current entity.Receipt, body []byte) ([]byte, error) {
+		return body, nil
+	})
+}
+
+// patchReceipt changes the fields of a stored receipt sent in the body as a
+// JSON merge patch (RFC 7386). Items are replaced as a whole.
+func (rc *receiptController) patchReceipt(c *gin.Context) {
+	rc.amendReceipt(c, func(current entity.Receipt, body []byte) ([]byte, error) {
+		return mergePatchReceipt(current, body)
+	})
+}
+
+// amendReceipt replaces a stored receipt with the one built from the request
+// body and scores it again, storing the change along with its entry in the
+// audit log, and screens it again.
+func (rc *receiptController) amendReceipt(c *gin.Context, build func(current entity.Receipt, body []byte) ([]byte, error)) {
+	receiptID := c.Param("receipt_id")
+
+	actor, ok := changeActor(c)
+	if !ok {
+		return
+	}
+
+	body, ok := readReceiptBody(c)
+	if !ok {
+		return
+	}
+
+	rc.mutations.Lock()
+	defer rc.mutations.Unlock()
+
+	record, ok := rc.findReceipt(c, receiptID)
+	if !ok {
+		return
+	}
+
+	data, err := build(record.Receipt, body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Code:    entity.FieldErrorInvalidJSON,
+			Message: "the request body is not a valid JSON object",
+		}}})
+		return
+	}
+
+	receipt, fieldErrors := entity.DecodeReceipt(data)
+	if fieldErrors == nil {
+		if err := rc.receiptService.ValidateReceipt(c, receipt); err != nil {
+			var validationErr *entity.ValidationError
+			if !errors.As(err, &validationErr) {
+				c.JSON(http.StatusInternalServerError, gin.H{"Error validating receipt": err.Error()})
+				return
+			}
+			fieldErrors = validationErr.Errors
+		}
+	}
+	if fieldErrors != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": fieldErrors})
+		return
+	}
+
+	previousPoints, err := rc.receiptRepository.GetReceiptPoints(c, receiptID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error getting receipt points": err.Error()})
+		return
+	}
+
+	merchantID, err := rc.resolveMerchant(c, receipt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error resolving the merchant of the receipt": err.Error()})
+		return
+	}
+
+	itemProductIDs, err := rc.matchProducts(c, receipt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error matching the items of the receipt to products": err.Error()})
+		return
+	}
+
+	amended := record
+	amended.Receipt = receipt
+	amended.MerchantID = merchantID
+	amended.ItemProductIDs = itemProductIDs
+
+	// Points that can't be calculated are stored as the failed scoring state,
+	// and the receipt is scored again when its points are requested.
+	points, err := rc.calculatePoints(c, amended, "")
+	if err != nil {
+		log.Printf("Error scoring amended receipt %s: %v", receiptID, err)
+	}
+
+	if !rc.checkHeldPoints(c, receiptID, points) {
+		return
+	}
+
+	entry := entity.NewReceiptAuditEntry(
+		receiptID, entity.AuditActionAmended, actor, c.GetHeader(changeReasonHeader), rc.now().UTC(),
+		&record.Receipt, &amended.Receipt, previousPoints, points,
+	)
+	if err := rc.auditRepository.AmendReceipt(c, amended, points, entry); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error amending receipt": err.Error()})
+		return
+	}
+
+	rc.rehashReceipt(c, record, &amended.Receipt)
+
+	if rc.fraudService != nil {
+		// The fingerprint and screening follow the amended receipt, before its
+		// points are credited. The amendment is already stored, so failures
+		// are only logged.
+		if _, err := rc.fraudService.ScreenReceipt(c, amended); err != nil {
+			log.Printf("Error screening amended receipt %s: %v", receiptID, err)
+		}
+	}
+
+	rc.settleReceiptPoints(c, amended, points)
+
+	c.JSON(http.StatusOK, newReceiptResponse(amended, points))
+}
+
+// checkHeldPoints checks that the points of an amended receipt still cover the
+// points held by its redemptions. If they don't, or the check fails, it writes
+// the error response and returns false.
+func (rc *receiptController) checkHeldPoints(c *gin.Context, receiptID string, points entity.ReceiptPoints) bool {
+	// Points that failed to be calculated are scored again when requested.
+	if rc.redemptionService == nil || !points.Scored() {
+		return true
+	}
+
+	held, err := rc.redemptionService.GetHeldPoints(c, receiptID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error getting the points held by the redemptions of the receipt": err.Error()})
+		return false
+	}
+
+	if points.Points < held {
+		c.JSON(http.StatusConflict, gin.H{"The amended receipt would have fewer points than its redemptions hold": held})
+		return false
+	}
+
+	return true
+}
+
+// deleteReceipt deletes a stored receipt along with the entry of the deletion
+// in its audit log.
+func (rc *receiptController) deleteReceipt(c *gin.Context) {
+	receiptID := c.Param("receipt_id")
+
+	actor, ok := changeActor(c)
+	if !ok {
+		return
+	}
+
+	rc.mutations.Lock()
+	defer rc.mutations.Unlock()
+
+	record, ok := rc.findReceipt(c, receiptID)
+	if !ok {
+		return
+	}
+
+	previousPoints, err := rc.receiptRepository.GetReceiptPoints(c, receiptID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error getting receipt points": err.Error()})
+		return
+	}
+
+	entry := entity.NewReceiptAuditEntry(
+		receiptID, entity.AuditActionDeleted, actor, c.GetHeader(changeReasonHeader), rc.now().UTC(),
+		&record.Receipt, nil, previousPoints, entity.ReceiptPoints{},
+	)
+	if err := rc.auditRepository.DeleteReceipt(c, receiptID, entry); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error deleting receipt": err.Error()})
+		return
+	}
+
+	rc.rehashReceipt(c, record, nil)
+
+	// The points of a deleted receipt are taken back from its account.
+	rc.settle(c, record, 0)
+
+	c.Status(http.StatusNoContent)
+}
+
+// getReceiptHistory gets the audit log of a receipt, which is kept after the
+// receipt is deleted.
+func (rc *receiptController) getReceiptHistory(c *gin.Context) {
+	receiptID := c.Param("receipt_id")
+
+	entries, err := rc.auditRepository.ListReceiptAuditEntries(c, receiptID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error getting receipt history": err.Error()})
+		return
+	}
+
+	// Receipts that were never changed have no history, but they must exist.
+	if len(entries) == 0 {
+		if _, ok := rc.findReceipt(c, receiptID); !ok {
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, receiptHistoryResponse{ReceiptID: receiptID, History: entries})
+}
+
+// rehashReceipt moves the idempotency record of the hash of a changed receipt
+// to the hash of the amended receipt, or deletes it if the receipt is deleted,
+// so submitting the receipt as it was creates a new one and submitting it as
+// amended returns it. The change is already stored, so failures are only logged.
+func (rc *receiptController) rehashReceipt(ctx context.Context, record entity.ReceiptRecord, amended *entity.Receipt) {
+	if rc.idempotencyRepository == nil || !rc.hashReceipts {
+		return
+	}
+
+	previousKey := rc.idempotencyRecordKey("", record.AccountID, record.Receipt)
+	if err := rc.idempotencyRepository.DeleteReceiptIdempotencyRecord(ctx, previousKey, record.ID); err != nil {
+		log.Printf("Error deleting the idempotency record of receipt %s: %v", record.ID, err)
+		return
+	}
+
+	if amended == nil {
+		return
+	}
+
+	// If another receipt has the same hash, its record is kept and the
+	// amended receipt is left to the fraud screening as a duplicate of it.
+	now := rc.now()
+	if _, _, err := rc.idempotencyRepository.SaveIdempotencyRecord(ctx, entity.IdempotencyRecord{
+		Key:         rc.idempotencyRecordKey("", record.AccountID, *amended),
+		RequestHash: amended.CanonicalHash(),
+		ReceiptID:   record.ID,
+		Completed:   true,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(rc.idempotencyRetention),
+	}); err != nil {
+		log.Printf("Error saving the idempotency record of receipt %s: %v", record.ID, err)
+	}
+}
+
+// changeActor gets who changes a receipt from the request headers. If it's
+// missing it writes the error response and returns false.
+func changeActor(c *gin.Context) (string, bool) {
+	actor := c.GetHeader(actorHeader)
+	if actor == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Field:   actorHeader,
+			Code:    entity.FieldErrorRequired,
+			Message: actorHeader + " header is required to change a receipt",
+		}}})
+		return "", false
+	}
+
+	return actor, true
+}
+
+// mergePatchReceipt applies a JSON merge patch to a receipt and returns the
+// patched receipt as JSON, to be decoded and validated as a submitted one.
+func mergePatchReceipt(receipt entity.Receipt, patch []byte) ([]byte, error) {
+	current, err := json.Marshal(receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	var document, patchDocument any
+	if err := decodeJSONValue(current, &document); err != nil {
+		return nil, err
+	}
+	if err := decodeJSONValue(patch, &patchDocument); err != nil {
+		return nil, err
+	}
+
+	// A patch that isn't an object would replace the whole receipt.
+	if _, ok := patchDocument.(map[string]any); !ok {
+		return nil, errors.New("the patch must be a JSON object")
+	}
+
+	return json.Marshal(mergePatch(document, patchDocument))
+}
+
+// mergePatch applies a JSON merge patch to a decoded JSON document: null
+// members are removed and the rest replace the members of the document,
+// merging the objects recursively.
+func mergePatch(document, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	documentObject, ok := document.(map[string]any)
+	if !ok {
+		documentObject = make(map[string]any)
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(documentObject, key)
+		} else {
+			documentObject[key] = mergePatch(documentObject[key], value)
+		}
+	}
+
+	return documentObject
+}
+
+// decodeJSONValue decodes JSON keeping the numbers as they were written.
+func decodeJSONValue(data []byte, value any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return decoder.Decode(value)
+}
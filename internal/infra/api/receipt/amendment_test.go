@@ -0,0 +1,504 @@
+package receipt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAmendReceipt(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	storedReceipt := entity.Receipt{
+		Retailer:     "Targt",
+		PurchaseDate: "2022-01-01",
+		PurchaseTime: "13:01",
+		Items: []entity.Item{
+			{
+				ShortDescription: "Mountain Dew 12PK",
+				Price:            entity.MustParseMoney("6.49"),
+			},
+		},
+		Total: entity.MustParseMoney("6.49"),
+	}
+
+	amendedReceipt := storedReceipt
+	amendedReceipt.Retailer = "Target"
+
+	testCases := []struct {
+		name string
+
+		method  string
+		actor   string
+		request string
+
+		storedErr  error
+		amendErr   error
+		heldPoints int64
+
+		wantAmended    *entity.Receipt
+		wantStatusCode int
+	}{
+		{
+			name: "should replace the receipt",
+
+			method: http.MethodPut,
+			actor:  "support",
+			request: `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01",` +
+				` "items": [{"shortDescription": "Mountain Dew 12PK", "price": "6.49"}], "total": "6.49"}`,
+
+			wantAmended:    &amendedReceipt,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "should patch only the given fields of the receipt",
+
+			method:  http.MethodPatch,
+			actor:   "support",
+			request: `{"retailer": "Target"}`,
+
+			wantAmended:    &amendedReceipt,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "should amend the receipt if its points still cover its redemptions",
+
+			method:  http.MethodPatch,
+			actor:   "support",
+			request: `{"retailer": "Target"}`,
+
+			heldPoints: 34,
+
+			wantAmended:    &amendedReceipt,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "should fail due amendment leaving fewer points than its redemptions hold",
+
+			method:  http.MethodPatch,
+			actor:   "support",
+			request: `{"retailer": "Target"}`,
+
+			heldPoints: 35,
+
+			wantAmended:    &amendedReceipt,
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name: "should fail due error storing the amendment",
+
+			method:  http.MethodPatch,
+			actor:   "support",
+			request: `{"retailer": "Target"}`,
+
+			amendErr: errors.New("database is locked"),
+
+			wantAmended:    &amendedReceipt,
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "should fail due patch removing a required field",
+
+			method:  http.MethodPatch,
+			actor:   "support",
+			request: `{"retailer": null}`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due patch that isn't an object",
+
+			method:  http.MethodPatch,
+			actor:   "support",
+			request: `["retailer"]`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due missing actor",
+
+			method:  http.MethodPatch,
+			request: `{"retailer": "Target"}`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due unknown receipt",
+
+			method:  http.MethodPut,
+			actor:   "support",
+			request: `{"retailer": "Target"}`,
+
+			storedErr: entity.ErrReceiptNotFound,
+
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockReceiptID := "1234567890"
+
+		mockService := &mocks.ReceiptService{}
+		mockRepository := &mocks.ReceiptRepository{}
+		mockAuditRepository := &mocks.ReceiptAuditRepository{}
+		mockRedemptionService := &mocks.RedemptionService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		controller := newReceiptController(
+			mockService, mockRepository, WithAuditLog(mockAuditRepository), WithRedemptions(mockRedemptionService),
+		)
+		controller.now = func() time.Time { return now }
+
+		mockRepository.On(
+			"GetReceiptByID",
+			mock.Anything, /* context.Context */
+			mockReceiptID,
+		).Return(entity.ReceiptRecord{ID: mockReceiptID, Receipt: storedReceipt, SubmittedAt: now.Add(-time.Hour)}, tc.storedErr).Maybe()
+
+		// The missing required fields are reported by the validation.
+		mockService.On(
+			"ValidateReceipt",
+			mock.Anything, /* context.Context */
+			mock.MatchedBy(func(receipt entity.Receipt) bool { return receipt.Retailer == "" }),
+		).Return(&entity.ValidationError{Errors: []entity.FieldError{{Field: "retailer", Code: entity.FieldErrorRequired}}}).Maybe()
+
+		if tc.wantAmended != nil {
+			amendedBreakdown := entity.PointsBreakdown{Points: 34, RuleSetVersion: "1"}
+
+			mockService.On(
+				"ValidateReceipt",
+				mock.Anything, /* context.Context */
+				*tc.wantAmended,
+			).Return(nil).Once()
+
+			mockRepository.On(
+				"GetReceiptPoints",
+				mock.Anything, /* context.Context */
+				mockReceiptID,
+			).Return(entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1"}, nil).Once()
+
+			mockService.On(
+				"GetRecordPointsBreakdown",
+				mock.Anything, /* context.Context */
+				mock.MatchedBy(func(record entity.ReceiptRecord) bool { return reflect.DeepEqual(record.Receipt, *tc.wantAmended) }),
+				"",
+			).Return(amendedBreakdown, nil).Once()
+
+			mockRedemptionService.On(
+				"GetHeldPoints",
+				mock.Anything, /* context.Context */
+				mockReceiptID,
+			).Return(tc.heldPoints, nil).Once()
+		}
+
+		// The amendments leaving fewer points than the redemptions hold aren't stored.
+		if tc.wantAmended != nil && tc.heldPoints <= 34 {
+			amendedBreakdown := entity.PointsBreakdown{Points: 34, RuleSetVersion: "1"}
+
+			// The receipt keeps its ID and submission time, and is stored along
+			// with its points and the entry of the amendment.
+			previousPoints, newPoints := int64(28), int64(34)
+			mockAuditRepository.On(
+				"AmendReceipt",
+				mock.Anything, /* context.Context */
+				entity.ReceiptRecord{ID: mockReceiptID, Receipt: *tc.wantAmended, SubmittedAt: now.Add(-time.Hour)},
+				entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 34, RuleSetVersion: "1", ScoredAt: &now, Breakdown: &amendedBreakdown},
+				entity.ReceiptAuditEntry{
+					ReceiptID:      mockReceiptID,
+					Action:         entity.AuditActionAmended,
+					Actor:          tc.actor,
+					Reason:         "typo in the retailer",
+					At:             now,
+					Previous:       &storedReceipt,
+					New:            tc.wantAmended,
+					PreviousPoints: &previousPoints,
+					NewPoints:      &newPoints,
+					PointsDelta:    6,
+				},
+			).Return(tc.amendErr).Once()
+		}
+
+		router.PUT("/:receipt_id", controller.replaceReceipt)
+		router.PATCH("/:receipt_id", controller.patchReceipt)
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			request, err := http.NewRequest(tc.method, fmt.Sprintf("%s/%s", server.URL, mockReceiptID), strings.NewReader(tc.request))
+			if err != nil {
+				t.Fatalf("AmendReceipt() = error %v", err)
+			}
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("X-Change-Reason", "typo in the retailer")
+			if tc.actor != "" {
+				request.Header.Set("X-Actor", tc.actor)
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatalf("AmendReceipt() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("AmendReceipt() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockService.AssertExpectations(t)
+			mockRepository.AssertExpectations(t)
+			mockAuditRepository.AssertExpectations(t)
+			mockRedemptionService.AssertExpectations(t)
+
+			if tc.wantStatusCode != http.StatusOK {
+				mockRepository.AssertNotCalled(t, "SaveReceipt", mock.Anything, mock.Anything)
+				mockRepository.AssertNotCalled(t, "SaveReceiptPoints", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			got := receiptResponse{}
+			if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+				t.Errorf("AmendReceipt() = Unmarshaling response error %v", err)
+			}
+
+			if !reflect.DeepEqual(got.Receipt, *tc.wantAmended) || got.Points == nil || *got.Points != 34 {
+				t.Errorf("AmendReceipt() = %+v, want %+v with 34 points", got, *tc.wantAmended)
+			}
+		})
+	}
+}
+
+func TestDeleteReceipt(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	storedReceipt := entity.Receipt{Retailer: "Target"}
+
+	testCases := []struct {
+		name string
+
+		actor     string
+		storedErr error
+		deleteErr error
+
+		wantStatusCode int
+	}{
+		{
+			name: "should delete the receipt recording it in its history",
+
+			actor: "fraud-team",
+
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "should fail due error deleting the receipt",
+
+			actor:     "fraud-team",
+			deleteErr: errors.New("database is locked"),
+
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "should fail due missing actor",
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due unknown receipt",
+
+			actor:     "fraud-team",
+			storedErr: entity.ErrReceiptNotFound,
+
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockReceiptID := "1234567890"
+
+		mockService := &mocks.ReceiptService{}
+		mockRepository := &mocks.ReceiptRepository{}
+		mockAuditRepository := &mocks.ReceiptAuditRepository{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		controller := newReceiptController(mockService, mockRepository, WithAuditLog(mockAuditRepository))
+		controller.now = func() time.Time { return now }
+
+		mockRepository.On(
+			"GetReceiptByID",
+			mock.Anything, /* context.Context */
+			mockReceiptID,
+		).Return(entity.ReceiptRecord{ID: mockReceiptID, Receipt: storedReceipt}, tc.storedErr).Maybe()
+
+		if tc.wantStatusCode == http.StatusNoContent || tc.deleteErr != nil {
+			mockRepository.On(
+				"GetReceiptPoints",
+				mock.Anything, /* context.Context */
+				mockReceiptID,
+			).Return(entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28}, nil).Once()
+
+			previousPoints := int64(28)
+			mockAuditRepository.On(
+				"DeleteReceipt",
+				mock.Anything, /* context.Context */
+				mockReceiptID,
+				entity.ReceiptAuditEntry{
+					ReceiptID:      mockReceiptID,
+					Action:         entity.AuditActionDeleted,
+					Actor:          tc.actor,
+					At:             now,
+					Previous:       &storedReceipt,
+					PreviousPoints: &previousPoints,
+					PointsDelta:    -28,
+				},
+			).Return(tc.deleteErr).Once()
+		}
+
+		router.DELETE("/:receipt_id", controller.deleteReceipt)
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%s", server.URL, mockReceiptID), nil)
+			if err != nil {
+				t.Fatalf("DeleteReceipt() = error %v", err)
+			}
+			if tc.actor != "" {
+				request.Header.Set("X-Actor", tc.actor)
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatalf("DeleteReceipt() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("DeleteReceipt() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockRepository.AssertExpectations(t)
+			mockAuditRepository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetReceiptHistory(t *testing.T) {
+	deletedEntry := entity.ReceiptAuditEntry{
+		ReceiptID: "1234567890",
+		Action:    entity.AuditActionDeleted,
+		Actor:     "fraud-team",
+		At:        time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		Previous:  &entity.Receipt{Retailer: "Target"},
+	}
+
+	testCases := []struct {
+		name string
+
+		entries   []entity.ReceiptAuditEntry
+		storedErr error
+
+		wantStatusCode int
+		wantHistory    []entity.ReceiptAuditEntry
+	}{
+		{
+			name: "should return the history of a deleted receipt",
+
+			entries: []entity.ReceiptAuditEntry{deletedEntry},
+
+			wantStatusCode: http.StatusOK,
+			wantHistory:    []entity.ReceiptAuditEntry{deletedEntry},
+		},
+		{
+			name: "should return an empty history for a receipt never changed",
+
+			entries: []entity.ReceiptAuditEntry{},
+
+			wantStatusCode: http.StatusOK,
+			wantHistory:    []entity.ReceiptAuditEntry{},
+		},
+		{
+			name: "should fail due unknown receipt",
+
+			entries:   []entity.ReceiptAuditEntry{},
+			storedErr: entity.ErrReceiptNotFound,
+
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockReceiptID := "1234567890"
+
+		mockService := &mocks.ReceiptService{}
+		mockRepository := &mocks.ReceiptRepository{}
+		mockAuditRepository := &mocks.ReceiptAuditRepository{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		controller := newReceiptController(mockService, mockRepository, WithAuditLog(mockAuditRepository))
+
+		mockAuditRepository.On(
+			"ListReceiptAuditEntries",
+			mock.Anything, /* context.Context */
+			mockReceiptID,
+		).Return(tc.entries, nil).Once()
+
+		mockRepository.On(
+			"GetReceiptByID",
+			mock.Anything, /* context.Context */
+			mockReceiptID,
+		).Return(entity.ReceiptRecord{ID: mockReceiptID}, tc.storedErr).Maybe()
+
+		router.GET("/:receipt_id/history", controller.getReceiptHistory)
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Get(fmt.Sprintf("%s/%s/history", server.URL, mockReceiptID))
+			if err != nil {
+				t.Fatalf("GetReceiptHistory() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("GetReceiptHistory() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			if tc.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			got := receiptHistoryResponse{}
+			if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+				t.Errorf("GetReceiptHistory() = Unmarshaling response error %v", err)
+			}
+
+			if !reflect.DeepEqual(got.History, tc.wantHistory) {
+				t.Errorf("GetReceiptHistory() = %+v, want %+v", got.History, tc.wantHistory)
+			}
+		})
+	}
+}
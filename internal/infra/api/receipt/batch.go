@@ -0,0 +1,150 @@
+package receipt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// Content types of the batches sent as newline delimited JSON, one receipt per line.
+var ndjsonContentTypes = map[string]bool{
+	"application/x-ndjson": true,
+	"application/jsonl":    true,
+}
+
+// maxBatchLineSize is the maximum size of a receipt in a NDJSON batch.
+const maxBatchLineSize = 1 << 20
+
+// maxBatchReceiptSize is the average size allowed for the receipts of a batch,
+// which bounds the size of the whole batch to the maximum number of receipts
+// times it, so the body isn't read without limit.
+const maxBatchReceiptSize = 64 << 10
+
+// batchEntryResult is the result of processing a receipt of a batch. Index is
+// the position of the receipt in the batch, starting from 0. Replayed is set
+// when an identical receipt was already stored. Errors lists the invalid
+// fields of rejected receipts, while Error describes why a valid receipt
+// couldn't be stored.
+type batchEntryResult struct {
+	Index    int                 `json:"index"`
+	ID       string              `json:"id,omitempty"`
+	Replayed bool                `json:"replayed,omitempty"`
+	Errors   []entity.FieldError `json:"errors,omitempty"`
+	Error    string              `json:"error,omitempty"`
+}
+
+type batchResponse struct {
+	Accepted int                `json:"accepted"`
+	Rejected int                `json:"rejected"`
+	Failed   int                `json:"failed"`
+	Results  []batchEntryResult `json:"results"`
+}
+
+// processReceiptBatch stores a batch of receipts sent as a JSON array or as
+// NDJSON. Each receipt is validated and stored on its own, so invalid receipts
+// don't prevent the others from being stored.
+func (rc *receiptController) processReceiptBatch(c *gin.Context) {
+	accountID, ok := rc.submissionAccount(c)
+	if !ok {
+		return
+	}
+
+	maxBytes := int64(rc.maxBatchSize) * maxBatchReceiptSize
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+
+	body, err := c.GetRawData()
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"The batch exceeds the maximum size in bytes": maxBytes})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error reading the batch": err.Error()})
+		return
+	}
+
+	var entries []json.RawMessage
+	if ndjsonContentTypes[c.ContentType()] {
+		entries, err = splitNDJSONBatch(body)
+	} else {
+		err = json.Unmarshal(body, &entries)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Code:    entity.FieldErrorInvalidJSON,
+			Message: fmt.Sprintf("the batch must be a JSON array or NDJSON of receipts: %s", err),
+		}}})
+		return
+	}
+
+	if len(entries) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Code:    entity.FieldErrorMinItems,
+			Message: "the batch must have at least one receipt",
+		}}})
+		return
+	}
+
+	if len(entries) > rc.maxBatchSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"The batch exceeds the maximum number of receipts": rc.maxBatchSize})
+		return
+	}
+
+	response := batchResponse{Results: make([]batchEntryResult, 0, len(entries))}
+
+	for i, entry := range entries {
+		// Idempotency keys identify whole requests, so only the hashes of the
+		// receipts can tell apart the receipts of a batch already stored.
+		processed, err := rc.processReceipt(c, entry, "", accountID)
+
+		result := batchEntryResult{
+			Index:    i,
+			ID:       processed.ID,
+			Replayed: processed.Replayed,
+			Errors:   processed.FieldErrors,
+		}
+
+		switch {
+		case processed.FieldErrors != nil:
+			response.Rejected++
+		case err != nil:
+			result.Error = err.Error()
+			response.Failed++
+		default:
+			response.Accepted++
+		}
+
+		response.Results = append(response.Results, result)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// splitNDJSONBatch splits a NDJSON batch in its receipts, skipping blank lines.
+func splitNDJSONBatch(body []byte) ([]json.RawMessage, error) {
+	var entries []json.RawMessage
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxBatchLineSize)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		entries = append(entries, json.RawMessage(bytes.Clone(line)))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
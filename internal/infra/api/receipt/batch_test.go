@@ -0,0 +1,175 @@
+package receipt
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProcessReceiptBatch(t *testing.T) {
+	validReceipt := `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01",` +
+		` "items": [{"shortDescription": "Mountain Dew 12PK", "price": "6.49"}], "total": "6.49"}`
+	malformedReceipt := `{"retailer": "Target", "total": 6.49}`
+
+	testCases := []struct {
+		name string
+
+		maxBatchSize int
+
+		contentType string
+		request     string
+
+		wantStatusCode int
+		wantResponse   batchResponse
+	}{
+		{
+			name: "should process a JSON array of receipts",
+
+			maxBatchSize: 10,
+
+			contentType: "application/json",
+			request:     fmt.Sprintf("[%s, %s]", validReceipt, malformedReceipt),
+
+			wantStatusCode: http.StatusOK,
+			wantResponse: batchResponse{
+				Accepted: 1,
+				Rejected: 1,
+				Results: []batchEntryResult{
+					{Index: 0, ID: "1234567890"},
+					{Index: 1},
+				},
+			},
+		},
+		{
+			name: "should process NDJSON receipts skipping blank lines",
+
+			maxBatchSize: 10,
+
+			contentType: "application/x-ndjson",
+			request:     validReceipt + "\n\n" + validReceipt + "\n",
+
+			wantStatusCode: http.StatusOK,
+			wantResponse: batchResponse{
+				Accepted: 2,
+				Results: []batchEntryResult{
+					{Index: 0, ID: "1234567890"},
+					{Index: 1, ID: "1234567890"},
+				},
+			},
+		},
+		{
+			name: "should fail due batch larger than the maximum size",
+
+			maxBatchSize: 1,
+
+			contentType: "application/json",
+			request:     fmt.Sprintf("[%s, %s]", validReceipt, validReceipt),
+
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "should fail due batch larger than the maximum size in bytes",
+
+			maxBatchSize: 1,
+
+			contentType: "application/x-ndjson",
+			request:     validReceipt + "\n" + strings.Repeat(" ", maxBatchReceiptSize),
+
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "should fail due batch that isn't an array",
+
+			maxBatchSize: 10,
+
+			contentType: "application/json",
+			request:     validReceipt,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.ReceiptService{}
+		mockRepository := &mocks.ReceiptRepository{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		controller := newReceiptController(mockService, mockRepository, WithMaxBatchSize(tc.maxBatchSize))
+
+		mockService.On(
+			"ValidateReceipt",
+			mock.Anything, /* context.Context */
+			mock.Anything, /* entity.Receipt */
+		).Return(nil)
+
+		mockService.On(
+			"CreateReceiptID",
+			mock.Anything, /* context.Context */
+		).Return("1234567890")
+
+		mockRepository.On(
+			"SaveReceipt",
+			mock.Anything, /* context.Context */
+			mock.Anything, /* entity.ReceiptRecord */
+		).Return(nil)
+
+		router.POST("/process/batch", controller.processReceiptBatch)
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Post(
+				fmt.Sprintf("%s/process/batch", server.URL),
+				tc.contentType,
+				strings.NewReader(tc.request),
+			)
+			if err != nil {
+				t.Fatalf("ProcessReceiptBatch() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("ProcessReceiptBatch() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			if tc.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			got := batchResponse{}
+			if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+				t.Errorf("ProcessReceiptBatch() = Unmarshaling response error %v", err)
+			}
+
+			if len(got.Results) != len(tc.wantResponse.Results) {
+				t.Fatalf("ProcessReceiptBatch() = %+v, want %+v", got, tc.wantResponse)
+			}
+
+			// Only the presence of field errors is checked, their contents are
+			// covered by the decoding and validation tests.
+			for i := range got.Results {
+				if (got.Results[i].Errors != nil) != (tc.wantResponse.Results[i].ID == "") {
+					t.Errorf("ProcessReceiptBatch() result %d = %+v", i, got.Results[i])
+				}
+				got.Results[i].Errors = nil
+			}
+
+			if !reflect.DeepEqual(got, tc.wantResponse) {
+				t.Errorf("ProcessReceiptBatch() = %+v, want %+v", got, tc.wantResponse)
+			}
+		})
+	}
+}
@@ -1,60 +1,728 @@
 package receipt
 
 import (
+	"context"
+	"errors"
+	"log"
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
 	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
 	"github.com/gin-gonic/gin"
 )
 
+// defaultMaxBatchSize is the maximum number of receipts of a batch unless
+// another one is set with WithMaxBatchSize.
+const defaultMaxBatchSize = 100
+
+// maxReceiptSize is the maximum size of the body of a receipt sent on its own,
+// the same as a receipt of a NDJSON batch.
+const maxReceiptSize = maxBatchLineSize
+
+// idempotencyKeyHeader is the header with the key clients send to retry a
+// submission without creating another receipt.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength is the maximum length of an idempotency key.
+const maxIdempotencyKeyLength = 255
+
+// Prefixes of the keys of the idempotency records, so the keys sent by clients
+// can't be confused with the hashes of the receipts.
+const (
+	idempotencyKeyPrefix = "key:"
+	receiptHashKeyPrefix = "hash:"
+	// accountKeyPrefix scopes the keys to an account. The account IDs have no
+	// colons, so the account can't be confused with the rest of the key.
+	accountKeyPrefix = "account:"
+)
+
 type receiptController struct {
-	receiptService port.ReceiptService
+	receiptService    port.ReceiptService
+	receiptRepository port.ReceiptRepository
+
+	maxBatchSize int
+
+	idempotencyRepository port.IdempotencyRepository
+	idempotencyRetention  time.Duration
+	hashReceipts          bool
+	now                   func() time.Time
+
+	fraudService port.FraudService
+
+	eagerScoring bool
+
+	auditRepository port.ReceiptAuditRepository
+
+	accountService port.AccountService
+
+	expiryService port.ExpiryService
+
+	merchantService port.MerchantService
+
+	productService port.ProductService
+
+	redemptionService port.RedemptionService
+
+	// mutations serializes the amendments and deletions, so each audit entry
+	// has the receipt as it was right before the change.
+	mutations sync.Mutex
+}
+
+// Option configures the receipt routes.
+type Option func(*receiptController)
+
+// WithMaxBatchSize sets the maximum number of receipts accepted in a batch.
+func WithMaxBatchSize(size int) Option {
+	return func(rc *receiptController) {
+		rc.maxBatchSize = size
+	}
+}
+
+// WithIdempotency makes the submissions with the same Idempotency-Key header
+// return the receipt created by the first of them during the retention. If
+// hashReceipts is set, submissions without the header are identified by the
+// canonical hash of the receipt instead, so identical receipts aren't stored twice.
+func WithIdempotency(repository port.IdempotencyRepository, retention time.Duration, hashReceipts bool) Option {
+	return func(rc *receiptController) {
+		rc.idempotencyRepository = repository
+		rc.idempotencyRetention = retention
+		rc.hashReceipts = hashReceipts
+	}
+}
+
+// WithFraudScreening screens the submitted receipts for duplicates, so the
+// flagged ones aren't awarded points.
+func WithFraudScreening(fraudService port.FraudService) Option {
+	return func(rc *receiptController) {
+		rc.fraudService = fraudService
+	}
+}
+
+// WithEagerScoring scores the receipts when they are submitted, instead of
+// the first time their points are requested.
+func WithEagerScoring() Option {
+	return func(rc *receiptController) {
+		rc.eagerScoring = true
+	}
+}
+
+// WithAuditLog allows amending and deleting receipts, recording each change
+// in the audit log.
+func WithAuditLog(repository port.ReceiptAuditRepository) Option {
+	return func(rc *receiptController) {
+		rc.auditRepository = repository
+	}
 }
 
-func newReceiptController(receiptService port.ReceiptService) *receiptController {
-	return &receiptController{
-		receiptService: receiptService,
+// WithAccounts allows submitting receipts to loyalty accounts, which are
+// credited the points of their receipts.
+func WithAccounts(accountService port.AccountService) Option {
+	return func(rc *receiptController) {
+		rc.accountService = accountService
 	}
 }
 
+// WithPointsExpiry reports the points of the receipts that are still valid
+// according to the expiry policy, and allows listing the receipts whose
+// points are about to expire.
+func WithPointsExpiry(expiryService port.ExpiryService) Option {
+	return func(rc *receiptController) {
+		rc.expiryService = expiryService
+	}
+}
+
+// WithMerchants assigns the receipts to the merchants their retailer names
+// are aliases of, so they can be listed by merchant.
+func WithMerchants(merchantService port.MerchantService) Option {
+	return func(rc *receiptController) {
+		rc.merchantService = merchantService
+	}
+}
+
+// WithProducts matches the items of the receipts to the products of the
+// catalogue, storing the ID of the product each item matched.
+func WithProducts(productService port.ProductService) Option {
+	return func(rc *receiptController) {
+		rc.productService = productService
+	}
+}
+
+// WithRedemptions rejects the amendments that would leave a receipt with fewer
+// points than its redemptions hold.
+func WithRedemptions(redemptionService port.RedemptionService) Option {
+	return func(rc *receiptController) {
+		rc.redemptionService = redemptionService
+	}
+}
+
+func newReceiptController(receiptService port.ReceiptService, receiptRepository port.ReceiptRepository, options ...Option) *receiptController {
+	rc := &receiptController{
+		receiptService:    receiptService,
+		receiptRepository: receiptRepository,
+		maxBatchSize:      defaultMaxBatchSize,
+		now:               time.Now,
+	}
+
+	for _, option := range options {
+		option(rc)
+	}
+
+	return rc
+}
+
 func (rc *receiptController) createReceipt(c *gin.Context) {
-	var receipt entity.Receipt
+	idempotencyKey := c.GetHeader(idempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		c.JSON(http.StatusBadRequest, gin.H{"The idempotency key is too long, the maximum length is": maxIdempotencyKeyLength})
+		return
+	}
+
+	accountID, ok := rc.submissionAccount(c)
+	if !ok {
+		return
+	}
 
-	if err := c.ShouldBindJSON(&receipt); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"The receipt is invalid": err.Error()})
+	body, ok := readReceiptBody(c)
+	if !ok {
+		return
+	}
+
+	result, err := rc.processReceipt(c, body, idempotencyKey, accountID)
+	if errors.Is(err, entity.ErrIdempotencyKeyReused) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"The idempotency key was used with a different receipt": idempotencyKey})
+		return
+	}
+	if errors.Is(err, entity.ErrIdempotencyKeyInProgress) {
+		c.JSON(http.StatusConflict, gin.H{"A submission with the same idempotency key is in progress": idempotencyKey})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error processing receipt": err.Error()})
+		return
+	}
+	if result.FieldErrors != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": result.FieldErrors})
 		return
 	}
 
-	receiptID := rc.receiptService.CreateReceiptID(c)
-	receiptByID[receiptID] = receipt
+	if result.Replayed {
+		c.Header("Idempotent-Replayed", "true")
+	}
 
-	c.JSON(http.StatusOK, gin.H{"id": receiptID})
+	c.JSON(http.StatusOK, gin.H{"id": result.ID})
+}
+
+// readReceiptBody reads the body of a request with a receipt, up to
+// maxReceiptSize bytes. If it can't be read it writes the error response and
+// returns false.
+func readReceiptBody(c *gin.Context) ([]byte, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxReceiptSize)
+
+	body, err := c.GetRawData()
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"The receipt exceeds the maximum size in bytes": maxReceiptSize})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error reading the receipt": err.Error()})
+		return nil, false
+	}
+
+	return body, true
+}
+
+// processResult is the outcome of processing a receipt: the ID of the receipt,
+// which was created before if the submission is Replayed, or the invalid fields.
+type processResult struct {
+	ID          string
+	Replayed    bool
+	FieldErrors []entity.FieldError
+}
+
+// processReceipt decodes, validates and stores a receipt, attached to the
+// account if any. Submissions already seen, by their idempotency key or the
+// hash of the receipt, return the ID of the receipt created by the first one.
+func (rc *receiptController) processReceipt(ctx context.Context, data []byte, idempotencyKey, accountID string) (processResult, error) {
+	receipt, fieldErrors := entity.DecodeReceipt(data)
+	if fieldErrors != nil {
+		return processResult{FieldErrors: fieldErrors}, nil
+	}
+
+	if err := rc.receiptService.ValidateReceipt(ctx, receipt); err != nil {
+		var validationErr *entity.ValidationError
+		if errors.As(err, &validationErr) {
+			return processResult{FieldErrors: validationErr.Errors}, nil
+		}
+
+		return processResult{}, err
+	}
+
+	merchantID, err := rc.resolveMerchant(ctx, receipt)
+	if err != nil {
+		return processResult{}, err
+	}
+
+	itemProductIDs, err := rc.matchProducts(ctx, receipt)
+	if err != nil {
+		return processResult{}, err
+	}
+
+	receiptID := rc.receiptService.CreateReceiptID(ctx)
+
+	recordKey := rc.idempotencyRecordKey(idempotencyKey, accountID, receipt)
+	if recordKey != "" {
+		now := rc.now()
+		requestHash := receipt.CanonicalHash()
+
+		existing, saved, err := rc.idempotencyRepository.SaveIdempotencyRecord(ctx, entity.IdempotencyRecord{
+			Key:         recordKey,
+			RequestHash: requestHash,
+			ReceiptID:   receiptID,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(rc.idempotencyRetention),
+		})
+		if err != nil {
+			return processResult{}, err
+		}
+
+		if !saved {
+			if existing.RequestHash != requestHash {
+				return processResult{}, entity.ErrIdempotencyKeyReused
+			}
+
+			// The receipt of the first submission may not be stored yet, or
+			// storing it may still fail.
+			if !existing.Completed {
+				return processResult{}, entity.ErrIdempotencyKeyInProgress
+			}
+
+			return processResult{ID: existing.ReceiptID, Replayed: true}, nil
+		}
+	}
+
+	record := entity.ReceiptRecord{
+		ID:          receiptID,
+		Receipt:     receipt,
+		SubmittedAt: rc.now().UTC(),
+		AccountID:   accountID,
+		MerchantID:  merchantID,
+
+		ItemProductIDs: itemProductIDs,
+	}
+
+	if err := rc.receiptRepository.SaveReceipt(ctx, record); err != nil {
+		// Release the idempotency record so the submission can be retried.
+		if recordKey != "" {
+			rc.idempotencyRepository.DeleteIdempotencyRecord(ctx, recordKey)
+		}
+
+		return processResult{}, err
+	}
+
+	if recordKey != "" {
+		// The receipt is stored, so retries get its ID even if the record
+		// can't be completed: they are rejected as in progress until it expires.
+		if err := rc.idempotencyRepository.CompleteIdempotencyRecord(ctx, recordKey); err != nil {
+			log.Printf("Error completing the idempotency record of receipt %s: %v", receiptID, err)
+		}
+	}
+
+	if rc.fraudService != nil {
+		// The receipt is already stored, so failing the submission would make
+		// the client retry it and flag the retry as a duplicate of it.
+		if _, err := rc.fraudService.ScreenReceipt(ctx, record); err != nil {
+			log.Printf("Error screening receipt %s: %v", receiptID, err)
+		}
+	}
+
+	if rc.eagerScoring {
+		// Failures are saved as the scoring state of the receipt, and it's
+		// scored again when its points are requested.
+		if _, err := rc.scoreReceipt(ctx, record, ""); err != nil {
+			log.Printf("Error scoring receipt %s: %v", receiptID, err)
+		}
+	}
+
+	return processResult{ID: receiptID}, nil
+}
+
+// idempotencyRecordKey returns the key identifying a submission, or an empty
+// key if submissions aren't deduplicated. The keys of the submissions to an
+// account are scoped to it, so the same receipt submitted to two accounts
+// creates a receipt for each of them.
+func (rc *receiptController) idempotencyRecordKey(idempotencyKey, accountID string, receipt entity.Receipt) string {
+	var key string
+
+	switch {
+	case rc.idempotencyRepository == nil:
+		return ""
+	case idempotencyKey != "":
+		key = idempotencyKeyPrefix + idempotencyKey
+	case rc.hashReceipts:
+		key = receiptHashKeyPrefix + receipt.CanonicalHash()
+	default:
+		return ""
+	}
+
+	if accountID != "" {
+		key = accountKeyPrefix + accountID + ":" + key
+	}
+
+	return key
+}
+
+// receiptResponse is a stored receipt along with how it was submitted and
+// scored. The points are only included once the receipt is scored.
+type receiptResponse struct {
+	ID          string         `json:"id"`
+	SubmittedAt *time.Time     `json:"submittedAt,omitempty"`
+	AccountID   string         `json:"accountId,omitempty"`
+	MerchantID  string         `json:"merchantId,omitempty"`
+	Receipt     entity.Receipt `json:"receipt"`
+
+	// ItemProductIDs are the products the items matched, as in the record.
+	ItemProductIDs []string `json:"itemProductIds,omitempty"`
+
+	ScoreStatus    string     `json:"scoreStatus"`
+	Points         *int64     `json:"points,omitempty"`
+	RuleSetVersion string     `json:"ruleSetVersion,omitempty"`
+	ScoredAt       *time.Time `json:"scoredAt,omitempty"`
+	ScoreError     string     `json:"scoreError,omitempty"`
+
+	// Screening is only set when the receipts are screened for fraud.
+	Screening *entity.FraudScreening `json:"screening,omitempty"`
+}
+
+func newReceiptResponse(record entity.ReceiptRecord, points entity.ReceiptPoints) receiptResponse {
+	response := receiptResponse{
+		ID:          record.ID,
+		AccountID:   record.AccountID,
+		MerchantID:  record.MerchantID,
+		Receipt:     record.Receipt,
+		ScoreStatus: points.Status,
+		ScoredAt:    points.ScoredAt,
+		ScoreError:  points.Error,
+
+		ItemProductIDs: record.ItemProductIDs,
+	}
+
+	if !record.SubmittedAt.IsZero() {
+		submittedAt := record.SubmittedAt
+		response.SubmittedAt = &submittedAt
+	}
+
+	if points.Scored() {
+		response.Points = &points.Points
+		response.RuleSetVersion = points.RuleSetVersion
+	}
+
+	return response
+}
+
+// getReceipt gets a stored receipt with its metadata, without scoring it.
+func (rc *receiptController) getReceipt(c *gin.Context) {
+	receiptID := c.Param("receipt_id")
+
+	record, ok := rc.findReceipt(c, receiptID)
+	if !ok {
+		return
+	}
+
+	points, err := rc.receiptRepository.GetReceiptPoints(c, receiptID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error getting receipt points": err.Error()})
+		return
+	}
+
+	response := newReceiptResponse(record, points)
+
+	if rc.fraudService != nil {
+		screening, ok, err := rc.fraudService.GetScreening(c, receiptID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"Error getting receipt screening": err.Error()})
+			return
+		}
+		if ok {
+			response.Screening = &screening
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 func (rc *receiptController) getReceiptPoints(c *gin.Context) {
 	receiptID := c.Param("receipt_id")
 
-	receipt, ok := receiptByID[receiptID]
+	record, ok := rc.findReceipt(c, receiptID)
 	if !ok {
-		c.JSON(http.StatusNotFound, gin.H{"Receipt not found for that id": receiptID})
+		return
+	}
+
+	if !rc.checkAwarded(c, receiptID) {
 		return
 	}
 
 	// If the points for the receipt ID are already calculated, return them.
-	if receiptPointsByID[receiptID] != 0 {
-		c.JSON(http.StatusOK, gin.H{"points": receiptPointsByID[receiptID]})
+	cachedPoints, err := rc.receiptRepository.GetReceiptPoints(c, receiptID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error getting receipt points": err.Error()})
+		return
+	}
+	if cachedPoints.Scored() {
+		rc.writePoints(c, record, cachedPoints)
 		return
 	}
 
-	points, err := rc.receiptService.GetReceiptPoints(c, receipt)
+	// Pending receipts are scored lazily, and failed ones are scored again.
+	points, err := rc.scoreReceipt(c, record, "")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"Error getting receipt points": err.Error()})
 		return
 	}
 
-	// Set the points for the receipt ID to avoid calculating it again.
-	receiptPointsByID[receiptID] = points
+	rc.writePoints(c, record, points)
+}
+
+// rescoreReceiptPoints calculates again the points of a receipt with the rule
+// set version given in the query, or the active one if none is given, and
+// replaces the stored points with them. Its items keep the products they were
+// matched to unless rematchProducts=true is given in the query.
+func (rc *receiptController) rescoreReceiptPoints(c *gin.Context) {
+	receiptID := c.Param("receipt_id")
+	ruleSetVersion := c.Query("ruleSetVersion")
+
+	var rematchProducts bool
+	if value := c.Query("rematchProducts"); value != "" {
+		var err error
+		if rematchProducts, err = strconv.ParseBool(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+				Field:   "rematchProducts",
+				Code:    entity.FieldErrorInvalidFormat,
+				Message: "rematchProducts must be true or false",
+			}}})
+			return
+		}
+	}
+
+	record, ok := rc.findReceipt(c, receiptID)
+	if !ok {
+		return
+	}
+
+	if !rc.checkAwarded(c, receiptID) {
+		return
+	}
+
+	if rematchProducts {
+		itemProductIDs, err := rc.matchProducts(c, record.Receipt)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"Error matching products": err.Error()})
+			return
+		}
+
+		record.ItemProductIDs = itemProductIDs
+		if err := rc.receiptRepository.SaveReceipt(c, record); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"Error saving receipt": err.Error()})
+			return
+		}
+	}
+
+	points, err := rc.scoreReceipt(c, record, ruleSetVersion)
+	if errors.Is(err, entity.ErrRuleSetNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"Rule set not found for that version": ruleSetVersion})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error getting receipt points": err.Error()})
+		return
+	}
+
+	rc.writePoints(c, record, points)
+}
+
+// scoreReceipt calculates the points of a receipt with the rule set of the
+// given version, or the active one if empty, and saves them pinned to the
+// rule set used, so later rule changes don't affect them, and credits them to
+// the account of the receipt. If they can't be calculated the failed state is
+// saved along with the error, unless the rule set doesn't exist.
+func (rc *receiptController) scoreReceipt(ctx context.Context, record entity.ReceiptRecord, ruleSetVersion string) (entity.ReceiptPoints, error) {
+	points, err := rc.calculatePoints(ctx, record, ruleSetVersion)
+	if errors.Is(err, entity.ErrRuleSetNotFound) {
+		return entity.ReceiptPoints{}, err
+	}
+
+	if saveErr := rc.receiptRepository.SaveReceiptPoints(ctx, record.ID, points); saveErr != nil {
+		return entity.ReceiptPoints{}, saveErr
+	}
+
+	rc.settleReceiptPoints(ctx, record, points)
+
+	return points, err
+}
+
+// calculatePoints calculates the points of a receipt with the rule set of the
+// given version, or the active one if empty, without saving them. If they
+// can't be calculated it returns the failed state along with the error,
+// unless the rule set doesn't exist.
+func (rc *receiptController) calculatePoints(ctx context.Context, record entity.ReceiptRecord, ruleSetVersion string) (entity.ReceiptPoints, error) {
+	breakdown, err := rc.receiptService.GetRecordPointsBreakdown(ctx, record, ruleSetVersion)
+	if errors.Is(err, entity.ErrRuleSetNotFound) {
+		return entity.ReceiptPoints{}, err
+	}
+
+	if err != nil {
+		return entity.ReceiptPoints{
+			Status: entity.ScoreStatusFailed,
+			Error:  err.Error(),
+		}, err
+	}
+
+	scoredAt := rc.now().UTC()
+
+	return entity.ReceiptPoints{
+		Status:         entity.ScoreStatusScored,
+		Points:         breakdown.Points,
+		RuleSetVersion: breakdown.RuleSetVersion,
+		ScoredAt:       &scoredAt,
+		Breakdown:      &breakdown,
+	}, nil
+}
+
+// getReceiptPointsBreakdown explains the points of a receipt as they were
+// calculated when scored, or with the active rule set if they weren't
+// calculated yet. Receipts scored before their breakdown was kept are
+// explained again with the rule set they were calculated with.
+func (rc *receiptController) getReceiptPointsBreakdown(c *gin.Context) {
+	receiptID := c.Param("receipt_id")
+
+	record, ok := rc.findReceipt(c, receiptID)
+	if !ok {
+		return
+	}
+
+	if !rc.checkAwarded(c, receiptID) {
+		return
+	}
+
+	cachedPoints, err := rc.receiptRepository.GetReceiptPoints(c, receiptID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error getting receipt points": err.Error()})
+		return
+	}
+
+	if cachedPoints.Scored() && cachedPoints.Breakdown != nil {
+		c.JSON(http.StatusOK, cachedPoints.Breakdown)
+		return
+	}
+
+	var ruleSetVersion string
+	if cachedPoints.Scored() {
+		ruleSetVersion = cachedPoints.RuleSetVersion
+	}
+
+	breakdown, err := rc.receiptService.GetRecordPointsBreakdown(c, record, ruleSetVersion)
+	if errors.Is(err, entity.ErrRuleSetNotFound) {
+		c.JSON(http.StatusConflict, gin.H{"Rule set of the receipt points is no longer available": cachedPoints.RuleSetVersion})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error getting receipt points": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, breakdown)
+}
+
+// findReceipt gets a stored receipt by its ID. If the receipt can't be found
+// it writes the error response and returns false.
+func (rc *receiptController) findReceipt(c *gin.Context, receiptID string) (entity.ReceiptRecord, bool) {
+	record, err := rc.receiptRepository.GetReceiptByID(c, receiptID)
+	if errors.Is(err, entity.ErrReceiptNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"Receipt not found for that id": receiptID})
+		return entity.ReceiptRecord{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error getting receipt": err.Error()})
+		return entity.ReceiptRecord{}, false
+	}
+
+	return record, true
+}
+
+// unawardedPoints is the response of the points of a receipt that isn't
+// awarded points due to its fraud screening.
+type unawardedPoints struct {
+	Points    int64                 `json:"points"`
+	Screening entity.FraudScreening `json:"screening"`
+}
+
+// checkAwarded checks whether a receipt can be awarded points according to
+// its fraud screening. If it can't, it writes the response with zero points
+// and the reason, and returns false.
+func (rc *receiptController) checkAwarded(c *gin.Context, receiptID string) bool {
+	if rc.fraudService == nil {
+		return true
+	}
+
+	screening, ok, err := rc.fraudService.GetScreening(c, receiptID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error getting receipt screening": err.Error()})
+		return false
+	}
+
+	// Receipts stored before the screening was enabled are awarded points.
+	if !ok || screening.Awarded() {
+		return true
+	}
+
+	c.JSON(http.StatusOK, unawardedPoints{Screening: screening})
+	return false
+}
+
+type reviewRequest struct {
+	Approved *bool  `json:"approved" binding:"required"`
+	Reason   string `json:"reason"`
+}
+
+// reviewReceipt approves or rejects a receipt flagged by the fraud screening.
+func (rc *receiptController) reviewReceipt(c *gin.Context) {
+	receiptID := c.Param("receipt_id")
+
+	var request reviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"The review is invalid": err.Error()})
+		return
+	}
+
+	record, ok := rc.findReceipt(c, receiptID)
+	if !ok {
+		return
+	}
+
+	screening, err := rc.fraudService.ReviewReceipt(c, receiptID, *request.Approved, request.Reason)
+	if errors.Is(err, entity.ErrScreeningNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"Receipt screening not found for that id": receiptID})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error reviewing receipt": err.Error()})
+		return
+	}
+
+	// The points already credited to the account of the receipt follow the
+	// review, the ones not calculated yet are credited when they are.
+	if rc.accountService != nil && record.AccountID != "" {
+		points, err := rc.receiptRepository.GetReceiptPoints(c, receiptID)
+		if err != nil {
+			log.Printf("Error getting points of reviewed receipt %s: %v", receiptID, err)
+		} else {
+			rc.settleReceiptPoints(c, record, points)
+		}
+	}
 
-	c.JSON(http.StatusOK, gin.H{"points": points})
+	c.JSON(http.StatusOK, screening)
 }
@@ -3,10 +3,14 @@ package receipt
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
 	"github.com/darcops/receipt-proccessor-challenge/mocks"
@@ -16,6 +20,7 @@ import (
 
 func TestCreateReceipt(t *testing.T) {
 	mockService := &mocks.ReceiptService{}
+	mockRepository := &mocks.ReceiptRepository{}
 
 	testCases := []struct {
 		name string
@@ -23,9 +28,15 @@ func TestCreateReceipt(t *testing.T) {
 		service             *mocks.ReceiptService
 		wantServiceResponse string
 
-		request entity.Receipt
+		wantValidationErr error
+
+		repository *mocks.ReceiptRepository
+
+		request    entity.Receipt
+		rawRequest string
 
 		wantStatusCode int
+		wantErrorCodes []string
 	}{
 		{
 			name: "should create a receipt",
@@ -33,6 +44,8 @@ func TestCreateReceipt(t *testing.T) {
 			service:             mockService,
 			wantServiceResponse: "1234567890",
 
+			repository: mockRepository,
+
 			request: entity.Receipt{
 				Retailer:     "$Walmart/   ",
 				PurchaseDate: "2020-01-01",
@@ -40,45 +53,150 @@ func TestCreateReceipt(t *testing.T) {
 				Items: []entity.Item{
 					{
 						ShortDescription: "Item 1",
-						Price:            "1.00",
+						Price:            entity.MustParseMoney("1.00"),
 					},
 					{
 						ShortDescription: "Item 1",
-						Price:            "1.00",
+						Price:            entity.MustParseMoney("1.00"),
 					},
 				},
-				Total: "2.00",
+				Total: entity.MustParseMoney("2.00"),
 			},
 
 			wantStatusCode: http.StatusOK,
 		},
+		{
+			name: "should fail due invalid receipt",
+
+			service: mockService,
+			wantValidationErr: &entity.ValidationError{Errors: []entity.FieldError{
+				{Field: "purchaseDate", Code: entity.FieldErrorInvalidDate, Message: "purchaseDate 2022-02-30 is not a valid date"},
+			}},
+
+			repository: mockRepository,
+
+			request: entity.Receipt{
+				Retailer:     "Target",
+				PurchaseDate: "2022-02-30",
+				PurchaseTime: "15:00",
+				Items: []entity.Item{
+					{
+						ShortDescription: "Item 1",
+						Price:            entity.MustParseMoney("1.00"),
+					},
+				},
+				Total: entity.MustParseMoney("1.00"),
+			},
+
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCodes: []string{entity.FieldErrorInvalidDate},
+		},
+		{
+			name: "should fail due invalid amounts of money",
+
+			service: mockService,
+
+			repository: mockRepository,
+
+			rawRequest: `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01",
+				"items": [{"shortDescription": "Item 1", "price": 1.25, "unitPrice": "1.25", "discount": "0"}], "total": "1.2"}`,
+
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCodes: []string{entity.FieldErrorInvalidFormat, entity.FieldErrorInvalidFormat, entity.FieldErrorInvalidFormat},
+		},
+		{
+			name: "should fail due missing amounts of money",
+
+			service: mockService,
+
+			repository: mockRepository,
+
+			rawRequest: `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01",
+				"items": [{"shortDescription": "Mountain Dew 12PK"}]}`,
+
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCodes: []string{entity.FieldErrorRequired, entity.FieldErrorRequired},
+		},
+		{
+			name: "should fail due quantity given as zero",
+
+			service: mockService,
+
+			repository: mockRepository,
+
+			rawRequest: `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01",
+				"items": [{"shortDescription": "Mountain Dew 12PK", "price": "6.49", "quantity": 0}], "total": "6.49"}`,
+
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCodes: []string{entity.FieldErrorInvalidValue},
+		},
+		{
+			name: "should fail due receipt larger than the maximum size in bytes",
+
+			service: mockService,
+
+			repository: mockRepository,
+
+			rawRequest: `{"retailer": "Target"}` + strings.Repeat(" ", maxReceiptSize),
+
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "should fail due malformed JSON",
+
+			service: mockService,
+
+			repository: mockRepository,
+
+			rawRequest: `{"retailer": "Target",`,
+
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCodes: []string{entity.FieldErrorInvalidJSON},
+		},
 	}
 
 	for _, tc := range testCases {
-		// Set the memory storage for receipts and points.
-		receiptByID = map[string]entity.Receipt{}
-		receiptPointsByID = make(map[string]int64)
-
 		// Create a new router for tests.
 		router := gin.Default()
 		gin.SetMode(gin.TestMode)
 
-		controller := newReceiptController(tc.service)
+		controller := newReceiptController(tc.service, tc.repository)
 
-		// Mock the desired response from the service.
-		tc.service.On(
-			"CreateReceiptID",
-			mock.Anything, /* context.Context */
-		).Return(tc.wantServiceResponse).Once()
+		if tc.rawRequest == "" {
+			tc.service.On(
+				"ValidateReceipt",
+				mock.Anything, /* context.Context */
+				tc.request,
+			).Return(tc.wantValidationErr).Once()
+		}
+
+		if tc.wantStatusCode == http.StatusOK {
+			// Mock the desired response from the service.
+			tc.service.On(
+				"CreateReceiptID",
+				mock.Anything, /* context.Context */
+			).Return(tc.wantServiceResponse).Once()
+
+			// Mock the storage of the receipt.
+			tc.repository.On(
+				"SaveReceipt",
+				mock.Anything, /* context.Context */
+				mock.Anything, /* entity.ReceiptRecord */
+			).Return(nil).Once()
+		}
 
 		router.POST("/process", controller.createReceipt)
 
 		server := httptest.NewServer(router)
 
 		t.Run(tc.name, func(t *testing.T) {
-			requestBody, err := json.Marshal(&tc.request)
-			if err != nil {
-				t.Errorf("CreateReceipt() = Marshaling error %v", err)
+			requestBody := []byte(tc.rawRequest)
+			if tc.rawRequest == "" {
+				var err error
+				requestBody, err = json.Marshal(&tc.request)
+				if err != nil {
+					t.Errorf("CreateReceipt() = Marshaling error %v", err)
+				}
 			}
 
 			response, err := http.Post(
@@ -94,79 +212,1265 @@ func TestCreateReceipt(t *testing.T) {
 			if response.StatusCode != tc.wantStatusCode {
 				t.Errorf("CreateReceipt() = %v, want %v", response.StatusCode, tc.wantStatusCode)
 			}
+
+			if tc.wantErrorCodes == nil {
+				return
+			}
+
+			got := struct {
+				Errors []entity.FieldError `json:"errors"`
+			}{}
+			if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+				t.Errorf("CreateReceipt() = Unmarshaling response error %v", err)
+			}
+
+			gotCodes := make([]string, 0, len(got.Errors))
+			for _, fieldError := range got.Errors {
+				gotCodes = append(gotCodes, fieldError.Code)
+			}
+
+			if !reflect.DeepEqual(gotCodes, tc.wantErrorCodes) {
+				t.Errorf("CreateReceipt() = %v, want %v", gotCodes, tc.wantErrorCodes)
+			}
 		})
 	}
 }
 
-func TestGetReceiptPoints(t *testing.T) {
-	mockService := &mocks.ReceiptService{}
+func TestCreateReceiptWithIdempotencyKey(t *testing.T) {
+	submittedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	receipt := entity.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-01",
+		PurchaseTime: "13:01",
+		Items: []entity.Item{
+			{
+				ShortDescription: "Mountain Dew 12PK",
+				Price:            entity.MustParseMoney("6.49"),
+			},
+		},
+		Total: entity.MustParseMoney("6.49"),
+	}
 
 	testCases := []struct {
 		name string
 
-		service             *mocks.ReceiptService
-		wantServiceResponse int64
+		idempotencyKey string
+		hashReceipts   bool
 
-		receiptID string
+		storedRecord *entity.IdempotencyRecord
 
+		wantRecordKey  string
 		wantStatusCode int
+		wantID         string
+		wantReplayed   bool
 	}{
 		{
-			name: "should return points for receipt",
+			name: "should create a receipt for a new idempotency key",
 
-			service:             mockService,
-			wantServiceResponse: 10,
+			idempotencyKey: "retry-1",
+
+			wantRecordKey:  "key:retry-1",
+			wantStatusCode: http.StatusOK,
+			wantID:         "1234567890",
+		},
+		{
+			name: "should return the receipt created with the same idempotency key",
+
+			idempotencyKey: "retry-1",
+
+			storedRecord: &entity.IdempotencyRecord{
+				Key:         "key:retry-1",
+				RequestHash: receipt.CanonicalHash(),
+				ReceiptID:   "original",
+				Completed:   true,
+			},
+
+			wantRecordKey:  "key:retry-1",
+			wantStatusCode: http.StatusOK,
+			wantID:         "original",
+			wantReplayed:   true,
+		},
+		{
+			name: "should fail due submission with the same idempotency key in progress",
+
+			idempotencyKey: "retry-1",
+
+			storedRecord: &entity.IdempotencyRecord{
+				Key:         "key:retry-1",
+				RequestHash: receipt.CanonicalHash(),
+				ReceiptID:   "original",
+			},
+
+			wantRecordKey:  "key:retry-1",
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name: "should fail due idempotency key used with a different receipt",
+
+			idempotencyKey: "retry-1",
+
+			storedRecord: &entity.IdempotencyRecord{
+				Key:         "key:retry-1",
+				RequestHash: "another receipt",
+				ReceiptID:   "original",
+			},
+
+			wantRecordKey:  "key:retry-1",
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "should return the identical receipt when hashing receipts",
+
+			hashReceipts: true,
+
+			storedRecord: &entity.IdempotencyRecord{
+				Key:         "hash:" + receipt.CanonicalHash(),
+				RequestHash: receipt.CanonicalHash(),
+				ReceiptID:   "original",
+				Completed:   true,
+			},
 
+			wantRecordKey:  "hash:" + receipt.CanonicalHash(),
 			wantStatusCode: http.StatusOK,
+			wantID:         "original",
+			wantReplayed:   true,
 		},
 	}
 
 	for _, tc := range testCases {
-		mockReceiptID := "1234567890"
-		// Set the memory storage for receipts and points.
-		receiptByID = map[string]entity.Receipt{
-			mockReceiptID: {},
-		}
-		receiptPointsByID = make(map[string]int64)
+		mockService := &mocks.ReceiptService{}
+		mockRepository := &mocks.ReceiptRepository{}
+		mockIdempotencyRepository := &mocks.IdempotencyRepository{}
 
 		// Create a new router for tests.
 		router := gin.Default()
 		gin.SetMode(gin.TestMode)
 
-		controller := newReceiptController(tc.service)
+		controller := newReceiptController(
+			mockService,
+			mockRepository,
+			WithIdempotency(mockIdempotencyRepository, time.Hour, tc.hashReceipts),
+		)
+		controller.now = func() time.Time { return submittedAt }
 
-		// Mock the desired response from the service.
-		tc.service.On(
-			"GetReceiptPoints",
+		mockService.On(
+			"ValidateReceipt",
 			mock.Anything, /* context.Context */
-			mock.Anything, /* entity.Receipt */
-		).Return(tc.wantServiceResponse, nil).Once()
+			receipt,
+		).Return(nil).Once()
 
-		router.GET("/:receipt_id/points", controller.getReceiptPoints)
+		mockService.On(
+			"CreateReceiptID",
+			mock.Anything, /* context.Context */
+		).Return("1234567890").Once()
+
+		matchRecordKey := mock.MatchedBy(func(record entity.IdempotencyRecord) bool {
+			return record.Key == tc.wantRecordKey && record.ReceiptID == "1234567890"
+		})
+
+		if tc.storedRecord != nil {
+			mockIdempotencyRepository.On(
+				"SaveIdempotencyRecord",
+				mock.Anything, /* context.Context */
+				matchRecordKey,
+			).Return(*tc.storedRecord, false, nil).Once()
+		} else {
+			mockIdempotencyRepository.On(
+				"SaveIdempotencyRecord",
+				mock.Anything, /* context.Context */
+				matchRecordKey,
+			).Return(entity.IdempotencyRecord{}, true, nil).Once()
+
+			mockRepository.On(
+				"SaveReceipt",
+				mock.Anything, /* context.Context */
+				entity.ReceiptRecord{ID: "1234567890", Receipt: receipt, SubmittedAt: submittedAt},
+			).Return(nil).Once()
+
+			mockIdempotencyRepository.On(
+				"CompleteIdempotencyRecord",
+				mock.Anything, /* context.Context */
+				tc.wantRecordKey,
+			).Return(nil).Once()
+		}
+
+		router.POST("/process", controller.createReceipt)
 
 		server := httptest.NewServer(router)
 
 		t.Run(tc.name, func(t *testing.T) {
-			response, err := http.Get(
-				fmt.Sprintf("%s/%s/points", server.URL, mockReceiptID),
-			)
+			defer server.Close()
+
+			requestBody, err := json.Marshal(&receipt)
+			if err != nil {
+				t.Fatalf("CreateReceipt() = Marshaling error %v", err)
+			}
+
+			request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/process", server.URL), bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Fatalf("CreateReceipt() = error %v", err)
+			}
+			request.Header.Set("Content-Type", "application/json")
+			if tc.idempotencyKey != "" {
+				request.Header.Set("Idempotency-Key", tc.idempotencyKey)
+			}
+
+			response, err := http.DefaultClient.Do(request)
 			if err != nil {
-				t.Errorf("GetReceiptPoints() = error %v", err)
+				t.Fatalf("CreateReceipt() = error %v", err)
 			}
 			defer response.Body.Close()
 
 			if response.StatusCode != tc.wantStatusCode {
-				t.Errorf("GetReceiptPoints() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+				t.Errorf("CreateReceipt() = %v, want %v", response.StatusCode, tc.wantStatusCode)
 			}
 
-			got := map[string]int64{}
-			err = json.NewDecoder(response.Body).Decode(&got)
+			if tc.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			got := struct {
+				ID string `json:"id"`
+			}{}
+			if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+				t.Errorf("CreateReceipt() = Unmarshaling response error %v", err)
+			}
+
+			if got.ID != tc.wantID {
+				t.Errorf("CreateReceipt() = %v, want %v", got.ID, tc.wantID)
+			}
+
+			if replayed := response.Header.Get("Idempotent-Replayed") == "true"; replayed != tc.wantReplayed {
+				t.Errorf("CreateReceipt() replayed = %v, want %v", replayed, tc.wantReplayed)
+			}
+
+			mockRepository.AssertExpectations(t)
+			mockIdempotencyRepository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIdempotencyRecordKey(t *testing.T) {
+	receipt := entity.Receipt{Retailer: "Target", Total: entity.MustParseMoney("6.49")}
+
+	testCases := []struct {
+		name string
+
+		idempotencyKey string
+		accountID      string
+		hashReceipts   bool
+
+		want string
+	}{
+		{
+			name: "should use the idempotency key",
+
+			idempotencyKey: "retry-1",
+
+			want: "key:retry-1",
+		},
+		{
+			name: "should scope the idempotency key to the account",
+
+			idempotencyKey: "retry-1",
+			accountID:      "1",
+
+			want: "account:1:key:retry-1",
+		},
+		{
+			name: "should scope the hash of the receipt to the account",
+
+			accountID:    "1",
+			hashReceipts: true,
+
+			want: "account:1:hash:" + receipt.CanonicalHash(),
+		},
+		{
+			name: "should not identify submissions without key when not hashing receipts",
+
+			accountID: "1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			controller := newReceiptController(nil, nil, WithIdempotency(&mocks.IdempotencyRepository{}, time.Hour, tc.hashReceipts))
+
+			got := controller.idempotencyRecordKey(tc.idempotencyKey, tc.accountID, receipt)
+
+			if got != tc.want {
+				t.Errorf("idempotencyRecordKey() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCreateReceiptWithEagerScoring(t *testing.T) {
+	scoredAt := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	scoringErr := errors.New("rule set has an invalid rule")
+
+	receipt := entity.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-01",
+		PurchaseTime: "13:01",
+		Items: []entity.Item{
+			{
+				ShortDescription: "Mountain Dew 12PK",
+				Price:            entity.MustParseMoney("6.49"),
+			},
+		},
+		Total: entity.MustParseMoney("6.49"),
+	}
+
+	testCases := []struct {
+		name string
+
+		wantServiceResponse entity.PointsBreakdown
+		wantServiceErr      error
+
+		wantSavedPoints entity.ReceiptPoints
+	}{
+		{
+			name: "should score the receipt when submitted",
+
+			wantServiceResponse: entity.PointsBreakdown{Points: 0, RuleSetVersion: "1"},
+
+			wantSavedPoints: entity.ReceiptPoints{
+				Status:         entity.ScoreStatusScored,
+				Points:         0,
+				RuleSetVersion: "1",
+				ScoredAt:       &scoredAt,
+				Breakdown:      &entity.PointsBreakdown{Points: 0, RuleSetVersion: "1"},
+			},
+		},
+		{
+			name: "should store the receipt even if scoring fails",
+
+			wantServiceErr: scoringErr,
+
+			wantSavedPoints: entity.ReceiptPoints{Status: entity.ScoreStatusFailed, Error: scoringErr.Error()},
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.ReceiptService{}
+		mockRepository := &mocks.ReceiptRepository{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		controller := newReceiptController(mockService, mockRepository, WithEagerScoring())
+		controller.now = func() time.Time { return scoredAt }
+
+		mockService.On(
+			"ValidateReceipt",
+			mock.Anything, /* context.Context */
+			receipt,
+		).Return(nil).Once()
+
+		mockService.On(
+			"CreateReceiptID",
+			mock.Anything, /* context.Context */
+		).Return("1234567890").Once()
+
+		mockRepository.On(
+			"SaveReceipt",
+			mock.Anything, /* context.Context */
+			entity.ReceiptRecord{ID: "1234567890", Receipt: receipt, SubmittedAt: scoredAt},
+		).Return(nil).Once()
+
+		mockService.On(
+			"GetRecordPointsBreakdown",
+			mock.Anything, /* context.Context */
+			entity.ReceiptRecord{ID: "1234567890", Receipt: receipt, SubmittedAt: scoredAt},
+			"",
+		).Return(tc.wantServiceResponse, tc.wantServiceErr).Once()
+
+		mockRepository.On(
+			"SaveReceiptPoints",
+			mock.Anything, /* context.Context */
+			"1234567890",
+			tc.wantSavedPoints,
+		).Return(nil).Once()
+
+		router.POST("/process", controller.createReceipt)
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			requestBody, err := json.Marshal(&receipt)
 			if err != nil {
-				t.Errorf("GetReceiptPoints() = Unmarshaling response error %v", err)
+				t.Fatalf("CreateReceipt() = Marshaling error %v", err)
+			}
+
+			response, err := http.Post(fmt.Sprintf("%s/process", server.URL), "application/json", bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Fatalf("CreateReceipt() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != http.StatusOK {
+				t.Errorf("CreateReceipt() = %v, want %v", response.StatusCode, http.StatusOK)
 			}
 
-			if got["points"] != tc.wantServiceResponse {
-				t.Errorf("GetReceiptPoints() = %v, want %v", got["points"], tc.wantServiceResponse)
+			mockService.AssertExpectations(t)
+			mockRepository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetReceiptPoints(t *testing.T) {
+	scoredAt := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	scoringErr := errors.New("rule set has an invalid rule")
+
+	testCases := []struct {
+		name string
+
+		storedPoints entity.ReceiptPoints
+
+		wantServiceResponse entity.PointsBreakdown
+		wantServiceErr      error
+		wantSavedPoints     *entity.ReceiptPoints
+
+		wantStatusCode int
+		wantPoints     entity.ReceiptPoints
+	}{
+		{
+			name: "should score a pending receipt",
+
+			storedPoints: entity.ReceiptPoints{Status: entity.ScoreStatusPending},
+
+			wantServiceResponse: entity.PointsBreakdown{Points: 10, RuleSetVersion: "1"},
+			wantSavedPoints: &entity.ReceiptPoints{
+				Status:         entity.ScoreStatusScored,
+				Points:         10,
+				RuleSetVersion: "1",
+				ScoredAt:       &scoredAt,
+				Breakdown:      &entity.PointsBreakdown{Points: 10, RuleSetVersion: "1"},
+			},
+
+			wantStatusCode: http.StatusOK,
+			wantPoints:     entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 10, RuleSetVersion: "1", ScoredAt: &scoredAt},
+		},
+		{
+			name: "should return scored zero points pinned to their rule set",
+
+			storedPoints: entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 0, RuleSetVersion: "1", ScoredAt: &scoredAt},
+
+			wantStatusCode: http.StatusOK,
+			wantPoints:     entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 0, RuleSetVersion: "1", ScoredAt: &scoredAt},
+		},
+		{
+			name: "should score again a receipt whose scoring failed",
+
+			storedPoints: entity.ReceiptPoints{Status: entity.ScoreStatusFailed, Error: scoringErr.Error()},
+
+			wantServiceResponse: entity.PointsBreakdown{Points: 25, RuleSetVersion: "2"},
+			wantSavedPoints: &entity.ReceiptPoints{
+				Status:         entity.ScoreStatusScored,
+				Points:         25,
+				RuleSetVersion: "2",
+				ScoredAt:       &scoredAt,
+				Breakdown:      &entity.PointsBreakdown{Points: 25, RuleSetVersion: "2"},
+			},
+
+			wantStatusCode: http.StatusOK,
+			wantPoints:     entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 25, RuleSetVersion: "2", ScoredAt: &scoredAt},
+		},
+		{
+			name: "should save the failed scoring",
+
+			storedPoints: entity.ReceiptPoints{Status: entity.ScoreStatusPending},
+
+			wantServiceErr:  scoringErr,
+			wantSavedPoints: &entity.ReceiptPoints{Status: entity.ScoreStatusFailed, Error: scoringErr.Error()},
+
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockReceiptID := "1234567890"
+
+		mockService := &mocks.ReceiptService{}
+		mockRepository := &mocks.ReceiptRepository{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		controller := newReceiptController(mockService, mockRepository)
+		controller.now = func() time.Time { return scoredAt }
+
+		mockRepository.On(
+			"GetReceiptByID",
+			mock.Anything, /* context.Context */
+			mockReceiptID,
+		).Return(entity.ReceiptRecord{ID: mockReceiptID}, nil).Once()
+
+		mockRepository.On(
+			"GetReceiptPoints",
+			mock.Anything, /* context.Context */
+			mockReceiptID,
+		).Return(tc.storedPoints, nil).Once()
+
+		if tc.wantSavedPoints != nil {
+			// Mock the desired response from the service.
+			mockService.On(
+				"GetRecordPointsBreakdown",
+				mock.Anything, /* context.Context */
+				mock.Anything, /* entity.ReceiptRecord */
+				"",
+			).Return(tc.wantServiceResponse, tc.wantServiceErr).Once()
+
+			mockRepository.On(
+				"SaveReceiptPoints",
+				mock.Anything, /* context.Context */
+				mockReceiptID,
+				*tc.wantSavedPoints,
+			).Return(nil).Once()
+		}
+
+		router.GET("/:receipt_id/points", controller.getReceiptPoints)
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Get(
+				fmt.Sprintf("%s/%s/points", server.URL, mockReceiptID),
+			)
+			if err != nil {
+				t.Fatalf("GetReceiptPoints() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("GetReceiptPoints() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockService.AssertExpectations(t)
+			mockRepository.AssertExpectations(t)
+
+			if tc.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			got := entity.ReceiptPoints{}
+			err = json.NewDecoder(response.Body).Decode(&got)
+			if err != nil {
+				t.Errorf("GetReceiptPoints() = Unmarshaling response error %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tc.wantPoints) {
+				t.Errorf("GetReceiptPoints() = %v, want %v", got, tc.wantPoints)
+			}
+		})
+	}
+}
+
+func TestGetReceipt(t *testing.T) {
+	submittedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	scoredAt := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	zeroPoints := int64(0)
+
+	storedRecord := entity.ReceiptRecord{
+		ID: "1234567890",
+		Receipt: entity.Receipt{
+			Retailer:     "Target",
+			PurchaseDate: "2022-01-01",
+			PurchaseTime: "13:01",
+			Items: []entity.Item{
+				{
+					ShortDescription: "Mountain Dew 12PK",
+					Price:            entity.MustParseMoney("6.49"),
+				},
+			},
+			Total: entity.MustParseMoney("6.49"),
+		},
+		SubmittedAt: submittedAt,
+	}
+
+	testCases := []struct {
+		name string
+
+		receiptErr   error
+		storedPoints entity.ReceiptPoints
+		screening    *entity.FraudScreening
+
+		wantStatusCode int
+		want           receiptResponse
+	}{
+		{
+			name: "should return a pending receipt without points nor scoring it",
+
+			storedPoints: entity.ReceiptPoints{Status: entity.ScoreStatusPending},
+
+			wantStatusCode: http.StatusOK,
+			want: receiptResponse{
+				ID:          storedRecord.ID,
+				SubmittedAt: &submittedAt,
+				Receipt:     storedRecord.Receipt,
+				ScoreStatus: entity.ScoreStatusPending,
+			},
+		},
+		{
+			name: "should return a scored receipt with its points and screening",
+
+			storedPoints: entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 0, RuleSetVersion: "1", ScoredAt: &scoredAt},
+			screening:    &entity.FraudScreening{Status: entity.ScreeningStatusClear},
+
+			wantStatusCode: http.StatusOK,
+			want: receiptResponse{
+				ID:             storedRecord.ID,
+				SubmittedAt:    &submittedAt,
+				Receipt:        storedRecord.Receipt,
+				ScoreStatus:    entity.ScoreStatusScored,
+				Points:         &zeroPoints,
+				RuleSetVersion: "1",
+				ScoredAt:       &scoredAt,
+				Screening:      &entity.FraudScreening{Status: entity.ScreeningStatusClear},
+			},
+		},
+		{
+			name: "should fail due unknown receipt",
+
+			receiptErr: entity.ErrReceiptNotFound,
+
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockReceiptID := "1234567890"
+
+		mockService := &mocks.ReceiptService{}
+		mockRepository := &mocks.ReceiptRepository{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		var options []Option
+		if tc.screening != nil {
+			mockFraudService := &mocks.FraudService{}
+			mockFraudService.On(
+				"GetScreening",
+				mock.Anything, /* context.Context */
+				mockReceiptID,
+			).Return(*tc.screening, true, nil).Once()
+
+			options = append(options, WithFraudScreening(mockFraudService))
+		}
+
+		controller := newReceiptController(mockService, mockRepository, options...)
+
+		if tc.receiptErr != nil {
+			mockRepository.On(
+				"GetReceiptByID",
+				mock.Anything, /* context.Context */
+				mockReceiptID,
+			).Return(entity.ReceiptRecord{}, tc.receiptErr).Once()
+		} else {
+			mockRepository.On(
+				"GetReceiptByID",
+				mock.Anything, /* context.Context */
+				mockReceiptID,
+			).Return(storedRecord, nil).Once()
+
+			mockRepository.On(
+				"GetReceiptPoints",
+				mock.Anything, /* context.Context */
+				mockReceiptID,
+			).Return(tc.storedPoints, nil).Once()
+		}
+
+		router.GET("/:receipt_id", controller.getReceipt)
+		router.GET("/:receipt_id/points", controller.getReceiptPoints)
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Get(fmt.Sprintf("%s/%s", server.URL, mockReceiptID))
+			if err != nil {
+				t.Fatalf("GetReceipt() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("GetReceipt() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			// Getting the receipt must not score it.
+			mockService.AssertNotCalled(t, "GetRecordPointsBreakdown", mock.Anything, mock.Anything, mock.Anything)
+
+			if tc.wantStatusCode != http.StatusOK {
+				got := map[string]string{}
+				if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+					t.Errorf("GetReceipt() = Unmarshaling response error %v", err)
+				}
+
+				// The not found error has the same shape as the points one.
+				if got["Receipt not found for that id"] != mockReceiptID {
+					t.Errorf("GetReceipt() = %v, want receipt not found for %v", got, mockReceiptID)
+				}
+				return
+			}
+
+			got := receiptResponse{}
+			if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+				t.Errorf("GetReceipt() = Unmarshaling response error %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("GetReceipt() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRescoreReceiptPoints(t *testing.T) {
+	scoredAt := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	record := entity.ReceiptRecord{
+		ID: "1234567890",
+		Receipt: entity.Receipt{
+			Items: []entity.Item{
+				{ShortDescription: "Gatorade 12PK", Price: entity.MustParseMoney("6.49")},
+				{ShortDescription: "Emils Cheese Pizza", Price: entity.MustParseMoney("12.25")},
+			},
+		},
+		ItemProductIDs: []string{"1", ""},
+	}
+
+	testCases := []struct {
+		name string
+
+		wantServiceResponse entity.PointsBreakdown
+		wantServiceErr      error
+
+		ruleSetVersion  string
+		rematchProducts string
+		products        []entity.Product
+
+		wantItemProductIDs []string
+		wantStatusCode     int
+	}{
+		{
+			name: "should rescore receipt with the requested rule set",
+
+			wantServiceResponse: entity.PointsBreakdown{Points: 20, RuleSetVersion: "2"},
+
+			ruleSetVersion: "2",
+
+			wantItemProductIDs: []string{"1", ""},
+			wantStatusCode:     http.StatusOK,
+		},
+		{
+			name: "should rescore receipt with its items matched to products again",
+
+			wantServiceResponse: entity.PointsBreakdown{Points: 20, RuleSetVersion: "2"},
+
+			ruleSetVersion:  "2",
+			rematchProducts: "true",
+			products:        []entity.Product{{}, {ID: "2"}},
+
+			wantItemProductIDs: []string{"", "2"},
+			wantStatusCode:     http.StatusOK,
+		},
+		{
+			name: "should fail due unknown rule set",
+
+			wantServiceErr: entity.ErrRuleSetNotFound,
+
+			ruleSetVersion: "3",
+
+			wantItemProductIDs: []string{"1", ""},
+			wantStatusCode:     http.StatusNotFound,
+		},
+		{
+			name: "should fail due invalid rematchProducts",
+
+			ruleSetVersion:  "2",
+			rematchProducts: "sometimes",
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.ReceiptService{}
+		mockRepository := &mocks.ReceiptRepository{}
+		mockProductService := &mocks.ProductService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		controller := newReceiptController(mockService, mockRepository, WithProducts(mockProductService))
+		controller.now = func() time.Time { return scoredAt }
+
+		if tc.wantItemProductIDs != nil {
+			mockRepository.On(
+				"GetReceiptByID",
+				mock.Anything, /* context.Context */
+				record.ID,
+			).Return(record, nil).Once()
+
+			rescored := record
+			rescored.ItemProductIDs = tc.wantItemProductIDs
+
+			if tc.products != nil {
+				mockProductService.On(
+					"MatchProducts",
+					mock.Anything, /* context.Context */
+					record.Receipt.Items,
+				).Return(tc.products, nil).Once()
+
+				mockRepository.On(
+					"SaveReceipt",
+					mock.Anything, /* context.Context */
+					rescored,
+				).Return(nil).Once()
+			}
+
+			mockService.On(
+				"GetRecordPointsBreakdown",
+				mock.Anything, /* context.Context */
+				rescored,
+				tc.ruleSetVersion,
+			).Return(tc.wantServiceResponse, tc.wantServiceErr).Once()
+		}
+
+		if tc.wantStatusCode == http.StatusOK {
+			breakdown := tc.wantServiceResponse
+			mockRepository.On(
+				"SaveReceiptPoints",
+				mock.Anything, /* context.Context */
+				record.ID,
+				entity.ReceiptPoints{
+					Status:         entity.ScoreStatusScored,
+					Points:         tc.wantServiceResponse.Points,
+					RuleSetVersion: tc.wantServiceResponse.RuleSetVersion,
+					ScoredAt:       &scoredAt,
+					Breakdown:      &breakdown,
+				},
+			).Return(nil).Once()
+		}
+
+		router.POST("/:receipt_id/points/rescore", controller.rescoreReceiptPoints)
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Post(
+				fmt.Sprintf("%s/%s/points/rescore?ruleSetVersion=%s&rematchProducts=%s", server.URL, record.ID, tc.ruleSetVersion, tc.rematchProducts),
+				"application/json",
+				nil,
+			)
+			if err != nil {
+				t.Fatalf("RescoreReceiptPoints() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("RescoreReceiptPoints() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockService.AssertExpectations(t)
+			mockRepository.AssertExpectations(t)
+			mockProductService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetReceiptPointsBreakdown(t *testing.T) {
+	scoredAt := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	storedBreakdown := entity.PointsBreakdown{
+		Points:         35,
+		RuleSetVersion: "1",
+		Rules: []entity.RulePoints{
+			{Rule: "purchase_time", Points: 10, Reason: "purchase time 15:00 is between 14:00 and 16:00"},
+		},
+		Promotions: []entity.PromotionPoints{
+			{PromotionID: "1", Name: "Double weekend", Points: 25, Reason: "purchased on a weekend"},
+		},
+	}
+
+	serviceBreakdown := entity.PointsBreakdown{
+		Points:         10,
+		RuleSetVersion: "1",
+		Rules: []entity.RulePoints{
+			{Rule: "purchase_time", Points: 10, Reason: "purchase time 15:00 is between 14:00 and 16:00"},
+		},
+	}
+
+	held := entity.FraudScreening{Status: entity.ScreeningStatusHeld, DuplicateOf: "first"}
+
+	testCases := []struct {
+		name string
+
+		storedReceipt bool
+		screening     *entity.FraudScreening
+		storedPoints  entity.ReceiptPoints
+
+		wantService        bool
+		wantServiceVersion string
+
+		wantStatusCode int
+		want           entity.PointsBreakdown
+		wantScreening  *entity.FraudScreening
+	}{
+		{
+			name: "should explain the points of a pending receipt with the active rule set",
+
+			storedReceipt: true,
+			storedPoints:  entity.ReceiptPoints{Status: entity.ScoreStatusPending},
+
+			wantService: true,
+
+			wantStatusCode: http.StatusOK,
+			want:           serviceBreakdown,
+		},
+		{
+			name: "should return the breakdown stored when the receipt was scored",
+
+			storedReceipt: true,
+			storedPoints: entity.ReceiptPoints{
+				Status:         entity.ScoreStatusScored,
+				Points:         35,
+				RuleSetVersion: "1",
+				ScoredAt:       &scoredAt,
+				Breakdown:      &storedBreakdown,
+			},
+
+			wantStatusCode: http.StatusOK,
+			want:           storedBreakdown,
+		},
+		{
+			name: "should explain again the points of a receipt scored without breakdown",
+
+			storedReceipt: true,
+			storedPoints:  entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 10, RuleSetVersion: "1", ScoredAt: &scoredAt},
+
+			wantService:        true,
+			wantServiceVersion: "1",
+
+			wantStatusCode: http.StatusOK,
+			want:           serviceBreakdown,
+		},
+		{
+			name: "should return zero points for a receipt held for review",
+
+			storedReceipt: true,
+			screening:     &held,
+
+			wantStatusCode: http.StatusOK,
+			wantScreening:  &held,
+		},
+		{
+			name: "should fail due unknown receipt",
+
+			storedReceipt: false,
+
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockReceiptID := "1234567890"
+
+		mockService := &mocks.ReceiptService{}
+		mockRepository := &mocks.ReceiptRepository{}
+		mockFraudService := &mocks.FraudService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		controller := newReceiptController(mockService, mockRepository, WithFraudScreening(mockFraudService))
+
+		if tc.storedReceipt {
+			mockRepository.On(
+				"GetReceiptByID",
+				mock.Anything, /* context.Context */
+				mockReceiptID,
+			).Return(entity.ReceiptRecord{ID: mockReceiptID}, nil).Once()
+
+			screening, screened := entity.FraudScreening{}, tc.screening != nil
+			if screened {
+				screening = *tc.screening
+			}
+			mockFraudService.On(
+				"GetScreening",
+				mock.Anything, /* context.Context */
+				mockReceiptID,
+			).Return(screening, screened, nil).Once()
+		} else {
+			mockRepository.On(
+				"GetReceiptByID",
+				mock.Anything, /* context.Context */
+				mockReceiptID,
+			).Return(entity.ReceiptRecord{}, entity.ErrReceiptNotFound).Once()
+		}
+
+		if tc.storedReceipt && tc.screening == nil {
+			mockRepository.On(
+				"GetReceiptPoints",
+				mock.Anything, /* context.Context */
+				mockReceiptID,
+			).Return(tc.storedPoints, nil).Once()
+		}
+
+		if tc.wantService {
+			// Mock the desired response from the service.
+			mockService.On(
+				"GetRecordPointsBreakdown",
+				mock.Anything, /* context.Context */
+				entity.ReceiptRecord{ID: mockReceiptID},
+				tc.wantServiceVersion,
+			).Return(serviceBreakdown, nil).Once()
+		}
+
+		router.GET("/:receipt_id/points/breakdown", controller.getReceiptPointsBreakdown)
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Get(
+				fmt.Sprintf("%s/%s/points/breakdown", server.URL, mockReceiptID),
+			)
+			if err != nil {
+				t.Fatalf("GetReceiptPointsBreakdown() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("GetReceiptPointsBreakdown() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockService.AssertExpectations(t)
+			mockRepository.AssertExpectations(t)
+			mockFraudService.AssertExpectations(t)
+
+			if tc.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			got := struct {
+				entity.PointsBreakdown
+				Screening *entity.FraudScreening `json:"screening"`
+			}{}
+			if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+				t.Fatalf("GetReceiptPointsBreakdown() = Unmarshaling response error %v", err)
+			}
+
+			if !reflect.DeepEqual(got.PointsBreakdown, tc.want) {
+				t.Errorf("GetReceiptPointsBreakdown() = %v, want %v", got.PointsBreakdown, tc.want)
+			}
+
+			if !reflect.DeepEqual(got.Screening, tc.wantScreening) {
+				t.Errorf("GetReceiptPointsBreakdown() = %v, want %v", got.Screening, tc.wantScreening)
+			}
+		})
+	}
+}
+
+func TestGetReceiptPointsWithFraudScreening(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		screening entity.FraudScreening
+
+		wantPoints    int64
+		wantScreening *entity.FraudScreening
+	}{
+		{
+			name: "should return points for a cleared receipt",
+
+			screening: entity.FraudScreening{Status: entity.ScreeningStatusClear},
+
+			wantPoints: 10,
+		},
+		{
+			name: "should return zero points for a receipt held for review",
+
+			screening: entity.FraudScreening{
+				Status:      entity.ScreeningStatusHeld,
+				Reason:      "same retailer, purchase date and time, total and items as receipt first",
+				DuplicateOf: "first",
+			},
+
+			wantScreening: &entity.FraudScreening{
+				Status:      entity.ScreeningStatusHeld,
+				Reason:      "same retailer, purchase date and time, total and items as receipt first",
+				DuplicateOf: "first",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		mockReceiptID := "1234567890"
+
+		mockService := &mocks.ReceiptService{}
+		mockRepository := &mocks.ReceiptRepository{}
+		mockFraudService := &mocks.FraudService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		controller := newReceiptController(mockService, mockRepository, WithFraudScreening(mockFraudService))
+
+		mockRepository.On(
+			"GetReceiptByID",
+			mock.Anything, /* context.Context */
+			mockReceiptID,
+		).Return(entity.ReceiptRecord{ID: mockReceiptID}, nil).Once()
+
+		mockFraudService.On(
+			"GetScreening",
+			mock.Anything, /* context.Context */
+			mockReceiptID,
+		).Return(tc.screening, true, nil).Once()
+
+		if tc.wantScreening == nil {
+			mockRepository.On(
+				"GetReceiptPoints",
+				mock.Anything, /* context.Context */
+				mockReceiptID,
+			).Return(entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: tc.wantPoints, RuleSetVersion: "1"}, nil).Once()
+		}
+
+		router.GET("/:receipt_id/points", controller.getReceiptPoints)
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Get(fmt.Sprintf("%s/%s/points", server.URL, mockReceiptID))
+			if err != nil {
+				t.Fatalf("GetReceiptPoints() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != http.StatusOK {
+				t.Errorf("GetReceiptPoints() = %v, want %v", response.StatusCode, http.StatusOK)
+			}
+
+			got := struct {
+				Points    int64                  `json:"points"`
+				Screening *entity.FraudScreening `json:"screening"`
+			}{}
+			if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+				t.Errorf("GetReceiptPoints() = Unmarshaling response error %v", err)
+			}
+
+			if got.Points != tc.wantPoints {
+				t.Errorf("GetReceiptPoints() = %v, want %v", got.Points, tc.wantPoints)
+			}
+
+			if !reflect.DeepEqual(got.Screening, tc.wantScreening) {
+				t.Errorf("GetReceiptPoints() = %v, want %v", got.Screening, tc.wantScreening)
+			}
+		})
+	}
+}
+
+func TestReviewReceipt(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		request string
+
+		wantServiceErr error
+
+		wantStatusCode int
+	}{
+		{
+			name: "should approve a receipt held for review",
+
+			request: `{"approved": true, "reason": "two receipts of the same purchase"}`,
+
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "should fail due missing decision",
+
+			request: `{"reason": "two receipts of the same purchase"}`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due receipt not screened",
+
+			request: `{"approved": false}`,
+
+			wantServiceErr: entity.ErrScreeningNotFound,
+
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockReceiptID := "1234567890"
+
+		mockService := &mocks.ReceiptService{}
+		mockRepository := &mocks.ReceiptRepository{}
+		mockFraudService := &mocks.FraudService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		controller := newReceiptController(mockService, mockRepository, WithFraudScreening(mockFraudService))
+
+		mockRepository.On(
+			"GetReceiptByID",
+			mock.Anything, /* context.Context */
+			mockReceiptID,
+		).Return(entity.ReceiptRecord{ID: mockReceiptID}, nil).Once()
+
+		mockFraudService.On(
+			"ReviewReceipt",
+			mock.Anything, /* context.Context */
+			mockReceiptID,
+			mock.Anything, /* bool */
+			mock.Anything, /* string */
+		).Return(entity.FraudScreening{Status: entity.ScreeningStatusClear}, tc.wantServiceErr).Once()
+
+		router.POST("/:receipt_id/review", controller.reviewReceipt)
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Post(
+				fmt.Sprintf("%s/%s/review", server.URL, mockReceiptID),
+				"application/json",
+				bytes.NewBufferString(tc.request),
+			)
+			if err != nil {
+				t.Fatalf("ReviewReceipt() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("ReviewReceipt() = %v, want %v", response.StatusCode, tc.wantStatusCode)
 			}
 		})
 	}
@@ -0,0 +1,124 @@
+package receipt
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// Days of the window of the expiring receipts when none is requested, and
+// the largest one that can be requested.
+const (
+	defaultExpiringDays = 30
+	maxExpiringDays     = 3660
+)
+
+// pointsResponse is the scoring state of a receipt along with its points that
+// are still valid and when they expire, if the points expire.
+type pointsResponse struct {
+	entity.ReceiptPoints
+	*entity.PointsValidity
+}
+
+// writePoints writes the response with the points of a receipt, evaluating
+// the expiry policy on them.
+func (rc *receiptController) writePoints(c *gin.Context, record entity.ReceiptRecord, points entity.ReceiptPoints) {
+	response := pointsResponse{ReceiptPoints: points}
+
+	if rc.expiryService != nil {
+		validity, err := rc.expiryService.GetPointsValidity(c, record.Receipt, points, rc.now().UTC())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"Error getting receipt points validity": err.Error()})
+			return
+		}
+		response.PointsValidity = &validity
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+type expiringReceiptResponse struct {
+	receiptResponse
+	entity.PointsValidity
+}
+
+type expiringReceiptListResponse struct {
+	Receipts   []expiringReceiptResponse `json:"receipts"`
+	NextCursor string                    `json:"nextCursor,omitempty"`
+}
+
+// listExpiringReceipts lists the scored receipts whose points are still valid
+// and expire within the number of days of the query, a page at a time.
+func (rc *receiptController) listExpiringReceipts(c *gin.Context) {
+	var fieldErrors []entity.FieldError
+
+	days := defaultExpiringDays
+	if value := c.Query("days"); value != "" {
+		var err error
+		if days, err = strconv.Atoi(value); err != nil || days < 1 || days > maxExpiringDays {
+			fieldErrors = append(fieldErrors, entity.FieldError{
+				Field:   "days",
+				Code:    entity.FieldErrorInvalidFormat,
+				Message: fmt.Sprintf("days must be a whole number between 1 and %d", maxExpiringDays),
+			})
+		}
+	}
+
+	limit := defaultPageSize
+	if value := c.Query("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > maxPageSize {
+			fieldErrors = append(fieldErrors, entity.FieldError{
+				Field:   "limit",
+				Code:    entity.FieldErrorInvalidFormat,
+				Message: fmt.Sprintf("limit must be a whole number between 1 and %d", maxPageSize),
+			})
+		}
+	}
+
+	if fieldErrors != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": fieldErrors})
+		return
+	}
+
+	now := rc.now().UTC()
+	within := time.Duration(days) * 24 * time.Hour
+
+	page, err := rc.expiryService.ListExpiringReceipts(c, now, within, c.Query("cursor"), limit)
+	if errors.Is(err, entity.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Field:   "cursor",
+			Code:    entity.FieldErrorInvalidFormat,
+			Message: "cursor must be the nextCursor of a page of expiring receipts",
+		}}})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error listing expiring receipts": err.Error()})
+		return
+	}
+
+	response := expiringReceiptListResponse{
+		Receipts:   make([]expiringReceiptResponse, 0, len(page.Entries)),
+		NextCursor: page.NextCursor,
+	}
+	for _, entry := range page.Entries {
+		validity, err := rc.expiryService.GetPointsValidity(c, entry.Record.Receipt, entry.Points, now)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"Error getting receipt points validity": err.Error()})
+			return
+		}
+
+		response.Receipts = append(response.Receipts, expiringReceiptResponse{
+			receiptResponse: newReceiptResponse(entry.Record, entry.Points),
+			PointsValidity:  validity,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,293 @@
+package receipt
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetReceiptPointsValidity(t *testing.T) {
+	now := time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	record := entity.ReceiptRecord{ID: "1", Receipt: entity.Receipt{PurchaseDate: "2022-01-01"}}
+	points := entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1"}
+
+	testCases := []struct {
+		name string
+
+		validity    entity.PointsValidity
+		validityErr error
+
+		wantStatusCode int
+		wantResponse   pointsResponse
+	}{
+		{
+			name: "should report the earned and the valid points",
+
+			validity: entity.PointsValidity{ValidPoints: 28},
+
+			wantStatusCode: http.StatusOK,
+			wantResponse:   pointsResponse{ReceiptPoints: points, PointsValidity: &entity.PointsValidity{ValidPoints: 28}},
+		},
+		{
+			name: "should report expired points with their expiry date",
+
+			validity: entity.PointsValidity{ExpiresAt: &expiresAt},
+
+			wantStatusCode: http.StatusOK,
+			wantResponse:   pointsResponse{ReceiptPoints: points, PointsValidity: &entity.PointsValidity{ExpiresAt: &expiresAt}},
+		},
+		{
+			name: "should fail due error evaluating the expiry policy",
+
+			validityErr: fmt.Errorf("invalid purchase date"),
+
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.ReceiptService{}
+		mockRepository := &mocks.ReceiptRepository{}
+		mockExpiryService := &mocks.ExpiryService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		controller := newReceiptController(mockService, mockRepository, WithPointsExpiry(mockExpiryService))
+		controller.now = func() time.Time { return now }
+
+		mockRepository.On(
+			"GetReceiptByID",
+			mock.Anything, /* context.Context */
+			record.ID,
+		).Return(record, nil).Once()
+
+		mockRepository.On(
+			"GetReceiptPoints",
+			mock.Anything, /* context.Context */
+			record.ID,
+		).Return(points, nil).Once()
+
+		mockExpiryService.On(
+			"GetPointsValidity",
+			mock.Anything, /* context.Context */
+			record.Receipt,
+			points,
+			now,
+		).Return(tc.validity, tc.validityErr).Once()
+
+		router.GET("/receipts/:receipt_id/points", controller.getReceiptPoints)
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Get(fmt.Sprintf("%s/receipts/%s/points", server.URL, record.ID))
+			if err != nil {
+				t.Fatalf("GetReceiptPoints() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("GetReceiptPoints() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockExpiryService.AssertExpectations(t)
+
+			if tc.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			got := pointsResponse{}
+			if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+				t.Fatalf("GetReceiptPoints() = Unmarshaling response error %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tc.wantResponse) {
+				t.Errorf("GetReceiptPoints() = %+v, want %+v", got, tc.wantResponse)
+			}
+		})
+	}
+}
+
+func TestListExpiringReceipts(t *testing.T) {
+	now := time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	scoredPoints := int64(28)
+
+	entry := entity.ReceiptListEntry{
+		Record: entity.ReceiptRecord{ID: "a", Receipt: entity.Receipt{Retailer: "Target", PurchaseDate: "2022-02-01"}},
+		Points: entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1"},
+	}
+	validity := entity.PointsValidity{ValidPoints: 28, ExpiresAt: &expiresAt}
+
+	testCases := []struct {
+		name string
+
+		rawQuery string
+
+		wantList   bool
+		wantWithin time.Duration
+		wantCursor string
+		wantLimit  int
+		page       entity.ReceiptPage
+		pageErr    error
+
+		wantStatusCode int
+		wantResponse   expiringReceiptListResponse
+		wantErrorCodes map[string]string
+	}{
+		{
+			name: "should list the receipts expiring within the default window",
+
+			wantList:   true,
+			wantWithin: defaultExpiringDays * 24 * time.Hour,
+			wantLimit:  defaultPageSize,
+			page:       entity.ReceiptPage{Entries: []entity.ReceiptListEntry{entry}, NextCursor: "next"},
+
+			wantStatusCode: http.StatusOK,
+			wantResponse: expiringReceiptListResponse{
+				Receipts: []expiringReceiptResponse{{
+					receiptResponse: receiptResponse{
+						ID: "a", Receipt: entry.Record.Receipt, ScoreStatus: entity.ScoreStatusScored, Points: &scoredPoints, RuleSetVersion: "1",
+					},
+					PointsValidity: validity,
+				}},
+				NextCursor: "next",
+			},
+		},
+		{
+			name: "should pass the window, cursor and limit",
+
+			rawQuery: "days=7&cursor=next&limit=5",
+
+			wantList:   true,
+			wantWithin: 7 * 24 * time.Hour,
+			wantCursor: "next",
+			wantLimit:  5,
+			page:       entity.ReceiptPage{Entries: []entity.ReceiptListEntry{}},
+
+			wantStatusCode: http.StatusOK,
+			wantResponse:   expiringReceiptListResponse{Receipts: []expiringReceiptResponse{}},
+		},
+		{
+			name: "should fail due invalid parameters",
+
+			rawQuery: "days=0&limit=1000",
+
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCodes: map[string]string{
+				"days":  entity.FieldErrorInvalidFormat,
+				"limit": entity.FieldErrorInvalidFormat,
+			},
+		},
+		{
+			name: "should fail due invalid cursor",
+
+			rawQuery: "cursor=unknown",
+
+			wantList:   true,
+			wantWithin: defaultExpiringDays * 24 * time.Hour,
+			wantCursor: "unknown",
+			wantLimit:  defaultPageSize,
+			pageErr:    entity.ErrInvalidCursor,
+
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCodes: map[string]string{
+				"cursor": entity.FieldErrorInvalidFormat,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		mockExpiryService := &mocks.ExpiryService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		controller := newReceiptController(&mocks.ReceiptService{}, &mocks.ReceiptRepository{}, WithPointsExpiry(mockExpiryService))
+		controller.now = func() time.Time { return now }
+
+		if tc.wantList {
+			mockExpiryService.On(
+				"ListExpiringReceipts",
+				mock.Anything, /* context.Context */
+				now,
+				tc.wantWithin,
+				tc.wantCursor,
+				tc.wantLimit,
+			).Return(tc.page, tc.pageErr).Once()
+		}
+
+		for _, entry := range tc.page.Entries {
+			mockExpiryService.On(
+				"GetPointsValidity",
+				mock.Anything, /* context.Context */
+				entry.Record.Receipt,
+				entry.Points,
+				now,
+			).Return(validity, nil).Once()
+		}
+
+		router.GET("/receipts/expiring", controller.listExpiringReceipts)
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Get(fmt.Sprintf("%s/receipts/expiring?%s", server.URL, tc.rawQuery))
+			if err != nil {
+				t.Fatalf("ListExpiringReceipts() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("ListExpiringReceipts() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockExpiryService.AssertExpectations(t)
+
+			if tc.wantErrorCodes != nil {
+				got := struct {
+					Errors []entity.FieldError `json:"errors"`
+				}{}
+				if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+					t.Fatalf("ListExpiringReceipts() = Unmarshaling response error %v", err)
+				}
+
+				gotCodes := make(map[string]string)
+				for _, fieldError := range got.Errors {
+					gotCodes[fieldError.Field] = fieldError.Code
+				}
+
+				if !reflect.DeepEqual(gotCodes, tc.wantErrorCodes) {
+					t.Errorf("ListExpiringReceipts() = %v, want %v", gotCodes, tc.wantErrorCodes)
+				}
+				return
+			}
+
+			got := expiringReceiptListResponse{}
+			if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+				t.Fatalf("ListExpiringReceipts() = Unmarshaling response error %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tc.wantResponse) {
+				t.Errorf("ListExpiringReceipts() = %+v, want %+v", got, tc.wantResponse)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package receipt
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/util"
+	"github.com/gin-gonic/gin"
+)
+
+// Size of the pages of listed receipts when none is requested, and the
+// largest one that can be requested.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type receiptListResponse struct {
+	Receipts   []receiptResponse `json:"receipts"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+}
+
+// listReceipts lists the stored receipts a page at a time, filtered and sorted
+// as requested in the query.
+func (rc *receiptController) listReceipts(c *gin.Context) {
+	query, fieldErrors := parseReceiptQuery(c)
+	if fieldErrors != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": fieldErrors})
+		return
+	}
+
+	page, err := rc.receiptRepository.QueryReceipts(c, query)
+	if errors.Is(err, entity.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Field:   "cursor",
+			Code:    entity.FieldErrorInvalidFormat,
+			Message: "cursor must be the nextCursor of a page listed with the same sortBy and order",
+		}}})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"Error listing receipts": err.Error()})
+		return
+	}
+
+	response := receiptListResponse{
+		Receipts:   make([]receiptResponse, 0, len(page.Entries)),
+		NextCursor: page.NextCursor,
+	}
+	for _, entry := range page.Entries {
+		response.Receipts = append(response.Receipts, newReceiptResponse(entry.Record, entry.Points))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseReceiptQuery reads the filters, order and page of a listing from the
+// query parameters, reporting every invalid one.
+func parseReceiptQuery(c *gin.Context) (entity.ReceiptQuery, []entity.FieldError) {
+	query := entity.ReceiptQuery{
+		Retailer:       c.Query("retailer"),
+		RetailerPrefix: c.Query("retailerPrefix"),
+		MerchantID:     c.Query("merchantId"),
+		SortBy:         c.DefaultQuery("sortBy", entity.ReceiptSortSubmittedAt),
+		Limit:          defaultPageSize,
+		Cursor:         c.Query("cursor"),
+	}
+
+	var fieldErrors []entity.FieldError
+	addError := func(field, code, message string) {
+		fieldErrors = append(fieldErrors, entity.FieldError{Field: field, Code: code, Message: message})
+	}
+
+	for _, param := range []struct {
+		name string
+		dest *string
+	}{
+		{"purchaseDateFrom", &query.PurchaseDateFrom},
+		{"purchaseDateTo", &query.PurchaseDateTo},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+
+		if _, err := util.ParseDate(value); err != nil {
+			addError(param.name, entity.FieldErrorInvalidDate,
+				fmt.Sprintf("%s %s is not a valid date with the format YYYY-MM-DD", param.name, value))
+			continue
+		}
+
+		*param.dest = value
+	}
+
+	for _, param := range []struct {
+		name string
+		dest **entity.Money
+	}{
+		{"totalMin", &query.TotalMin},
+		{"totalMax", &query.TotalMax},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+
+		amount, err := entity.ParseMoney(value)
+		if err != nil {
+			addError(param.name, entity.FieldErrorInvalidFormat,
+				fmt.Sprintf("%s must be an amount with two decimals, e.g. 6.49", param.name))
+			continue
+		}
+
+		*param.dest = &amount
+	}
+
+	for _, param := range []struct {
+		name string
+		dest **int64
+	}{
+		{"pointsMin", &query.PointsMin},
+		{"pointsMax", &query.PointsMax},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+
+		points, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			addError(param.name, entity.FieldErrorInvalidFormat,
+				fmt.Sprintf("%s must be a whole number", param.name))
+			continue
+		}
+
+		*param.dest = &points
+	}
+
+	switch query.SortBy {
+	case entity.ReceiptSortSubmittedAt, entity.ReceiptSortPoints:
+	default:
+		addError("sortBy", entity.FieldErrorInvalidFormat,
+			fmt.Sprintf("sortBy must be %s or %s", entity.ReceiptSortSubmittedAt, entity.ReceiptSortPoints))
+	}
+
+	switch order := c.DefaultQuery("order", "asc"); order {
+	case "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		addError("order", entity.FieldErrorInvalidFormat, "order must be asc or desc")
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			addError("limit", entity.FieldErrorInvalidFormat,
+				fmt.Sprintf("limit must be a whole number between 1 and %d", maxPageSize))
+		} else {
+			query.Limit = limit
+		}
+	}
+
+	return query, fieldErrors
+}
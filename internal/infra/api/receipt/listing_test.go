@@ -0,0 +1,182 @@
+package receipt
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListReceipts(t *testing.T) {
+	totalMin := entity.MustParseMoney("10.00")
+	pointsMax := int64(50)
+	scoredPoints := int64(28)
+
+	testCases := []struct {
+		name string
+
+		rawQuery string
+
+		wantQuery *entity.ReceiptQuery
+		page      entity.ReceiptPage
+		pageErr   error
+
+		wantStatusCode int
+		wantResponse   receiptListResponse
+		wantErrorCodes map[string]string
+	}{
+		{
+			name: "should list the first page with the default order and size",
+
+			wantQuery: &entity.ReceiptQuery{SortBy: entity.ReceiptSortSubmittedAt, Limit: defaultPageSize},
+			page: entity.ReceiptPage{
+				Entries: []entity.ReceiptListEntry{
+					{
+						Record: entity.ReceiptRecord{ID: "a", Receipt: entity.Receipt{Retailer: "Target"}},
+						Points: entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1"},
+					},
+					{
+						Record: entity.ReceiptRecord{ID: "b", Receipt: entity.Receipt{Retailer: "Walgreens"}},
+						Points: entity.ReceiptPoints{Status: entity.ScoreStatusPending},
+					},
+				},
+				NextCursor: "next",
+			},
+
+			wantStatusCode: http.StatusOK,
+			wantResponse: receiptListResponse{
+				Receipts: []receiptResponse{
+					{ID: "a", Receipt: entity.Receipt{Retailer: "Target"}, ScoreStatus: entity.ScoreStatusScored, Points: &scoredPoints, RuleSetVersion: "1"},
+					{ID: "b", Receipt: entity.Receipt{Retailer: "Walgreens"}, ScoreStatus: entity.ScoreStatusPending},
+				},
+				NextCursor: "next",
+			},
+		},
+		{
+			name: "should pass the filters, order and cursor to the storage",
+
+			rawQuery: "retailer=Target&retailerPrefix=Tar&merchantId=1&purchaseDateFrom=2022-01-01&purchaseDateTo=2022-01-31" +
+				"&totalMin=10.00&pointsMax=50&sortBy=points&order=desc&limit=5&cursor=next",
+
+			wantQuery: &entity.ReceiptQuery{
+				Retailer:         "Target",
+				RetailerPrefix:   "Tar",
+				MerchantID:       "1",
+				PurchaseDateFrom: "2022-01-01",
+				PurchaseDateTo:   "2022-01-31",
+				TotalMin:         &totalMin,
+				PointsMax:        &pointsMax,
+				SortBy:           entity.ReceiptSortPoints,
+				Descending:       true,
+				Limit:            5,
+				Cursor:           "next",
+			},
+			page: entity.ReceiptPage{Entries: []entity.ReceiptListEntry{}},
+
+			wantStatusCode: http.StatusOK,
+			wantResponse:   receiptListResponse{Receipts: []receiptResponse{}},
+		},
+		{
+			name: "should fail due invalid parameters",
+
+			rawQuery: "purchaseDateFrom=2022-02-30&totalMax=10&pointsMin=many&sortBy=total&order=up&limit=1000",
+
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCodes: map[string]string{
+				"purchaseDateFrom": entity.FieldErrorInvalidDate,
+				"totalMax":         entity.FieldErrorInvalidFormat,
+				"pointsMin":        entity.FieldErrorInvalidFormat,
+				"sortBy":           entity.FieldErrorInvalidFormat,
+				"order":            entity.FieldErrorInvalidFormat,
+				"limit":            entity.FieldErrorInvalidFormat,
+			},
+		},
+		{
+			name: "should fail due invalid cursor",
+
+			rawQuery: "cursor=unknown",
+
+			wantQuery: &entity.ReceiptQuery{SortBy: entity.ReceiptSortSubmittedAt, Limit: defaultPageSize, Cursor: "unknown"},
+			pageErr:   entity.ErrInvalidCursor,
+
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCodes: map[string]string{
+				"cursor": entity.FieldErrorInvalidFormat,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.ReceiptService{}
+		mockRepository := &mocks.ReceiptRepository{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		controller := newReceiptController(mockService, mockRepository)
+
+		if tc.wantQuery != nil {
+			mockRepository.On(
+				"QueryReceipts",
+				mock.Anything, /* context.Context */
+				*tc.wantQuery,
+			).Return(tc.page, tc.pageErr).Once()
+		}
+
+		router.GET("/receipts", controller.listReceipts)
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Get(fmt.Sprintf("%s/receipts?%s", server.URL, tc.rawQuery))
+			if err != nil {
+				t.Fatalf("ListReceipts() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("ListReceipts() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockRepository.AssertExpectations(t)
+
+			if tc.wantErrorCodes != nil {
+				got := struct {
+					Errors []entity.FieldError `json:"errors"`
+				}{}
+				if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+					t.Fatalf("ListReceipts() = Unmarshaling response error %v", err)
+				}
+
+				gotCodes := make(map[string]string)
+				for _, fieldError := range got.Errors {
+					gotCodes[fieldError.Field] = fieldError.Code
+				}
+
+				if !reflect.DeepEqual(gotCodes, tc.wantErrorCodes) {
+					t.Errorf("ListReceipts() = %v, want %v", gotCodes, tc.wantErrorCodes)
+				}
+				return
+			}
+
+			got := receiptListResponse{}
+			if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+				t.Fatalf("ListReceipts() = Unmarshaling response error %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tc.wantResponse) {
+				t.Errorf("ListReceipts() = %+v, want %+v", got, tc.wantResponse)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package receipt
+
+import (
+	"context"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// resolveMerchant returns the ID of the merchant the retailer of the receipt
+// is an alias of, which is empty if there is none or merchants are disabled.
+func (rc *receiptController) resolveMerchant(ctx context.Context, receipt entity.Receipt) (string, error) {
+	if rc.merchantService == nil {
+		return "", nil
+	}
+
+	return rc.merchantService.ResolveRetailer(ctx, receipt.Retailer)
+}
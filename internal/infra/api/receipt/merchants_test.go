@@ -0,0 +1,118 @@
+package receipt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateReceiptWithMerchant(t *testing.T) {
+	submittedAt := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	receipt := entity.Receipt{
+		Retailer:     "M & M CORNER MARKET ",
+		PurchaseDate: "2022-01-01",
+		PurchaseTime: "13:01",
+		Items: []entity.Item{
+			{
+				ShortDescription: "Mountain Dew 12PK",
+				Price:            entity.MustParseMoney("6.49"),
+			},
+		},
+		Total: entity.MustParseMoney("6.49"),
+	}
+
+	testCases := []struct {
+		name string
+
+		merchantID string
+		resolveErr error
+
+		wantStatusCode int
+	}{
+		{
+			name: "should store the merchant of the retailer with the receipt",
+
+			merchantID: "1",
+
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "should store the receipt without merchant if the retailer has none",
+
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "should fail due error resolving the merchant",
+
+			resolveErr: errors.New("unexpected error"),
+
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.ReceiptService{}
+		mockRepository := &mocks.ReceiptRepository{}
+		mockMerchantService := &mocks.MerchantService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		controller := newReceiptController(mockService, mockRepository, WithMerchants(mockMerchantService))
+		controller.now = func() time.Time { return submittedAt }
+
+		mockService.On("ValidateReceipt", mock.Anything /* context.Context */, receipt).Return(nil).Once()
+
+		mockMerchantService.On(
+			"ResolveRetailer",
+			mock.Anything, /* context.Context */
+			receipt.Retailer,
+		).Return(tc.merchantID, tc.resolveErr).Once()
+
+		if tc.wantStatusCode == http.StatusOK {
+			record := entity.ReceiptRecord{ID: "1234567890", Receipt: receipt, SubmittedAt: submittedAt, MerchantID: tc.merchantID}
+
+			mockService.On("CreateReceiptID", mock.Anything /* context.Context */).Return("1234567890").Once()
+			mockRepository.On("SaveReceipt", mock.Anything /* context.Context */, record).Return(nil).Once()
+		}
+
+		router.POST("/process", controller.createReceipt)
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			requestBody, err := json.Marshal(&receipt)
+			if err != nil {
+				t.Fatalf("CreateReceipt() = Marshaling error %v", err)
+			}
+
+			response, err := http.Post(fmt.Sprintf("%s/process", server.URL), "application/json", bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Fatalf("CreateReceipt() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("CreateReceipt() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockService.AssertExpectations(t)
+			mockRepository.AssertExpectations(t)
+			mockMerchantService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package receipt
+
+import (
+	"context"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// matchProducts returns the IDs of the products the items of the receipt
+// match, in the order of the items and empty for the unmatched ones. It
+// returns nil if no item matched or products are disabled.
+func (rc *receiptController) matchProducts(ctx context.Context, receipt entity.Receipt) ([]string, error) {
+	if rc.productService == nil {
+		return nil, nil
+	}
+
+	products, err := rc.productService.MatchProducts(ctx, receipt.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	var productIDs []string
+	for i, product := range products {
+		if product.ID == "" {
+			continue
+		}
+		if productIDs == nil {
+			productIDs = make([]string, len(products))
+		}
+		productIDs[i] = product.ID
+	}
+
+	return productIDs, nil
+}
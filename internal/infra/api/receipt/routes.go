@@ -1,21 +1,33 @@
 package receipt
 
 import (
-	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
 	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
 	"github.com/gin-gonic/gin"
 )
 
-// Memory storage for receipts and points.
-var receiptByID map[string]entity.Receipt
-var receiptPointsByID map[string]int64
-
-func RegisterRoutes(router *gin.RouterGroup, receiptService port.ReceiptService) {
-	receiptByID = make(map[string]entity.Receipt)
-	receiptPointsByID = make(map[string]int64)
-
-	controller := newReceiptController(receiptService)
+func RegisterRoutes(router *gin.RouterGroup, receiptService port.ReceiptService, receiptRepository port.ReceiptRepository, options ...Option) {
+	controller := newReceiptController(receiptService, receiptRepository, options...)
 
 	router.POST("/process", controller.createReceipt)
+	router.POST("/process/batch", controller.processReceiptBatch)
+	router.GET("", controller.listReceipts)
+	router.GET("/:receipt_id", controller.getReceipt)
 	router.GET("/:receipt_id/points", controller.getReceiptPoints)
+	router.GET("/:receipt_id/points/breakdown", controller.getReceiptPointsBreakdown)
+	router.POST("/:receipt_id/points/rescore", controller.rescoreReceiptPoints)
+
+	if controller.fraudService != nil {
+		router.POST("/:receipt_id/review", controller.reviewReceipt)
+	}
+
+	if controller.auditRepository != nil {
+		router.PUT("/:receipt_id", controller.replaceReceipt)
+		router.PATCH("/:receipt_id", controller.patchReceipt)
+		router.DELETE("/:receipt_id", controller.deleteReceipt)
+		router.GET("/:receipt_id/history", controller.getReceiptHistory)
+	}
+
+	if controller.expiryService != nil {
+		router.GET("/expiring", controller.listExpiringReceipts)
+	}
 }
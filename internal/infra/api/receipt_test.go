@@ -0,0 +1,286 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/infra/config"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/gin-gonic/gin"
+)
+
+func TestProcessReceiptRetailers(t *testing.T) {
+	cfg := config.Default()
+	cfg.GinMode = gin.TestMode
+
+	server, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer() = %v, want nil", err)
+	}
+
+	testServer := httptest.NewServer(server.httpServer.Handler)
+	defer testServer.Close()
+
+	var merchant entity.Merchant
+	postJSON(t, testServer.URL+"/api/v1/merchants", `{"name": "Café Ñandú"}`, http.StatusCreated, &merchant)
+
+	testCases := []struct {
+		name string
+
+		retailer string
+
+		wantStatusCode     int
+		wantRetailerPoints int64
+		wantMerchantID     string
+	}{
+		{
+			name: "should accept a retailer with accents and assign its merchant",
+
+			retailer: "Café Ñandú",
+
+			wantStatusCode:     http.StatusOK,
+			wantRetailerPoints: 9,
+			wantMerchantID:     merchant.ID,
+		},
+		{
+			name: "should assign the merchant of a retailer written without accents",
+
+			retailer: "CAFE NANDU",
+
+			wantStatusCode:     http.StatusOK,
+			wantRetailerPoints: 9,
+			wantMerchantID:     merchant.ID,
+		},
+		{
+			name: "should accept a retailer in another script",
+
+			retailer: "東京マート",
+
+			wantStatusCode:     http.StatusOK,
+			wantRetailerPoints: 5,
+		},
+		{
+			name: "should accept a retailer with apostrophes and periods",
+
+			retailer: "St. Mary's Market",
+
+			wantStatusCode:     http.StatusOK,
+			wantRetailerPoints: 13,
+		},
+		{
+			name: "should fail due retailer with symbols",
+
+			retailer: "$Walmart/",
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body := fmt.Sprintf(`{"retailer": %q, "purchaseDate": "2022-01-02", "purchaseTime": "13:01",`+
+				` "items": [{"shortDescription": "Mountain Dew 12PK", "price": "6.49"}], "total": "6.49"}`, tc.retailer)
+
+			var processed struct {
+				ID string `json:"id"`
+			}
+			postJSON(t, testServer.URL+"/api/v1/receipts/process", body, tc.wantStatusCode, &processed)
+
+			if tc.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			var receipt struct {
+				MerchantID string `json:"merchantId"`
+			}
+			getJSON(t, testServer.URL+"/api/v1/receipts/"+processed.ID, &receipt)
+
+			if receipt.MerchantID != tc.wantMerchantID {
+				t.Errorf("ProcessReceipt() merchant = %q, want %q", receipt.MerchantID, tc.wantMerchantID)
+			}
+
+			var breakdown entity.PointsBreakdown
+			getJSON(t, testServer.URL+"/api/v1/receipts/"+processed.ID+"/points/breakdown", &breakdown)
+
+			if len(breakdown.Rules) == 0 || breakdown.Rules[0].Points != tc.wantRetailerPoints {
+				t.Errorf("ProcessReceipt() breakdown = %v, want %d points for the retailer name", breakdown.Rules, tc.wantRetailerPoints)
+			}
+		})
+	}
+}
+
+func TestProcessReceiptIdempotencyForAccounts(t *testing.T) {
+	cfg := config.Default()
+	cfg.GinMode = gin.TestMode
+
+	server, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer() = %v, want nil", err)
+	}
+
+	testServer := httptest.NewServer(server.httpServer.Handler)
+	defer testServer.Close()
+
+	var first, second entity.Account
+	postJSON(t, testServer.URL+"/api/v1/accounts", `{"name": "Jane"}`, http.StatusCreated, &first)
+	postJSON(t, testServer.URL+"/api/v1/accounts", `{"name": "John"}`, http.StatusCreated, &second)
+
+	submit := func(accountID string) string {
+		t.Helper()
+
+		request, err := http.NewRequest(http.MethodPost, testServer.URL+"/api/v1/receipts/process?accountId="+accountID,
+			strings.NewReader(`{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01",`+
+				` "items": [{"shortDescription": "Mountain Dew 12PK", "price": "6.49"}], "total": "6.49"}`))
+		if err != nil {
+			t.Fatalf("ProcessReceipt() = error %v", err)
+		}
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Idempotency-Key", "retry-1")
+
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf("ProcessReceipt() = error %v", err)
+		}
+		defer response.Body.Close()
+
+		var processed struct {
+			ID string `json:"id"`
+		}
+		if err := json.NewDecoder(response.Body).Decode(&processed); err != nil {
+			t.Fatalf("ProcessReceipt() = Decoding error %v", err)
+		}
+
+		return processed.ID
+	}
+
+	firstID := submit(first.ID)
+	secondID := submit(second.ID)
+
+	if firstID == "" || firstID == secondID {
+		t.Errorf("ProcessReceipt() = %q and %q, want a receipt for each account", firstID, secondID)
+	}
+
+	if retriedID := submit(first.ID); retriedID != firstID {
+		t.Errorf("ProcessReceipt() retried = %q, want %q", retriedID, firstID)
+	}
+}
+
+func TestAmendReceiptScreeningAndIdempotency(t *testing.T) {
+	cfg := config.Default()
+	cfg.GinMode = gin.TestMode
+	cfg.Idempotency.HashReceipts = true
+	cfg.Fraud.Enabled = true
+
+	server, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer() = %v, want nil", err)
+	}
+
+	testServer := httptest.NewServer(server.httpServer.Handler)
+	defer testServer.Close()
+
+	receiptURL := testServer.URL + "/api/v1/receipts/"
+
+	receiptJSON := func(purchaseDate, purchaseTime string) string {
+		return fmt.Sprintf(`{"retailer": "Target", "purchaseDate": %q, "purchaseTime": %q,`+
+			` "items": [{"shortDescription": "Mountain Dew 12PK", "price": "6.49"}], "total": "6.49"}`, purchaseDate, purchaseTime)
+	}
+
+	submit := func(body string) string {
+		t.Helper()
+
+		var processed struct {
+			ID string `json:"id"`
+		}
+		postJSON(t, receiptURL+"process", body, http.StatusOK, &processed)
+
+		return processed.ID
+	}
+
+	change := func(method, receiptID, body string) {
+		t.Helper()
+
+		request, err := http.NewRequest(method, receiptURL+receiptID, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("%s = error %v", method, err)
+		}
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("X-Actor", "support")
+
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf("%s = error %v", method, err)
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNoContent {
+			t.Fatalf("%s = %v, want a success", method, response.StatusCode)
+		}
+	}
+
+	screeningStatus := func(receiptID string) string {
+		t.Helper()
+
+		var got struct {
+			Screening *entity.FraudScreening `json:"screening"`
+		}
+		getJSON(t, receiptURL+receiptID, &got)
+
+		if got.Screening == nil {
+			return ""
+		}
+		return got.Screening.Status
+	}
+
+	originalID := submit(receiptJSON("2022-01-01", "13:01"))
+
+	// Another submission of the same purchase at another time is held.
+	duplicateID := submit(receiptJSON("2022-01-01", "14:00"))
+	if got := screeningStatus(duplicateID); got != entity.ScreeningStatusHeld {
+		t.Fatalf("ProcessReceipt() screening = %q, want %q", got, entity.ScreeningStatusHeld)
+	}
+
+	// The original isn't taken as a duplicate of the receipts submitted after
+	// it when it's amended.
+	change(http.MethodPatch, originalID, `{"purchaseTime": "13:05"}`)
+	if got := screeningStatus(originalID); got != entity.ScreeningStatusClear {
+		t.Errorf("AmendReceipt() original screening = %q, want %q", got, entity.ScreeningStatusClear)
+	}
+
+	// Once amended to another purchase, it's screened again and cleared.
+	change(http.MethodPatch, duplicateID, `{"purchaseDate": "2022-02-01"}`)
+	if got := screeningStatus(duplicateID); got != entity.ScreeningStatusClear {
+		t.Errorf("AmendReceipt() screening = %q, want %q", got, entity.ScreeningStatusClear)
+	}
+
+	// Submitting the amended receipt returns it, and the receipt as it was
+	// before the amendment creates another one.
+	if got := submit(receiptJSON("2022-02-01", "14:00")); got != duplicateID {
+		t.Errorf("ProcessReceipt() amended = %q, want %q", got, duplicateID)
+	}
+	if got := submit(receiptJSON("2022-01-01", "14:00")); got == duplicateID {
+		t.Errorf("ProcessReceipt() before the amendment = %q, want a new receipt", got)
+	}
+
+	// The receipt is submitted again once deleted.
+	change(http.MethodDelete, originalID, "")
+	if got := submit(receiptJSON("2022-01-01", "13:05")); got == originalID {
+		t.Errorf("ProcessReceipt() deleted = %q, want a new receipt", got)
+	}
+
+	// An approved duplicate stays approved when it's amended.
+	submit(receiptJSON("2022-03-01", "13:01"))
+	approvedID := submit(receiptJSON("2022-03-01", "14:00"))
+
+	var reviewed entity.FraudScreening
+	postJSON(t, receiptURL+approvedID+"/review", `{"approved": true}`, http.StatusOK, &reviewed)
+
+	change(http.MethodPatch, approvedID, `{"purchaseTime": "14:30"}`)
+	if got := screeningStatus(approvedID); got != entity.ScreeningStatusClear {
+		t.Errorf("AmendReceipt() approved screening = %q, want %q", got, entity.ScreeningStatusClear)
+	}
+}
@@ -0,0 +1,146 @@
+package redemption
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/gin-gonic/gin"
+)
+
+type redemptionController struct {
+	redemptionService port.RedemptionService
+}
+
+func newRedemptionController(redemptionService port.RedemptionService) *redemptionController {
+	return &redemptionController{
+		redemptionService: redemptionService,
+	}
+}
+
+// reservationRequest reserves points of the receipts for a reward, all of
+// their available points if none are given.
+type reservationRequest struct {
+	ReceiptIDs []string `json:"receiptIds"`
+	Points     int64    `json:"points"`
+	Reward     string   `json:"reward"`
+}
+
+// reservePoints holds points of one or more receipts for a reward until the
+// reservation is confirmed, cancelled or expires.
+func (rc *redemptionController) reservePoints(c *gin.Context) {
+	var request reservationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Code:    entity.FieldErrorInvalidJSON,
+			Message: "the request body is not a valid reservation",
+		}}})
+		return
+	}
+
+	var fieldErrors []entity.FieldError
+	if len(request.ReceiptIDs) == 0 {
+		fieldErrors = append(fieldErrors, entity.FieldError{
+			Field:   "receiptIds",
+			Code:    entity.FieldErrorMinItems,
+			Message: "receiptIds must have at least one receipt",
+		})
+	}
+	if request.Points < 0 {
+		fieldErrors = append(fieldErrors, entity.FieldError{
+			Field:   "points",
+			Code:    entity.FieldErrorInvalidFormat,
+			Message: "points must be a whole number greater than zero, or zero to reserve all of them",
+		})
+	}
+	if strings.TrimSpace(request.Reward) == "" {
+		fieldErrors = append(fieldErrors, entity.FieldError{
+			Field:   "reward",
+			Code:    entity.FieldErrorRequired,
+			Message: "reward is required",
+		})
+	}
+	if fieldErrors != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": fieldErrors})
+		return
+	}
+
+	redemption, err := rc.redemptionService.Reserve(c, request.ReceiptIDs, request.Points, request.Reward)
+	switch {
+	case errors.Is(err, entity.ErrReceiptNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"Receipt not found": err.Error()})
+	case errors.Is(err, entity.ErrReceiptNotScored):
+		c.JSON(http.StatusConflict, gin.H{"The points of the receipt weren't calculated yet": err.Error()})
+	case errors.Is(err, entity.ErrInsufficientPoints):
+		c.JSON(http.StatusConflict, gin.H{"The receipts don't have enough points available to reserve": request.Points})
+	case errors.Is(err, entity.ErrMixedAccounts):
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []entity.FieldError{{
+			Field:   "receiptIds",
+			Code:    entity.FieldErrorInvalidFormat,
+			Message: "the receipts must belong to the same account",
+		}}})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"Error reserving points": err.Error()})
+	default:
+		c.JSON(http.StatusCreated, redemption)
+	}
+}
+
+func (rc *redemptionController) getRedemption(c *gin.Context) {
+	redemptionID := c.Param("redemption_id")
+
+	redemption, err := rc.redemptionService.GetRedemption(c, redemptionID)
+	if !checkRedemptionError(c, redemptionID, err, "Error getting redemption") {
+		return
+	}
+
+	c.JSON(http.StatusOK, redemption)
+}
+
+// confirmRedemption spends the points held by a reservation.
+func (rc *redemptionController) confirmRedemption(c *gin.Context) {
+	redemptionID := c.Param("redemption_id")
+
+	redemption, err := rc.redemptionService.Confirm(c, redemptionID)
+	if errors.Is(err, entity.ErrInsufficientPoints) {
+		c.JSON(http.StatusConflict, gin.H{"The account of the receipts doesn't have enough points to redeem": redemptionID})
+		return
+	}
+	if !checkRedemptionError(c, redemptionID, err, "Error confirming redemption") {
+		return
+	}
+
+	c.JSON(http.StatusOK, redemption)
+}
+
+// cancelRedemption releases the points held by a reservation.
+func (rc *redemptionController) cancelRedemption(c *gin.Context) {
+	redemptionID := c.Param("redemption_id")
+
+	redemption, err := rc.redemptionService.Cancel(c, redemptionID)
+	if !checkRedemptionError(c, redemptionID, err, "Error cancelling redemption") {
+		return
+	}
+
+	c.JSON(http.StatusOK, redemption)
+}
+
+// checkRedemptionError writes the error response if there is an error, and
+// returns whether there was none.
+func checkRedemptionError(c *gin.Context, redemptionID string, err error, message string) bool {
+	switch {
+	case errors.Is(err, entity.ErrRedemptionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"Redemption not found for that id": redemptionID})
+		return false
+	case errors.Is(err, entity.ErrRedemptionResolved):
+		c.JSON(http.StatusConflict, gin.H{"Redemption already confirmed, cancelled or expired": redemptionID})
+		return false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{message: err.Error()})
+		return false
+	default:
+		return true
+	}
+}
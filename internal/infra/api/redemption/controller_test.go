@@ -0,0 +1,252 @@
+package redemption
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReservePoints(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	reserved := entity.Redemption{
+		ID:          "1",
+		Reward:      "coffee",
+		Points:      10,
+		Allocations: []entity.RedemptionAllocation{{ReceiptID: "a", Points: 10}},
+		Status:      entity.RedemptionStatusReserved,
+		CreatedAt:   createdAt,
+		ExpiresAt:   createdAt.Add(15 * time.Minute),
+	}
+
+	testCases := []struct {
+		name string
+
+		requestBody string
+		reserveErr  error
+
+		wantReserve    bool
+		wantStatusCode int
+	}{
+		{
+			name: "should reserve the points",
+
+			requestBody: `{"receiptIds": ["a"], "points": 10, "reward": "coffee"}`,
+
+			wantReserve:    true,
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name: "should fail due missing receipts",
+
+			requestBody: `{"receiptIds": [], "points": 10, "reward": "coffee"}`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due negative points",
+
+			requestBody: `{"receiptIds": ["a"], "points": -1, "reward": "coffee"}`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due missing reward",
+
+			requestBody: `{"receiptIds": ["a"], "points": 10}`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due invalid JSON",
+
+			requestBody: `{"receiptIds":`,
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should fail due unknown receipt",
+
+			requestBody: `{"receiptIds": ["a"], "points": 10, "reward": "coffee"}`,
+			reserveErr:  fmt.Errorf("receipt a: %w", entity.ErrReceiptNotFound),
+
+			wantReserve:    true,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "should fail due insufficient points",
+
+			requestBody: `{"receiptIds": ["a"], "points": 10, "reward": "coffee"}`,
+			reserveErr:  entity.ErrInsufficientPoints,
+
+			wantReserve:    true,
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name: "should fail due receipt not scored",
+
+			requestBody: `{"receiptIds": ["a"], "points": 10, "reward": "coffee"}`,
+			reserveErr:  fmt.Errorf("receipt a: %w", entity.ErrReceiptNotScored),
+
+			wantReserve:    true,
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name: "should fail due receipts of different accounts",
+
+			requestBody: `{"receiptIds": ["a"], "points": 10, "reward": "coffee"}`,
+			reserveErr:  entity.ErrMixedAccounts,
+
+			wantReserve:    true,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.RedemptionService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		RegisterRoutes(router.Group("/redemptions"), mockService)
+
+		if tc.wantReserve {
+			mockService.On(
+				"Reserve",
+				mock.Anything, /* context.Context */
+				[]string{"a"},
+				int64(10),
+				"coffee",
+			).Return(reserved, tc.reserveErr).Once()
+		}
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Post(fmt.Sprintf("%s/redemptions", server.URL), "application/json", bytes.NewBufferString(tc.requestBody))
+			if err != nil {
+				t.Fatalf("ReservePoints() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("ReservePoints() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			if tc.wantStatusCode == http.StatusCreated {
+				var got entity.Redemption
+				if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+					t.Fatalf("ReservePoints() = Decoding error %v", err)
+				}
+
+				if !reflect.DeepEqual(got, reserved) {
+					t.Errorf("ReservePoints() = %v, want %v", got, reserved)
+				}
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResolveRedemption(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		action     string
+		method     string
+		resolveErr error
+
+		wantStatusCode int
+	}{
+		{
+			name: "should confirm the redemption",
+
+			action: "confirm",
+			method: "Confirm",
+
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "should cancel the redemption",
+
+			action: "cancel",
+			method: "Cancel",
+
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "should fail due unknown redemption",
+
+			action:     "confirm",
+			method:     "Confirm",
+			resolveErr: entity.ErrRedemptionNotFound,
+
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "should fail due resolved redemption",
+
+			action:     "cancel",
+			method:     "Cancel",
+			resolveErr: entity.ErrRedemptionResolved,
+
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name: "should fail due insufficient points in the account",
+
+			action:     "confirm",
+			method:     "Confirm",
+			resolveErr: entity.ErrInsufficientPoints,
+
+			wantStatusCode: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		mockService := &mocks.RedemptionService{}
+
+		// Create a new router for tests.
+		router := gin.Default()
+		gin.SetMode(gin.TestMode)
+
+		RegisterRoutes(router.Group("/redemptions"), mockService)
+
+		mockService.On(
+			tc.method,
+			mock.Anything, /* context.Context */
+			"1",
+		).Return(entity.Redemption{ID: "1"}, tc.resolveErr).Once()
+
+		server := httptest.NewServer(router)
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer server.Close()
+
+			response, err := http.Post(fmt.Sprintf("%s/redemptions/1/%s", server.URL, tc.action), "application/json", nil)
+			if err != nil {
+				t.Fatalf("ResolveRedemption() = error %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tc.wantStatusCode {
+				t.Errorf("ResolveRedemption() = %v, want %v", response.StatusCode, tc.wantStatusCode)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
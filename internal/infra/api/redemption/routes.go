@@ -0,0 +1,15 @@
+package redemption
+
+import (
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, redemptionService port.RedemptionService) {
+	controller := newRedemptionController(redemptionService)
+
+	router.POST("", controller.reservePoints)
+	router.GET("/:redemption_id", controller.getRedemption)
+	router.POST("/:redemption_id/confirm", controller.confirmRedemption)
+	router.POST("/:redemption_id/cancel", controller.cancelRedemption)
+}
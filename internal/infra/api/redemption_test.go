@@ -0,0 +1,166 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/infra/config"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/gin-gonic/gin"
+)
+
+func TestConcurrentRedemptions(t *testing.T) {
+	cfg := config.Default()
+	cfg.GinMode = gin.TestMode
+
+	server, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer() = %v, want nil", err)
+	}
+
+	testServer := httptest.NewServer(server.httpServer.Handler)
+	defer testServer.Close()
+
+	var account entity.Account
+	postJSON(t, testServer.URL+"/api/v1/accounts", `{"name": "Jane"}`, http.StatusCreated, &account)
+
+	var processed struct {
+		ID string `json:"id"`
+	}
+	postJSON(t, testServer.URL+"/api/v1/receipts/process?accountId="+account.ID,
+		`{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01",`+
+			` "items": [{"shortDescription": "Mountain Dew 12PK", "price": "6.49"}], "total": "6.49"}`,
+		http.StatusOK, &processed)
+
+	var points struct {
+		Points int64 `json:"points"`
+	}
+	getJSON(t, testServer.URL+"/api/v1/receipts/"+processed.ID+"/points", &points)
+	if points.Points == 0 {
+		t.Fatalf("ConcurrentRedemptions() = receipt without points")
+	}
+
+	// Twice as many one point reservations as the receipt has points race for them.
+	requests := int(points.Points) * 2
+	redemptionIDs := make(chan string, requests)
+
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			body := fmt.Sprintf(`{"receiptIds": [%q], "points": 1, "reward": "sticker"}`, processed.ID)
+			response, err := http.Post(testServer.URL+"/api/v1/redemptions", "application/json", bytes.NewBufferString(body))
+			if err != nil {
+				t.Errorf("Reserve() = error %v", err)
+				return
+			}
+			defer response.Body.Close()
+
+			switch response.StatusCode {
+			case http.StatusCreated:
+				var redemption entity.Redemption
+				if err := json.NewDecoder(response.Body).Decode(&redemption); err != nil {
+					t.Errorf("Reserve() = Decoding error %v", err)
+					return
+				}
+				redemptionIDs <- redemption.ID
+			case http.StatusConflict:
+			default:
+				t.Errorf("Reserve() = %v, want %v or %v", response.StatusCode, http.StatusCreated, http.StatusConflict)
+			}
+		}()
+	}
+	wg.Wait()
+	close(redemptionIDs)
+
+	var reserved []string
+	for id := range redemptionIDs {
+		reserved = append(reserved, id)
+	}
+
+	if int64(len(reserved)) != points.Points {
+		t.Fatalf("Reserve() = %v reservations, want %v", len(reserved), points.Points)
+	}
+
+	// Confirming each reservation twice at the same time spends its points once.
+	confirmed := make(chan int, len(reserved)*2)
+	for _, id := range reserved {
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+
+				response, err := http.Post(testServer.URL+"/api/v1/redemptions/"+id+"/confirm", "application/json", nil)
+				if err != nil {
+					t.Errorf("Confirm() = error %v", err)
+					return
+				}
+				response.Body.Close()
+
+				confirmed <- response.StatusCode
+			}(id)
+		}
+	}
+	wg.Wait()
+	close(confirmed)
+
+	statusCodes := make(map[int]int)
+	for statusCode := range confirmed {
+		statusCodes[statusCode]++
+	}
+
+	want := map[int]int{http.StatusOK: len(reserved), http.StatusConflict: len(reserved)}
+	if fmt.Sprint(statusCodes) != fmt.Sprint(want) {
+		t.Errorf("Confirm() = %v, want %v", statusCodes, want)
+	}
+
+	var balance entity.AccountBalance
+	getJSON(t, testServer.URL+"/api/v1/accounts/"+account.ID+"/balance", &balance)
+
+	if balance.Balance != 0 {
+		t.Errorf("GetBalance() = %v, want %v", balance.Balance, 0)
+	}
+}
+
+func postJSON(t *testing.T, url, body string, wantStatusCode int, value any) {
+	t.Helper()
+
+	response, err := http.Post(url, "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("POST %s = error %v", url, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != wantStatusCode {
+		t.Fatalf("POST %s = %v, want %v", url, response.StatusCode, wantStatusCode)
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(value); err != nil {
+		t.Fatalf("POST %s = Decoding error %v", url, err)
+	}
+}
+
+func getJSON(t *testing.T, url string, value any) {
+	t.Helper()
+
+	response, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s = error %v", url, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s = %v, want %v", url, response.StatusCode, http.StatusOK)
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(value); err != nil {
+		t.Fatalf("GET %s = Decoding error %v", url, err)
+	}
+}
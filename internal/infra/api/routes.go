@@ -1,22 +1,121 @@
 package api
 
 import (
+	"context"
+	"time"
+
+	accountapi "github.com/darcops/receipt-proccessor-challenge/internal/infra/api/account"
+	merchantapi "github.com/darcops/receipt-proccessor-challenge/internal/infra/api/merchant"
+	productapi "github.com/darcops/receipt-proccessor-challenge/internal/infra/api/product"
+	promotionapi "github.com/darcops/receipt-proccessor-challenge/internal/infra/api/promotion"
 	receiptapi "github.com/darcops/receipt-proccessor-challenge/internal/infra/api/receipt"
+	redemptionapi "github.com/darcops/receipt-proccessor-challenge/internal/infra/api/redemption"
+	"github.com/darcops/receipt-proccessor-challenge/internal/infra/config"
+	"github.com/darcops/receipt-proccessor-challenge/internal/infra/storage"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
 	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/service/account"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/service/expiry"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/service/fraud"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/service/merchant"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/service/product"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/service/promotion"
 	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/service/receipt"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/service/redemption"
 	"github.com/gin-gonic/gin"
 )
 
-var (
-	receiptService port.ReceiptService
-)
+// registerAppRoutes sets up the routes of the application and returns the
+// sweepers of the records its services leave behind.
+func registerAppRoutes(server *gin.Engine, store *storage.Storage, ruleSets entity.RuleSetsConfig, cfg config.Config) []sweeper {
+	// Services are created for each server, so several servers can run in the same process.
+	receiptRepository := store.ReceiptRepository
+	var merchantService port.MerchantService = merchant.NewMerchantService(store.MerchantRepository, receiptRepository)
+	var promotionService port.PromotionService = promotion.NewPromotionService(store.PromotionRepository, merchantService)
+	var productService port.ProductService = product.NewProductService(store.ProductRepository)
+	var receiptService port.ReceiptService = receipt.NewReceiptService(
+		receipt.WithRuleSets(ruleSets), receipt.WithPromotions(promotionService), receipt.WithProducts(productService),
+	)
+	var accountService port.AccountService = account.NewAccountService(store.AccountRepository)
+	var expiryService port.ExpiryService = expiry.NewExpiryService(
+		cfg.PointsExpiry, receiptRepository, expiry.WithAccounts(accountService),
+	)
+
+	receiptOptions := []receiptapi.Option{
+		receiptapi.WithMaxBatchSize(cfg.MaxBatchSize),
+		receiptapi.WithIdempotency(
+			store.IdempotencyRepository,
+			cfg.Idempotency.Retention,
+			cfg.Idempotency.HashReceipts,
+		),
+		receiptapi.WithAuditLog(store.AuditRepository),
+		receiptapi.WithAccounts(accountService),
+		receiptapi.WithPointsExpiry(expiryService),
+		receiptapi.WithMerchants(merchantService),
+		receiptapi.WithProducts(productService),
+	}
+
+	redemptionOptions := []redemption.Option{
+		redemption.WithReservationTTL(cfg.Redemption.ReservationTTL),
+		redemption.WithAccounts(accountService),
+		redemption.WithPointsExpiry(expiryService),
+	}
+
+	if cfg.Fraud.Enabled {
+		fraudService := fraud.NewFraudService(store.FraudRepository, fraud.WithAction(cfg.Fraud.Action))
+		receiptOptions = append(receiptOptions, receiptapi.WithFraudScreening(fraudService))
+		redemptionOptions = append(redemptionOptions, redemption.WithFraudScreening(fraudService))
+	}
 
-func registerAppRoutes(server *gin.Engine) {
-	receiptService = receipt.NewReceiptService()
+	var redemptionService port.RedemptionService = redemption.NewRedemptionService(
+		store.RedemptionRepository, receiptRepository, redemptionOptions...,
+	)
+	receiptOptions = append(receiptOptions, receiptapi.WithRedemptions(redemptionService))
+
+	if cfg.Scoring.Mode == config.ScoringModeEager {
+		receiptOptions = append(receiptOptions, receiptapi.WithEagerScoring())
+	}
 
 	apiV1 := server.Group("/api/v1")
 
 	receiptRoutes := apiV1.Group("/receipts")
 
-	receiptapi.RegisterRoutes(receiptRoutes, receiptService)
+	receiptapi.RegisterRoutes(receiptRoutes, receiptService, receiptRepository, receiptOptions...)
+
+	accountRoutes := apiV1.Group("/accounts")
+
+	accountapi.RegisterRoutes(accountRoutes, accountService)
+
+	redemptionRoutes := apiV1.Group("/redemptions")
+
+	redemptionapi.RegisterRoutes(redemptionRoutes, redemptionService)
+
+	merchantRoutes := apiV1.Group("/merchants")
+
+	merchantapi.RegisterRoutes(merchantRoutes, merchantService)
+
+	promotionRoutes := apiV1.Group("/promotions")
+
+	promotionapi.RegisterRoutes(promotionRoutes, promotionService)
+
+	productRoutes := apiV1.Group("/products")
+
+	productapi.RegisterRoutes(productRoutes, productService)
+
+	return []sweeper{
+		{
+			name: "expired redemption reservations",
+			sweep: func(ctx context.Context, now time.Time) error {
+				_, err := redemptionService.ExpireReservations(ctx, now)
+				return err
+			},
+		},
+		{
+			name: "expired receipt points",
+			sweep: func(ctx context.Context, now time.Time) error {
+				_, err := expiryService.ExpireReceipts(ctx, now)
+				return err
+			},
+		},
+	}
 }
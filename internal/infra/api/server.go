@@ -1,26 +1,209 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/darcops/receipt-proccessor-challenge/internal/infra/config"
+	"github.com/darcops/receipt-proccessor-challenge/internal/infra/rules"
+	"github.com/darcops/receipt-proccessor-challenge/internal/infra/storage"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/service/receipt"
 	"github.com/gin-gonic/gin"
 	cors "github.com/itsjamie/gin-cors"
 )
 
-func RunServer() {
-	server := gin.Default()
+// sweepInterval is how often the expired idempotency records are deleted and
+// the other sweepers run.
+const sweepInterval = time.Minute
 
-	server.Use(cors.Middleware(cors.Config{
-		Origins:        "*",
-		Methods:        "GET, POST", // Only GET and POST methods are allowed for this API.
-		RequestHeaders: "Origin,Authorization,Content-Type,Access-Control-Allow-Origin",
-		MaxAge:         50 * time.Second,
+// sweeper periodically removes or expires the stale records of a service.
+type sweeper struct {
+	name  string
+	sweep func(ctx context.Context, now time.Time) error
+}
+
+// Server is the HTTP server of the application along with the storage it uses.
+type Server struct {
+	httpServer          *http.Server
+	store               *storage.Storage
+	listener            net.Listener
+	shutdownGracePeriod time.Duration
+	sweepers            []sweeper
+}
+
+// NewServer loads the rules and opens the storage of the config, and sets up
+// the routes of the server. The storage is closed when Run returns.
+func NewServer(cfg config.Config) (*Server, error) {
+	ruleSets := receipt.DefaultRuleSets()
+	if cfg.RulesFile != "" {
+		var err error
+		if ruleSets, err = rules.LoadFile(cfg.RulesFile); err != nil {
+			return nil, fmt.Errorf("loading the rules: %w", err)
+		}
+	}
+
+	store, err := storage.New(cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("opening the storage: %w", err)
+	}
+
+	gin.SetMode(cfg.GinMode)
+
+	router := gin.New()
+	router.Use(gin.Logger(), gin.Recovery())
+
+	router.Use(cors.Middleware(cors.Config{
+		Origins:        strings.Join(cfg.CORS.Origins, ", "),
+		Methods:        strings.Join(cfg.CORS.Methods, ", "),
+		RequestHeaders: "Origin,Authorization,Content-Type,Access-Control-Allow-Origin,Idempotency-Key,X-Actor,X-Change-Reason",
+		MaxAge:         cfg.CORS.MaxAge,
 	}))
 
-	registerAppRoutes(server)
+	sweepers := []sweeper{
+		{
+			name: "expired idempotency records",
+			sweep: func(ctx context.Context, now time.Time) error {
+				_, err := store.IdempotencyRepository.DeleteExpiredIdempotencyRecords(ctx, now)
+				return err
+			},
+		},
+	}
+	sweepers = append(sweepers, registerAppRoutes(router, store, ruleSets, cfg)...)
+
+	return &Server{
+		httpServer: &http.Server{
+			Addr:              cfg.ListenAddr,
+			Handler:           router,
+			ReadTimeout:       cfg.Timeouts.Read,
+			ReadHeaderTimeout: cfg.Timeouts.ReadHeader,
+			WriteTimeout:      cfg.Timeouts.Write,
+			IdleTimeout:       cfg.Timeouts.Idle,
+		},
+		store:               store,
+		shutdownGracePeriod: cfg.Timeouts.ShutdownGracePeriod,
+		sweepers:            sweepers,
+	}, nil
+}
+
+// Listen binds the listen address of the server. It's called by Run when it
+// wasn't called before, and allows to know the address before serving, e.g.
+// when listening on port 0.
+func (s *Server) Listen() error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", s.httpServer.Addr, err)
+	}
+
+	s.listener = listener
+	return nil
+}
+
+// Addr returns the address the server listens on, or nil if it isn't listening.
+func (s *Server) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+
+	return s.listener.Addr()
+}
+
+// Run serves requests until the context is done. Then it stops accepting
+// requests, waits for the in-flight ones during the shutdown grace period and
+// closes the storage. It returns nil when the server stopped gracefully.
+func (s *Server) Run(ctx context.Context) error {
+	defer func() {
+		if err := s.store.Close(); err != nil {
+			log.Printf("Error closing the storage: %v", err)
+		}
+	}()
+
+	if s.listener == nil {
+		if err := s.Listen(); err != nil {
+			return err
+		}
+	}
+
+	// The sweepers are stopped before the storage is closed.
+	sweepCtx, stopSweep := context.WithCancel(ctx)
+	sweepDone := make(chan struct{})
+	go func() {
+		defer close(sweepDone)
+		s.sweep(sweepCtx)
+	}()
+	defer func() {
+		stopSweep()
+		<-sweepDone
+	}()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.httpServer.Serve(s.listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("serving requests: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownGracePeriod)
+	defer cancel()
+
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		// The requests still in-flight after the grace period are dropped.
+		s.httpServer.Close()
+		return fmt.Errorf("shutting down the server: %w", err)
+	}
+
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serving requests: %w", err)
+	}
+
+	return nil
+}
+
+// sweep periodically runs the sweepers until the context is done.
+func (s *Server) sweep(ctx context.Context) {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, sweeper := range s.sweepers {
+				if err := sweeper.sweep(ctx, now); err != nil {
+					log.Printf("Error sweeping the %s: %v", sweeper.name, err)
+				}
+			}
+		}
+	}
+}
+
+// RunServer runs the server with the given config until it receives SIGINT
+// or SIGTERM, and then shuts it down gracefully.
+func RunServer(cfg config.Config) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server, err := NewServer(cfg)
+	if err != nil {
+		log.Fatalf("Error creating the server: %v", err)
+	}
+
+	if err := server.Run(ctx); err != nil {
+		log.Fatalf("Error running the server: %v", err)
+	}
 
-	server.Run(
-		fmt.Sprintf(":8080"),
-	)
+	log.Println("Server stopped")
 }
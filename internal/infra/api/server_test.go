@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/infra/config"
+	"github.com/gin-gonic/gin"
+)
+
+func TestServerRun(t *testing.T) {
+	cfg := config.Default()
+	cfg.ListenAddr = "127.0.0.1:0"
+	cfg.GinMode = gin.TestMode
+	// Shutdown waits 5 seconds before closing the connections that never sent a
+	// request, so the grace period must be longer for them not to fail it.
+	cfg.Timeouts.ShutdownGracePeriod = 15 * time.Second
+
+	server, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer() = %v, want nil", err)
+	}
+
+	// Add a slow route to have a request in-flight while the server shuts down.
+	requestStarted := make(chan struct{})
+	server.httpServer.Handler.(*gin.Engine).GET("/slow", func(c *gin.Context) {
+		close(requestStarted)
+		time.Sleep(200 * time.Millisecond)
+		c.String(http.StatusOK, "done")
+	})
+
+	if err := server.Listen(); err != nil {
+		t.Fatalf("Listen() = %v, want nil", err)
+	}
+	baseURL := fmt.Sprintf("http://%s", server.Addr())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- server.Run(ctx)
+	}()
+
+	// Connections aren't reused, so none is left idle or unused when the
+	// server shuts down.
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	defer client.CloseIdleConnections()
+
+	response, err := client.Post(
+		baseURL+"/api/v1/receipts/process",
+		"application/json",
+		strings.NewReader(`{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01",`+
+			` "items": [{"shortDescription": "Mountain Dew 12PK", "price": "6.49"}], "total": "6.49"}`),
+	)
+	if err != nil {
+		t.Fatalf("Run() = request error %v", err)
+	}
+	io.Copy(io.Discard, response.Body)
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Run() = %v, want %v", response.StatusCode, http.StatusOK)
+	}
+
+	slowResponse := make(chan *http.Response, 1)
+	go func() {
+		response, err := client.Get(baseURL + "/slow")
+		if err != nil {
+			t.Errorf("Run() = in-flight request error %v", err)
+		}
+		slowResponse <- response
+	}()
+
+	<-requestStarted
+	client.CloseIdleConnections()
+	cancel()
+
+	if err := <-runErr; err != nil {
+		t.Errorf("Run() = %v, want nil", err)
+	}
+
+	// The in-flight request is completed before the server stops.
+	if response := <-slowResponse; response == nil || response.StatusCode != http.StatusOK {
+		t.Errorf("Run() = in-flight response %v, want %v", response, http.StatusOK)
+	} else {
+		io.Copy(io.Discard, response.Body)
+		response.Body.Close()
+	}
+
+	if _, err := client.Get(baseURL + "/slow"); err == nil {
+		t.Errorf("Run() = server still accepting requests after shutdown")
+	}
+}
+
+func TestServerRunListenError(t *testing.T) {
+	cfg := config.Default()
+	cfg.ListenAddr = "127.0.0.1:-1"
+	cfg.GinMode = gin.TestMode
+
+	server, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer() = %v, want nil", err)
+	}
+
+	if err := server.Run(context.Background()); err == nil {
+		t.Errorf("Run() = nil, want listen error")
+	}
+}
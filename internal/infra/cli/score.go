@@ -0,0 +1,315 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/infra/rules"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/service/receipt"
+)
+
+// Output formats of the score command.
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatCSV   = "csv"
+)
+
+// Exit codes of the score command.
+const (
+	ExitOK           = 0
+	ExitScoreFailure = 1
+	ExitUsage        = 2
+)
+
+// stdinSource is the name of the standard input in the list of files to score.
+const stdinSource = "-"
+
+// scoreResult holds the points of a receipt read from Source, which is the
+// file name followed by the position of the receipt in the file, e.g. "a.json#2".
+type scoreResult struct {
+	Source         string              `json:"source"`
+	Points         int64               `json:"points"`
+	RuleSetVersion string              `json:"ruleSetVersion,omitempty"`
+	Rules          []entity.RulePoints `json:"rules,omitempty"`
+	Error          string              `json:"error,omitempty"`
+}
+
+// RunScore runs the score command, which prints the points of the receipts of
+// the given JSON files without starting the server. Each file may contain a
+// receipt, a JSON array of receipts or NDJSON, and the standard input is read
+// when no files are given or a file is "-". It returns the exit code.
+func RunScore(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("score", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: receipt-processor score [flags] [file ...]")
+		flags.PrintDefaults()
+	}
+
+	rulesFile := flags.String("rules-file", "", "JSON or YAML file with the parameters of the points rules, the default rules are used if empty")
+	ruleSetVersion := flags.String("rule-set", "", "version of the rule set used to score the receipts, the active one is used if empty")
+	breakdown := flags.Bool("breakdown", false, "print the points awarded by each rule")
+	format := flags.String("format", FormatTable, "output format: table, json or csv")
+
+	if err := flags.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	if *format != FormatTable && *format != FormatJSON && *format != FormatCSV {
+		fmt.Fprintf(stderr, "unknown output format %q, it must be table, json or csv\n", *format)
+		return ExitUsage
+	}
+
+	ruleSets := receipt.DefaultRuleSets()
+	if *rulesFile != "" {
+		var err error
+		if ruleSets, err = rules.LoadFile(*rulesFile); err != nil {
+			fmt.Fprintf(stderr, "error loading the rules: %v\n", err)
+			return ExitUsage
+		}
+	}
+
+	scorer := &receiptScorer{
+		receiptService: receipt.NewReceiptService(receipt.WithRuleSets(ruleSets)),
+		ruleSetVersion: *ruleSetVersion,
+		breakdown:      *breakdown,
+	}
+
+	files := flags.Args()
+	if len(files) == 0 {
+		files = []string{stdinSource}
+	}
+
+	var results []scoreResult
+
+	for _, file := range files {
+		fileResults, err := scorer.scoreFile(file, stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "error reading receipts: %v\n", err)
+			return ExitScoreFailure
+		}
+
+		results = append(results, fileResults...)
+	}
+
+	if err := writeResults(stdout, *format, *breakdown, results); err != nil {
+		fmt.Fprintf(stderr, "error writing the results: %v\n", err)
+		return ExitScoreFailure
+	}
+
+	for _, result := range results {
+		if result.Error != "" {
+			return ExitScoreFailure
+		}
+	}
+
+	return ExitOK
+}
+
+type receiptScorer struct {
+	receiptService port.ReceiptService
+	ruleSetVersion string
+	breakdown      bool
+}
+
+// scoreFile scores every receipt of a file. Receipts that can't be decoded or
+// are invalid are reported in their results, while reading errors are returned.
+func (rs *receiptScorer) scoreFile(file string, stdin io.Reader) ([]scoreResult, error) {
+	name := file
+	input := stdin
+
+	if file == stdinSource {
+		name = "stdin"
+	} else {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		input = f
+	}
+
+	var results []scoreResult
+
+	decoder := json.NewDecoder(bufio.NewReader(input))
+	for {
+		var document json.RawMessage
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", name, err)
+		}
+
+		// A JSON array holds several receipts.
+		documents := []json.RawMessage{document}
+		if len(document) > 0 && document[0] == '[' {
+			if err := json.Unmarshal(document, &documents); err != nil {
+				return nil, fmt.Errorf("decoding %s: %w", name, err)
+			}
+		}
+
+		for _, receiptDocument := range documents {
+			source := fmt.Sprintf("%s#%d", name, len(results)+1)
+			results = append(results, rs.scoreReceipt(source, receiptDocument))
+		}
+	}
+
+	return results, nil
+}
+
+func (rs *receiptScorer) scoreReceipt(source string, document json.RawMessage) scoreResult {
+	ctx := context.Background()
+	result := scoreResult{Source: source}
+
+	receipt, fieldErrors := entity.DecodeReceipt(document)
+	if fieldErrors != nil {
+		result.Error = (&entity.ValidationError{Errors: fieldErrors}).Error()
+		return result
+	}
+
+	if err := rs.receiptService.ValidateReceipt(ctx, receipt); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if !rs.breakdown && rs.ruleSetVersion == "" {
+		points, err := rs.receiptService.GetReceiptPoints(ctx, receipt)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+
+		result.Points = points
+		return result
+	}
+
+	var breakdown entity.PointsBreakdown
+	var err error
+
+	if rs.ruleSetVersion == "" {
+		breakdown, err = rs.receiptService.GetReceiptPointsBreakdown(ctx, receipt)
+	} else {
+		breakdown, err = rs.receiptService.GetReceiptPointsBreakdownWithRuleSet(ctx, receipt, rs.ruleSetVersion)
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Points = breakdown.Points
+	result.RuleSetVersion = breakdown.RuleSetVersion
+	if rs.breakdown {
+		result.Rules = breakdown.Rules
+	}
+
+	return result
+}
+
+func writeResults(w io.Writer, format string, breakdown bool, results []scoreResult) error {
+	switch format {
+	case FormatJSON:
+		return writeJSONResults(w, results)
+	case FormatCSV:
+		return writeCSVResults(w, breakdown, results)
+	default:
+		return writeTableResults(w, breakdown, results)
+	}
+}
+
+// writeJSONResults writes a JSON object per line, so the output can be
+// processed as NDJSON in the same way as the input.
+func writeJSONResults(w io.Writer, results []scoreResult) error {
+	encoder := json.NewEncoder(w)
+	for _, result := range results {
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeCSVResults writes a row per receipt, or a row per rule of each receipt
+// when the breakdown is requested.
+func writeCSVResults(w io.Writer, breakdown bool, results []scoreResult) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"source", "points", "error"}
+	if breakdown {
+		header = []string{"source", "points", "ruleSetVersion", "error", "rule", "rulePoints", "reason"}
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		points := strconv.FormatInt(result.Points, 10)
+
+		if !breakdown {
+			if err := writer.Write([]string{result.Source, points, result.Error}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if len(result.Rules) == 0 {
+			row := []string{result.Source, points, result.RuleSetVersion, result.Error, "", "", ""}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, rule := range result.Rules {
+			row := []string{
+				result.Source, points, result.RuleSetVersion, result.Error,
+				rule.Rule, strconv.FormatInt(rule.Points, 10), rule.Reason,
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeTableResults writes aligned columns for reading in a terminal, with the
+// points of each rule below their receipt when the breakdown is requested.
+func writeTableResults(w io.Writer, breakdown bool, results []scoreResult) error {
+	writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(writer, "SOURCE\tPOINTS\tDETAILS")
+
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			fmt.Fprintf(writer, "%s\t-\terror: %s\n", result.Source, result.Error)
+		case breakdown:
+			fmt.Fprintf(writer, "%s\t%d\trule set %s\n", result.Source, result.Points, result.RuleSetVersion)
+			for _, rule := range result.Rules {
+				fmt.Fprintf(writer, "  %s\t%d\t%s\n", rule.Rule, rule.Points, rule.Reason)
+			}
+		default:
+			fmt.Fprintf(writer, "%s\t%d\t\n", result.Source, result.Points)
+		}
+	}
+
+	return writer.Flush()
+}
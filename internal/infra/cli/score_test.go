@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const targetReceipt = `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [` +
+	`{"shortDescription": "Mountain Dew 12PK", "price": "6.49"}, {"shortDescription": "Emils Cheese Pizza", "price": "12.25"}, ` +
+	`{"shortDescription": "Knorr Creamy Chicken", "price": "1.26"}, {"shortDescription": "Doritos Nacho Cheese", "price": "3.35"}, ` +
+	`{"shortDescription": "   Klarbrunn 12-PK 12 FL OZ  ", "price": "12.00"}], "total": "35.35"}`
+
+const cornerMarketReceipt = `{"retailer": "M&M Corner Market", "purchaseDate": "2022-03-20", "purchaseTime": "14:33", "items": [` +
+	`{"shortDescription": "Gatorade", "price": "2.25"}, {"shortDescription": "Gatorade", "price": "2.25"}, ` +
+	`{"shortDescription": "Gatorade", "price": "2.25"}, {"shortDescription": "Gatorade", "price": "2.25"}], "total": "9.00"}`
+
+func TestRunScore(t *testing.T) {
+	receiptsFile := filepath.Join(t.TempDir(), "receipts.json")
+	if err := os.WriteFile(receiptsFile, []byte("["+targetReceipt+","+cornerMarketReceipt+"]"), 0o600); err != nil {
+		t.Fatalf("RunScore() = error writing receipts file %v", err)
+	}
+
+	testCases := []struct {
+		name string
+
+		args  []string
+		stdin string
+
+		want         string
+		wantExitCode int
+	}{
+		{
+			name: "should score NDJSON receipts from stdin",
+
+			args:  []string{"-format", "json"},
+			stdin: targetReceipt + "\n" + cornerMarketReceipt + "\n",
+
+			want: `{"source":"stdin#1","points":28}` + "\n" +
+				`{"source":"stdin#2","points":109}` + "\n",
+			wantExitCode: ExitOK,
+		},
+		{
+			name: "should score an array of receipts from a file",
+
+			args: []string{"-format", "csv", receiptsFile},
+
+			want: "source,points,error\n" +
+				receiptsFile + "#1,28,\n" +
+				receiptsFile + "#2,109,\n",
+			wantExitCode: ExitOK,
+		},
+		{
+			name: "should print the breakdown of the points",
+
+			args:  []string{"-format", "csv", "-breakdown"},
+			stdin: cornerMarketReceipt,
+
+			want: "source,points,ruleSetVersion,error,rule,rulePoints,reason\n" +
+				"stdin#1,109,1,,retailer_name,14,14 alphanumeric characters in the retailer name\n" +
+				"stdin#1,109,1,,total_rounded,50,total 9.00 is a round dollar amount with no cents\n" +
+				"stdin#1,109,1,,total_multiple,25,total 9.00 is a multiple of 0.25\n" +
+				"stdin#1,109,1,,item_pairs,10,2 pairs of items\n" +
+				"stdin#1,109,1,,item_descriptions,0,0 items with description length divisible by 3\n" +
+				"stdin#1,109,1,,purchase_date,0,purchase day of 2022-03-20 is even\n" +
+				"stdin#1,109,1,,purchase_time,10,purchase time 14:33 is between 14:00 and 16:00\n",
+			wantExitCode: ExitOK,
+		},
+		{
+			name: "should report invalid receipts",
+
+			args:  []string{"-format", "json"},
+			stdin: `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [], "total": "0.00"}`,
+
+			want:         `{"source":"stdin#1","points":0,"error":"invalid receipt: items: the receipt must have at least one item"}` + "\n",
+			wantExitCode: ExitScoreFailure,
+		},
+		{
+			name: "should report receipts without amounts",
+
+			args:  []string{"-format", "json"},
+			stdin: `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Mountain Dew 12PK"}]}`,
+
+			want:         `{"source":"stdin#1","points":0,"error":"invalid receipt: items[0].price: items[0].price is required; total: total is required"}` + "\n",
+			wantExitCode: ExitScoreFailure,
+		},
+		{
+			name: "should report receipts with quantities given as zero",
+
+			args:  []string{"-format", "json"},
+			stdin: `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Mountain Dew 12PK", "price": "6.49", "quantity": 0}], "total": "6.49"}`,
+
+			want:         `{"source":"stdin#1","points":0,"error":"invalid receipt: items[0].quantity: items[0].quantity must be at least 1 when given"}` + "\n",
+			wantExitCode: ExitScoreFailure,
+		},
+		{
+			name: "should fail due unknown output format",
+
+			args: []string{"-format", "xml"},
+
+			wantExitCode: ExitUsage,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			got := RunScore(tc.args, strings.NewReader(tc.stdin), &stdout, &stderr)
+
+			if got != tc.wantExitCode {
+				t.Errorf("RunScore() = %v, want %v, stderr %q", got, tc.wantExitCode, stderr.String())
+			}
+
+			if stdout.String() != tc.want {
+				t.Errorf("RunScore() = %q, want %q", stdout.String(), tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,510 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/infra/storage"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/service/fraud"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/service/redemption"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is the prefix of the environment variables read by Load.
+const EnvPrefix = "RECEIPT_PROCESSOR_"
+
+// Scoring modes: lazy scores the receipts the first time their points are
+// requested, eager when they are submitted.
+const (
+	ScoringModeLazy  = "lazy"
+	ScoringModeEager = "eager"
+)
+
+// Config holds the settings of the server.
+type Config struct {
+	ListenAddr   string         `yaml:"listenAddr"`
+	GinMode      string         `yaml:"ginMode"`
+	CORS         CORSConfig     `yaml:"cors"`
+	Timeouts     TimeoutsConfig `yaml:"timeouts"`
+	Storage      storage.Config `yaml:"storage"`
+	RulesFile    string         `yaml:"rulesFile"`
+	MaxBatchSize int            `yaml:"maxBatchSize"`
+
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	Fraud       FraudConfig       `yaml:"fraud"`
+	Scoring     ScoringConfig     `yaml:"scoring"`
+	Redemption  RedemptionConfig  `yaml:"redemption"`
+
+	// PointsExpiry is the policy deciding when the points of the receipts expire.
+	PointsExpiry entity.ExpiryPolicy `yaml:"pointsExpiry"`
+}
+
+// CORSConfig holds the allowed cross-origin requests.
+type CORSConfig struct {
+	Origins []string      `yaml:"origins"`
+	Methods []string      `yaml:"methods"`
+	MaxAge  time.Duration `yaml:"maxAge"`
+}
+
+// TimeoutsConfig holds the timeouts of the HTTP server, zero means no timeout.
+// ShutdownGracePeriod is how long in-flight requests are waited for when the
+// server stops, zero means they aren't waited for.
+type TimeoutsConfig struct {
+	Read                time.Duration `yaml:"read"`
+	ReadHeader          time.Duration `yaml:"readHeader"`
+	Write               time.Duration `yaml:"write"`
+	Idle                time.Duration `yaml:"idle"`
+	ShutdownGracePeriod time.Duration `yaml:"shutdownGracePeriod"`
+}
+
+// IdempotencyConfig holds how long the idempotency keys are retained and
+// whether identical receipts submitted without a key are deduplicated by
+// their canonical hash.
+type IdempotencyConfig struct {
+	Retention    time.Duration `yaml:"retention"`
+	HashReceipts bool          `yaml:"hashReceipts"`
+}
+
+// FraudConfig holds whether the receipts are screened for duplicates and the
+// action taken on the flagged ones: hold them for review or award zero points.
+type FraudConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Action  string `yaml:"action"`
+}
+
+// ScoringConfig holds when the receipts are scored.
+type ScoringConfig struct {
+	Mode string `yaml:"mode"`
+}
+
+// RedemptionConfig holds how long the reservations hold the points of their
+// receipts before they expire.
+type RedemptionConfig struct {
+	ReservationTTL time.Duration `yaml:"reservationTTL"`
+}
+
+// Default returns the settings used when they aren't provided.
+func Default() Config {
+	return Config{
+		ListenAddr: ":8080",
+		GinMode:    gin.DebugMode,
+		CORS: CORSConfig{
+			Origins: []string{"*"},
+			Methods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+			MaxAge:  50 * time.Second,
+		},
+		Timeouts: TimeoutsConfig{
+			Read:       15 * time.Second,
+			ReadHeader: 5 * time.Second,
+			Write:      30 * time.Second,
+			Idle:       60 * time.Second,
+
+			ShutdownGracePeriod: 15 * time.Second,
+		},
+		Storage: storage.Config{
+			Backend:    storage.BackendMemory,
+			SQLitePath: "receipts.db",
+		},
+		MaxBatchSize: 100,
+		Idempotency: IdempotencyConfig{
+			Retention: 24 * time.Hour,
+		},
+		Fraud: FraudConfig{
+			Action: fraud.ActionHold,
+		},
+		Scoring: ScoringConfig{
+			Mode: ScoringModeLazy,
+		},
+		Redemption: RedemptionConfig{
+			ReservationTTL: redemption.DefaultReservationTTL,
+		},
+		PointsExpiry: entity.ExpiryPolicy{
+			Type: entity.ExpiryPolicyNever,
+		},
+	}
+}
+
+// setting is a value of the config that can be set with a flag and an
+// environment variable.
+type setting struct {
+	flag   string
+	env    string
+	usage  string
+	isBool bool
+	set    func(config *Config, value string) error
+}
+
+var settings = []setting{
+	{
+		flag: "listen-addr", env: "LISTEN_ADDR",
+		usage: "address the server listens on, e.g. :8080",
+		set:   func(c *Config, v string) error { c.ListenAddr = v; return nil },
+	},
+	{
+		flag: "gin-mode", env: "GIN_MODE",
+		usage: "gin mode: debug, release or test",
+		set:   func(c *Config, v string) error { c.GinMode = v; return nil },
+	},
+	{
+		flag: "cors-origins", env: "CORS_ORIGINS",
+		usage: "comma-separated origins allowed to make cross-origin requests",
+		set:   func(c *Config, v string) error { c.CORS.Origins = splitList(v); return nil },
+	},
+	{
+		flag: "cors-methods", env: "CORS_METHODS",
+		usage: "comma-separated methods allowed in cross-origin requests",
+		set:   func(c *Config, v string) error { c.CORS.Methods = splitList(v); return nil },
+	},
+	{
+		flag: "cors-max-age", env: "CORS_MAX_AGE",
+		usage: "how long the preflight responses can be cached, e.g. 50s",
+		set:   func(c *Config, v string) error { return parseDuration(v, &c.CORS.MaxAge) },
+	},
+	{
+		flag: "read-timeout", env: "READ_TIMEOUT",
+		usage: "maximum duration for reading a request, including its body",
+		set:   func(c *Config, v string) error { return parseDuration(v, &c.Timeouts.Read) },
+	},
+	{
+		flag: "read-header-timeout", env: "READ_HEADER_TIMEOUT",
+		usage: "maximum duration for reading the headers of a request",
+		set:   func(c *Config, v string) error { return parseDuration(v, &c.Timeouts.ReadHeader) },
+	},
+	{
+		flag: "write-timeout", env: "WRITE_TIMEOUT",
+		usage: "maximum duration for writing a response",
+		set:   func(c *Config, v string) error { return parseDuration(v, &c.Timeouts.Write) },
+	},
+	{
+		flag: "idle-timeout", env: "IDLE_TIMEOUT",
+		usage: "maximum duration to wait for the next request on keep-alive connections",
+		set:   func(c *Config, v string) error { return parseDuration(v, &c.Timeouts.Idle) },
+	},
+	{
+		flag: "shutdown-grace-period", env: "SHUTDOWN_GRACE_PERIOD",
+		usage: "maximum duration to wait for in-flight requests when the server stops",
+		set:   func(c *Config, v string) error { return parseDuration(v, &c.Timeouts.ShutdownGracePeriod) },
+	},
+	{
+		flag: "storage", env: "STORAGE",
+		usage: "storage backend for receipts: memory or sqlite",
+		set:   func(c *Config, v string) error { c.Storage.Backend = v; return nil },
+	},
+	{
+		flag: "sqlite-path", env: "SQLITE_PATH",
+		usage: "file of the SQLite database, used by the sqlite storage",
+		set:   func(c *Config, v string) error { c.Storage.SQLitePath = v; return nil },
+	},
+	{
+		flag: "rules-file", env: "RULES_FILE",
+		usage: "JSON or YAML file with the parameters of the points rules, the default rules are used if empty",
+		set:   func(c *Config, v string) error { c.RulesFile = v; return nil },
+	},
+	{
+		flag: "max-batch-size", env: "MAX_BATCH_SIZE",
+		usage: "maximum number of receipts accepted in a batch",
+		set:   func(c *Config, v string) error { return parseInt(v, &c.MaxBatchSize) },
+	},
+	{
+		flag: "idempotency-retention", env: "IDEMPOTENCY_RETENTION",
+		usage: "how long the idempotency keys of the submitted receipts are retained",
+		set:   func(c *Config, v string) error { return parseDuration(v, &c.Idempotency.Retention) },
+	},
+	{
+		flag: "idempotency-hash-receipts", env: "IDEMPOTENCY_HASH_RECEIPTS", isBool: true,
+		usage: "deduplicate identical receipts submitted without an idempotency key",
+		set: func(c *Config, v string) error {
+			hashReceipts, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("%q is not a boolean", v)
+			}
+			c.Idempotency.HashReceipts = hashReceipts
+			return nil
+		},
+	},
+	{
+		flag: "fraud-screening", env: "FRAUD_SCREENING", isBool: true,
+		usage: "screen the submitted receipts for duplicates",
+		set: func(c *Config, v string) error {
+			enabled, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("%q is not a boolean", v)
+			}
+			c.Fraud.Enabled = enabled
+			return nil
+		},
+	},
+	{
+		flag: "fraud-action", env: "FRAUD_ACTION",
+		usage: "action taken on the receipts flagged as duplicates: hold or zero",
+		set:   func(c *Config, v string) error { c.Fraud.Action = v; return nil },
+	},
+	{
+		flag: "scoring-mode", env: "SCORING_MODE",
+		usage: "when the receipts are scored: lazy (when their points are requested) or eager (when submitted)",
+		set:   func(c *Config, v string) error { c.Scoring.Mode = v; return nil },
+	},
+	{
+		flag: "redemption-reservation-ttl", env: "REDEMPTION_RESERVATION_TTL",
+		usage: "how long the reservations hold the points of their receipts before they expire",
+		set:   func(c *Config, v string) error { return parseDuration(v, &c.Redemption.ReservationTTL) },
+	},
+	{
+		flag: "points-expiry-policy", env: "POINTS_EXPIRY_POLICY",
+		usage: "when the points of the receipts expire: never, after_purchase (a number of months after the purchase date) or end_of_year (of the purchase year)",
+		set:   func(c *Config, v string) error { c.PointsExpiry.Type = v; return nil },
+	},
+	{
+		flag: "points-expiry-months", env: "POINTS_EXPIRY_MONTHS",
+		usage: "months after the purchase date the points expire, used by the after_purchase policy",
+		set:   func(c *Config, v string) error { return parseInt(v, &c.PointsExpiry.Months) },
+	},
+	{
+		flag: "points-expiry-years", env: "POINTS_EXPIRY_YEARS",
+		usage: "years after the end of the purchase year the points expire, used by the end_of_year policy",
+		set:   func(c *Config, v string) error { return parseInt(v, &c.PointsExpiry.Years) },
+	},
+}
+
+// Load builds the config from, in increasing order of precedence, the default
+// values, the config file, the environment variables and the command-line
+// flags. The config file is given with the -config flag or the
+// RECEIPT_PROCESSOR_CONFIG environment variable, and may be YAML or JSON.
+func Load(args []string, getenv func(string) string, output io.Writer) (Config, error) {
+	flags := flag.NewFlagSet("receipt-processor", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	configFile := flags.String("config", getenv(EnvPrefix+"CONFIG"), "YAML or JSON config file, also set with "+EnvPrefix+"CONFIG")
+
+	flagValues := make(map[string]string)
+
+	for _, s := range settings {
+		s := s
+		usage := fmt.Sprintf("%s, also set with %s%s", s.usage, EnvPrefix, s.env)
+
+		// Flags are applied after the environment variables, once all of them are parsed.
+		storeValue := func(value string) error {
+			flagValues[s.flag] = value
+			return nil
+		}
+
+		if s.isBool {
+			flags.BoolFunc(s.flag, usage, storeValue)
+		} else {
+			flags.Func(s.flag, usage, storeValue)
+		}
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	config := Default()
+
+	if *configFile != "" {
+		if err := loadFile(*configFile, &config); err != nil {
+			return Config{}, err
+		}
+	}
+
+	var errs []error
+
+	for _, s := range settings {
+		if value := getenv(EnvPrefix + s.env); value != "" {
+			if err := s.set(&config, value); err != nil {
+				errs = append(errs, fmt.Errorf("environment variable %s%s: %w", EnvPrefix, s.env, err))
+			}
+		}
+	}
+
+	for _, s := range settings {
+		if value, ok := flagValues[s.flag]; ok {
+			if err := s.set(&config, value); err != nil {
+				errs = append(errs, fmt.Errorf("flag -%s: %w", s.flag, err))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return Config{}, errors.Join(errs...)
+	}
+
+	if err := config.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return config, nil
+}
+
+// loadFile sets the values of the config file over the given config. Unknown
+// keys are rejected so typos don't go unnoticed.
+func loadFile(path string, config *Config) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".yaml", ".yml":
+	default:
+		return fmt.Errorf("config file %s must be a .json, .yaml or .yml file", path)
+	}
+
+	// YAML is a superset of JSON, so both formats are decoded as YAML.
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("decoding config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Validate checks the config, returning an error that describes every invalid setting.
+func (c Config) Validate() error {
+	var errs []error
+
+	if _, _, err := net.SplitHostPort(c.ListenAddr); err != nil {
+		errs = append(errs, fmt.Errorf("listen address %q must have the form host:port: %w", c.ListenAddr, err))
+	}
+
+	switch c.GinMode {
+	case gin.DebugMode, gin.ReleaseMode, gin.TestMode:
+	default:
+		errs = append(errs, fmt.Errorf("gin mode %q must be debug, release or test", c.GinMode))
+	}
+
+	if len(c.CORS.Origins) == 0 {
+		errs = append(errs, errors.New("at least one CORS origin is required, use * to allow any origin"))
+	}
+
+	if len(c.CORS.Methods) == 0 {
+		errs = append(errs, errors.New("at least one CORS method is required"))
+	}
+	for _, method := range c.CORS.Methods {
+		if !isHTTPMethod(method) {
+			errs = append(errs, fmt.Errorf("CORS method %q is not an HTTP method", method))
+		}
+	}
+
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"CORS max age", c.CORS.MaxAge},
+		{"read timeout", c.Timeouts.Read},
+		{"read header timeout", c.Timeouts.ReadHeader},
+		{"write timeout", c.Timeouts.Write},
+		{"idle timeout", c.Timeouts.Idle},
+		{"shutdown grace period", c.Timeouts.ShutdownGracePeriod},
+	}
+	for _, duration := range durations {
+		if duration.value < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative, got %s", duration.name, duration.value))
+		}
+	}
+
+	switch c.Storage.Backend {
+	case storage.BackendMemory:
+	case storage.BackendSQLite:
+		if c.Storage.SQLitePath == "" {
+			errs = append(errs, errors.New("the SQLite path is required by the sqlite storage"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("storage backend %q must be memory or sqlite", c.Storage.Backend))
+	}
+
+	if c.RulesFile != "" {
+		if _, err := os.Stat(c.RulesFile); err != nil {
+			errs = append(errs, fmt.Errorf("rules file: %w", err))
+		}
+	}
+
+	if c.Idempotency.Retention <= 0 {
+		errs = append(errs, fmt.Errorf("the idempotency retention must be positive, got %s", c.Idempotency.Retention))
+	}
+
+	switch c.Fraud.Action {
+	case fraud.ActionHold, fraud.ActionZero:
+	default:
+		errs = append(errs, fmt.Errorf("fraud action %q must be hold or zero", c.Fraud.Action))
+	}
+
+	switch c.Scoring.Mode {
+	case ScoringModeLazy, ScoringModeEager:
+	default:
+		errs = append(errs, fmt.Errorf("scoring mode %q must be lazy or eager", c.Scoring.Mode))
+	}
+
+	if c.Redemption.ReservationTTL <= 0 {
+		errs = append(errs, fmt.Errorf("the redemption reservation TTL must be positive, got %s", c.Redemption.ReservationTTL))
+	}
+
+	if err := c.PointsExpiry.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if c.MaxBatchSize < 1 {
+		errs = append(errs, fmt.Errorf("the maximum batch size must be at least 1, got %d", c.MaxBatchSize))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func isHTTPMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+
+	return false
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func parseDuration(value string, duration *time.Duration) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%q is not a duration, e.g. 30s or 1m", value)
+	}
+
+	*duration = d
+	return nil
+}
+
+func parseInt(value string, number *int) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("%q is not an integer", value)
+	}
+
+	*number = n
+	return nil
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/gin-gonic/gin"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	configFile := filepath.Join(dir, "config.yaml")
+	writeFile(t, configFile, "listenAddr: \":9000\"\nginMode: release\ncors:\n  origins: [https://example.com]\ntimeouts:\n  write: 5s\n")
+
+	jsonConfigFile := filepath.Join(dir, "config.json")
+	writeFile(t, jsonConfigFile, `{"maxBatchSize": 10, "storage": {"backend": "sqlite"}}`)
+
+	unknownKeyFile := filepath.Join(dir, "unknown.yaml")
+	writeFile(t, unknownKeyFile, "listenAdress: \":9000\"\n")
+
+	testCases := []struct {
+		name string
+
+		args []string
+		env  map[string]string
+
+		want    func() Config
+		wantErr bool
+	}{
+		{
+			name: "should use the default values",
+
+			want: Default,
+		},
+		{
+			name: "should give precedence to flags over environment over config file",
+
+			args: []string{"-config", configFile, "-gin-mode", "test"},
+			env: map[string]string{
+				"RECEIPT_PROCESSOR_GIN_MODE":     "debug",
+				"RECEIPT_PROCESSOR_CORS_METHODS": "GET, POST, PUT",
+				"RECEIPT_PROCESSOR_IDLE_TIMEOUT": "2m",
+			},
+
+			want: func() Config {
+				config := Default()
+				config.ListenAddr = ":9000"
+				config.GinMode = gin.TestMode
+				config.CORS.Origins = []string{"https://example.com"}
+				config.CORS.Methods = []string{"GET", "POST", "PUT"}
+				config.Timeouts.Write = 5 * time.Second
+				config.Timeouts.Idle = 2 * time.Minute
+				return config
+			},
+		},
+		{
+			name: "should read the config file from the environment",
+
+			env: map[string]string{"RECEIPT_PROCESSOR_CONFIG": jsonConfigFile},
+
+			want: func() Config {
+				config := Default()
+				config.MaxBatchSize = 10
+				config.Storage.Backend = "sqlite"
+				return config
+			},
+		},
+		{
+			name: "should fail due unknown key in config file",
+
+			args: []string{"-config", unknownKeyFile},
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due invalid duration",
+
+			env: map[string]string{"RECEIPT_PROCESSOR_READ_TIMEOUT": "10"},
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due invalid settings",
+
+			args: []string{"-listen-addr", "8080", "-storage", "postgres", "-max-batch-size", "0"},
+
+			wantErr: true,
+		},
+		{
+			name: "should set the scoring mode",
+
+			env: map[string]string{"RECEIPT_PROCESSOR_SCORING_MODE": "eager"},
+
+			want: func() Config {
+				config := Default()
+				config.Scoring.Mode = ScoringModeEager
+				return config
+			},
+		},
+		{
+			name: "should fail due unknown scoring mode",
+
+			args: []string{"-scoring-mode", "never"},
+
+			wantErr: true,
+		},
+		{
+			name: "should set the redemption reservation TTL",
+
+			args: []string{"-redemption-reservation-ttl", "5m"},
+
+			want: func() Config {
+				config := Default()
+				config.Redemption.ReservationTTL = 5 * time.Minute
+				return config
+			},
+		},
+		{
+			name: "should fail due non-positive redemption reservation TTL",
+
+			env: map[string]string{"RECEIPT_PROCESSOR_REDEMPTION_RESERVATION_TTL": "0s"},
+
+			wantErr: true,
+		},
+		{
+			name: "should set the points expiry policy",
+
+			args: []string{"-points-expiry-policy", "after_purchase"},
+			env:  map[string]string{"RECEIPT_PROCESSOR_POINTS_EXPIRY_MONTHS": "12"},
+
+			want: func() Config {
+				config := Default()
+				config.PointsExpiry = entity.ExpiryPolicy{Type: entity.ExpiryPolicyAfterPurchase, Months: 12}
+				return config
+			},
+		},
+		{
+			name: "should fail due points expiry policy without months",
+
+			args: []string{"-points-expiry-policy", "after_purchase"},
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due missing rules file",
+
+			args: []string{"-rules-file", filepath.Join(dir, "rules.yaml")},
+
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			getenv := func(key string) string { return tc.env[key] }
+
+			got, err := Load(tc.args, getenv, io.Discard)
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Load() = %v, want error %v", err, tc.wantErr)
+			}
+
+			if tc.wantErr {
+				return
+			}
+
+			if want := tc.want(); !reflect.DeepEqual(got, want) {
+				t.Errorf("Load() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadExampleFile(t *testing.T) {
+	// The rules file of the example is relative to the root of the project.
+	rulesFile := "../../../rules.example.yaml"
+
+	got, err := Load(
+		[]string{"-config", "../../../config.example.yaml", "-rules-file", rulesFile},
+		func(string) string { return "" },
+		io.Discard,
+	)
+	if err != nil {
+		t.Fatalf("Load() = %v, want nil", err)
+	}
+
+	want := Default()
+	want.GinMode = gin.ReleaseMode
+	want.RulesFile = rulesFile
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+}
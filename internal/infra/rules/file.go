@@ -0,0 +1,132 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/service/receipt"
+	"gopkg.in/yaml.v3"
+)
+
+// ruleSetsFile is the layout of a rules file with several rule sets. The
+// rules of every rule set are decoded on their own so the parameters missing
+// from the file keep their default values.
+type ruleSetsFile struct {
+	ActiveVersion string `json:"activeVersion"`
+	RuleSets      []struct {
+		Name    string          `json:"name"`
+		Version string          `json:"version"`
+		Rules   json.RawMessage `json:"rules"`
+	} `json:"ruleSets"`
+}
+
+// LoadFile reads the rule sets from a JSON or YAML file, chosen by its
+// extension. The file either lists several rule sets with the version of the
+// active one, or only contains the rules of the default rule set. Rules or
+// parameters missing from the file keep their default values.
+func LoadFile(path string) (entity.RuleSetsConfig, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return entity.RuleSetsConfig{}, fmt.Errorf("reading rules file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+
+	case ".yaml", ".yml":
+		// YAML files are converted to JSON so both formats are decoded the same way.
+		var document any
+		if err := yaml.Unmarshal(content, &document); err != nil {
+			return entity.RuleSetsConfig{}, fmt.Errorf("decoding rules file %s: %w", path, err)
+		}
+
+		if content, err = json.Marshal(document); err != nil {
+			return entity.RuleSetsConfig{}, fmt.Errorf("decoding rules file %s: %w", path, err)
+		}
+
+	default:
+		return entity.RuleSetsConfig{}, fmt.Errorf("rules file %s must be a .json, .yaml or .yml file", path)
+	}
+
+	config, err := decodeRuleSets(content)
+	if err != nil {
+		return entity.RuleSetsConfig{}, fmt.Errorf("decoding rules file %s: %w", path, err)
+	}
+
+	if err := receipt.ValidateRuleSets(config); err != nil {
+		return entity.RuleSetsConfig{}, fmt.Errorf("invalid rules in %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+func decodeRuleSets(content []byte) (entity.RuleSetsConfig, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return entity.RuleSetsConfig{}, err
+	}
+
+	// Files without rule sets only contain the rules of the default rule set.
+	if _, ok := fields["ruleSets"]; !ok {
+		rules, err := decodeRules(content)
+		if err != nil {
+			return entity.RuleSetsConfig{}, err
+		}
+
+		return receipt.SingleRuleSet(rules), nil
+	}
+
+	var file ruleSetsFile
+	if err := decodeStrict(content, &file); err != nil {
+		return entity.RuleSetsConfig{}, err
+	}
+
+	config := entity.RuleSetsConfig{
+		ActiveVersion: file.ActiveVersion,
+		RuleSets:      make([]entity.RuleSet, 0, len(file.RuleSets)),
+	}
+
+	for _, ruleSet := range file.RuleSets {
+		rules, err := decodeRules(ruleSet.Rules)
+		if err != nil {
+			return entity.RuleSetsConfig{}, fmt.Errorf("rule set version %q: %w", ruleSet.Version, err)
+		}
+
+		config.RuleSets = append(config.RuleSets, entity.RuleSet{
+			Name:    ruleSet.Name,
+			Version: ruleSet.Version,
+			Rules:   rules,
+		})
+	}
+
+	return config, nil
+}
+
+// decodeRules decodes the rules over the default ones.
+func decodeRules(content []byte) (entity.Rules, error) {
+	rules := receipt.DefaultRules()
+
+	if len(content) == 0 {
+		return rules, nil
+	}
+
+	if err := decodeStrict(content, &rules); err != nil {
+		return entity.Rules{}, err
+	}
+
+	return rules, nil
+}
+
+// decodeStrict decodes JSON content failing on unknown fields, so typos in the
+// rules file are not silently ignored.
+func decodeStrict(content []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(v)
+}
@@ -0,0 +1,157 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/service/receipt"
+)
+
+func TestLoadFile(t *testing.T) {
+	tunedRules := receipt.DefaultRules()
+	tunedRules.TotalRounded.Points = 100
+	tunedRules.PurchaseTime = entity.PurchaseTimeRule{Points: 20, StartHour: 12, EndHour: 13}
+
+	testCases := []struct {
+		name string
+
+		fileName string
+		content  string
+
+		want    entity.RuleSetsConfig
+		wantErr bool
+	}{
+		{
+			name: "should load rules from a YAML file keeping missing ones as default",
+
+			fileName: "rules.yaml",
+			content: `
+totalRounded:
+  points: 100
+purchaseTime:
+  points: 20
+  startHour: 12
+  endHour: 13
+`,
+
+			want: receipt.SingleRuleSet(tunedRules),
+		},
+		{
+			name: "should load rules from a JSON file keeping missing ones as default",
+
+			fileName: "rules.json",
+			content:  `{"totalRounded": {"points": 100}, "purchaseTime": {"points": 20, "startHour": 12, "endHour": 13}}`,
+
+			want: receipt.SingleRuleSet(tunedRules),
+		},
+		{
+			name: "should load versioned rule sets from a YAML file",
+
+			fileName: "rules.yml",
+			content: `
+activeVersion: "2"
+ruleSets:
+  - name: default
+    version: "1"
+  - name: happy-hour
+    version: "2"
+    rules:
+      totalRounded:
+        points: 100
+      purchaseTime:
+        points: 20
+        startHour: 12
+        endHour: 13
+`,
+
+			want: entity.RuleSetsConfig{
+				ActiveVersion: "2",
+				RuleSets: []entity.RuleSet{
+					{Name: "default", Version: "1", Rules: receipt.DefaultRules()},
+					{Name: "happy-hour", Version: "2", Rules: tunedRules},
+				},
+			},
+		},
+		{
+			name: "should load versioned rule sets from a JSON file",
+
+			fileName: "rules.json",
+			content:  `{"activeVersion": "1", "ruleSets": [{"name": "default", "version": "1"}]}`,
+
+			want: receipt.DefaultRuleSets(),
+		},
+		{
+			name: "should fail due unknown rule",
+
+			fileName: "rules.yaml",
+			content:  "unknownRule:\n  points: 1\n",
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due invalid rule parameters",
+
+			fileName: "rules.json",
+			content:  `{"itemDescriptions": {"lengthMultiple": 0}}`,
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due duplicated rule set versions",
+
+			fileName: "rules.json",
+			content:  `{"activeVersion": "1", "ruleSets": [{"version": "1"}, {"version": "1"}]}`,
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due unknown active version",
+
+			fileName: "rules.json",
+			content:  `{"activeVersion": "2", "ruleSets": [{"version": "1"}]}`,
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due unsupported file extension",
+
+			fileName: "rules.toml",
+			content:  "",
+
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.fileName)
+			if err := os.WriteFile(path, []byte(tc.content), 0o600); err != nil {
+				t.Fatalf("WriteFile() = error %v", err)
+			}
+
+			got, err := LoadFile(path)
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("LoadFile() = %v, want %v", got, tc.want)
+			}
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("LoadFile() = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadExampleFile(t *testing.T) {
+	got, err := LoadFile(filepath.Join("..", "..", "..", "rules.example.yaml"))
+	if err != nil {
+		t.Fatalf("LoadFile() = error %v", err)
+	}
+
+	if got.ActiveVersion != "1" || len(got.RuleSets) == 0 || !reflect.DeepEqual(got.RuleSets[0].Rules, receipt.DefaultRules()) {
+		t.Errorf("LoadFile() = %v, want the default rules as active rule set", got)
+	}
+}
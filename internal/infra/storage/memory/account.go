@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// receiptCredit identifies the points credited to an account for a receipt.
+type receiptCredit struct {
+	accountID string
+	receiptID string
+}
+
+// accountRepository keeps the loyalty accounts and their points ledger in
+// memory. It is safe for concurrent use.
+type accountRepository struct {
+	mu sync.RWMutex
+
+	accountByID map[string]entity.Account
+
+	// Transactions in the order they were posted, their sequence number is
+	// their position as they are never removed.
+	transactions             []entity.LedgerTransaction
+	transactionSeqsByAccount map[string][]int
+	balanceByLedgerAccount   map[string]int64
+	creditedByReceipt        map[receiptCredit]int64
+}
+
+// NewAccountRepository creates a new in-memory account repository.
+func NewAccountRepository() *accountRepository {
+	return &accountRepository{
+		accountByID:              make(map[string]entity.Account),
+		transactionSeqsByAccount: make(map[string][]int),
+		balanceByLedgerAccount:   make(map[string]int64),
+		creditedByReceipt:        make(map[receiptCredit]int64),
+	}
+}
+
+// SaveAccount stores a loyalty account.
+func (ar *accountRepository) SaveAccount(ctx context.Context, account entity.Account) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	ar.accountByID[account.ID] = account
+
+	return nil
+}
+
+// GetAccount gets a loyalty account by its ID.
+func (ar *accountRepository) GetAccount(ctx context.Context, accountID string) (entity.Account, error) {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	account, ok := ar.accountByID[accountID]
+	if !ok {
+		return entity.Account{}, entity.ErrAccountNotFound
+	}
+
+	return account, nil
+}
+
+// SettleReceiptPoints posts a transaction bringing the points credited to the
+// account for the receipt to the given points.
+func (ar *accountRepository) SettleReceiptPoints(ctx context.Context, transactionID, accountID, receiptID string, points int64, at time.Time) (entity.LedgerTransaction, bool, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if _, ok := ar.accountByID[accountID]; !ok {
+		return entity.LedgerTransaction{}, false, entity.ErrAccountNotFound
+	}
+
+	key := receiptCredit{accountID: accountID, receiptID: receiptID}
+	credited, settled := ar.creditedByReceipt[key]
+	if settled && credited == points {
+		return entity.LedgerTransaction{}, false, nil
+	}
+
+	transactionType := entity.TransactionTypeEarning
+	if settled {
+		transactionType = entity.TransactionTypeAdjustment
+	}
+
+	transaction := entity.NewLedgerTransaction(transactionID, accountID, transactionType, receiptID, points-credited, at)
+	ar.post(transaction)
+	ar.creditedByReceipt[key] = points
+
+	return cloneTransaction(transaction), true, nil
+}
+
+// PostRedemption posts a redemption transaction if the balance of the account
+// is enough.
+func (ar *accountRepository) PostRedemption(ctx context.Context, transaction entity.LedgerTransaction) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if _, ok := ar.accountByID[transaction.AccountID]; !ok {
+		return entity.ErrAccountNotFound
+	}
+
+	if ar.balanceByLedgerAccount[entity.AccountLedgerAccount(transaction.AccountID)]+transaction.Amount < 0 {
+		return entity.ErrInsufficientPoints
+	}
+
+	ar.post(cloneTransaction(transaction))
+
+	return nil
+}
+
+// post appends a transaction to the ledger and updates the balances of its
+// ledger accounts. The caller must hold the lock.
+func (ar *accountRepository) post(transaction entity.LedgerTransaction) {
+	ar.transactionSeqsByAccount[transaction.AccountID] = append(
+		ar.transactionSeqsByAccount[transaction.AccountID], len(ar.transactions),
+	)
+	ar.transactions = append(ar.transactions, transaction)
+
+	for _, entry := range transaction.Entries {
+		ar.balanceByLedgerAccount[entry.LedgerAccount] += entry.Credit - entry.Debit
+	}
+}
+
+// GetBalance gets the points held by the account.
+func (ar *accountRepository) GetBalance(ctx context.Context, accountID string) (int64, error) {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	if _, ok := ar.accountByID[accountID]; !ok {
+		return 0, entity.ErrAccountNotFound
+	}
+
+	return ar.balanceByLedgerAccount[entity.AccountLedgerAccount(accountID)], nil
+}
+
+// ListTransactions lists a page of the transactions of the account, newest
+// first. A limit of zero lists all of them.
+func (ar *accountRepository) ListTransactions(ctx context.Context, accountID, cursor string, limit int) (entity.LedgerPage, error) {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	if _, ok := ar.accountByID[accountID]; !ok {
+		return entity.LedgerPage{}, entity.ErrAccountNotFound
+	}
+
+	seqs := ar.transactionSeqsByAccount[accountID]
+
+	end := len(seqs)
+	if cursor != "" {
+		after, err := entity.DecodeSeqCursor(cursor)
+		if err != nil {
+			return entity.LedgerPage{}, err
+		}
+
+		// The transactions older than the cursor are the ones before it.
+		end = 0
+		for end < len(seqs) && int64(seqs[end]) < after {
+			end++
+		}
+	}
+
+	page := entity.LedgerPage{Transactions: []entity.LedgerTransaction{}}
+	for i := end - 1; i >= 0; i-- {
+		if limit > 0 && len(page.Transactions) == limit {
+			page.NextCursor = entity.EncodeSeqCursor(int64(seqs[i+1]))
+			break
+		}
+
+		page.Transactions = append(page.Transactions, cloneTransaction(ar.transactions[seqs[i]]))
+	}
+
+	return page, nil
+}
+
+// cloneTransaction copies the entries of a transaction so the stored one
+// can't be modified.
+func cloneTransaction(transaction entity.LedgerTransaction) entity.LedgerTransaction {
+	if transaction.Entries != nil {
+		entries := make([]entity.LedgerEntry, len(transaction.Entries))
+		copy(entries, transaction.Entries)
+		transaction.Entries = entries
+	}
+
+	return transaction
+}
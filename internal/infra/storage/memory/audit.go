@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// receiptAuditRepository keeps the audit log of the receipts in memory. It is
+// safe for concurrent use.
+type receiptAuditRepository struct {
+	mu sync.RWMutex
+
+	receipts *receiptRepository
+
+	entriesByReceiptID map[string][]entity.ReceiptAuditEntry
+}
+
+// NewReceiptAuditRepository creates a new in-memory receipt audit repository
+// for the receipts of the given repository.
+func NewReceiptAuditRepository(receipts *receiptRepository) *receiptAuditRepository {
+	return &receiptAuditRepository{
+		receipts:           receipts,
+		entriesByReceiptID: make(map[string][]entity.ReceiptAuditEntry),
+	}
+}
+
+// AppendReceiptAuditEntry appends an entry to the audit log of its receipt.
+func (ar *receiptAuditRepository) AppendReceiptAuditEntry(ctx context.Context, entry entity.ReceiptAuditEntry) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	ar.entriesByReceiptID[entry.ReceiptID] = append(ar.entriesByReceiptID[entry.ReceiptID], cloneAuditEntry(entry))
+
+	return nil
+}
+
+// AmendReceipt stores an amended receipt with its points and appends the
+// entry of the amendment to its audit log.
+func (ar *receiptAuditRepository) AmendReceipt(ctx context.Context, record entity.ReceiptRecord, points entity.ReceiptPoints, entry entity.ReceiptAuditEntry) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	ar.receipts.amendReceipt(record, points)
+	ar.entriesByReceiptID[entry.ReceiptID] = append(ar.entriesByReceiptID[entry.ReceiptID], cloneAuditEntry(entry))
+
+	return nil
+}
+
+// DeleteReceipt deletes a receipt and appends the entry of the deletion to
+// its audit log, only if the receipt is deleted.
+func (ar *receiptAuditRepository) DeleteReceipt(ctx context.Context, receiptID string, entry entity.ReceiptAuditEntry) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.receipts.DeleteReceipt(ctx, receiptID); err != nil {
+		return err
+	}
+
+	ar.entriesByReceiptID[entry.ReceiptID] = append(ar.entriesByReceiptID[entry.ReceiptID], cloneAuditEntry(entry))
+
+	return nil
+}
+
+// ListReceiptAuditEntries lists the audit entries of a receipt in the order
+// they were appended.
+func (ar *receiptAuditRepository) ListReceiptAuditEntries(ctx context.Context, receiptID string) ([]entity.ReceiptAuditEntry, error) {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	stored := ar.entriesByReceiptID[receiptID]
+
+	entries := make([]entity.ReceiptAuditEntry, 0, len(stored))
+	for _, entry := range stored {
+		entries = append(entries, cloneAuditEntry(entry))
+	}
+
+	return entries, nil
+}
+
+// cloneAuditEntry copies the receipts and points of an entry so the stored
+// one can't be modified.
+func cloneAuditEntry(entry entity.ReceiptAuditEntry) entity.ReceiptAuditEntry {
+	if entry.Previous != nil {
+		previous := cloneRecord(entity.ReceiptRecord{Receipt: *entry.Previous}).Receipt
+		entry.Previous = &previous
+	}
+	if entry.New != nil {
+		updated := cloneRecord(entity.ReceiptRecord{Receipt: *entry.New}).Receipt
+		entry.New = &updated
+	}
+	if entry.PreviousPoints != nil {
+		previousPoints := *entry.PreviousPoints
+		entry.PreviousPoints = &previousPoints
+	}
+	if entry.NewPoints != nil {
+		newPoints := *entry.NewPoints
+		entry.NewPoints = &newPoints
+	}
+
+	return entry
+}
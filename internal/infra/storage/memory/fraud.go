@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// fraudRepository keeps the fingerprints and screenings of the receipts in
+// memory. It is safe for concurrent use.
+type fraudRepository struct {
+	mu sync.RWMutex
+
+	receipts *receiptRepository
+
+	fingerprints         []entity.ReceiptFingerprint // Keeps the insertion order for matching.
+	screeningByReceiptID map[string]entity.FraudScreening
+}
+
+// NewFraudRepository creates a new in-memory fraud repository for the
+// receipts of the given repository. The fingerprints and screenings of the
+// receipts are deleted along with them.
+func NewFraudRepository(receipts *receiptRepository) *fraudRepository {
+	fr := &fraudRepository{
+		receipts:             receipts,
+		screeningByReceiptID: make(map[string]entity.FraudScreening),
+	}
+
+	receipts.onDelete = append(receipts.onDelete, fr.deleteReceipt)
+
+	return fr
+}
+
+// SaveFingerprint stores the fingerprint of a receipt and returns the IDs of
+// the receipts saved before with the same exact and partial fingerprints,
+// in the order they were saved. When it's saved again, the receipts
+// fingerprinted after it first was don't match, so it isn't taken as a
+// duplicate of its own copies.
+func (fr *fraudRepository) SaveFingerprint(ctx context.Context, fingerprint entity.ReceiptFingerprint) ([]string, []string, error) {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	if !fr.receipts.receiptExists(fingerprint.ReceiptID) {
+		return nil, nil, entity.ErrReceiptNotFound
+	}
+
+	var exactMatches, partialMatches []string
+
+	for i, saved := range fr.fingerprints {
+		if saved.ReceiptID == fingerprint.ReceiptID {
+			fr.fingerprints[i] = fingerprint
+			return exactMatches, partialMatches, nil
+		}
+
+		if saved.Exact == fingerprint.Exact {
+			exactMatches = append(exactMatches, saved.ReceiptID)
+		}
+		if saved.Partial == fingerprint.Partial {
+			partialMatches = append(partialMatches, saved.ReceiptID)
+		}
+	}
+
+	fr.fingerprints = append(fr.fingerprints, fingerprint)
+
+	return exactMatches, partialMatches, nil
+}
+
+// SaveScreening stores the screening of a receipt, replacing the previous one.
+func (fr *fraudRepository) SaveScreening(ctx context.Context, receiptID string, screening entity.FraudScreening) error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	if !fr.receipts.receiptExists(receiptID) {
+		return entity.ErrReceiptNotFound
+	}
+
+	fr.screeningByReceiptID[receiptID] = screening
+
+	return nil
+}
+
+// GetScreening gets the screening of a receipt, if it was screened.
+func (fr *fraudRepository) GetScreening(ctx context.Context, receiptID string) (entity.FraudScreening, bool, error) {
+	fr.mu.RLock()
+	defer fr.mu.RUnlock()
+
+	screening, ok := fr.screeningByReceiptID[receiptID]
+
+	return screening, ok, nil
+}
+
+// deleteReceipt deletes the fingerprint and screening of a deleted receipt.
+func (fr *fraudRepository) deleteReceipt(receiptID string) {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	delete(fr.screeningByReceiptID, receiptID)
+
+	for i, saved := range fr.fingerprints {
+		if saved.ReceiptID == receiptID {
+			fr.fingerprints = append(fr.fingerprints[:i], fr.fingerprints[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// idempotencyRepository keeps the idempotency records in memory. It is safe
+// for concurrent use.
+type idempotencyRepository struct {
+	mu sync.Mutex
+
+	recordByKey map[string]entity.IdempotencyRecord
+}
+
+// NewIdempotencyRepository creates a new in-memory idempotency repository.
+func NewIdempotencyRepository() *idempotencyRepository {
+	return &idempotencyRepository{
+		recordByKey: make(map[string]entity.IdempotencyRecord),
+	}
+}
+
+// SaveIdempotencyRecord saves the record unless there is an unexpired one with
+// the same key, in which case it returns the existing record and false.
+func (ir *idempotencyRepository) SaveIdempotencyRecord(ctx context.Context, record entity.IdempotencyRecord) (entity.IdempotencyRecord, bool, error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	if existing, ok := ir.recordByKey[record.Key]; ok && !existing.Expired(record.CreatedAt) {
+		return existing, false, nil
+	}
+
+	ir.recordByKey[record.Key] = record
+
+	return record, true, nil
+}
+
+// CompleteIdempotencyRecord marks the record with the given key as completed,
+// if there is one.
+func (ir *idempotencyRepository) CompleteIdempotencyRecord(ctx context.Context, key string) error {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	if record, ok := ir.recordByKey[key]; ok {
+		record.Completed = true
+		ir.recordByKey[key] = record
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyRecord deletes the record with the given key, if any.
+func (ir *idempotencyRepository) DeleteIdempotencyRecord(ctx context.Context, key string) error {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	delete(ir.recordByKey, key)
+
+	return nil
+}
+
+// DeleteReceiptIdempotencyRecord deletes the record with the given key if
+// it's of the given receipt.
+func (ir *idempotencyRepository) DeleteReceiptIdempotencyRecord(ctx context.Context, key, receiptID string) error {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	if record, ok := ir.recordByKey[key]; ok && record.ReceiptID == receiptID {
+		delete(ir.recordByKey, key)
+	}
+
+	return nil
+}
+
+// DeleteExpiredIdempotencyRecords deletes the records expired at the given
+// time and returns how many were deleted.
+func (ir *idempotencyRepository) DeleteExpiredIdempotencyRecords(ctx context.Context, now time.Time) (int64, error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	var deleted int64
+	for key, record := range ir.recordByKey {
+		if record.Expired(now) {
+			delete(ir.recordByKey, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
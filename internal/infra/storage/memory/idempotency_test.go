@@ -0,0 +1,209 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+func TestSaveIdempotencyRecord(t *testing.T) {
+	now := time.Date(2022, 1, 1, 13, 0, 0, 0, time.UTC)
+
+	storedRecord := entity.IdempotencyRecord{
+		Key:         "key:1",
+		RequestHash: "hash",
+		ReceiptID:   "1234567890",
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name string
+		ctx  context.Context
+
+		record entity.IdempotencyRecord
+
+		want      entity.IdempotencyRecord
+		wantSaved bool
+	}{
+		{
+			name: "should save a record with a new key",
+			ctx:  context.Background(),
+
+			record: entity.IdempotencyRecord{Key: "key:2", ReceiptID: "2", CreatedAt: now, ExpiresAt: now.Add(time.Hour)},
+
+			want:      entity.IdempotencyRecord{Key: "key:2", ReceiptID: "2", CreatedAt: now, ExpiresAt: now.Add(time.Hour)},
+			wantSaved: true,
+		},
+		{
+			name: "should return the unexpired record with the same key",
+			ctx:  context.Background(),
+
+			record: entity.IdempotencyRecord{Key: "key:1", ReceiptID: "2", CreatedAt: now.Add(time.Minute)},
+
+			want:      storedRecord,
+			wantSaved: false,
+		},
+		{
+			name: "should replace the expired record with the same key",
+			ctx:  context.Background(),
+
+			record: entity.IdempotencyRecord{Key: "key:1", ReceiptID: "2", CreatedAt: now.Add(time.Hour)},
+
+			want:      entity.IdempotencyRecord{Key: "key:1", ReceiptID: "2", CreatedAt: now.Add(time.Hour)},
+			wantSaved: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		repository := NewIdempotencyRepository()
+
+		if _, _, err := repository.SaveIdempotencyRecord(tc.ctx, storedRecord); err != nil {
+			t.Fatalf("SaveIdempotencyRecord() = error %v", err)
+		}
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, saved, err := repository.SaveIdempotencyRecord(tc.ctx, tc.record)
+			if err != nil {
+				t.Errorf("SaveIdempotencyRecord() = error %v", err)
+			}
+
+			if got != tc.want {
+				t.Errorf("SaveIdempotencyRecord() = %v, want %v", got, tc.want)
+			}
+
+			if saved != tc.wantSaved {
+				t.Errorf("SaveIdempotencyRecord() saved = %v, want %v", saved, tc.wantSaved)
+			}
+		})
+	}
+}
+
+func TestDeleteExpiredIdempotencyRecords(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 1, 1, 13, 0, 0, 0, time.UTC)
+
+	repository := NewIdempotencyRepository()
+
+	for key, expiresAt := range map[string]time.Time{
+		"key:expired": now.Add(-time.Second),
+		"key:now":     now,
+		"key:valid":   now.Add(time.Second),
+	} {
+		record := entity.IdempotencyRecord{Key: key, CreatedAt: now.Add(-time.Hour), ExpiresAt: expiresAt}
+		if _, _, err := repository.SaveIdempotencyRecord(ctx, record); err != nil {
+			t.Fatalf("SaveIdempotencyRecord() = error %v", err)
+		}
+	}
+
+	got, err := repository.DeleteExpiredIdempotencyRecords(ctx, now)
+	if err != nil {
+		t.Fatalf("DeleteExpiredIdempotencyRecords() = error %v", err)
+	}
+
+	if got != 2 {
+		t.Errorf("DeleteExpiredIdempotencyRecords() = %v, want %v", got, 2)
+	}
+
+	if _, ok := repository.recordByKey["key:valid"]; !ok {
+		t.Errorf("DeleteExpiredIdempotencyRecords() deleted the unexpired record")
+	}
+}
+
+func TestSaveIdempotencyRecordConcurrently(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	repository := NewIdempotencyRepository()
+
+	const submissions = 50
+
+	var wg sync.WaitGroup
+	saved := make(chan bool, submissions)
+
+	for i := 0; i < submissions; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, ok, err := repository.SaveIdempotencyRecord(ctx, entity.IdempotencyRecord{
+				Key:       "key:retried",
+				CreatedAt: now,
+				ExpiresAt: now.Add(time.Hour),
+			})
+			if err != nil {
+				t.Errorf("SaveIdempotencyRecord() = error %v", err)
+			}
+			saved <- ok
+		}()
+	}
+
+	wg.Wait()
+	close(saved)
+
+	var got int
+	for ok := range saved {
+		if ok {
+			got++
+		}
+	}
+
+	if got != 1 {
+		t.Errorf("SaveIdempotencyRecord() saved %v records, want 1", got)
+	}
+}
+
+func TestDeleteReceiptIdempotencyRecord(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 1, 1, 13, 0, 0, 0, time.UTC)
+
+	repository := NewIdempotencyRepository()
+
+	record := entity.IdempotencyRecord{Key: "hash:a", ReceiptID: "1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	if _, _, err := repository.SaveIdempotencyRecord(ctx, record); err != nil {
+		t.Fatalf("SaveIdempotencyRecord() = error %v", err)
+	}
+
+	testCases := []struct {
+		name string
+
+		receiptID string
+
+		wantDeleted bool
+	}{
+		{
+			name: "should keep the record of another receipt",
+
+			receiptID: "2",
+		},
+		{
+			name: "should delete the record of the receipt",
+
+			receiptID: "1",
+
+			wantDeleted: true,
+		},
+	}
+
+	// Cases run in order, as the last one deletes the record.
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := repository.DeleteReceiptIdempotencyRecord(ctx, record.Key, tc.receiptID); err != nil {
+				t.Fatalf("DeleteReceiptIdempotencyRecord() = error %v", err)
+			}
+
+			// Saving the key again only succeeds once the record is deleted.
+			_, saved, err := repository.SaveIdempotencyRecord(ctx, entity.IdempotencyRecord{Key: record.Key, CreatedAt: now})
+			if err != nil {
+				t.Fatalf("SaveIdempotencyRecord() = error %v", err)
+			}
+
+			if saved != tc.wantDeleted {
+				t.Errorf("DeleteReceiptIdempotencyRecord() deleted = %v, want %v", saved, tc.wantDeleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// merchantRepository keeps the merchants and their aliases in memory. It is
+// safe for concurrent use.
+type merchantRepository struct {
+	mu sync.RWMutex
+
+	merchantByID         map[string]entity.Merchant
+	merchantIDs          []string // Keeps the creation order for listing.
+	merchantIDByRetailer map[string]string
+}
+
+// NewMerchantRepository creates a new in-memory merchant repository.
+func NewMerchantRepository() *merchantRepository {
+	return &merchantRepository{
+		merchantByID:         make(map[string]entity.Merchant),
+		merchantIDByRetailer: make(map[string]string),
+	}
+}
+
+// SaveMerchant stores a new merchant with its aliases.
+func (mr *merchantRepository) SaveMerchant(ctx context.Context, merchant entity.Merchant) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, alias := range merchant.Aliases {
+		if _, ok := mr.merchantIDByRetailer[entity.NormalizeRetailer(alias)]; ok {
+			return entity.ErrMerchantAliasTaken
+		}
+	}
+
+	// Aliases with the same normalized name are only kept once.
+	aliases := []string{}
+	for _, alias := range merchant.Aliases {
+		key := entity.NormalizeRetailer(alias)
+		if _, ok := mr.merchantIDByRetailer[key]; !ok {
+			mr.merchantIDByRetailer[key] = merchant.ID
+			aliases = append(aliases, alias)
+		}
+	}
+	merchant.Aliases = aliases
+
+	mr.merchantIDs = append(mr.merchantIDs, merchant.ID)
+	mr.merchantByID[merchant.ID] = cloneMerchant(merchant)
+
+	return nil
+}
+
+// GetMerchant gets a merchant by its ID.
+func (mr *merchantRepository) GetMerchant(ctx context.Context, merchantID string) (entity.Merchant, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	merchant, ok := mr.merchantByID[merchantID]
+	if !ok {
+		return entity.Merchant{}, entity.ErrMerchantNotFound
+	}
+
+	return cloneMerchant(merchant), nil
+}
+
+// ListMerchants lists the merchants in the order they were created.
+func (mr *merchantRepository) ListMerchants(ctx context.Context) ([]entity.Merchant, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	merchants := make([]entity.Merchant, 0, len(mr.merchantIDs))
+	for _, merchantID := range mr.merchantIDs {
+		merchants = append(merchants, cloneMerchant(mr.merchantByID[merchantID]))
+	}
+
+	return merchants, nil
+}
+
+// RenameMerchant changes the name of a merchant.
+func (mr *merchantRepository) RenameMerchant(ctx context.Context, merchantID, name string) (entity.Merchant, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	merchant, ok := mr.merchantByID[merchantID]
+	if !ok {
+		return entity.Merchant{}, entity.ErrMerchantNotFound
+	}
+
+	merchant.Name = name
+	mr.merchantByID[merchantID] = merchant
+
+	return cloneMerchant(merchant), nil
+}
+
+// DeleteMerchant deletes a merchant with its aliases.
+func (mr *merchantRepository) DeleteMerchant(ctx context.Context, merchantID string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	merchant, ok := mr.merchantByID[merchantID]
+	if !ok {
+		return entity.ErrMerchantNotFound
+	}
+
+	for _, alias := range merchant.Aliases {
+		delete(mr.merchantIDByRetailer, entity.NormalizeRetailer(alias))
+	}
+	delete(mr.merchantByID, merchantID)
+
+	for i, id := range mr.merchantIDs {
+		if id == merchantID {
+			mr.merchantIDs = append(mr.merchantIDs[:i], mr.merchantIDs[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// AddMerchantAlias adds an alias to a merchant.
+func (mr *merchantRepository) AddMerchantAlias(ctx context.Context, merchantID, alias string) (entity.Merchant, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	merchant, ok := mr.merchantByID[merchantID]
+	if !ok {
+		return entity.Merchant{}, entity.ErrMerchantNotFound
+	}
+
+	key := entity.NormalizeRetailer(alias)
+	if ownerID, ok := mr.merchantIDByRetailer[key]; ok {
+		if ownerID != merchantID {
+			return entity.Merchant{}, entity.ErrMerchantAliasTaken
+		}
+
+		return cloneMerchant(merchant), nil
+	}
+
+	mr.merchantIDByRetailer[key] = merchantID
+	merchant.Aliases = append(merchant.Aliases, alias)
+	mr.merchantByID[merchantID] = cloneMerchant(merchant)
+
+	return cloneMerchant(merchant), nil
+}
+
+// DeleteMerchantAlias removes the alias of a merchant with the same
+// normalized name.
+func (mr *merchantRepository) DeleteMerchantAlias(ctx context.Context, merchantID, alias string) (entity.Merchant, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	merchant, ok := mr.merchantByID[merchantID]
+	if !ok {
+		return entity.Merchant{}, entity.ErrMerchantNotFound
+	}
+
+	key := entity.NormalizeRetailer(alias)
+	if mr.merchantIDByRetailer[key] != merchantID {
+		return entity.Merchant{}, entity.ErrMerchantAliasNotFound
+	}
+
+	delete(mr.merchantIDByRetailer, key)
+
+	aliases := make([]string, 0, len(merchant.Aliases)-1)
+	for _, existing := range merchant.Aliases {
+		if entity.NormalizeRetailer(existing) != key {
+			aliases = append(aliases, existing)
+		}
+	}
+	merchant.Aliases = aliases
+	mr.merchantByID[merchantID] = merchant
+
+	return cloneMerchant(merchant), nil
+}
+
+// FindMerchantByRetailer returns the ID of the merchant with an alias of the
+// normalized retailer name.
+func (mr *merchantRepository) FindMerchantByRetailer(ctx context.Context, retailerKey string) (string, bool, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	merchantID, ok := mr.merchantIDByRetailer[retailerKey]
+
+	return merchantID, ok, nil
+}
+
+// cloneMerchant copies the aliases of a merchant so callers can't modify the
+// stored ones.
+func cloneMerchant(merchant entity.Merchant) entity.Merchant {
+	merchant.Aliases = append([]string{}, merchant.Aliases...)
+	return merchant
+}
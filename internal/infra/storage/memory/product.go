@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// productRepository keeps the catalogue of products in memory. It is safe for
+// concurrent use.
+type productRepository struct {
+	mu sync.RWMutex
+
+	productByID map[string]entity.Product
+	productIDs  []string // Keeps the creation order for listing.
+}
+
+// NewProductRepository creates a new in-memory product repository.
+func NewProductRepository() *productRepository {
+	return &productRepository{
+		productByID: make(map[string]entity.Product),
+	}
+}
+
+// SaveProduct stores a new product.
+func (pr *productRepository) SaveProduct(ctx context.Context, product entity.Product) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if _, ok := pr.productByID[product.ID]; !ok {
+		pr.productIDs = append(pr.productIDs, product.ID)
+	}
+	pr.productByID[product.ID] = cloneProduct(product)
+
+	return nil
+}
+
+// GetProduct gets a product by its ID.
+func (pr *productRepository) GetProduct(ctx context.Context, productID string) (entity.Product, error) {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+
+	product, ok := pr.productByID[productID]
+	if !ok {
+		return entity.Product{}, entity.ErrProductNotFound
+	}
+
+	return cloneProduct(product), nil
+}
+
+// ListProducts lists the products in the order they were created.
+func (pr *productRepository) ListProducts(ctx context.Context) ([]entity.Product, error) {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+
+	products := make([]entity.Product, 0, len(pr.productIDs))
+	for _, productID := range pr.productIDs {
+		products = append(products, cloneProduct(pr.productByID[productID]))
+	}
+
+	return products, nil
+}
+
+// UpdateProduct replaces a stored product, keeping its creation time.
+func (pr *productRepository) UpdateProduct(ctx context.Context, product entity.Product) (entity.Product, error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	stored, ok := pr.productByID[product.ID]
+	if !ok {
+		return entity.Product{}, entity.ErrProductNotFound
+	}
+
+	product.CreatedAt = stored.CreatedAt
+	pr.productByID[product.ID] = cloneProduct(product)
+
+	return cloneProduct(product), nil
+}
+
+// DeleteProduct deletes a product.
+func (pr *productRepository) DeleteProduct(ctx context.Context, productID string) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if _, ok := pr.productByID[productID]; !ok {
+		return entity.ErrProductNotFound
+	}
+
+	delete(pr.productByID, productID)
+
+	for i, id := range pr.productIDs {
+		if id == productID {
+			pr.productIDs = append(pr.productIDs[:i], pr.productIDs[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// cloneProduct copies the keywords of a product so callers can't modify the
+// stored ones.
+func cloneProduct(product entity.Product) entity.Product {
+	product.Keywords = append([]string{}, product.Keywords...)
+	return product
+}
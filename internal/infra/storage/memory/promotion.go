@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// promotionRepository keeps the promotions in memory. It is safe for
+// concurrent use.
+type promotionRepository struct {
+	mu sync.RWMutex
+
+	promotionByID map[string]entity.Promotion
+	promotionIDs  []string // Keeps the creation order for listing.
+}
+
+// NewPromotionRepository creates a new in-memory promotion repository.
+func NewPromotionRepository() *promotionRepository {
+	return &promotionRepository{
+		promotionByID: make(map[string]entity.Promotion),
+	}
+}
+
+// SavePromotion stores a new promotion.
+func (pr *promotionRepository) SavePromotion(ctx context.Context, promotion entity.Promotion) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if _, ok := pr.promotionByID[promotion.ID]; !ok {
+		pr.promotionIDs = append(pr.promotionIDs, promotion.ID)
+	}
+	pr.promotionByID[promotion.ID] = promotion
+
+	return nil
+}
+
+// GetPromotion gets a promotion by its ID.
+func (pr *promotionRepository) GetPromotion(ctx context.Context, promotionID string) (entity.Promotion, error) {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+
+	promotion, ok := pr.promotionByID[promotionID]
+	if !ok {
+		return entity.Promotion{}, entity.ErrPromotionNotFound
+	}
+
+	return promotion, nil
+}
+
+// ListPromotions lists the promotions in the order they were created.
+func (pr *promotionRepository) ListPromotions(ctx context.Context) ([]entity.Promotion, error) {
+	return pr.listPromotions(func(entity.Promotion) bool { return true }), nil
+}
+
+// ListActivePromotions lists the promotions whose window includes the
+// purchase date, in the order they were created.
+func (pr *promotionRepository) ListActivePromotions(ctx context.Context, purchaseDate string) ([]entity.Promotion, error) {
+	return pr.listPromotions(func(promotion entity.Promotion) bool {
+		return promotion.ActiveOn(purchaseDate)
+	}), nil
+}
+
+func (pr *promotionRepository) listPromotions(include func(entity.Promotion) bool) []entity.Promotion {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+
+	promotions := []entity.Promotion{}
+	for _, promotionID := range pr.promotionIDs {
+		if promotion := pr.promotionByID[promotionID]; include(promotion) {
+			promotions = append(promotions, promotion)
+		}
+	}
+
+	return promotions
+}
+
+// UpdatePromotion replaces a stored promotion, keeping its creation time.
+func (pr *promotionRepository) UpdatePromotion(ctx context.Context, promotion entity.Promotion) (entity.Promotion, error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	stored, ok := pr.promotionByID[promotion.ID]
+	if !ok {
+		return entity.Promotion{}, entity.ErrPromotionNotFound
+	}
+
+	promotion.CreatedAt = stored.CreatedAt
+	pr.promotionByID[promotion.ID] = promotion
+
+	return promotion, nil
+}
+
+// DeletePromotion deletes a promotion.
+func (pr *promotionRepository) DeletePromotion(ctx context.Context, promotionID string) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if _, ok := pr.promotionByID[promotionID]; !ok {
+		return entity.ErrPromotionNotFound
+	}
+
+	delete(pr.promotionByID, promotionID)
+
+	for i, id := range pr.promotionIDs {
+		if id == promotionID {
+			pr.promotionIDs = append(pr.promotionIDs[:i], pr.promotionIDs[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,328 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// receiptRepository keeps receipts and their points in memory. It is safe
+// for concurrent use.
+type receiptRepository struct {
+	mu sync.RWMutex
+
+	receiptByID       map[string]entity.ReceiptRecord
+	receiptPointsByID map[string]entity.ReceiptPoints
+	receiptIDs        []string // Keeps the insertion order for listing.
+
+	// Sequence numbers of the receipts in insertion order, which unlike their
+	// position don't change when receipts are deleted.
+	seqByID map[string]int64
+	nextSeq int64
+
+	// Called when a receipt is deleted, so the repositories referencing the
+	// receipts delete what belongs to it as the SQLite foreign keys do.
+	onDelete []func(receiptID string)
+}
+
+// NewReceiptRepository creates a new in-memory receipt repository.
+func NewReceiptRepository() *receiptRepository {
+	return &receiptRepository{
+		receiptByID:       make(map[string]entity.ReceiptRecord),
+		receiptPointsByID: make(map[string]entity.ReceiptPoints),
+		seqByID:           make(map[string]int64),
+	}
+}
+
+// SaveReceipt stores a receipt, replacing any receipt with the same ID but
+// keeping when and to which account it was submitted.
+func (rr *receiptRepository) SaveReceipt(ctx context.Context, record entity.ReceiptRecord) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	rr.saveReceipt(record)
+
+	return nil
+}
+
+// amendReceipt stores an amended receipt along with its points.
+func (rr *receiptRepository) amendReceipt(record entity.ReceiptRecord, points entity.ReceiptPoints) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	rr.saveReceipt(record)
+	rr.receiptPointsByID[record.ID] = points
+}
+
+// saveReceipt stores a receipt. The lock must be held.
+func (rr *receiptRepository) saveReceipt(record entity.ReceiptRecord) {
+	if existing, ok := rr.receiptByID[record.ID]; ok {
+		record.SubmittedAt = existing.SubmittedAt
+		record.AccountID = existing.AccountID
+	} else {
+		rr.receiptIDs = append(rr.receiptIDs, record.ID)
+		rr.seqByID[record.ID] = rr.nextSeq
+		rr.nextSeq++
+	}
+
+	rr.receiptByID[record.ID] = cloneRecord(record)
+}
+
+// GetReceiptByID gets a receipt by its ID.
+func (rr *receiptRepository) GetReceiptByID(ctx context.Context, receiptID string) (entity.ReceiptRecord, error) {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+
+	record, ok := rr.receiptByID[receiptID]
+	if !ok {
+		return entity.ReceiptRecord{}, entity.ErrReceiptNotFound
+	}
+
+	return cloneRecord(record), nil
+}
+
+// DeleteReceipt deletes a receipt with its points, and what the repositories
+// referencing the receipts keep of it.
+func (rr *receiptRepository) DeleteReceipt(ctx context.Context, receiptID string) error {
+	if err := rr.deleteReceipt(receiptID); err != nil {
+		return err
+	}
+
+	// The lock is already released, as the repositories referencing the
+	// receipts check they exist while holding their own locks.
+	for _, onDelete := range rr.onDelete {
+		onDelete(receiptID)
+	}
+
+	return nil
+}
+
+func (rr *receiptRepository) deleteReceipt(receiptID string) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	if _, ok := rr.receiptByID[receiptID]; !ok {
+		return entity.ErrReceiptNotFound
+	}
+
+	delete(rr.receiptByID, receiptID)
+	delete(rr.receiptPointsByID, receiptID)
+	delete(rr.seqByID, receiptID)
+
+	for i, id := range rr.receiptIDs {
+		if id == receiptID {
+			rr.receiptIDs = append(rr.receiptIDs[:i], rr.receiptIDs[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// receiptExists reports whether a receipt is stored.
+func (rr *receiptRepository) receiptExists(receiptID string) bool {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+
+	_, ok := rr.receiptByID[receiptID]
+
+	return ok
+}
+
+// SaveReceiptPoints stores the scoring state of a receipt.
+func (rr *receiptRepository) SaveReceiptPoints(ctx context.Context, receiptID string, points entity.ReceiptPoints) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	if _, ok := rr.receiptByID[receiptID]; !ok {
+		return entity.ErrReceiptNotFound
+	}
+
+	rr.receiptPointsByID[receiptID] = points
+
+	return nil
+}
+
+// ExpireReceiptPoints marks the points of a receipt expired at the given time
+// if they are still the given scored and unexpired ones, and reports whether
+// they were.
+func (rr *receiptRepository) ExpireReceiptPoints(ctx context.Context, receiptID string, points entity.ReceiptPoints, expiredAt time.Time) (bool, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	if _, ok := rr.receiptByID[receiptID]; !ok {
+		return false, entity.ErrReceiptNotFound
+	}
+
+	stored, ok := rr.receiptPointsByID[receiptID]
+	if !ok || !stored.Scored() || stored.ExpiredAt != nil || stored.Points != points.Points ||
+		!sameTime(stored.ScoredAt, points.ScoredAt) {
+		return false, nil
+	}
+
+	stored.ExpiredAt = &expiredAt
+	rr.receiptPointsByID[receiptID] = stored
+
+	return true, nil
+}
+
+// sameTime reports whether two optional times are both unset or equal.
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
+
+// GetReceiptPoints gets the scoring state of a receipt, which is pending if
+// its points weren't saved yet.
+func (rr *receiptRepository) GetReceiptPoints(ctx context.Context, receiptID string) (entity.ReceiptPoints, error) {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+
+	if _, ok := rr.receiptByID[receiptID]; !ok {
+		return entity.ReceiptPoints{}, entity.ErrReceiptNotFound
+	}
+
+	points, ok := rr.receiptPointsByID[receiptID]
+	if !ok {
+		return entity.ReceiptPoints{Status: entity.ScoreStatusPending}, nil
+	}
+
+	return points, nil
+}
+
+// ListReceipts lists all the stored receipts in the order they were saved.
+func (rr *receiptRepository) ListReceipts(ctx context.Context) ([]entity.ReceiptRecord, error) {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+
+	records := make([]entity.ReceiptRecord, 0, len(rr.receiptIDs))
+	for _, receiptID := range rr.receiptIDs {
+		records = append(records, cloneRecord(rr.receiptByID[receiptID]))
+	}
+
+	return records, nil
+}
+
+// QueryReceipts returns a page of the receipts matching the query.
+func (rr *receiptRepository) QueryReceipts(ctx context.Context, query entity.ReceiptQuery) (entity.ReceiptPage, error) {
+	var cursor *entity.ReceiptCursor
+	if query.Cursor != "" {
+		decoded, err := entity.DecodeReceiptCursor(query)
+		if err != nil {
+			return entity.ReceiptPage{}, err
+		}
+		cursor = &decoded
+	}
+
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+
+	type match struct {
+		entry   entity.ReceiptListEntry
+		sortKey int64
+		seq     int64
+	}
+
+	var matches []match
+	for _, receiptID := range rr.receiptIDs {
+		record := rr.receiptByID[receiptID]
+
+		points, ok := rr.receiptPointsByID[receiptID]
+		if !ok {
+			points = entity.ReceiptPoints{Status: entity.ScoreStatusPending}
+		}
+
+		if !query.Matches(record, points) {
+			continue
+		}
+
+		// Receipts are kept in the order they were submitted.
+		seq := rr.seqByID[receiptID]
+		sortKey := seq
+		if query.SortBy == entity.ReceiptSortPoints {
+			sortKey = entity.ReceiptPointsSortKey(points)
+		}
+
+		if cursor != nil && !cursor.After(sortKey, seq) {
+			continue
+		}
+
+		matches = append(matches, match{
+			entry:   entity.ReceiptListEntry{Record: record, Points: points},
+			sortKey: sortKey,
+			seq:     seq,
+		})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if query.Descending {
+			a, b = b, a
+		}
+		if a.sortKey != b.sortKey {
+			return a.sortKey < b.sortKey
+		}
+		return a.seq < b.seq
+	})
+
+	page := entity.ReceiptPage{Entries: []entity.ReceiptListEntry{}}
+	for i, m := range matches {
+		if query.Limit > 0 && i == query.Limit {
+			last := matches[i-1]
+			page.NextCursor = entity.ReceiptCursor{
+				SortBy:     query.SortBy,
+				Descending: query.Descending,
+				SortKey:    last.sortKey,
+				Seq:        last.seq,
+			}.Encode()
+			break
+		}
+
+		m.entry.Record = cloneRecord(m.entry.Record)
+		page.Entries = append(page.Entries, m.entry)
+	}
+
+	return page, nil
+}
+
+// AssignMerchant sets the merchant of the receipts whose normalized retailer
+// name is the given one.
+func (rr *receiptRepository) AssignMerchant(ctx context.Context, retailerKey, merchantID string) (int64, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	var assigned int64
+	for receiptID, record := range rr.receiptByID {
+		if entity.NormalizeRetailer(record.Receipt.Retailer) != retailerKey {
+			continue
+		}
+
+		record.MerchantID = merchantID
+		rr.receiptByID[receiptID] = record
+		assigned++
+	}
+
+	return assigned, nil
+}
+
+// cloneRecord copies the items of a record and their products so callers
+// can't modify the stored ones.
+func cloneRecord(record entity.ReceiptRecord) entity.ReceiptRecord {
+	if record.Receipt.Items != nil {
+		items := make([]entity.Item, len(record.Receipt.Items))
+		copy(items, record.Receipt.Items)
+		record.Receipt.Items = items
+	}
+
+	if record.ItemProductIDs != nil {
+		record.ItemProductIDs = append([]string{}, record.ItemProductIDs...)
+	}
+
+	return record
+}
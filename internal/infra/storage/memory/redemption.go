@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// redemptionRepository keeps the redemptions in memory. It is safe for
+// concurrent use.
+type redemptionRepository struct {
+	mu sync.RWMutex
+
+	redemptionByID         map[string]entity.Redemption
+	redemptionIDsByReceipt map[string][]string
+}
+
+// NewRedemptionRepository creates a new in-memory redemption repository.
+func NewRedemptionRepository() *redemptionRepository {
+	return &redemptionRepository{
+		redemptionByID:         make(map[string]entity.Redemption),
+		redemptionIDsByReceipt: make(map[string][]string),
+	}
+}
+
+// ReserveReceiptPoints allocates the points of the redemption from the points
+// of the receipts not held by other redemptions, and stores it.
+func (rr *redemptionRepository) ReserveReceiptPoints(ctx context.Context, redemption entity.Redemption, receiptPoints []entity.RedemptionAllocation) (entity.Redemption, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	available := make([]entity.RedemptionAllocation, len(receiptPoints))
+	for i, receipt := range receiptPoints {
+		available[i] = entity.RedemptionAllocation{
+			ReceiptID: receipt.ReceiptID,
+			Points:    receipt.Points - rr.heldPoints(receipt.ReceiptID, redemption.CreatedAt),
+		}
+	}
+
+	allocations, err := entity.AllocateRedemption(redemption.Points, available)
+	if err != nil {
+		return entity.Redemption{}, err
+	}
+
+	redemption.Allocations = allocations
+	redemption.Points = 0
+	for _, allocation := range allocations {
+		redemption.Points += allocation.Points
+		rr.redemptionIDsByReceipt[allocation.ReceiptID] = append(rr.redemptionIDsByReceipt[allocation.ReceiptID], redemption.ID)
+	}
+
+	rr.redemptionByID[redemption.ID] = cloneRedemption(redemption)
+
+	return redemption, nil
+}
+
+// heldPoints sums the points of the receipt held by the redemptions at the
+// given time. The caller must hold the lock.
+func (rr *redemptionRepository) heldPoints(receiptID string, now time.Time) int64 {
+	var held int64
+	for _, redemptionID := range rr.redemptionIDsByReceipt[receiptID] {
+		redemption := rr.redemptionByID[redemptionID]
+		if !redemption.Holds(now) {
+			continue
+		}
+
+		for _, allocation := range redemption.Allocations {
+			if allocation.ReceiptID == receiptID {
+				held += allocation.Points
+			}
+		}
+	}
+
+	return held
+}
+
+// GetRedemption gets a redemption by its ID.
+func (rr *redemptionRepository) GetRedemption(ctx context.Context, redemptionID string) (entity.Redemption, error) {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+
+	redemption, ok := rr.redemptionByID[redemptionID]
+	if !ok {
+		return entity.Redemption{}, entity.ErrRedemptionNotFound
+	}
+
+	return cloneRedemption(redemption), nil
+}
+
+// GetHeldPoints sums the points of the receipt held by the redemptions at the
+// given time.
+func (rr *redemptionRepository) GetHeldPoints(ctx context.Context, receiptID string, now time.Time) (int64, error) {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+
+	return rr.heldPoints(receiptID, now), nil
+}
+
+// ResolveRedemption changes a reserved redemption to the given status, or
+// marks it as expired if it expired at the given time.
+func (rr *redemptionRepository) ResolveRedemption(ctx context.Context, redemptionID, status string, at time.Time) (entity.Redemption, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	redemption, ok := rr.redemptionByID[redemptionID]
+	if !ok {
+		return entity.Redemption{}, entity.ErrRedemptionNotFound
+	}
+
+	if redemption.Status != entity.RedemptionStatusReserved {
+		return entity.Redemption{}, entity.ErrRedemptionResolved
+	}
+
+	if !redemption.Holds(at) {
+		rr.resolve(&redemption, entity.RedemptionStatusExpired, redemption.ExpiresAt)
+		return entity.Redemption{}, entity.ErrRedemptionResolved
+	}
+
+	rr.resolve(&redemption, status, at)
+
+	return cloneRedemption(redemption), nil
+}
+
+// ReopenRedemption changes a confirmed redemption back to reserved.
+func (rr *redemptionRepository) ReopenRedemption(ctx context.Context, redemptionID string) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	redemption, ok := rr.redemptionByID[redemptionID]
+	if !ok {
+		return entity.ErrRedemptionNotFound
+	}
+
+	if redemption.Status == entity.RedemptionStatusConfirmed {
+		redemption.Status = entity.RedemptionStatusReserved
+		redemption.ResolvedAt = nil
+		rr.redemptionByID[redemptionID] = redemption
+	}
+
+	return nil
+}
+
+// ExpireRedemptions marks the reserved redemptions expired at the given time.
+func (rr *redemptionRepository) ExpireRedemptions(ctx context.Context, now time.Time) (int64, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	var expired int64
+	for _, redemption := range rr.redemptionByID {
+		if redemption.Status == entity.RedemptionStatusReserved && !redemption.Holds(now) {
+			rr.resolve(&redemption, entity.RedemptionStatusExpired, redemption.ExpiresAt)
+			expired++
+		}
+	}
+
+	return expired, nil
+}
+
+// resolve stores the redemption with the status it was resolved with. The
+// caller must hold the lock.
+func (rr *redemptionRepository) resolve(redemption *entity.Redemption, status string, at time.Time) {
+	redemption.Status = status
+	redemption.ResolvedAt = &at
+
+	rr.redemptionByID[redemption.ID] = cloneRedemption(*redemption)
+}
+
+// cloneRedemption copies the allocations and resolution time of a redemption
+// so the stored one can't be modified.
+func cloneRedemption(redemption entity.Redemption) entity.Redemption {
+	if redemption.Allocations != nil {
+		allocations := make([]entity.RedemptionAllocation, len(redemption.Allocations))
+		copy(allocations, redemption.Allocations)
+		redemption.Allocations = allocations
+	}
+	if redemption.ResolvedAt != nil {
+		resolvedAt := *redemption.ResolvedAt
+		redemption.ResolvedAt = &resolvedAt
+	}
+
+	return redemption
+}
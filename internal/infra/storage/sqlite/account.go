@@ -0,0 +1,323 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// accountRepository keeps the loyalty accounts and their points ledger in a
+// SQLite database.
+type accountRepository struct {
+	db *sql.DB
+}
+
+// NewAccountRepository creates a new SQLite account repository.
+func NewAccountRepository(db *sql.DB) *accountRepository {
+	return &accountRepository{
+		db: db,
+	}
+}
+
+// SaveAccount stores a loyalty account.
+func (ar *accountRepository) SaveAccount(ctx context.Context, account entity.Account) error {
+	_, err := ar.db.ExecContext(ctx, `
+		INSERT INTO accounts (id, name, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET name = excluded.name`,
+		account.ID, account.Name, account.CreatedAt.UnixNano(),
+	)
+
+	return err
+}
+
+// GetAccount gets a loyalty account by its ID.
+func (ar *accountRepository) GetAccount(ctx context.Context, accountID string) (entity.Account, error) {
+	account := entity.Account{ID: accountID}
+	var createdAt int64
+
+	err := ar.db.QueryRowContext(ctx, `SELECT name, created_at FROM accounts WHERE id = ?`, accountID).
+		Scan(&account.Name, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Account{}, entity.ErrAccountNotFound
+	}
+	if err != nil {
+		return entity.Account{}, err
+	}
+
+	account.CreatedAt = timeFromNull(sql.NullInt64{Int64: createdAt, Valid: true})
+
+	return account, nil
+}
+
+// SettleReceiptPoints posts a transaction bringing the points credited to the
+// account for the receipt to the given points.
+func (ar *accountRepository) SettleReceiptPoints(ctx context.Context, transactionID, accountID, receiptID string, points int64, at time.Time) (entity.LedgerTransaction, bool, error) {
+	// Transactions take the write lock when they begin, so concurrent
+	// settlements see each other's transactions.
+	tx, err := ar.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.LedgerTransaction{}, false, err
+	}
+	defer tx.Rollback()
+
+	if err := accountExists(ctx, tx, accountID); err != nil {
+		return entity.LedgerTransaction{}, false, err
+	}
+
+	var settled int
+	var credited int64
+	if err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(amount), 0)
+		FROM ledger_transactions
+		WHERE account_id = ? AND receipt_id = ? AND type IN (?, ?)`,
+		accountID, receiptID, entity.TransactionTypeEarning, entity.TransactionTypeAdjustment,
+	).Scan(&settled, &credited); err != nil {
+		return entity.LedgerTransaction{}, false, err
+	}
+
+	if settled > 0 && credited == points {
+		return entity.LedgerTransaction{}, false, nil
+	}
+
+	transactionType := entity.TransactionTypeEarning
+	if settled > 0 {
+		transactionType = entity.TransactionTypeAdjustment
+	}
+
+	transaction := entity.NewLedgerTransaction(transactionID, accountID, transactionType, receiptID, points-credited, at)
+	if err := postTransaction(ctx, tx, transaction); err != nil {
+		return entity.LedgerTransaction{}, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.LedgerTransaction{}, false, err
+	}
+
+	return transaction, true, nil
+}
+
+// PostRedemption posts a redemption transaction if the balance of the account
+// is enough.
+func (ar *accountRepository) PostRedemption(ctx context.Context, transaction entity.LedgerTransaction) error {
+	tx, err := ar.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	balance, err := accountBalance(ctx, tx, transaction.AccountID)
+	if err != nil {
+		return err
+	}
+
+	if balance+transaction.Amount < 0 {
+		return entity.ErrInsufficientPoints
+	}
+
+	if err := postTransaction(ctx, tx, transaction); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetBalance gets the points held by the account.
+func (ar *accountRepository) GetBalance(ctx context.Context, accountID string) (int64, error) {
+	return accountBalance(ctx, ar.db, accountID)
+}
+
+// ListTransactions lists a page of the transactions of the account, newest
+// first. A limit of zero lists all of them.
+func (ar *accountRepository) ListTransactions(ctx context.Context, accountID, cursor string, limit int) (entity.LedgerPage, error) {
+	if _, err := ar.GetAccount(ctx, accountID); err != nil {
+		return entity.LedgerPage{}, err
+	}
+
+	statement := `
+		SELECT seq, id, type, receipt_id, description, amount, created_at
+		FROM ledger_transactions
+		WHERE account_id = ?`
+	args := []any{accountID}
+
+	if cursor != "" {
+		before, err := entity.DecodeSeqCursor(cursor)
+		if err != nil {
+			return entity.LedgerPage{}, err
+		}
+
+		statement += " AND seq < ?"
+		args = append(args, before)
+	}
+
+	statement += " ORDER BY seq DESC"
+
+	// One more transaction is fetched to know if there is a next page.
+	if limit > 0 {
+		statement += " LIMIT ?"
+		args = append(args, limit+1)
+	}
+
+	rows, err := ar.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return entity.LedgerPage{}, err
+	}
+	defer rows.Close()
+
+	page := entity.LedgerPage{Transactions: []entity.LedgerTransaction{}}
+	var lastSeq int64
+
+	for rows.Next() {
+		if limit > 0 && len(page.Transactions) == limit {
+			page.NextCursor = entity.EncodeSeqCursor(lastSeq)
+			break
+		}
+
+		transaction := entity.LedgerTransaction{AccountID: accountID}
+		var createdAt int64
+
+		if err := rows.Scan(
+			&lastSeq,
+			&transaction.ID,
+			&transaction.Type,
+			&transaction.ReceiptID,
+			&transaction.Description,
+			&transaction.Amount,
+			&createdAt,
+		); err != nil {
+			return entity.LedgerPage{}, err
+		}
+
+		transaction.CreatedAt = timeFromNull(sql.NullInt64{Int64: createdAt, Valid: true})
+
+		page.Transactions = append(page.Transactions, transaction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return entity.LedgerPage{}, err
+	}
+
+	if err := ar.getEntries(ctx, page.Transactions); err != nil {
+		return entity.LedgerPage{}, err
+	}
+
+	return page, nil
+}
+
+// getEntries gets the entries of the transactions.
+func (ar *accountRepository) getEntries(ctx context.Context, transactions []entity.LedgerTransaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(transactions))
+	transactionIDs := make([]any, len(transactions))
+	for i, transaction := range transactions {
+		placeholders[i] = "?"
+		transactionIDs[i] = transaction.ID
+	}
+
+	rows, err := ar.db.QueryContext(ctx, `
+		SELECT transaction_id, ledger_account, debit, credit
+		FROM ledger_entries
+		WHERE transaction_id IN (`+strings.Join(placeholders, ", ")+`)
+		ORDER BY transaction_id, position`,
+		transactionIDs...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	entriesByTransactionID := make(map[string][]entity.LedgerEntry)
+	for rows.Next() {
+		var transactionID string
+		var entry entity.LedgerEntry
+
+		if err := rows.Scan(&transactionID, &entry.LedgerAccount, &entry.Debit, &entry.Credit); err != nil {
+			return err
+		}
+
+		entriesByTransactionID[transactionID] = append(entriesByTransactionID[transactionID], entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range transactions {
+		transactions[i].Entries = entriesByTransactionID[transactions[i].ID]
+	}
+
+	return nil
+}
+
+// queryRower is implemented by both sql.DB and sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// accountExists returns entity.ErrAccountNotFound if there is no account with
+// the ID.
+func accountExists(ctx context.Context, q queryRower, accountID string) error {
+	var exists bool
+	if err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM accounts WHERE id = ?)`, accountID).
+		Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return entity.ErrAccountNotFound
+	}
+
+	return nil
+}
+
+// accountBalance sums the credits minus the debits of the ledger account of
+// the account.
+func accountBalance(ctx context.Context, q queryRower, accountID string) (int64, error) {
+	if err := accountExists(ctx, q, accountID); err != nil {
+		return 0, err
+	}
+
+	var balance int64
+	err := q.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(credit - debit), 0)
+		FROM ledger_entries
+		WHERE ledger_account = ?`,
+		entity.AccountLedgerAccount(accountID),
+	).Scan(&balance)
+
+	return balance, err
+}
+
+// postTransaction inserts a transaction with its entries.
+func postTransaction(ctx context.Context, tx *sql.Tx, transaction entity.LedgerTransaction) error {
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO ledger_transactions (id, account_id, type, receipt_id, description, amount, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		transaction.ID, transaction.AccountID, transaction.Type, transaction.ReceiptID,
+		transaction.Description, transaction.Amount, transaction.CreatedAt.UnixNano(),
+	); err != nil {
+		if isForeignKeyError(err) {
+			return entity.ErrAccountNotFound
+		}
+		return err
+	}
+
+	for position, entry := range transaction.Entries {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO ledger_entries (transaction_id, position, ledger_account, debit, credit)
+			VALUES (?, ?, ?, ?, ?)`,
+			transaction.ID, position, entry.LedgerAccount, entry.Debit, entry.Credit,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
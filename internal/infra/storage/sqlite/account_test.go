@@ -0,0 +1,204 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+func TestGetAccount(t *testing.T) {
+	ctx := context.Background()
+	repository := NewAccountRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+	account := entity.Account{ID: "1", Name: "Jane", CreatedAt: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+	if err := repository.SaveAccount(ctx, account); err != nil {
+		t.Fatalf("SaveAccount() = error %v", err)
+	}
+
+	got, err := repository.GetAccount(ctx, "1")
+	if err != nil {
+		t.Fatalf("GetAccount() = error %v", err)
+	}
+
+	if got != account {
+		t.Errorf("GetAccount() = %v, want %v", got, account)
+	}
+
+	if _, err := repository.GetAccount(ctx, "unknown"); !errors.Is(err, entity.ErrAccountNotFound) {
+		t.Errorf("GetAccount() error = %v, want %v", err, entity.ErrAccountNotFound)
+	}
+}
+
+func TestSettleReceiptPoints(t *testing.T) {
+	ctx := context.Background()
+	repository := NewAccountRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	if err := repository.SaveAccount(ctx, entity.Account{ID: "1", Name: "Jane", CreatedAt: at}); err != nil {
+		t.Fatalf("SaveAccount() = error %v", err)
+	}
+
+	steps := []struct {
+		name   string
+		points int64
+
+		wantPosted  bool
+		wantType    string
+		wantAmount  int64
+		wantBalance int64
+	}{
+		{name: "earning", points: 28, wantPosted: true, wantType: entity.TransactionTypeEarning, wantAmount: 28, wantBalance: 28},
+		{name: "same points", points: 28, wantBalance: 28},
+		{name: "fewer points", points: 10, wantPosted: true, wantType: entity.TransactionTypeAdjustment, wantAmount: -18, wantBalance: 10},
+		{name: "no points", points: 0, wantPosted: true, wantType: entity.TransactionTypeAdjustment, wantAmount: -10, wantBalance: 0},
+	}
+
+	for i, step := range steps {
+		got, posted, err := repository.SettleReceiptPoints(ctx, fmt.Sprint(i), "1", "receipt", step.points, at)
+		if err != nil {
+			t.Fatalf("SettleReceiptPoints(%s) = error %v", step.name, err)
+		}
+
+		if posted != step.wantPosted {
+			t.Errorf("SettleReceiptPoints(%s) posted = %v, want %v", step.name, posted, step.wantPosted)
+		}
+
+		if posted && (got.Type != step.wantType || got.Amount != step.wantAmount || !got.Balanced()) {
+			t.Errorf("SettleReceiptPoints(%s) = %v, want a balanced %s of %d", step.name, got, step.wantType, step.wantAmount)
+		}
+
+		balance, err := repository.GetBalance(ctx, "1")
+		if err != nil {
+			t.Fatalf("GetBalance() = error %v", err)
+		}
+
+		if balance != step.wantBalance {
+			t.Errorf("GetBalance() after %s = %d, want %d", step.name, balance, step.wantBalance)
+		}
+	}
+
+	if _, _, err := repository.SettleReceiptPoints(ctx, "tx", "unknown", "receipt", 28, at); !errors.Is(err, entity.ErrAccountNotFound) {
+		t.Errorf("SettleReceiptPoints() error = %v, want %v", err, entity.ErrAccountNotFound)
+	}
+}
+
+func TestPostRedemption(t *testing.T) {
+	ctx := context.Background()
+	repository := NewAccountRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	if err := repository.SaveAccount(ctx, entity.Account{ID: "1", Name: "Jane", CreatedAt: at}); err != nil {
+		t.Fatalf("SaveAccount() = error %v", err)
+	}
+
+	if _, _, err := repository.SettleReceiptPoints(ctx, "earning", "1", "receipt", 100, at); err != nil {
+		t.Fatalf("SettleReceiptPoints() = error %v", err)
+	}
+
+	// Only ten of the concurrent redemptions fit in the balance.
+	var wg sync.WaitGroup
+	errs := make([]error, 20)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			transaction := entity.NewLedgerTransaction(fmt.Sprint(i), "1", entity.TransactionTypeRedemption, "", -10, at)
+			errs[i] = repository.PostRedemption(ctx, transaction)
+		}(i)
+	}
+	wg.Wait()
+
+	var redeemed int
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			redeemed++
+		case !errors.Is(err, entity.ErrInsufficientPoints):
+			t.Fatalf("PostRedemption() = error %v", err)
+		}
+	}
+
+	if redeemed != 10 {
+		t.Errorf("PostRedemption() redeemed %d times, want 10", redeemed)
+	}
+
+	balance, err := repository.GetBalance(ctx, "1")
+	if err != nil {
+		t.Fatalf("GetBalance() = error %v", err)
+	}
+
+	if balance != 0 {
+		t.Errorf("GetBalance() = %d, want 0", balance)
+	}
+
+	transaction := entity.NewLedgerTransaction("unknown", "unknown", entity.TransactionTypeRedemption, "", -10, at)
+	if err := repository.PostRedemption(ctx, transaction); !errors.Is(err, entity.ErrAccountNotFound) {
+		t.Errorf("PostRedemption() error = %v, want %v", err, entity.ErrAccountNotFound)
+	}
+}
+
+func TestListTransactions(t *testing.T) {
+	ctx := context.Background()
+	repository := NewAccountRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, accountID := range []string{"1", "2"} {
+		if err := repository.SaveAccount(ctx, entity.Account{ID: accountID, Name: "Jane", CreatedAt: at}); err != nil {
+			t.Fatalf("SaveAccount() = error %v", err)
+		}
+	}
+
+	// The transactions of the other account are interleaved with the listed ones.
+	for i := 0; i < 5; i++ {
+		for _, accountID := range []string{"1", "2"} {
+			transactionID := fmt.Sprintf("%s-%d", accountID, i)
+			if _, _, err := repository.SettleReceiptPoints(ctx, transactionID, accountID, fmt.Sprint(i), int64(i+1), at.Add(time.Duration(i)*time.Minute)); err != nil {
+				t.Fatalf("SettleReceiptPoints() = error %v", err)
+			}
+		}
+	}
+
+	var got []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatalf("ListTransactions() didn't reach the last page")
+		}
+
+		page, err := repository.ListTransactions(ctx, "1", cursor, 2)
+		if err != nil {
+			t.Fatalf("ListTransactions() = error %v", err)
+		}
+
+		for _, transaction := range page.Transactions {
+			if len(transaction.Entries) != 2 {
+				t.Errorf("ListTransactions() entries of %s = %v, want 2", transaction.ID, transaction.Entries)
+			}
+			got = append(got, transaction.ID)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	want := []string{"1-4", "1-3", "1-2", "1-1", "1-0"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("ListTransactions() = %v, want %v", got, want)
+	}
+
+	if _, err := repository.ListTransactions(ctx, "1", "not a cursor!", 2); !errors.Is(err, entity.ErrInvalidCursor) {
+		t.Errorf("ListTransactions() error = %v, want %v", err, entity.ErrInvalidCursor)
+	}
+
+	if _, err := repository.ListTransactions(ctx, "unknown", "", 2); !errors.Is(err, entity.ErrAccountNotFound) {
+		t.Errorf("ListTransactions() error = %v, want %v", err, entity.ErrAccountNotFound)
+	}
+}
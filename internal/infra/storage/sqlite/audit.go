@@ -0,0 +1,189 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// receiptAuditRepository keeps the audit log of the receipts in a SQLite
+// database.
+type receiptAuditRepository struct {
+	db *sql.DB
+}
+
+// NewReceiptAuditRepository creates a new SQLite receipt audit repository.
+func NewReceiptAuditRepository(db *sql.DB) *receiptAuditRepository {
+	return &receiptAuditRepository{
+		db: db,
+	}
+}
+
+// AppendReceiptAuditEntry appends an entry to the audit log of its receipt.
+func (ar *receiptAuditRepository) AppendReceiptAuditEntry(ctx context.Context, entry entity.ReceiptAuditEntry) error {
+	return appendAuditEntry(ctx, ar.db, entry)
+}
+
+// AmendReceipt stores an amended receipt with its points and appends the
+// entry of the amendment to its audit log in the same transaction.
+func (ar *receiptAuditRepository) AmendReceipt(ctx context.Context, record entity.ReceiptRecord, points entity.ReceiptPoints, entry entity.ReceiptAuditEntry) error {
+	tx, err := ar.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := saveReceipt(ctx, tx, record); err != nil {
+		return err
+	}
+
+	if err := saveReceiptPoints(ctx, tx, record.ID, points); err != nil {
+		return err
+	}
+
+	if err := appendAuditEntry(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteReceipt deletes a receipt and appends the entry of the deletion to
+// its audit log in the same transaction.
+func (ar *receiptAuditRepository) DeleteReceipt(ctx context.Context, receiptID string, entry entity.ReceiptAuditEntry) error {
+	tx, err := ar.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := deleteReceipt(ctx, tx, receiptID); err != nil {
+		return err
+	}
+
+	if err := appendAuditEntry(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// appendAuditEntry appends an entry to the audit log of its receipt.
+func appendAuditEntry(ctx context.Context, db execer, entry entity.ReceiptAuditEntry) error {
+	previous, err := nullReceipt(entry.Previous)
+	if err != nil {
+		return err
+	}
+
+	updated, err := nullReceipt(entry.New)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO receipt_audit_entries
+			(receipt_id, action, actor, reason, at, previous, new, previous_points, new_points, points_delta)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.ReceiptID, entry.Action, entry.Actor, entry.Reason, entry.At.UnixNano(),
+		previous, updated, nullInt64(entry.PreviousPoints), nullInt64(entry.NewPoints), entry.PointsDelta,
+	)
+
+	return err
+}
+
+// ListReceiptAuditEntries lists the audit entries of a receipt in the order
+// they were appended.
+func (ar *receiptAuditRepository) ListReceiptAuditEntries(ctx context.Context, receiptID string) ([]entity.ReceiptAuditEntry, error) {
+	rows, err := ar.db.QueryContext(ctx, `
+		SELECT action, actor, reason, at, previous, new, previous_points, new_points, points_delta
+		FROM receipt_audit_entries
+		WHERE receipt_id = ?
+		ORDER BY seq`,
+		receiptID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []entity.ReceiptAuditEntry{}
+	for rows.Next() {
+		entry := entity.ReceiptAuditEntry{ReceiptID: receiptID}
+		var at int64
+		var previous, updated sql.NullString
+		var previousPoints, newPoints sql.NullInt64
+
+		if err := rows.Scan(
+			&entry.Action,
+			&entry.Actor,
+			&entry.Reason,
+			&at,
+			&previous,
+			&updated,
+			&previousPoints,
+			&newPoints,
+			&entry.PointsDelta,
+		); err != nil {
+			return nil, err
+		}
+
+		entry.At = timeFromNull(sql.NullInt64{Int64: at, Valid: true})
+
+		if entry.Previous, err = receiptFromNull(previous); err != nil {
+			return nil, err
+		}
+		if entry.New, err = receiptFromNull(updated); err != nil {
+			return nil, err
+		}
+
+		if previousPoints.Valid {
+			entry.PreviousPoints = &previousPoints.Int64
+		}
+		if newPoints.Valid {
+			entry.NewPoints = &newPoints.Int64
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// nullReceipt encodes a receipt as JSON, or NULL if there is none.
+func nullReceipt(receipt *entity.Receipt) (sql.NullString, error) {
+	if receipt == nil {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(receipt)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// receiptFromNull decodes a receipt encoded by nullReceipt.
+func receiptFromNull(value sql.NullString) (*entity.Receipt, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+
+	var receipt entity.Receipt
+	if err := json.Unmarshal([]byte(value.String), &receipt); err != nil {
+		return nil, err
+	}
+
+	return &receipt, nil
+}
+
+// nullInt64 stores an optional number, NULL if there is none.
+func nullInt64(value *int64) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: *value, Valid: true}
+}
@@ -0,0 +1,223 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+func TestReceiptAuditRepository(t *testing.T) {
+	ctx := context.Background()
+	repository := NewReceiptAuditRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	previousPoints := int64(28)
+	newPoints := int64(10)
+
+	previous := entity.Receipt{
+		Retailer: "Target",
+		Items:    []entity.Item{{ShortDescription: "Mountain Dew 12PK", Price: entity.MustParseMoney("6.49")}},
+		Total:    entity.MustParseMoney("6.49"),
+	}
+	amended := previous
+	amended.Retailer = "Walgreens"
+
+	entries := []entity.ReceiptAuditEntry{
+		{
+			ReceiptID:      "1",
+			Action:         entity.AuditActionAmended,
+			Actor:          "support",
+			Reason:         "typo in the retailer",
+			At:             at,
+			Previous:       &previous,
+			New:            &amended,
+			PreviousPoints: &previousPoints,
+			NewPoints:      &newPoints,
+			PointsDelta:    -18,
+		},
+		{
+			ReceiptID:   "2",
+			Action:      entity.AuditActionDeleted,
+			Actor:       "support",
+			At:          at,
+			Previous:    &previous,
+			PointsDelta: 0,
+		},
+		{
+			ReceiptID:      "1",
+			Action:         entity.AuditActionDeleted,
+			Actor:          "fraud-team",
+			At:             at.Add(time.Hour),
+			Previous:       &amended,
+			PreviousPoints: &newPoints,
+			PointsDelta:    -10,
+		},
+	}
+
+	for _, entry := range entries {
+		if err := repository.AppendReceiptAuditEntry(ctx, entry); err != nil {
+			t.Fatalf("AppendReceiptAuditEntry() = error %v", err)
+		}
+	}
+
+	testCases := []struct {
+		name string
+
+		receiptID string
+
+		want []entity.ReceiptAuditEntry
+	}{
+		{
+			name: "should list the entries of a receipt in order",
+
+			receiptID: "1",
+
+			want: []entity.ReceiptAuditEntry{entries[0], entries[2]},
+		},
+		{
+			name: "should list no entries for a receipt without changes",
+
+			receiptID: "3",
+
+			want: []entity.ReceiptAuditEntry{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := repository.ListReceiptAuditEntries(ctx, tc.receiptID)
+			if err != nil {
+				t.Fatalf("ListReceiptAuditEntries() = error %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ListReceiptAuditEntries() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAmendReceiptWithAuditEntry(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t, filepath.Join(t.TempDir(), "receipts.db"))
+
+	receiptRepository := NewReceiptRepository(db)
+	repository := NewReceiptAuditRepository(db)
+
+	scoredAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	points := entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 10, RuleSetVersion: "1", ScoredAt: &scoredAt}
+	amended := entity.Receipt{Retailer: "Walgreens", Total: entity.MustParseMoney("6.49")}
+
+	testCases := []struct {
+		name string
+
+		record entity.ReceiptRecord
+
+		wantErr     error
+		wantEntries int
+	}{
+		{
+			name: "should store the amendment with its points and entry",
+
+			record: entity.ReceiptRecord{ID: "1", Receipt: amended},
+
+			wantEntries: 1,
+		},
+		{
+			name: "should not append the entry due failing amendment",
+
+			record: entity.ReceiptRecord{ID: "2", Receipt: amended, AccountID: "unknown"},
+
+			wantErr: entity.ErrAccountNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entry := entity.ReceiptAuditEntry{ReceiptID: tc.record.ID, Action: entity.AuditActionAmended, Actor: "support", At: scoredAt, New: &amended}
+
+			if err := repository.AmendReceipt(ctx, tc.record, points, entry); err != tc.wantErr {
+				t.Fatalf("AmendReceipt() = %v, want %v", err, tc.wantErr)
+			}
+
+			entries, err := repository.ListReceiptAuditEntries(ctx, tc.record.ID)
+			if err != nil {
+				t.Fatalf("ListReceiptAuditEntries() = error %v", err)
+			}
+
+			if len(entries) != tc.wantEntries {
+				t.Errorf("AmendReceipt() entries = %v, want %v", len(entries), tc.wantEntries)
+			}
+
+			if tc.wantErr != nil {
+				if _, err := receiptRepository.GetReceiptByID(ctx, tc.record.ID); err != entity.ErrReceiptNotFound {
+					t.Errorf("GetReceiptByID() = %v, want %v", err, entity.ErrReceiptNotFound)
+				}
+				return
+			}
+
+			got, err := receiptRepository.GetReceiptPoints(ctx, tc.record.ID)
+			if err != nil || !reflect.DeepEqual(got, points) {
+				t.Errorf("GetReceiptPoints() = %+v, %v, want %+v", got, err, points)
+			}
+		})
+	}
+}
+
+func TestDeleteReceiptWithAuditEntry(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t, filepath.Join(t.TempDir(), "receipts.db"))
+
+	if err := NewReceiptRepository(db).SaveReceipt(ctx, entity.ReceiptRecord{ID: "1"}); err != nil {
+		t.Fatalf("SaveReceipt() = error %v", err)
+	}
+
+	repository := NewReceiptAuditRepository(db)
+
+	testCases := []struct {
+		name string
+
+		receiptID string
+
+		wantErr     error
+		wantEntries int
+	}{
+		{
+			name: "should delete the receipt with its entry",
+
+			receiptID: "1",
+
+			wantEntries: 1,
+		},
+		{
+			name: "should not append the entry due unknown receipt",
+
+			receiptID: "2",
+
+			wantErr: entity.ErrReceiptNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entry := entity.ReceiptAuditEntry{ReceiptID: tc.receiptID, Action: entity.AuditActionDeleted, Actor: "support"}
+
+			if err := repository.DeleteReceipt(ctx, tc.receiptID, entry); err != tc.wantErr {
+				t.Fatalf("DeleteReceipt() = %v, want %v", err, tc.wantErr)
+			}
+
+			entries, err := repository.ListReceiptAuditEntries(ctx, tc.receiptID)
+			if err != nil {
+				t.Fatalf("ListReceiptAuditEntries() = error %v", err)
+			}
+
+			if len(entries) != tc.wantEntries {
+				t.Errorf("DeleteReceipt() entries = %v, want %v", len(entries), tc.wantEntries)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Open opens the SQLite database stored in the given file, creating it if it
+// doesn't exist, and applies the pending schema migrations.
+func Open(path string) (*sql.DB, error) {
+	// Immediate transactions avoid deadlocks between concurrent writers and the
+	// busy timeout makes them wait for each other instead of failing.
+	dsn := fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate", path)
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// isForeignKeyError reports whether the error is caused by a reference to a
+// row that doesn't exist.
+func isForeignKeyError(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey
+}
+
+// nullTime stores a time as Unix nanoseconds, or NULL if it's zero.
+func nullTime(t time.Time) sql.NullInt64 {
+	if t.IsZero() {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: t.UnixNano(), Valid: true}
+}
+
+// timeFromNull reads a time stored by nullTime, in UTC.
+func timeFromNull(value sql.NullInt64) time.Time {
+	if !value.Valid {
+		return time.Time{}
+	}
+
+	return time.Unix(0, value.Int64).UTC()
+}
+
+// nullString stores a string, or NULL if it's empty.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
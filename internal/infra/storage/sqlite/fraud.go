@@ -0,0 +1,135 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// fraudRepository keeps the fingerprints and screenings of the receipts in a
+// SQLite database.
+type fraudRepository struct {
+	db *sql.DB
+}
+
+// NewFraudRepository creates a new SQLite fraud repository.
+func NewFraudRepository(db *sql.DB) *fraudRepository {
+	return &fraudRepository{
+		db: db,
+	}
+}
+
+// SaveFingerprint stores the fingerprint of a receipt and returns the IDs of
+// the receipts saved before it with the same exact and partial fingerprints,
+// in the order they were saved. When it's saved again, the receipts
+// fingerprinted after it first was don't match, so it isn't taken as a
+// duplicate of its own copies.
+func (fr *fraudRepository) SaveFingerprint(ctx context.Context, fingerprint entity.ReceiptFingerprint) ([]string, []string, error) {
+	// Transactions take the write lock when they begin, so concurrent
+	// duplicates see each other's fingerprints.
+	tx, err := fr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	exactMatches, err := matchingReceiptIDs(ctx, tx, "exact", fingerprint.Exact, fingerprint.ReceiptID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	partialMatches, err := matchingReceiptIDs(ctx, tx, "partial", fingerprint.Partial, fingerprint.ReceiptID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO receipt_fingerprints (receipt_id, exact, partial)
+		VALUES (?, ?, ?)
+		ON CONFLICT (receipt_id) DO UPDATE SET
+			exact = excluded.exact,
+			partial = excluded.partial`,
+		fingerprint.ReceiptID, fingerprint.Exact, fingerprint.Partial,
+	); err != nil {
+		if isForeignKeyError(err) {
+			return nil, nil, entity.ErrReceiptNotFound
+		}
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	return exactMatches, partialMatches, nil
+}
+
+// matchingReceiptIDs gets the IDs of the receipts fingerprinted before the
+// given one with the fingerprint in the column, which is one of the
+// fingerprint columns.
+func matchingReceiptIDs(ctx context.Context, tx *sql.Tx, column, fingerprint, receiptID string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT receipt_id FROM receipt_fingerprints AS saved
+		WHERE `+column+` = ? AND NOT EXISTS (
+			SELECT 1 FROM receipt_fingerprints AS own
+			WHERE own.receipt_id = ? AND own.seq <= saved.seq
+		)
+		ORDER BY seq`,
+		fingerprint, receiptID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var receiptIDs []string
+	for rows.Next() {
+		var matchID string
+		if err := rows.Scan(&matchID); err != nil {
+			return nil, err
+		}
+
+		receiptIDs = append(receiptIDs, matchID)
+	}
+
+	return receiptIDs, rows.Err()
+}
+
+// SaveScreening stores the screening of a receipt, replacing the previous one.
+func (fr *fraudRepository) SaveScreening(ctx context.Context, receiptID string, screening entity.FraudScreening) error {
+	_, err := fr.db.ExecContext(ctx, `
+		INSERT INTO receipt_screenings (receipt_id, status, reason, duplicate_of, reviewed)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (receipt_id) DO UPDATE SET
+			status = excluded.status,
+			reason = excluded.reason,
+			duplicate_of = excluded.duplicate_of,
+			reviewed = excluded.reviewed`,
+		receiptID, screening.Status, screening.Reason, screening.DuplicateOf, screening.Reviewed,
+	)
+	if err != nil && isForeignKeyError(err) {
+		return entity.ErrReceiptNotFound
+	}
+
+	return err
+}
+
+// GetScreening gets the screening of a receipt, if it was screened.
+func (fr *fraudRepository) GetScreening(ctx context.Context, receiptID string) (entity.FraudScreening, bool, error) {
+	var screening entity.FraudScreening
+
+	err := fr.db.QueryRowContext(ctx, `
+		SELECT status, reason, duplicate_of, reviewed FROM receipt_screenings WHERE receipt_id = ?`,
+		receiptID,
+	).Scan(&screening.Status, &screening.Reason, &screening.DuplicateOf, &screening.Reviewed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.FraudScreening{}, false, nil
+	}
+	if err != nil {
+		return entity.FraudScreening{}, false, err
+	}
+
+	return screening, true, nil
+}
@@ -0,0 +1,161 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+func TestSaveFingerprint(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t, filepath.Join(t.TempDir(), "receipts.db"))
+
+	receiptRepository := NewReceiptRepository(db)
+	for _, receiptID := range []string{"1", "2", "3"} {
+		if err := receiptRepository.SaveReceipt(ctx, entity.ReceiptRecord{ID: receiptID}); err != nil {
+			t.Fatalf("SaveReceipt() = error %v", err)
+		}
+	}
+
+	repository := NewFraudRepository(db)
+
+	testCases := []struct {
+		name string
+
+		fingerprint entity.ReceiptFingerprint
+
+		wantExactMatches   []string
+		wantPartialMatches []string
+		wantErr            error
+	}{
+		{
+			name: "should not match the first fingerprint",
+
+			fingerprint: entity.ReceiptFingerprint{ReceiptID: "1", Exact: "a", Partial: "x"},
+		},
+		{
+			name: "should match a partial fingerprint",
+
+			fingerprint: entity.ReceiptFingerprint{ReceiptID: "2", Exact: "b", Partial: "x"},
+
+			wantPartialMatches: []string{"1"},
+		},
+		{
+			name: "should match exact and partial fingerprints in saving order",
+
+			fingerprint: entity.ReceiptFingerprint{ReceiptID: "3", Exact: "a", Partial: "x"},
+
+			wantExactMatches:   []string{"1"},
+			wantPartialMatches: []string{"1", "2"},
+		},
+		{
+			name: "should not match the receipts fingerprinted after it when saved again",
+
+			fingerprint: entity.ReceiptFingerprint{ReceiptID: "1", Exact: "a", Partial: "x"},
+		},
+		{
+			name: "should match only the receipts fingerprinted before it when saved again",
+
+			fingerprint: entity.ReceiptFingerprint{ReceiptID: "2", Exact: "a", Partial: "x"},
+
+			wantExactMatches:   []string{"1"},
+			wantPartialMatches: []string{"1"},
+		},
+		{
+			name: "should fail due unknown receipt",
+
+			fingerprint: entity.ReceiptFingerprint{ReceiptID: "unknown", Exact: "a", Partial: "x"},
+
+			wantErr: entity.ErrReceiptNotFound,
+		},
+	}
+
+	// Cases run in order, as each of them saves a fingerprint.
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotExact, gotPartial, err := repository.SaveFingerprint(ctx, tc.fingerprint)
+			if err != tc.wantErr {
+				t.Errorf("SaveFingerprint() = error %v, want %v", err, tc.wantErr)
+			}
+
+			if tc.wantErr != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(gotExact, tc.wantExactMatches) {
+				t.Errorf("SaveFingerprint() exact = %v, want %v", gotExact, tc.wantExactMatches)
+			}
+
+			if !reflect.DeepEqual(gotPartial, tc.wantPartialMatches) {
+				t.Errorf("SaveFingerprint() partial = %v, want %v", gotPartial, tc.wantPartialMatches)
+			}
+		})
+	}
+}
+
+func TestGetScreening(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t, filepath.Join(t.TempDir(), "receipts.db"))
+
+	if err := NewReceiptRepository(db).SaveReceipt(ctx, entity.ReceiptRecord{ID: "2"}); err != nil {
+		t.Fatalf("SaveReceipt() = error %v", err)
+	}
+
+	repository := NewFraudRepository(db)
+
+	screening := entity.FraudScreening{Status: entity.ScreeningStatusClear, Reason: "duplicate", DuplicateOf: "1", Reviewed: true}
+	if err := repository.SaveScreening(ctx, "2", screening); err != nil {
+		t.Fatalf("SaveScreening() = error %v", err)
+	}
+
+	got, ok, err := repository.GetScreening(ctx, "2")
+	if err != nil || !ok || got != screening {
+		t.Errorf("GetScreening() = %v, %v, %v, want %v, true, nil", got, ok, err, screening)
+	}
+
+	if _, ok, _ := repository.GetScreening(ctx, "unknown"); ok {
+		t.Errorf("GetScreening() = found, want not found")
+	}
+
+	if err := repository.SaveScreening(ctx, "unknown", screening); err != entity.ErrReceiptNotFound {
+		t.Errorf("SaveScreening() = %v, want %v", err, entity.ErrReceiptNotFound)
+	}
+}
+
+func TestDeleteReceiptFraudRecords(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t, filepath.Join(t.TempDir(), "receipts.db"))
+
+	receiptRepository := NewReceiptRepository(db)
+	for _, receiptID := range []string{"1", "2"} {
+		if err := receiptRepository.SaveReceipt(ctx, entity.ReceiptRecord{ID: receiptID}); err != nil {
+			t.Fatalf("SaveReceipt() = error %v", err)
+		}
+	}
+
+	repository := NewFraudRepository(db)
+
+	if _, _, err := repository.SaveFingerprint(ctx, entity.ReceiptFingerprint{ReceiptID: "1", Exact: "a", Partial: "x"}); err != nil {
+		t.Fatalf("SaveFingerprint() = error %v", err)
+	}
+	if err := repository.SaveScreening(ctx, "1", entity.FraudScreening{Status: entity.ScreeningStatusClear}); err != nil {
+		t.Fatalf("SaveScreening() = error %v", err)
+	}
+
+	if err := receiptRepository.DeleteReceipt(ctx, "1"); err != nil {
+		t.Fatalf("DeleteReceipt() = error %v", err)
+	}
+
+	// The deleted receipt is no longer screened nor matched as a duplicate.
+	if _, ok, _ := repository.GetScreening(ctx, "1"); ok {
+		t.Errorf("GetScreening() = found, want not found")
+	}
+
+	gotExact, gotPartial, err := repository.SaveFingerprint(ctx, entity.ReceiptFingerprint{ReceiptID: "2", Exact: "a", Partial: "x"})
+	if err != nil || gotExact != nil || gotPartial != nil {
+		t.Errorf("SaveFingerprint() = %v, %v, %v, want no matches", gotExact, gotPartial, err)
+	}
+}
@@ -0,0 +1,108 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// idempotencyRepository keeps the idempotency records in a SQLite database.
+type idempotencyRepository struct {
+	db *sql.DB
+}
+
+// NewIdempotencyRepository creates a new SQLite idempotency repository.
+func NewIdempotencyRepository(db *sql.DB) *idempotencyRepository {
+	return &idempotencyRepository{
+		db: db,
+	}
+}
+
+// SaveIdempotencyRecord saves the record unless there is an unexpired one with
+// the same key, in which case it returns the existing record and false.
+func (ir *idempotencyRepository) SaveIdempotencyRecord(ctx context.Context, record entity.IdempotencyRecord) (entity.IdempotencyRecord, bool, error) {
+	// Transactions take the write lock when they begin, so concurrent saves
+	// of the same key are serialized.
+	tx, err := ir.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.IdempotencyRecord{}, false, err
+	}
+	defer tx.Rollback()
+
+	existing := entity.IdempotencyRecord{Key: record.Key}
+	var createdAt, expiresAt int64
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT request_hash, receipt_id, completed, created_at, expires_at
+		FROM idempotency_records
+		WHERE key = ?`,
+		record.Key,
+	).Scan(&existing.RequestHash, &existing.ReceiptID, &existing.Completed, &createdAt, &expiresAt)
+
+	switch {
+	case err == nil:
+		existing.CreatedAt = time.Unix(0, createdAt).UTC()
+		existing.ExpiresAt = time.Unix(0, expiresAt).UTC()
+
+		if !existing.Expired(record.CreatedAt) {
+			return existing, false, nil
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		return entity.IdempotencyRecord{}, false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO idempotency_records (key, request_hash, receipt_id, completed, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			request_hash = excluded.request_hash,
+			receipt_id = excluded.receipt_id,
+			completed = excluded.completed,
+			created_at = excluded.created_at,
+			expires_at = excluded.expires_at`,
+		record.Key, record.RequestHash, record.ReceiptID, record.Completed,
+		record.CreatedAt.UnixNano(), record.ExpiresAt.UnixNano(),
+	); err != nil {
+		return entity.IdempotencyRecord{}, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.IdempotencyRecord{}, false, err
+	}
+
+	return record, true, nil
+}
+
+// CompleteIdempotencyRecord marks the record with the given key as completed,
+// if there is one.
+func (ir *idempotencyRepository) CompleteIdempotencyRecord(ctx context.Context, key string) error {
+	_, err := ir.db.ExecContext(ctx, `UPDATE idempotency_records SET completed = 1 WHERE key = ?`, key)
+	return err
+}
+
+// DeleteIdempotencyRecord deletes the record with the given key, if any.
+func (ir *idempotencyRepository) DeleteIdempotencyRecord(ctx context.Context, key string) error {
+	_, err := ir.db.ExecContext(ctx, `DELETE FROM idempotency_records WHERE key = ?`, key)
+	return err
+}
+
+// DeleteReceiptIdempotencyRecord deletes the record with the given key if
+// it's of the given receipt.
+func (ir *idempotencyRepository) DeleteReceiptIdempotencyRecord(ctx context.Context, key, receiptID string) error {
+	_, err := ir.db.ExecContext(ctx, `DELETE FROM idempotency_records WHERE key = ? AND receipt_id = ?`, key, receiptID)
+	return err
+}
+
+// DeleteExpiredIdempotencyRecords deletes the records expired at the given
+// time and returns how many were deleted.
+func (ir *idempotencyRepository) DeleteExpiredIdempotencyRecords(ctx context.Context, now time.Time) (int64, error) {
+	result, err := ir.db.ExecContext(ctx, `DELETE FROM idempotency_records WHERE expires_at <= ?`, now.UnixNano())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,201 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+func TestSaveIdempotencyRecord(t *testing.T) {
+	now := time.Date(2022, 1, 1, 13, 0, 0, 0, time.UTC)
+
+	storedRecord := entity.IdempotencyRecord{
+		Key:         "key:1",
+		RequestHash: "hash",
+		ReceiptID:   "1234567890",
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name string
+		ctx  context.Context
+
+		completeStored bool
+		record         entity.IdempotencyRecord
+
+		want      entity.IdempotencyRecord
+		wantSaved bool
+	}{
+		{
+			name: "should save a record with a new key",
+			ctx:  context.Background(),
+
+			record: entity.IdempotencyRecord{Key: "key:2", ReceiptID: "2", CreatedAt: now, ExpiresAt: now.Add(time.Hour)},
+
+			want:      entity.IdempotencyRecord{Key: "key:2", ReceiptID: "2", CreatedAt: now, ExpiresAt: now.Add(time.Hour)},
+			wantSaved: true,
+		},
+		{
+			name: "should return the unexpired record with the same key",
+			ctx:  context.Background(),
+
+			record: entity.IdempotencyRecord{Key: "key:1", ReceiptID: "2", CreatedAt: now.Add(time.Minute)},
+
+			want:      storedRecord,
+			wantSaved: false,
+		},
+		{
+			name: "should return the completed record with the same key",
+			ctx:  context.Background(),
+
+			completeStored: true,
+			record:         entity.IdempotencyRecord{Key: "key:1", ReceiptID: "2", CreatedAt: now.Add(time.Minute)},
+
+			want: entity.IdempotencyRecord{
+				Key:         "key:1",
+				RequestHash: "hash",
+				ReceiptID:   "1234567890",
+				Completed:   true,
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(time.Hour),
+			},
+			wantSaved: false,
+		},
+		{
+			name: "should replace the expired record with the same key",
+			ctx:  context.Background(),
+
+			record: entity.IdempotencyRecord{Key: "key:1", ReceiptID: "2", CreatedAt: now.Add(time.Hour), ExpiresAt: now.Add(2 * time.Hour)},
+
+			want:      entity.IdempotencyRecord{Key: "key:1", ReceiptID: "2", CreatedAt: now.Add(time.Hour), ExpiresAt: now.Add(2 * time.Hour)},
+			wantSaved: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		repository := NewIdempotencyRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+		if _, _, err := repository.SaveIdempotencyRecord(tc.ctx, storedRecord); err != nil {
+			t.Fatalf("SaveIdempotencyRecord() = error %v", err)
+		}
+
+		if tc.completeStored {
+			if err := repository.CompleteIdempotencyRecord(tc.ctx, storedRecord.Key); err != nil {
+				t.Fatalf("CompleteIdempotencyRecord() = error %v", err)
+			}
+		}
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, saved, err := repository.SaveIdempotencyRecord(tc.ctx, tc.record)
+			if err != nil {
+				t.Errorf("SaveIdempotencyRecord() = error %v", err)
+			}
+
+			if !got.CreatedAt.Equal(tc.want.CreatedAt) || !got.ExpiresAt.Equal(tc.want.ExpiresAt) {
+				t.Errorf("SaveIdempotencyRecord() = %v, want %v", got, tc.want)
+			}
+
+			got.CreatedAt, got.ExpiresAt = tc.want.CreatedAt, tc.want.ExpiresAt
+			if got != tc.want {
+				t.Errorf("SaveIdempotencyRecord() = %v, want %v", got, tc.want)
+			}
+
+			if saved != tc.wantSaved {
+				t.Errorf("SaveIdempotencyRecord() saved = %v, want %v", saved, tc.wantSaved)
+			}
+		})
+	}
+}
+
+func TestDeleteExpiredIdempotencyRecords(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 1, 1, 13, 0, 0, 0, time.UTC)
+
+	repository := NewIdempotencyRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+	for key, expiresAt := range map[string]time.Time{
+		"key:expired": now.Add(-time.Second),
+		"key:now":     now,
+		"key:valid":   now.Add(time.Second),
+	} {
+		record := entity.IdempotencyRecord{Key: key, CreatedAt: now.Add(-time.Hour), ExpiresAt: expiresAt}
+		if _, _, err := repository.SaveIdempotencyRecord(ctx, record); err != nil {
+			t.Fatalf("SaveIdempotencyRecord() = error %v", err)
+		}
+	}
+
+	got, err := repository.DeleteExpiredIdempotencyRecords(ctx, now)
+	if err != nil {
+		t.Fatalf("DeleteExpiredIdempotencyRecords() = error %v", err)
+	}
+
+	if got != 2 {
+		t.Errorf("DeleteExpiredIdempotencyRecords() = %v, want %v", got, 2)
+	}
+
+	// The unexpired record is kept, so saving its key again returns it.
+	_, saved, err := repository.SaveIdempotencyRecord(ctx, entity.IdempotencyRecord{Key: "key:valid", CreatedAt: now})
+	if err != nil {
+		t.Fatalf("SaveIdempotencyRecord() = error %v", err)
+	}
+
+	if saved {
+		t.Errorf("DeleteExpiredIdempotencyRecords() deleted the unexpired record")
+	}
+}
+
+func TestDeleteReceiptIdempotencyRecord(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 1, 1, 13, 0, 0, 0, time.UTC)
+
+	repository := NewIdempotencyRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+	record := entity.IdempotencyRecord{Key: "hash:a", ReceiptID: "1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	if _, _, err := repository.SaveIdempotencyRecord(ctx, record); err != nil {
+		t.Fatalf("SaveIdempotencyRecord() = error %v", err)
+	}
+
+	testCases := []struct {
+		name string
+
+		receiptID string
+
+		wantDeleted bool
+	}{
+		{
+			name: "should keep the record of another receipt",
+
+			receiptID: "2",
+		},
+		{
+			name: "should delete the record of the receipt",
+
+			receiptID: "1",
+
+			wantDeleted: true,
+		},
+	}
+
+	// Cases run in order, as the last one deletes the record.
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := repository.DeleteReceiptIdempotencyRecord(ctx, record.Key, tc.receiptID); err != nil {
+				t.Fatalf("DeleteReceiptIdempotencyRecord() = error %v", err)
+			}
+
+			// Saving the key again only succeeds once the record is deleted.
+			_, saved, err := repository.SaveIdempotencyRecord(ctx, entity.IdempotencyRecord{Key: record.Key, CreatedAt: now})
+			if err != nil {
+				t.Fatalf("SaveIdempotencyRecord() = error %v", err)
+			}
+
+			if saved != tc.wantDeleted {
+				t.Errorf("DeleteReceiptIdempotencyRecord() deleted = %v, want %v", saved, tc.wantDeleted)
+			}
+		})
+	}
+}
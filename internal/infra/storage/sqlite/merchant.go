@@ -0,0 +1,328 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// merchantRepository keeps the merchants and their aliases in a SQLite
+// database.
+type merchantRepository struct {
+	db *sql.DB
+}
+
+// NewMerchantRepository creates a new SQLite merchant repository.
+func NewMerchantRepository(db *sql.DB) *merchantRepository {
+	return &merchantRepository{
+		db: db,
+	}
+}
+
+// SaveMerchant stores a new merchant with its aliases.
+func (mr *merchantRepository) SaveMerchant(ctx context.Context, merchant entity.Merchant) error {
+	// Transactions take the write lock when they begin, so the aliases can't
+	// be taken between checking and inserting them.
+	tx, err := mr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO merchants (id, name, created_at)
+		VALUES (?, ?, ?)`,
+		merchant.ID, merchant.Name, merchant.CreatedAt.UnixNano(),
+	); err != nil {
+		return err
+	}
+
+	for _, alias := range merchant.Aliases {
+		if err := addAlias(ctx, tx, merchant.ID, alias); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetMerchant gets a merchant with its aliases by its ID.
+func (mr *merchantRepository) GetMerchant(ctx context.Context, merchantID string) (entity.Merchant, error) {
+	return getMerchant(ctx, mr.db, merchantID)
+}
+
+// ListMerchants lists the merchants with their aliases in the order they were
+// created.
+func (mr *merchantRepository) ListMerchants(ctx context.Context) ([]entity.Merchant, error) {
+	rows, err := mr.db.QueryContext(ctx, `SELECT id, name, created_at FROM merchants ORDER BY seq`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	merchants := []entity.Merchant{}
+	for rows.Next() {
+		var merchant entity.Merchant
+		var createdAt int64
+
+		if err := rows.Scan(&merchant.ID, &merchant.Name, &createdAt); err != nil {
+			return nil, err
+		}
+
+		merchant.CreatedAt = timeFromNull(sql.NullInt64{Int64: createdAt, Valid: true})
+		merchant.Aliases = []string{}
+
+		merchants = append(merchants, merchant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	aliasesByMerchantID, err := getAliases(ctx, mr.db, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range merchants {
+		if aliases, ok := aliasesByMerchantID[merchants[i].ID]; ok {
+			merchants[i].Aliases = aliases
+		}
+	}
+
+	return merchants, nil
+}
+
+// RenameMerchant changes the name of a merchant.
+func (mr *merchantRepository) RenameMerchant(ctx context.Context, merchantID, name string) (entity.Merchant, error) {
+	tx, err := mr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.Merchant{}, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE merchants SET name = ? WHERE id = ?`, name, merchantID)
+	if err != nil {
+		return entity.Merchant{}, err
+	}
+
+	if err := checkMerchantChanged(result); err != nil {
+		return entity.Merchant{}, err
+	}
+
+	return commitMerchant(ctx, tx, merchantID)
+}
+
+// DeleteMerchant deletes a merchant with its aliases.
+func (mr *merchantRepository) DeleteMerchant(ctx context.Context, merchantID string) error {
+	result, err := mr.db.ExecContext(ctx, `DELETE FROM merchants WHERE id = ?`, merchantID)
+	if err != nil {
+		return err
+	}
+
+	return checkMerchantChanged(result)
+}
+
+// AddMerchantAlias adds an alias to a merchant.
+func (mr *merchantRepository) AddMerchantAlias(ctx context.Context, merchantID, alias string) (entity.Merchant, error) {
+	tx, err := mr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.Merchant{}, err
+	}
+	defer tx.Rollback()
+
+	if err := addAlias(ctx, tx, merchantID, alias); err != nil {
+		return entity.Merchant{}, err
+	}
+
+	return commitMerchant(ctx, tx, merchantID)
+}
+
+// DeleteMerchantAlias removes the alias of a merchant with the same
+// normalized name.
+func (mr *merchantRepository) DeleteMerchantAlias(ctx context.Context, merchantID, alias string) (entity.Merchant, error) {
+	tx, err := mr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.Merchant{}, err
+	}
+	defer tx.Rollback()
+
+	if err := merchantExists(ctx, tx, merchantID); err != nil {
+		return entity.Merchant{}, err
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		DELETE FROM merchant_aliases
+		WHERE merchant_id = ? AND retailer_key = ?`,
+		merchantID, entity.NormalizeRetailer(alias),
+	)
+	if err != nil {
+		return entity.Merchant{}, err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return entity.Merchant{}, err
+	}
+
+	if deleted == 0 {
+		return entity.Merchant{}, entity.ErrMerchantAliasNotFound
+	}
+
+	return commitMerchant(ctx, tx, merchantID)
+}
+
+// FindMerchantByRetailer returns the ID of the merchant with an alias of the
+// normalized retailer name.
+func (mr *merchantRepository) FindMerchantByRetailer(ctx context.Context, retailerKey string) (string, bool, error) {
+	var merchantID string
+
+	err := mr.db.QueryRowContext(ctx, `SELECT merchant_id FROM merchant_aliases WHERE retailer_key = ?`, retailerKey).
+		Scan(&merchantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return merchantID, true, nil
+}
+
+// getMerchant gets a merchant with its aliases by its ID.
+func getMerchant(ctx context.Context, q queryer, merchantID string) (entity.Merchant, error) {
+	merchant := entity.Merchant{ID: merchantID}
+	var createdAt int64
+
+	err := q.QueryRowContext(ctx, `SELECT name, created_at FROM merchants WHERE id = ?`, merchantID).
+		Scan(&merchant.Name, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Merchant{}, entity.ErrMerchantNotFound
+	}
+	if err != nil {
+		return entity.Merchant{}, err
+	}
+
+	aliasesByMerchantID, err := getAliases(ctx, q, `WHERE merchant_id = ?`, merchantID)
+	if err != nil {
+		return entity.Merchant{}, err
+	}
+
+	merchant.CreatedAt = timeFromNull(sql.NullInt64{Int64: createdAt, Valid: true})
+	merchant.Aliases = aliasesByMerchantID[merchantID]
+	if merchant.Aliases == nil {
+		merchant.Aliases = []string{}
+	}
+
+	return merchant, nil
+}
+
+// getAliases gets the aliases matching the given filter grouped by merchant
+// ID, in the order they were added.
+func getAliases(ctx context.Context, q queryer, filter string, args ...any) (map[string][]string, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT merchant_id, alias
+		FROM merchant_aliases `+filter+`
+		ORDER BY seq`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliasesByMerchantID := make(map[string][]string)
+	for rows.Next() {
+		var merchantID, alias string
+
+		if err := rows.Scan(&merchantID, &alias); err != nil {
+			return nil, err
+		}
+
+		aliasesByMerchantID[merchantID] = append(aliasesByMerchantID[merchantID], alias)
+	}
+
+	return aliasesByMerchantID, rows.Err()
+}
+
+// addAlias adds an alias to a merchant in the transaction, unless it already
+// has it.
+func addAlias(ctx context.Context, tx *sql.Tx, merchantID, alias string) error {
+	if err := merchantExists(ctx, tx, merchantID); err != nil {
+		return err
+	}
+
+	key := entity.NormalizeRetailer(alias)
+
+	var ownerID string
+	err := tx.QueryRowContext(ctx, `SELECT merchant_id FROM merchant_aliases WHERE retailer_key = ?`, key).
+		Scan(&ownerID)
+	if err == nil {
+		if ownerID != merchantID {
+			return entity.ErrMerchantAliasTaken
+		}
+
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO merchant_aliases (merchant_id, alias, retailer_key)
+		VALUES (?, ?, ?)`,
+		merchantID, alias, key,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// merchantExists returns entity.ErrMerchantNotFound if there is no merchant
+// with the ID.
+func merchantExists(ctx context.Context, q queryRower, merchantID string) error {
+	var exists bool
+	if err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM merchants WHERE id = ?)`, merchantID).
+		Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return entity.ErrMerchantNotFound
+	}
+
+	return nil
+}
+
+// commitMerchant commits the changes of a merchant and returns it as it was
+// left by them.
+func commitMerchant(ctx context.Context, tx *sql.Tx, merchantID string) (entity.Merchant, error) {
+	merchant, err := getMerchant(ctx, tx, merchantID)
+	if err != nil {
+		return entity.Merchant{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.Merchant{}, err
+	}
+
+	return merchant, nil
+}
+
+// checkMerchantChanged returns entity.ErrMerchantNotFound if the statement
+// didn't change any merchant.
+func checkMerchantChanged(result sql.Result) error {
+	changed, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if changed == 0 {
+		return entity.ErrMerchantNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+func TestMerchantRepository(t *testing.T) {
+	ctx := context.Background()
+	repository := NewMerchantRepository(openTestDB(t, filepath.Join(t.TempDir(), "merchants.db")))
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	merchant := entity.Merchant{ID: "1", Name: "M&M Corner Market", Aliases: []string{"M&M Corner Market"}, CreatedAt: createdAt}
+	if err := repository.SaveMerchant(ctx, merchant); err != nil {
+		t.Fatalf("SaveMerchant() = error %v", err)
+	}
+
+	other := entity.Merchant{ID: "2", Name: "Target", Aliases: []string{"Target", "m & m corner market"}, CreatedAt: createdAt}
+	if err := repository.SaveMerchant(ctx, other); !errors.Is(err, entity.ErrMerchantAliasTaken) {
+		t.Errorf("SaveMerchant() error = %v, want %v", err, entity.ErrMerchantAliasTaken)
+	}
+
+	other.Aliases = []string{"Target"}
+	if err := repository.SaveMerchant(ctx, other); err != nil {
+		t.Fatalf("SaveMerchant() = error %v", err)
+	}
+
+	t.Run("should add aliases once", func(t *testing.T) {
+		for _, alias := range []string{"m&m corner mkt", "M&M CORNER MKT"} {
+			if _, err := repository.AddMerchantAlias(ctx, "1", alias); err != nil {
+				t.Fatalf("AddMerchantAlias() = error %v", err)
+			}
+		}
+
+		got, err := repository.GetMerchant(ctx, "1")
+		if err != nil {
+			t.Fatalf("GetMerchant() = error %v", err)
+		}
+
+		want := []string{"M&M Corner Market", "m&m corner mkt"}
+		if !reflect.DeepEqual(got.Aliases, want) {
+			t.Errorf("GetMerchant() aliases = %v, want %v", got.Aliases, want)
+		}
+	})
+
+	t.Run("should fail due alias of another merchant", func(t *testing.T) {
+		if _, err := repository.AddMerchantAlias(ctx, "2", "MM Corner Market"); !errors.Is(err, entity.ErrMerchantAliasTaken) {
+			t.Errorf("AddMerchantAlias() error = %v, want %v", err, entity.ErrMerchantAliasTaken)
+		}
+	})
+
+	t.Run("should find the merchant by the normalized retailer", func(t *testing.T) {
+		got, ok, err := repository.FindMerchantByRetailer(ctx, "mmcornermkt")
+		if err != nil {
+			t.Fatalf("FindMerchantByRetailer() = error %v", err)
+		}
+
+		if got != "1" || !ok {
+			t.Errorf("FindMerchantByRetailer() = %q, %v, want %q, true", got, ok, "1")
+		}
+	})
+
+	t.Run("should delete an alias by its normalized name", func(t *testing.T) {
+		got, err := repository.DeleteMerchantAlias(ctx, "1", "M&M corner MKT")
+		if err != nil {
+			t.Fatalf("DeleteMerchantAlias() = error %v", err)
+		}
+
+		if want := []string{"M&M Corner Market"}; !reflect.DeepEqual(got.Aliases, want) {
+			t.Errorf("DeleteMerchantAlias() aliases = %v, want %v", got.Aliases, want)
+		}
+
+		if _, err := repository.DeleteMerchantAlias(ctx, "1", "Target"); !errors.Is(err, entity.ErrMerchantAliasNotFound) {
+			t.Errorf("DeleteMerchantAlias() error = %v, want %v", err, entity.ErrMerchantAliasNotFound)
+		}
+
+		if _, ok, _ := repository.FindMerchantByRetailer(ctx, "mmcornermkt"); ok {
+			t.Errorf("FindMerchantByRetailer() found the deleted alias")
+		}
+	})
+
+	t.Run("should rename a merchant", func(t *testing.T) {
+		got, err := repository.RenameMerchant(ctx, "2", "Target Corporation")
+		if err != nil {
+			t.Fatalf("RenameMerchant() = error %v", err)
+		}
+
+		if got.Name != "Target Corporation" {
+			t.Errorf("RenameMerchant() name = %q, want %q", got.Name, "Target Corporation")
+		}
+	})
+
+	t.Run("should delete a merchant with its aliases", func(t *testing.T) {
+		if err := repository.DeleteMerchant(ctx, "1"); err != nil {
+			t.Fatalf("DeleteMerchant() = error %v", err)
+		}
+
+		if _, err := repository.GetMerchant(ctx, "1"); !errors.Is(err, entity.ErrMerchantNotFound) {
+			t.Errorf("GetMerchant() error = %v, want %v", err, entity.ErrMerchantNotFound)
+		}
+
+		merchants, err := repository.ListMerchants(ctx)
+		if err != nil {
+			t.Fatalf("ListMerchants() = error %v", err)
+		}
+
+		want := []entity.Merchant{{ID: "2", Name: "Target Corporation", Aliases: []string{"Target"}, CreatedAt: createdAt}}
+		if !reflect.DeepEqual(merchants, want) {
+			t.Errorf("ListMerchants() = %v, want %v", merchants, want)
+		}
+
+		// The aliases of a deleted merchant can be used by another one.
+		if _, err := repository.AddMerchantAlias(ctx, "2", "M&M Corner Market"); err != nil {
+			t.Errorf("AddMerchantAlias() = error %v", err)
+		}
+	})
+
+	for _, err := range []error{
+		repository.DeleteMerchant(ctx, "unknown"),
+		func() error { _, err := repository.RenameMerchant(ctx, "unknown", "name"); return err }(),
+		func() error { _, err := repository.AddMerchantAlias(ctx, "unknown", "alias"); return err }(),
+	} {
+		if !errors.Is(err, entity.ErrMerchantNotFound) {
+			t.Errorf("error = %v, want %v", err, entity.ErrMerchantNotFound)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package sqlite
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+type migration struct {
+	version int
+	name    string
+	query   string
+	// prepare keeps in Go what the query drops, in the same transaction.
+	prepare func(tx *sql.Tx) error
+	// backfill fills in Go what the query can't, in the same transaction.
+	backfill func(tx *sql.Tx) error
+}
+
+// preparations are the steps of the migrations, by version, that are done in
+// Go before their query.
+var preparations = map[int]func(tx *sql.Tx) error{
+	3: keepDecimalAmounts,
+}
+
+// backfills are the steps of the migrations, by version, that are done in Go
+// after their query.
+var backfills = map[int]func(tx *sql.Tx) error{
+	17: backfillRetailerKeys,
+}
+
+// migrate applies, in order, the migrations that were not applied yet to the database.
+func migrate(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TEXT NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("creating schema_migrations table: %w", err)
+	}
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if err := applyMigration(db, m); err != nil {
+			return fmt.Errorf("applying migration %s: %w", m.name, err)
+		}
+	}
+
+	return nil
+}
+
+func applyMigration(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var applied int
+	if err := tx.QueryRow(
+		`SELECT COUNT(*) FROM schema_migrations WHERE version = ?`, m.version,
+	).Scan(&applied); err != nil {
+		return err
+	}
+
+	if applied > 0 {
+		return nil
+	}
+
+	if m.prepare != nil {
+		if err := m.prepare(tx); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(m.query); err != nil {
+		return err
+	}
+
+	if m.backfill != nil {
+		if err := m.backfill(tx); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(
+		`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`,
+		m.version, time.Now().UTC().Format(time.RFC3339),
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// loadMigrations reads the embedded migrations sorted by version. Migration
+// files are named with their version as prefix, e.g. 0001_create_receipts.sql.
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]migration, 0, len(entries))
+	for _, entry := range entries {
+		prefix, _, ok := strings.Cut(entry.Name(), "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s has no version prefix", entry.Name())
+		}
+
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has an invalid version: %w", entry.Name(), err)
+		}
+
+		query, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, migration{
+			version:  version,
+			name:     entry.Name(),
+			query:    string(query),
+			prepare:  preparations[version],
+			backfill: backfills[version],
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	return migrations, nil
+}
+
+// keepDecimalAmounts keeps the amounts of money of the receipts as the decimal
+// text they were stored as before 0003 converts them to cents and drops it, so
+// 0019 can convert them again exactly. The position of the totals is NULL.
+func keepDecimalAmounts(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE decimal_amounts (
+			receipt_id TEXT    NOT NULL,
+			position   INTEGER,
+			amount     TEXT    NOT NULL
+		);
+
+		INSERT INTO decimal_amounts (receipt_id, position, amount)
+		SELECT id, NULL, total FROM receipts;
+
+		INSERT INTO decimal_amounts (receipt_id, position, amount)
+		SELECT receipt_id, position, price FROM receipt_items;`)
+
+	return err
+}
+
+// backfillRetailerKeys fills the normalized retailer names of the receipts
+// stored before they were kept.
+func backfillRetailerKeys(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT DISTINCT retailer FROM receipts`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var retailers []string
+	for rows.Next() {
+		var retailer string
+		if err := rows.Scan(&retailer); err != nil {
+			return err
+		}
+		retailers = append(retailers, retailer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, retailer := range retailers {
+		if _, err := tx.Exec(
+			`UPDATE receipts SET retailer_key = ? WHERE retailer = ?`,
+			entity.NormalizeRetailer(retailer), retailer,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+CREATE TABLE receipts (
+    seq           INTEGER PRIMARY KEY AUTOINCREMENT,
+    id            TEXT    NOT NULL UNIQUE,
+    retailer      TEXT    NOT NULL,
+    purchase_date TEXT    NOT NULL,
+    purchase_time TEXT    NOT NULL,
+    total         TEXT    NOT NULL,
+    points        INTEGER
+);
+
+CREATE TABLE receipt_items (
+    receipt_id        TEXT    NOT NULL REFERENCES receipts (id) ON DELETE CASCADE,
+    position          INTEGER NOT NULL,
+    short_description TEXT    NOT NULL,
+    price             TEXT    NOT NULL,
+    PRIMARY KEY (receipt_id, position)
+);
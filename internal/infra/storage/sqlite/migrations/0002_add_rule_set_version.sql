@@ -0,0 +1,5 @@
+ALTER TABLE receipts ADD COLUMN rule_set_version TEXT;
+
+-- Points calculated before rule sets existed used the default rules, which
+-- are version 1 of the default rule set.
+UPDATE receipts SET rule_set_version = '1' WHERE points IS NOT NULL;
@@ -0,0 +1,8 @@
+-- Amounts of money are stored as integer cents instead of text.
+ALTER TABLE receipts ADD COLUMN total_cents INTEGER NOT NULL DEFAULT 0;
+UPDATE receipts SET total_cents = CAST(ROUND(CAST(total AS REAL) * 100) AS INTEGER);
+ALTER TABLE receipts DROP COLUMN total;
+
+ALTER TABLE receipt_items ADD COLUMN price_cents INTEGER NOT NULL DEFAULT 0;
+UPDATE receipt_items SET price_cents = CAST(ROUND(CAST(price AS REAL) * 100) AS INTEGER);
+ALTER TABLE receipt_items DROP COLUMN price;
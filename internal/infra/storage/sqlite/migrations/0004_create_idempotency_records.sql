@@ -0,0 +1,10 @@
+-- Times are stored as Unix nanoseconds so they can be compared in queries.
+CREATE TABLE idempotency_records (
+    key TEXT PRIMARY KEY,
+    request_hash TEXT NOT NULL,
+    receipt_id TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    expires_at INTEGER NOT NULL
+);
+
+CREATE INDEX idempotency_records_expires_at ON idempotency_records (expires_at);
@@ -0,0 +1,16 @@
+CREATE TABLE receipt_fingerprints (
+    seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+    receipt_id TEXT    NOT NULL UNIQUE REFERENCES receipts (id) ON DELETE CASCADE,
+    exact      TEXT    NOT NULL,
+    partial    TEXT    NOT NULL
+);
+
+CREATE INDEX receipt_fingerprints_exact ON receipt_fingerprints (exact);
+CREATE INDEX receipt_fingerprints_partial ON receipt_fingerprints (partial);
+
+CREATE TABLE receipt_screenings (
+    receipt_id   TEXT PRIMARY KEY REFERENCES receipts (id) ON DELETE CASCADE,
+    status       TEXT NOT NULL,
+    reason       TEXT NOT NULL,
+    duplicate_of TEXT NOT NULL
+);
@@ -0,0 +1,7 @@
+ALTER TABLE receipts ADD COLUMN score_status TEXT NOT NULL DEFAULT 'pending';
+ALTER TABLE receipts ADD COLUMN score_error TEXT NOT NULL DEFAULT '';
+-- Unix nanoseconds, as the other times.
+ALTER TABLE receipts ADD COLUMN scored_at INTEGER;
+
+-- When the points were calculated before is unknown, so scored_at is left empty.
+UPDATE receipts SET score_status = 'scored' WHERE points IS NOT NULL;
@@ -0,0 +1,3 @@
+-- Unix nanoseconds, as the other times. When the existing receipts were
+-- submitted is unknown, so it's left empty for them.
+ALTER TABLE receipts ADD COLUMN submitted_at INTEGER;
@@ -0,0 +1,17 @@
+-- The audit log outlives the receipts, so it doesn't reference them. The
+-- receipts are stored as JSON as they were at the time of the change.
+CREATE TABLE receipt_audit_entries (
+    seq             INTEGER PRIMARY KEY AUTOINCREMENT,
+    receipt_id      TEXT    NOT NULL,
+    action          TEXT    NOT NULL,
+    actor           TEXT    NOT NULL,
+    reason          TEXT    NOT NULL,
+    at              INTEGER NOT NULL,
+    previous        TEXT,
+    new             TEXT,
+    previous_points INTEGER,
+    new_points      INTEGER,
+    points_delta    INTEGER NOT NULL
+);
+
+CREATE INDEX receipt_audit_entries_receipt_id ON receipt_audit_entries (receipt_id);
@@ -0,0 +1,33 @@
+CREATE TABLE accounts (
+    id         TEXT    PRIMARY KEY,
+    name       TEXT    NOT NULL,
+    created_at INTEGER NOT NULL
+);
+
+ALTER TABLE receipts ADD COLUMN account_id TEXT REFERENCES accounts (id);
+
+-- The ledger outlives the receipts, so the transactions don't reference them.
+-- Transactions are listed in the order they were posted, by seq.
+CREATE TABLE ledger_transactions (
+    seq         INTEGER PRIMARY KEY AUTOINCREMENT,
+    id          TEXT    NOT NULL UNIQUE,
+    account_id  TEXT    NOT NULL REFERENCES accounts (id),
+    type        TEXT    NOT NULL,
+    receipt_id  TEXT    NOT NULL,
+    description TEXT    NOT NULL,
+    amount      INTEGER NOT NULL,
+    created_at  INTEGER NOT NULL
+);
+
+CREATE INDEX ledger_transactions_account_id ON ledger_transactions (account_id, receipt_id);
+
+CREATE TABLE ledger_entries (
+    transaction_id TEXT    NOT NULL REFERENCES ledger_transactions (id),
+    position       INTEGER NOT NULL,
+    ledger_account TEXT    NOT NULL,
+    debit          INTEGER NOT NULL,
+    credit         INTEGER NOT NULL,
+    PRIMARY KEY (transaction_id, position)
+);
+
+CREATE INDEX ledger_entries_ledger_account ON ledger_entries (ledger_account);
@@ -0,0 +1,23 @@
+-- Redemptions outlive the receipts, so the allocations don't reference them.
+CREATE TABLE redemptions (
+    id          TEXT    PRIMARY KEY,
+    reward      TEXT    NOT NULL,
+    account_id  TEXT    NOT NULL,
+    points      INTEGER NOT NULL,
+    status      TEXT    NOT NULL,
+    created_at  INTEGER NOT NULL,
+    expires_at  INTEGER NOT NULL,
+    resolved_at INTEGER
+);
+
+CREATE INDEX redemptions_status ON redemptions (status, expires_at);
+
+CREATE TABLE redemption_allocations (
+    redemption_id TEXT    NOT NULL REFERENCES redemptions (id) ON DELETE CASCADE,
+    position      INTEGER NOT NULL,
+    receipt_id    TEXT    NOT NULL,
+    points        INTEGER NOT NULL,
+    PRIMARY KEY (redemption_id, position)
+);
+
+CREATE INDEX redemption_allocations_receipt_id ON redemption_allocations (receipt_id);
@@ -0,0 +1,2 @@
+-- Unix nanoseconds of when the points were found expired, as the other times.
+ALTER TABLE receipts ADD COLUMN points_expired_at INTEGER;
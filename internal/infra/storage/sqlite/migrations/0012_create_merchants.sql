@@ -0,0 +1,24 @@
+-- Merchants are listed in the order they were created, by seq.
+CREATE TABLE merchants (
+    seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+    id         TEXT    NOT NULL UNIQUE,
+    name       TEXT    NOT NULL,
+    created_at INTEGER NOT NULL
+);
+
+-- Aliases are matched by their normalized retailer name, which is an alias of
+-- at most one merchant. They are listed in the order they were added, by seq.
+CREATE TABLE merchant_aliases (
+    seq          INTEGER PRIMARY KEY AUTOINCREMENT,
+    merchant_id  TEXT    NOT NULL REFERENCES merchants (id) ON DELETE CASCADE,
+    alias        TEXT    NOT NULL,
+    retailer_key TEXT    NOT NULL UNIQUE
+);
+
+CREATE INDEX merchant_aliases_merchant_id ON merchant_aliases (merchant_id);
+
+-- The receipts are unassigned when their merchant is deleted, so it isn't a
+-- reference and the existing receipts have none until their aliases are added.
+ALTER TABLE receipts ADD COLUMN merchant_id TEXT;
+
+CREATE INDEX receipts_merchant_id ON receipts (merchant_id);
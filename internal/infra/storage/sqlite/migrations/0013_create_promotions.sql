@@ -0,0 +1,16 @@
+-- Promotions are listed in the order they were created, by seq. Empty dates
+-- leave their window open, and the merchant isn't a reference so promotions
+-- outlive the merchants they were created for without matching any receipt.
+CREATE TABLE promotions (
+    seq         INTEGER PRIMARY KEY AUTOINCREMENT,
+    id          TEXT    NOT NULL UNIQUE,
+    name        TEXT    NOT NULL,
+    merchant_id TEXT,
+    start_date  TEXT,
+    end_date    TEXT,
+    multiplier  REAL    NOT NULL DEFAULT 0,
+    bonus       INTEGER NOT NULL DEFAULT 0,
+    max_points  INTEGER NOT NULL DEFAULT 0,
+    stacking    TEXT    NOT NULL,
+    created_at  INTEGER NOT NULL
+);
@@ -0,0 +1,15 @@
+-- Products are listed in the order they were created, by seq. Their keywords
+-- are few and only read with the product, so they are kept as a JSON array.
+CREATE TABLE products (
+    seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+    id         TEXT    NOT NULL UNIQUE,
+    name       TEXT    NOT NULL,
+    brand      TEXT,
+    category   TEXT,
+    keywords   TEXT    NOT NULL,
+    created_at INTEGER NOT NULL
+);
+
+-- The products the items matched when the receipt was stored. They aren't
+-- references, so the receipts keep them after the products are deleted.
+ALTER TABLE receipt_items ADD COLUMN product_id TEXT;
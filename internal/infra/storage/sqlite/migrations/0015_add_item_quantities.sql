@@ -0,0 +1,5 @@
+-- The quantity, unit price and discount of the items are zero when they
+-- weren't given, as the receipts stored before they were added.
+ALTER TABLE receipt_items ADD COLUMN quantity INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE receipt_items ADD COLUMN unit_price_cents INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE receipt_items ADD COLUMN discount_cents INTEGER NOT NULL DEFAULT 0;
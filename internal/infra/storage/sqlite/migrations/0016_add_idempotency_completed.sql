@@ -0,0 +1,4 @@
+-- The records reserve their keys until the receipt of the submission is
+-- stored. The records saved before are completed, as their receipts were
+-- stored or the records deleted when storing them failed.
+ALTER TABLE idempotency_records ADD COLUMN completed INTEGER NOT NULL DEFAULT 1;
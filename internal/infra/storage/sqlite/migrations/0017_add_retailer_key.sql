@@ -0,0 +1,6 @@
+-- The receipts are assigned to merchants by their normalized retailer name,
+-- kept so they can be found by the index. It's filled for the existing
+-- receipts by backfillRetailerKeys, as SQLite can't normalize the names.
+ALTER TABLE receipts ADD COLUMN retailer_key TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX receipts_retailer_key ON receipts (retailer_key);
@@ -0,0 +1,4 @@
+-- JSON of how the points were calculated, kept when they are scored so the
+-- breakdown doesn't change with the catalogue or the promotions. It's empty
+-- for the receipts scored before.
+ALTER TABLE receipts ADD COLUMN points_breakdown TEXT;
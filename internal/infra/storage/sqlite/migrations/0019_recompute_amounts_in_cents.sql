@@ -0,0 +1,34 @@
+-- Amounts of money were converted to cents by 0003 through floating point
+-- numbers, which could round some of them. They are converted again exactly
+-- from their decimal text, kept by keepDecimalAmounts before 0003 dropped it,
+-- splitting the dollars and the cents at the decimal point. Databases that
+-- ran 0003 before the text was kept have no text to convert, so their amounts
+-- are left as they are.
+CREATE TABLE IF NOT EXISTS decimal_amounts (
+    receipt_id TEXT    NOT NULL,
+    position   INTEGER,
+    amount     TEXT    NOT NULL
+);
+
+UPDATE decimal_amounts SET amount = CASE
+    WHEN instr(amount, '.') = 0 THEN CAST(amount AS INTEGER) * 100
+    ELSE CAST(substr(amount, 1, instr(amount, '.') - 1) AS INTEGER) * 100
+        + CAST(substr(substr(amount, instr(amount, '.') + 1) || '00', 1, 2) AS INTEGER)
+END;
+
+UPDATE receipts SET total_cents = (
+    SELECT CAST(amount AS INTEGER) FROM decimal_amounts
+    WHERE decimal_amounts.receipt_id = receipts.id AND decimal_amounts.position IS NULL
+)
+WHERE id IN (SELECT receipt_id FROM decimal_amounts WHERE position IS NULL);
+
+UPDATE receipt_items SET price_cents = (
+    SELECT CAST(amount AS INTEGER) FROM decimal_amounts
+    WHERE decimal_amounts.receipt_id = receipt_items.receipt_id AND decimal_amounts.position = receipt_items.position
+)
+WHERE EXISTS (
+    SELECT 1 FROM decimal_amounts
+    WHERE decimal_amounts.receipt_id = receipt_items.receipt_id AND decimal_amounts.position = receipt_items.position
+);
+
+DROP TABLE decimal_amounts;
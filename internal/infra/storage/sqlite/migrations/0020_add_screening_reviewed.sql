@@ -0,0 +1,3 @@
+-- Whether the screening was decided by a review, so screening the receipt
+-- again when it's amended keeps the decision.
+ALTER TABLE receipt_screenings ADD COLUMN reviewed INTEGER NOT NULL DEFAULT 0;
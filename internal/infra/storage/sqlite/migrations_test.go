@@ -0,0 +1,278 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+func TestMigrateAmountsToCents(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "receipts.db")+"?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("sql.Open() = error %v", err)
+	}
+	defer db.Close()
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations() = error %v", err)
+	}
+
+	if _, err := db.Exec(`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, applied_at TEXT NOT NULL)`); err != nil {
+		t.Fatalf("Exec() = error %v", err)
+	}
+
+	// Set up the schema as it was before amounts were stored in cents.
+	for _, m := range migrations {
+		if m.version >= 3 {
+			break
+		}
+
+		if err := applyMigration(db, m); err != nil {
+			t.Fatalf("applyMigration(%s) = error %v", m.name, err)
+		}
+	}
+
+	// Amounts that can't be represented exactly as floating point numbers are
+	// converted exactly.
+	amounts := []string{"35.35", "6.49", "0.29", "0.07", "1234567.89", "92233720368547757.99"}
+
+	for i, amount := range amounts {
+		receiptID := fmt.Sprint(i)
+
+		if _, err := db.Exec(`
+			INSERT INTO receipts (id, retailer, purchase_date, purchase_time, total)
+			VALUES (?, 'Target', '2022-01-01', '13:01', ?)`,
+			receiptID, amount,
+		); err != nil {
+			t.Fatalf("Exec() = error %v", err)
+		}
+
+		if _, err := db.Exec(`
+			INSERT INTO receipt_items (receipt_id, position, short_description, price)
+			VALUES (?, 0, 'Mountain Dew 12PK', ?)`,
+			receiptID, amount,
+		); err != nil {
+			t.Fatalf("Exec() = error %v", err)
+		}
+	}
+
+	if err := migrate(db); err != nil {
+		t.Fatalf("migrate() = error %v", err)
+	}
+
+	repository := NewReceiptRepository(db)
+
+	for i, amount := range amounts {
+		t.Run(amount, func(t *testing.T) {
+			got, err := repository.GetReceiptByID(ctx, fmt.Sprint(i))
+			if err != nil {
+				t.Fatalf("GetReceiptByID() = error %v", err)
+			}
+
+			want := entity.MustParseMoney(amount)
+
+			if got.Receipt.Total != want {
+				t.Errorf("GetReceiptByID() total = %v, want %v", got.Receipt.Total, want)
+			}
+
+			if len(got.Receipt.Items) != 1 || got.Receipt.Items[0].Price != want {
+				t.Errorf("GetReceiptByID() items = %v, want one item of %v", got.Receipt.Items, want)
+			}
+		})
+	}
+}
+
+func TestMigrateAmountsConvertedBeforeKeepingTheirText(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "receipts.db")+"?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("sql.Open() = error %v", err)
+	}
+	defer db.Close()
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations() = error %v", err)
+	}
+
+	if _, err := db.Exec(`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, applied_at TEXT NOT NULL)`); err != nil {
+		t.Fatalf("Exec() = error %v", err)
+	}
+
+	// Set up the schema as it was before amounts were stored in cents.
+	for _, m := range migrations {
+		if m.version >= 3 {
+			break
+		}
+
+		if err := applyMigration(db, m); err != nil {
+			t.Fatalf("applyMigration(%s) = error %v", m.name, err)
+		}
+	}
+
+	if _, err := db.Exec(`
+		INSERT INTO receipts (id, retailer, purchase_date, purchase_time, total)
+		VALUES ('1234567890', 'Target', '2022-01-01', '13:01', '35.35')`,
+	); err != nil {
+		t.Fatalf("Exec() = error %v", err)
+	}
+
+	// Convert the amounts as databases did before their text was kept.
+	converted := migrations[2]
+	converted.prepare = nil
+	if err := applyMigration(db, converted); err != nil {
+		t.Fatalf("applyMigration(%s) = error %v", converted.name, err)
+	}
+
+	if err := migrate(db); err != nil {
+		t.Fatalf("migrate() = error %v", err)
+	}
+
+	got, err := NewReceiptRepository(db).GetReceiptByID(ctx, "1234567890")
+	if err != nil {
+		t.Fatalf("GetReceiptByID() = error %v", err)
+	}
+
+	if got.Receipt.Total != entity.MustParseMoney("35.35") {
+		t.Errorf("GetReceiptByID() total = %v, want 35.35", got.Receipt.Total)
+	}
+}
+
+func TestMigrateScoreStatus(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "receipts.db")+"?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("sql.Open() = error %v", err)
+	}
+	defer db.Close()
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations() = error %v", err)
+	}
+
+	if _, err := db.Exec(`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, applied_at TEXT NOT NULL)`); err != nil {
+		t.Fatalf("Exec() = error %v", err)
+	}
+
+	// Set up the schema as it was before the scoring state was stored.
+	for _, m := range migrations {
+		if m.version >= 6 {
+			break
+		}
+
+		if err := applyMigration(db, m); err != nil {
+			t.Fatalf("applyMigration(%s) = error %v", m.name, err)
+		}
+	}
+
+	if _, err := db.Exec(`
+		INSERT INTO receipts (id, retailer, purchase_date, purchase_time, total_cents, points, rule_set_version)
+		VALUES
+			('scored', 'Target', '2022-01-01', '13:01', 3535, 0, '1'),
+			('unscored', 'Target', '2022-01-01', '13:01', 3535, NULL, NULL)`,
+	); err != nil {
+		t.Fatalf("Exec() = error %v", err)
+	}
+
+	if err := migrate(db); err != nil {
+		t.Fatalf("migrate() = error %v", err)
+	}
+
+	repository := NewReceiptRepository(db)
+
+	testCases := []struct {
+		receiptID string
+		want      entity.ReceiptPoints
+	}{
+		{receiptID: "scored", want: entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 0, RuleSetVersion: "1"}},
+		{receiptID: "unscored", want: entity.ReceiptPoints{Status: entity.ScoreStatusPending}},
+	}
+
+	for _, tc := range testCases {
+		got, err := repository.GetReceiptPoints(ctx, tc.receiptID)
+		if err != nil {
+			t.Fatalf("GetReceiptPoints(%s) = error %v", tc.receiptID, err)
+		}
+
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("GetReceiptPoints(%s) = %v, want %v", tc.receiptID, got, tc.want)
+		}
+	}
+}
+
+func TestMigrateRetailerKeys(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "receipts.db")+"?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("sql.Open() = error %v", err)
+	}
+	defer db.Close()
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations() = error %v", err)
+	}
+
+	if _, err := db.Exec(`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, applied_at TEXT NOT NULL)`); err != nil {
+		t.Fatalf("Exec() = error %v", err)
+	}
+
+	// Set up the schema as it was before the normalized retailers were kept.
+	for _, m := range migrations {
+		if m.version >= 17 {
+			break
+		}
+
+		if err := applyMigration(db, m); err != nil {
+			t.Fatalf("applyMigration(%s) = error %v", m.name, err)
+		}
+	}
+
+	if _, err := db.Exec(`
+		INSERT INTO receipts (id, retailer, purchase_date, purchase_time, total_cents)
+		VALUES
+			('1', 'Café Ñandú', '2022-01-01', '13:01', 3535),
+			('2', 'CAFE NANDU ', '2022-01-01', '13:01', 3535),
+			('3', 'Target', '2022-01-01', '13:01', 3535)`,
+	); err != nil {
+		t.Fatalf("Exec() = error %v", err)
+	}
+
+	if err := migrate(db); err != nil {
+		t.Fatalf("migrate() = error %v", err)
+	}
+
+	repository := NewReceiptRepository(db)
+
+	assigned, err := repository.AssignMerchant(ctx, entity.NormalizeRetailer("Café Ñandú"), "merchant")
+	if err != nil {
+		t.Fatalf("AssignMerchant() = error %v", err)
+	}
+
+	if assigned != 2 {
+		t.Errorf("AssignMerchant() = %v, want 2", assigned)
+	}
+
+	for receiptID, wantMerchantID := range map[string]string{"1": "merchant", "2": "merchant", "3": ""} {
+		got, err := repository.GetReceiptByID(ctx, receiptID)
+		if err != nil {
+			t.Fatalf("GetReceiptByID() = error %v", err)
+		}
+
+		if got.MerchantID != wantMerchantID {
+			t.Errorf("GetReceiptByID(%s) merchant = %q, want %q", receiptID, got.MerchantID, wantMerchantID)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// productColumns are the columns scanned by scanProduct.
+const productColumns = `id, name, brand, category, keywords, created_at`
+
+// productRepository keeps the catalogue of products in a SQLite database.
+type productRepository struct {
+	db *sql.DB
+}
+
+// NewProductRepository creates a new SQLite product repository.
+func NewProductRepository(db *sql.DB) *productRepository {
+	return &productRepository{
+		db: db,
+	}
+}
+
+// SaveProduct stores a new product.
+func (pr *productRepository) SaveProduct(ctx context.Context, product entity.Product) error {
+	keywords, err := encodeKeywords(product.Keywords)
+	if err != nil {
+		return err
+	}
+
+	_, err = pr.db.ExecContext(ctx, `
+		INSERT INTO products (`+productColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		product.ID,
+		product.Name,
+		nullString(product.Brand),
+		nullString(product.Category),
+		keywords,
+		product.CreatedAt.UnixNano(),
+	)
+
+	return err
+}
+
+// GetProduct gets a product by its ID.
+func (pr *productRepository) GetProduct(ctx context.Context, productID string) (entity.Product, error) {
+	return getProduct(ctx, pr.db, productID)
+}
+
+// ListProducts lists the products in the order they were created.
+func (pr *productRepository) ListProducts(ctx context.Context) ([]entity.Product, error) {
+	rows, err := pr.db.QueryContext(ctx, `SELECT `+productColumns+` FROM products ORDER BY seq`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []entity.Product{}
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		products = append(products, product)
+	}
+
+	return products, rows.Err()
+}
+
+// UpdateProduct replaces a stored product, keeping its creation time.
+func (pr *productRepository) UpdateProduct(ctx context.Context, product entity.Product) (entity.Product, error) {
+	keywords, err := encodeKeywords(product.Keywords)
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	tx, err := pr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.Product{}, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE products
+		SET name = ?, brand = ?, category = ?, keywords = ?
+		WHERE id = ?`,
+		product.Name,
+		nullString(product.Brand),
+		nullString(product.Category),
+		keywords,
+		product.ID,
+	)
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	if err := checkProductChanged(result); err != nil {
+		return entity.Product{}, err
+	}
+
+	product, err = getProduct(ctx, tx, product.ID)
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.Product{}, err
+	}
+
+	return product, nil
+}
+
+// DeleteProduct deletes a product. The items that matched it keep its ID.
+func (pr *productRepository) DeleteProduct(ctx context.Context, productID string) error {
+	result, err := pr.db.ExecContext(ctx, `DELETE FROM products WHERE id = ?`, productID)
+	if err != nil {
+		return err
+	}
+
+	return checkProductChanged(result)
+}
+
+// getProduct gets a product by its ID.
+func getProduct(ctx context.Context, q queryRower, productID string) (entity.Product, error) {
+	product, err := scanProduct(
+		q.QueryRowContext(ctx, `SELECT `+productColumns+` FROM products WHERE id = ?`, productID),
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Product{}, entity.ErrProductNotFound
+	}
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	return product, nil
+}
+
+// scanProduct scans a row with the productColumns.
+func scanProduct(row rowScanner) (entity.Product, error) {
+	var product entity.Product
+	var brand, category sql.NullString
+	var keywords string
+	var createdAt int64
+
+	if err := row.Scan(&product.ID, &product.Name, &brand, &category, &keywords, &createdAt); err != nil {
+		return entity.Product{}, err
+	}
+
+	if err := json.Unmarshal([]byte(keywords), &product.Keywords); err != nil {
+		return entity.Product{}, err
+	}
+
+	product.Brand = brand.String
+	product.Category = category.String
+	product.CreatedAt = timeFromNull(sql.NullInt64{Int64: createdAt, Valid: true})
+
+	return product, nil
+}
+
+// encodeKeywords encodes the keywords of a product as a JSON array, which is
+// empty if it has none.
+func encodeKeywords(keywords []string) (string, error) {
+	if keywords == nil {
+		keywords = []string{}
+	}
+
+	data, err := json.Marshal(keywords)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// checkProductChanged returns entity.ErrProductNotFound if the statement
+// didn't change any product.
+func checkProductChanged(result sql.Result) error {
+	changed, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if changed == 0 {
+		return entity.ErrProductNotFound
+	}
+
+	return nil
+}
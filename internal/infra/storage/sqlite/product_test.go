@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+func TestProductRepository(t *testing.T) {
+	ctx := context.Background()
+	repository := NewProductRepository(openTestDB(t, filepath.Join(t.TempDir(), "products.db")))
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	products := []entity.Product{
+		{ID: "1", Name: "Gatorade", Brand: "Gatorade", Category: "Drinks", Keywords: []string{"Gatorade Thirst Quencher"}, CreatedAt: createdAt},
+		{ID: "2", Name: "Mountain Dew", Keywords: []string{}, CreatedAt: createdAt},
+	}
+
+	for _, product := range products {
+		if err := repository.SaveProduct(ctx, product); err != nil {
+			t.Fatalf("SaveProduct() = error %v", err)
+		}
+	}
+
+	t.Run("should get a product", func(t *testing.T) {
+		got, err := repository.GetProduct(ctx, "1")
+		if err != nil {
+			t.Fatalf("GetProduct() = error %v", err)
+		}
+
+		if !reflect.DeepEqual(got, products[0]) {
+			t.Errorf("GetProduct() = %v, want %v", got, products[0])
+		}
+	})
+
+	t.Run("should update a product keeping its creation time", func(t *testing.T) {
+		update := entity.Product{ID: "2", Name: "Mountain Dew", Brand: "PepsiCo", Keywords: []string{"Mtn Dew"}}
+
+		got, err := repository.UpdateProduct(ctx, update)
+		if err != nil {
+			t.Fatalf("UpdateProduct() = error %v", err)
+		}
+
+		update.CreatedAt = createdAt
+		if !reflect.DeepEqual(got, update) {
+			t.Errorf("UpdateProduct() = %v, want %v", got, update)
+		}
+	})
+
+	t.Run("should delete a product", func(t *testing.T) {
+		if err := repository.DeleteProduct(ctx, "1"); err != nil {
+			t.Fatalf("DeleteProduct() = error %v", err)
+		}
+
+		if _, err := repository.GetProduct(ctx, "1"); !errors.Is(err, entity.ErrProductNotFound) {
+			t.Errorf("GetProduct() error = %v, want %v", err, entity.ErrProductNotFound)
+		}
+
+		got, err := repository.ListProducts(ctx)
+		if err != nil {
+			t.Fatalf("ListProducts() = error %v", err)
+		}
+
+		if len(got) != 1 || got[0].ID != "2" {
+			t.Errorf("ListProducts() = %v, want only product 2", got)
+		}
+	})
+
+	for _, err := range []error{
+		repository.DeleteProduct(ctx, "unknown"),
+		func() error { _, err := repository.UpdateProduct(ctx, entity.Product{ID: "unknown"}); return err }(),
+	} {
+		if !errors.Is(err, entity.ErrProductNotFound) {
+			t.Errorf("error = %v, want %v", err, entity.ErrProductNotFound)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// promotionColumns are the columns scanned by scanPromotion.
+const promotionColumns = `id, name, merchant_id, start_date, end_date, multiplier, bonus, max_points, stacking, created_at`
+
+// promotionRepository keeps the promotions in a SQLite database.
+type promotionRepository struct {
+	db *sql.DB
+}
+
+// NewPromotionRepository creates a new SQLite promotion repository.
+func NewPromotionRepository(db *sql.DB) *promotionRepository {
+	return &promotionRepository{
+		db: db,
+	}
+}
+
+// SavePromotion stores a new promotion.
+func (pr *promotionRepository) SavePromotion(ctx context.Context, promotion entity.Promotion) error {
+	_, err := pr.db.ExecContext(ctx, `
+		INSERT INTO promotions (`+promotionColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		promotion.ID,
+		promotion.Name,
+		nullString(promotion.MerchantID),
+		nullString(promotion.StartDate),
+		nullString(promotion.EndDate),
+		promotion.Multiplier,
+		promotion.Bonus,
+		promotion.MaxPoints,
+		promotion.Stacking,
+		promotion.CreatedAt.UnixNano(),
+	)
+
+	return err
+}
+
+// GetPromotion gets a promotion by its ID.
+func (pr *promotionRepository) GetPromotion(ctx context.Context, promotionID string) (entity.Promotion, error) {
+	return getPromotion(ctx, pr.db, promotionID)
+}
+
+// ListPromotions lists the promotions in the order they were created.
+func (pr *promotionRepository) ListPromotions(ctx context.Context) ([]entity.Promotion, error) {
+	return pr.listPromotions(ctx, "")
+}
+
+// ListActivePromotions lists the promotions whose window includes the
+// purchase date, in the order they were created. Dates in the YYYY-MM-DD
+// format are compared as text.
+func (pr *promotionRepository) ListActivePromotions(ctx context.Context, purchaseDate string) ([]entity.Promotion, error) {
+	return pr.listPromotions(ctx, `
+		WHERE (start_date IS NULL OR start_date <= ?)
+		AND (end_date IS NULL OR end_date >= ?)`,
+		purchaseDate, purchaseDate,
+	)
+}
+
+func (pr *promotionRepository) listPromotions(ctx context.Context, filter string, args ...any) ([]entity.Promotion, error) {
+	rows, err := pr.db.QueryContext(ctx, `SELECT `+promotionColumns+` FROM promotions `+filter+` ORDER BY seq`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := []entity.Promotion{}
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		promotions = append(promotions, promotion)
+	}
+
+	return promotions, rows.Err()
+}
+
+// UpdatePromotion replaces a stored promotion, keeping its creation time.
+func (pr *promotionRepository) UpdatePromotion(ctx context.Context, promotion entity.Promotion) (entity.Promotion, error) {
+	tx, err := pr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE promotions
+		SET name = ?, merchant_id = ?, start_date = ?, end_date = ?,
+			multiplier = ?, bonus = ?, max_points = ?, stacking = ?
+		WHERE id = ?`,
+		promotion.Name,
+		nullString(promotion.MerchantID),
+		nullString(promotion.StartDate),
+		nullString(promotion.EndDate),
+		promotion.Multiplier,
+		promotion.Bonus,
+		promotion.MaxPoints,
+		promotion.Stacking,
+		promotion.ID,
+	)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+
+	if err := checkPromotionChanged(result); err != nil {
+		return entity.Promotion{}, err
+	}
+
+	promotion, err = getPromotion(ctx, tx, promotion.ID)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.Promotion{}, err
+	}
+
+	return promotion, nil
+}
+
+// DeletePromotion deletes a promotion.
+func (pr *promotionRepository) DeletePromotion(ctx context.Context, promotionID string) error {
+	result, err := pr.db.ExecContext(ctx, `DELETE FROM promotions WHERE id = ?`, promotionID)
+	if err != nil {
+		return err
+	}
+
+	return checkPromotionChanged(result)
+}
+
+// getPromotion gets a promotion by its ID.
+func getPromotion(ctx context.Context, q queryRower, promotionID string) (entity.Promotion, error) {
+	promotion, err := scanPromotion(
+		q.QueryRowContext(ctx, `SELECT `+promotionColumns+` FROM promotions WHERE id = ?`, promotionID),
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Promotion{}, entity.ErrPromotionNotFound
+	}
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+
+	return promotion, nil
+}
+
+// rowScanner is implemented by sql.Row and sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanPromotion scans a row with the promotionColumns.
+func scanPromotion(row rowScanner) (entity.Promotion, error) {
+	var promotion entity.Promotion
+	var merchantID, startDate, endDate sql.NullString
+	var createdAt int64
+
+	if err := row.Scan(
+		&promotion.ID,
+		&promotion.Name,
+		&merchantID,
+		&startDate,
+		&endDate,
+		&promotion.Multiplier,
+		&promotion.Bonus,
+		&promotion.MaxPoints,
+		&promotion.Stacking,
+		&createdAt,
+	); err != nil {
+		return entity.Promotion{}, err
+	}
+
+	promotion.MerchantID = merchantID.String
+	promotion.StartDate = startDate.String
+	promotion.EndDate = endDate.String
+	promotion.CreatedAt = timeFromNull(sql.NullInt64{Int64: createdAt, Valid: true})
+
+	return promotion, nil
+}
+
+// checkPromotionChanged returns entity.ErrPromotionNotFound if the statement
+// didn't change any promotion.
+func checkPromotionChanged(result sql.Result) error {
+	changed, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if changed == 0 {
+		return entity.ErrPromotionNotFound
+	}
+
+	return nil
+}
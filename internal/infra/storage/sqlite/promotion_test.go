@@ -0,0 +1,131 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+func TestPromotionRepository(t *testing.T) {
+	ctx := context.Background()
+	repository := NewPromotionRepository(openTestDB(t, filepath.Join(t.TempDir(), "promotions.db")))
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	promotions := []entity.Promotion{
+		{
+			ID:         "1",
+			Name:       "Double points at Target",
+			MerchantID: "target",
+			StartDate:  "2024-03-01",
+			EndDate:    "2024-03-07",
+			Multiplier: 2,
+			MaxPoints:  500,
+			Stacking:   entity.PromotionStackingCombine,
+			CreatedAt:  createdAt,
+		},
+		{
+			ID:        "2",
+			Name:      "New partner",
+			StartDate: "2024-03-05",
+			Bonus:     100,
+			Stacking:  entity.PromotionStackingExclusive,
+			CreatedAt: createdAt,
+		},
+	}
+
+	for _, promotion := range promotions {
+		if err := repository.SavePromotion(ctx, promotion); err != nil {
+			t.Fatalf("SavePromotion() = error %v", err)
+		}
+	}
+
+	t.Run("should get a promotion", func(t *testing.T) {
+		got, err := repository.GetPromotion(ctx, "1")
+		if err != nil {
+			t.Fatalf("GetPromotion() = error %v", err)
+		}
+
+		if !reflect.DeepEqual(got, promotions[0]) {
+			t.Errorf("GetPromotion() = %v, want %v", got, promotions[0])
+		}
+	})
+
+	t.Run("should list the promotions active on a date", func(t *testing.T) {
+		testCases := []struct {
+			purchaseDate string
+
+			want []string
+		}{
+			{purchaseDate: "2024-02-29", want: []string{}},
+			{purchaseDate: "2024-03-01", want: []string{"1"}},
+			{purchaseDate: "2024-03-07", want: []string{"1", "2"}},
+			{purchaseDate: "2025-01-01", want: []string{"2"}},
+		}
+
+		for _, tc := range testCases {
+			active, err := repository.ListActivePromotions(ctx, tc.purchaseDate)
+			if err != nil {
+				t.Fatalf("ListActivePromotions() = error %v", err)
+			}
+
+			got := []string{}
+			for _, promotion := range active {
+				got = append(got, promotion.ID)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ListActivePromotions(%s) = %v, want %v", tc.purchaseDate, got, tc.want)
+			}
+		}
+	})
+
+	t.Run("should update a promotion keeping its creation time", func(t *testing.T) {
+		update := promotions[1]
+		update.Bonus = 200
+		update.StartDate = ""
+		update.CreatedAt = time.Time{}
+
+		got, err := repository.UpdatePromotion(ctx, update)
+		if err != nil {
+			t.Fatalf("UpdatePromotion() = error %v", err)
+		}
+
+		update.CreatedAt = createdAt
+		if !reflect.DeepEqual(got, update) {
+			t.Errorf("UpdatePromotion() = %v, want %v", got, update)
+		}
+	})
+
+	t.Run("should delete a promotion", func(t *testing.T) {
+		if err := repository.DeletePromotion(ctx, "1"); err != nil {
+			t.Fatalf("DeletePromotion() = error %v", err)
+		}
+
+		if _, err := repository.GetPromotion(ctx, "1"); !errors.Is(err, entity.ErrPromotionNotFound) {
+			t.Errorf("GetPromotion() error = %v, want %v", err, entity.ErrPromotionNotFound)
+		}
+
+		got, err := repository.ListPromotions(ctx)
+		if err != nil {
+			t.Fatalf("ListPromotions() = error %v", err)
+		}
+
+		if len(got) != 1 || got[0].ID != "2" {
+			t.Errorf("ListPromotions() = %v, want only promotion 2", got)
+		}
+	})
+
+	for _, err := range []error{
+		repository.DeletePromotion(ctx, "unknown"),
+		func() error { _, err := repository.UpdatePromotion(ctx, entity.Promotion{ID: "unknown"}); return err }(),
+	} {
+		if !errors.Is(err, entity.ErrPromotionNotFound) {
+			t.Errorf("error = %v, want %v", err, entity.ErrPromotionNotFound)
+		}
+	}
+}
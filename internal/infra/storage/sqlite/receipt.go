@@ -0,0 +1,587 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// receiptRepository keeps receipts, their items and points in a SQLite database.
+type receiptRepository struct {
+	db *sql.DB
+}
+
+// NewReceiptRepository creates a new SQLite receipt repository.
+func NewReceiptRepository(db *sql.DB) *receiptRepository {
+	return &receiptRepository{
+		db: db,
+	}
+}
+
+// SaveReceipt stores a receipt with its items, replacing any receipt with the
+// same ID but keeping when and to which account it was submitted.
+func (rr *receiptRepository) SaveReceipt(ctx context.Context, record entity.ReceiptRecord) error {
+	tx, err := rr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := saveReceipt(ctx, tx, record); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// execer is a database or transaction the changes are executed on.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// saveReceipt stores a receipt with its items. It must run in a transaction,
+// as the items are replaced with several statements.
+func saveReceipt(ctx context.Context, tx execer, record entity.ReceiptRecord) error {
+	receipt := record.Receipt
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO receipts (
+			id, retailer, retailer_key, purchase_date, purchase_time, total_cents, submitted_at, account_id, merchant_id
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			retailer = excluded.retailer,
+			retailer_key = excluded.retailer_key,
+			purchase_date = excluded.purchase_date,
+			purchase_time = excluded.purchase_time,
+			total_cents = excluded.total_cents,
+			merchant_id = excluded.merchant_id`,
+		record.ID, receipt.Retailer, entity.NormalizeRetailer(receipt.Retailer), receipt.PurchaseDate, receipt.PurchaseTime,
+		receipt.Total, nullTime(record.SubmittedAt),
+		nullString(record.AccountID), nullString(record.MerchantID),
+	); err != nil {
+		if isForeignKeyError(err) {
+			return entity.ErrAccountNotFound
+		}
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM receipt_items WHERE receipt_id = ?`, record.ID); err != nil {
+		return err
+	}
+
+	for position, item := range receipt.Items {
+		var productID string
+		if position < len(record.ItemProductIDs) {
+			productID = record.ItemProductIDs[position]
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO receipt_items (
+				receipt_id, position, short_description, price_cents, quantity, unit_price_cents, discount_cents, product_id
+			)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			record.ID, position, item.ShortDescription, item.Price, item.Quantity, item.UnitPrice, item.Discount,
+			nullString(productID),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetReceiptByID gets a receipt with its items by its ID.
+func (rr *receiptRepository) GetReceiptByID(ctx context.Context, receiptID string) (entity.ReceiptRecord, error) {
+	record := entity.ReceiptRecord{ID: receiptID}
+	var submittedAt sql.NullInt64
+	var accountID, merchantID sql.NullString
+
+	err := rr.db.QueryRowContext(ctx, `
+		SELECT retailer, purchase_date, purchase_time, total_cents, submitted_at, account_id, merchant_id
+		FROM receipts
+		WHERE id = ?`,
+		receiptID,
+	).Scan(
+		&record.Receipt.Retailer,
+		&record.Receipt.PurchaseDate,
+		&record.Receipt.PurchaseTime,
+		&record.Receipt.Total,
+		&submittedAt,
+		&accountID,
+		&merchantID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ReceiptRecord{}, entity.ErrReceiptNotFound
+	}
+	if err != nil {
+		return entity.ReceiptRecord{}, err
+	}
+
+	itemsByReceiptID, err := rr.getItems(ctx, `WHERE receipt_id = ?`, receiptID)
+	if err != nil {
+		return entity.ReceiptRecord{}, err
+	}
+
+	itemsByReceiptID[receiptID].setOn(&record)
+	record.SubmittedAt = timeFromNull(submittedAt)
+	record.AccountID = accountID.String
+	record.MerchantID = merchantID.String
+
+	return record, nil
+}
+
+// DeleteReceipt deletes a receipt along with its items, points and fraud
+// screening.
+func (rr *receiptRepository) DeleteReceipt(ctx context.Context, receiptID string) error {
+	return deleteReceipt(ctx, rr.db, receiptID)
+}
+
+// deleteReceipt deletes a receipt, and its items, fingerprint and screening
+// with it.
+func deleteReceipt(ctx context.Context, db execer, receiptID string) error {
+	result, err := db.ExecContext(ctx, `DELETE FROM receipts WHERE id = ?`, receiptID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return entity.ErrReceiptNotFound
+	}
+
+	return nil
+}
+
+// SaveReceiptPoints stores the scoring state of a receipt.
+func (rr *receiptRepository) SaveReceiptPoints(ctx context.Context, receiptID string, points entity.ReceiptPoints) error {
+	return saveReceiptPoints(ctx, rr.db, receiptID, points)
+}
+
+// saveReceiptPoints stores the scoring state of a receipt.
+func saveReceiptPoints(ctx context.Context, db execer, receiptID string, points entity.ReceiptPoints) error {
+	var scoredAt, expiredAt sql.NullInt64
+	if points.ScoredAt != nil {
+		scoredAt = nullTime(*points.ScoredAt)
+	}
+	if points.ExpiredAt != nil {
+		expiredAt = nullTime(*points.ExpiredAt)
+	}
+
+	// Points are only kept once calculated, as unscored receipts have none.
+	var pointsValue sql.NullInt64
+	if points.Scored() {
+		pointsValue = sql.NullInt64{Int64: points.Points, Valid: true}
+	}
+
+	var breakdown sql.NullString
+	if points.Breakdown != nil {
+		data, err := json.Marshal(points.Breakdown)
+		if err != nil {
+			return err
+		}
+		breakdown = nullString(string(data))
+	}
+
+	result, err := db.ExecContext(ctx, `
+		UPDATE receipts
+		SET points = ?, rule_set_version = ?, score_status = ?, score_error = ?, scored_at = ?, points_expired_at = ?,
+			points_breakdown = ?
+		WHERE id = ?`,
+		pointsValue, points.RuleSetVersion, points.Status, points.Error, scoredAt, expiredAt, breakdown, receiptID,
+	)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return entity.ErrReceiptNotFound
+	}
+
+	return nil
+}
+
+// ExpireReceiptPoints marks the points of a receipt expired at the given time
+// if they are still the given scored and unexpired ones, and reports whether
+// they were.
+func (rr *receiptRepository) ExpireReceiptPoints(ctx context.Context, receiptID string, points entity.ReceiptPoints, expiredAt time.Time) (bool, error) {
+	var scoredAt sql.NullInt64
+	if points.ScoredAt != nil {
+		scoredAt = nullTime(*points.ScoredAt)
+	}
+
+	result, err := rr.db.ExecContext(ctx, `
+		UPDATE receipts
+		SET points_expired_at = ?
+		WHERE id = ? AND score_status = ? AND points = ? AND scored_at IS ? AND points_expired_at IS NULL`,
+		nullTime(expiredAt), receiptID, entity.ScoreStatusScored, points.Points, scoredAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if updated == 0 {
+		if _, err := rr.GetReceiptPoints(ctx, receiptID); err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// GetReceiptPoints gets the scoring state of a receipt, which is pending if
+// its points weren't saved yet.
+func (rr *receiptRepository) GetReceiptPoints(ctx context.Context, receiptID string) (entity.ReceiptPoints, error) {
+	var points entity.ReceiptPoints
+	var pointsValue, scoredAt, expiredAt sql.NullInt64
+	var ruleSetVersion, breakdown sql.NullString
+
+	err := rr.db.QueryRowContext(ctx, `
+		SELECT score_status, points, rule_set_version, score_error, scored_at, points_expired_at, points_breakdown
+		FROM receipts
+		WHERE id = ?`,
+		receiptID,
+	).Scan(&points.Status, &pointsValue, &ruleSetVersion, &points.Error, &scoredAt, &expiredAt, &breakdown)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ReceiptPoints{}, entity.ErrReceiptNotFound
+	}
+	if err != nil {
+		return entity.ReceiptPoints{}, err
+	}
+
+	return readPoints(points, pointsValue, ruleSetVersion, scoredAt, expiredAt, breakdown)
+}
+
+// readPoints completes the scoring state read from the nullable columns.
+func readPoints(points entity.ReceiptPoints, pointsValue sql.NullInt64, ruleSetVersion sql.NullString, scoredAt, expiredAt sql.NullInt64, breakdown sql.NullString) (entity.ReceiptPoints, error) {
+	points.Points = pointsValue.Int64
+	points.RuleSetVersion = ruleSetVersion.String
+
+	if scoredAt.Valid {
+		t := timeFromNull(scoredAt)
+		points.ScoredAt = &t
+	}
+	if expiredAt.Valid {
+		t := timeFromNull(expiredAt)
+		points.ExpiredAt = &t
+	}
+
+	if breakdown.Valid {
+		points.Breakdown = &entity.PointsBreakdown{}
+		if err := json.Unmarshal([]byte(breakdown.String), points.Breakdown); err != nil {
+			return entity.ReceiptPoints{}, err
+		}
+	}
+
+	return points, nil
+}
+
+// ListReceipts lists all the stored receipts in the order they were saved.
+func (rr *receiptRepository) ListReceipts(ctx context.Context) ([]entity.ReceiptRecord, error) {
+	rows, err := rr.db.QueryContext(ctx, `
+		SELECT id, retailer, purchase_date, purchase_time, total_cents, submitted_at, account_id, merchant_id
+		FROM receipts
+		ORDER BY seq`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []entity.ReceiptRecord{}
+	for rows.Next() {
+		var record entity.ReceiptRecord
+		var submittedAt sql.NullInt64
+		var accountID, merchantID sql.NullString
+
+		if err := rows.Scan(
+			&record.ID,
+			&record.Receipt.Retailer,
+			&record.Receipt.PurchaseDate,
+			&record.Receipt.PurchaseTime,
+			&record.Receipt.Total,
+			&submittedAt,
+			&accountID,
+			&merchantID,
+		); err != nil {
+			return nil, err
+		}
+
+		record.SubmittedAt = timeFromNull(submittedAt)
+		record.AccountID = accountID.String
+		record.MerchantID = merchantID.String
+
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	itemsByReceiptID, err := rr.getItems(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range records {
+		itemsByReceiptID[records[i].ID].setOn(&records[i])
+	}
+
+	return records, nil
+}
+
+// QueryReceipts returns a page of the receipts matching the query.
+func (rr *receiptRepository) QueryReceipts(ctx context.Context, query entity.ReceiptQuery) (entity.ReceiptPage, error) {
+	// Receipts are sorted by the order they were submitted in, or by their
+	// points, with the ones not scored yet first.
+	sortKey := "seq"
+	if query.SortBy == entity.ReceiptSortPoints {
+		sortKey = "COALESCE(points, -1)"
+	}
+
+	var conditions []string
+	var args []any
+
+	addCondition := func(condition string, conditionArgs ...any) {
+		conditions = append(conditions, condition)
+		args = append(args, conditionArgs...)
+	}
+
+	if query.Retailer != "" {
+		addCondition("retailer = ?", query.Retailer)
+	}
+	if query.RetailerPrefix != "" {
+		// LIKE ignores the case and has wildcards, so the prefix is compared instead.
+		addCondition("substr(retailer, 1, length(?)) = ?", query.RetailerPrefix, query.RetailerPrefix)
+	}
+	if query.MerchantID != "" {
+		addCondition("merchant_id = ?", query.MerchantID)
+	}
+	if query.PurchaseDateFrom != "" {
+		addCondition("purchase_date >= ?", query.PurchaseDateFrom)
+	}
+	if query.PurchaseDateTo != "" {
+		addCondition("purchase_date <= ?", query.PurchaseDateTo)
+	}
+	if query.TotalMin != nil {
+		addCondition("total_cents >= ?", *query.TotalMin)
+	}
+	if query.TotalMax != nil {
+		addCondition("total_cents <= ?", *query.TotalMax)
+	}
+	// Receipts that aren't scored have no points, so they never match.
+	if query.PointsMin != nil {
+		addCondition("points >= ?", *query.PointsMin)
+	}
+	if query.PointsMax != nil {
+		addCondition("points <= ?", *query.PointsMax)
+	}
+	if query.Unexpired {
+		addCondition("points_expired_at IS NULL")
+	}
+
+	if query.Cursor != "" {
+		cursor, err := entity.DecodeReceiptCursor(query)
+		if err != nil {
+			return entity.ReceiptPage{}, err
+		}
+
+		comparison := ">"
+		if query.Descending {
+			comparison = "<"
+		}
+
+		addCondition(
+			fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND seq %[2]s ?))", sortKey, comparison),
+			cursor.SortKey, cursor.SortKey, cursor.Seq,
+		)
+	}
+
+	statement := `
+		SELECT id, retailer, purchase_date, purchase_time, total_cents, submitted_at, account_id, merchant_id,
+			seq, ` + sortKey + `, score_status, points, rule_set_version, score_error, scored_at, points_expired_at,
+			points_breakdown
+		FROM receipts`
+
+	if len(conditions) > 0 {
+		statement += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	direction := "ASC"
+	if query.Descending {
+		direction = "DESC"
+	}
+	statement += fmt.Sprintf(" ORDER BY %[1]s %[2]s, seq %[2]s", sortKey, direction)
+
+	// One more receipt is fetched to know if there is a next page.
+	if query.Limit > 0 {
+		statement += " LIMIT ?"
+		args = append(args, query.Limit+1)
+	}
+
+	rows, err := rr.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return entity.ReceiptPage{}, err
+	}
+	defer rows.Close()
+
+	page := entity.ReceiptPage{Entries: []entity.ReceiptListEntry{}}
+	var last entity.ReceiptCursor
+
+	for rows.Next() {
+		if query.Limit > 0 && len(page.Entries) == query.Limit {
+			page.NextCursor = last.Encode()
+			break
+		}
+
+		var entry entity.ReceiptListEntry
+		var submittedAt, pointsValue, scoredAt, expiredAt sql.NullInt64
+		var ruleSetVersion, accountID, merchantID, breakdown sql.NullString
+
+		last = entity.ReceiptCursor{SortBy: query.SortBy, Descending: query.Descending}
+
+		if err := rows.Scan(
+			&entry.Record.ID,
+			&entry.Record.Receipt.Retailer,
+			&entry.Record.Receipt.PurchaseDate,
+			&entry.Record.Receipt.PurchaseTime,
+			&entry.Record.Receipt.Total,
+			&submittedAt,
+			&accountID,
+			&merchantID,
+			&last.Seq,
+			&last.SortKey,
+			&entry.Points.Status,
+			&pointsValue,
+			&ruleSetVersion,
+			&entry.Points.Error,
+			&scoredAt,
+			&expiredAt,
+			&breakdown,
+		); err != nil {
+			return entity.ReceiptPage{}, err
+		}
+
+		entry.Record.SubmittedAt = timeFromNull(submittedAt)
+		entry.Record.AccountID = accountID.String
+		entry.Record.MerchantID = merchantID.String
+		if entry.Points, err = readPoints(entry.Points, pointsValue, ruleSetVersion, scoredAt, expiredAt, breakdown); err != nil {
+			return entity.ReceiptPage{}, err
+		}
+
+		page.Entries = append(page.Entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return entity.ReceiptPage{}, err
+	}
+
+	if len(page.Entries) == 0 {
+		return page, nil
+	}
+
+	placeholders := make([]string, len(page.Entries))
+	receiptIDs := make([]any, len(page.Entries))
+	for i, entry := range page.Entries {
+		placeholders[i] = "?"
+		receiptIDs[i] = entry.Record.ID
+	}
+
+	itemsByReceiptID, err := rr.getItems(ctx, "WHERE receipt_id IN ("+strings.Join(placeholders, ", ")+")", receiptIDs...)
+	if err != nil {
+		return entity.ReceiptPage{}, err
+	}
+
+	for i := range page.Entries {
+		itemsByReceiptID[page.Entries[i].Record.ID].setOn(&page.Entries[i].Record)
+	}
+
+	return page, nil
+}
+
+// AssignMerchant sets the merchant of the receipts whose normalized retailer
+// name is the given one.
+func (rr *receiptRepository) AssignMerchant(ctx context.Context, retailerKey, merchantID string) (int64, error) {
+	result, err := rr.db.ExecContext(ctx, `
+		UPDATE receipts
+		SET merchant_id = ?
+		WHERE retailer_key = ?`,
+		nullString(merchantID), retailerKey,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// storedItems are the items of a receipt with the products they matched.
+type storedItems struct {
+	items      []entity.Item
+	productIDs []string
+	matched    bool
+}
+
+// setOn sets the items of the record, and their products if any matched one.
+func (s storedItems) setOn(record *entity.ReceiptRecord) {
+	record.Receipt.Items = s.items
+	if s.matched {
+		record.ItemProductIDs = s.productIDs
+	}
+}
+
+// getItems gets the items matching the given filter grouped by receipt ID.
+func (rr *receiptRepository) getItems(ctx context.Context, filter string, args ...any) (map[string]storedItems, error) {
+	rows, err := rr.db.QueryContext(ctx, `
+		SELECT receipt_id, short_description, price_cents, quantity, unit_price_cents, discount_cents, product_id
+		FROM receipt_items `+filter+`
+		ORDER BY receipt_id, position`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	itemsByReceiptID := make(map[string]storedItems)
+	for rows.Next() {
+		var receiptID string
+		var item entity.Item
+		var productID sql.NullString
+
+		if err := rows.Scan(
+			&receiptID, &item.ShortDescription, &item.Price, &item.Quantity, &item.UnitPrice, &item.Discount, &productID,
+		); err != nil {
+			return nil, err
+		}
+
+		stored := itemsByReceiptID[receiptID]
+		stored.items = append(stored.items, item)
+		stored.productIDs = append(stored.productIDs, productID.String)
+		stored.matched = stored.matched || productID.Valid
+		itemsByReceiptID[receiptID] = stored
+	}
+
+	return itemsByReceiptID, rows.Err()
+}
@@ -0,0 +1,874 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+func openTestDB(t *testing.T, path string) *sql.DB {
+	t.Helper()
+
+	db, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() = error %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestGetReceiptByID(t *testing.T) {
+	storedRecord := entity.ReceiptRecord{
+		ID: "1234567890",
+		Receipt: entity.Receipt{
+			Retailer:     "Target",
+			PurchaseDate: "2022-01-01",
+			PurchaseTime: "13:01",
+			Items: []entity.Item{
+				{
+					ShortDescription: "Mountain Dew 12PK",
+					Price:            entity.MustParseMoney("6.49"),
+				},
+				{
+					ShortDescription: "Emils Cheese Pizza",
+					Price:            entity.MustParseMoney("12.25"),
+				},
+			},
+			Total: entity.MustParseMoney("18.74"),
+		},
+		SubmittedAt: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	testCases := []struct {
+		name string
+		ctx  context.Context
+
+		receiptID string
+
+		want    entity.ReceiptRecord
+		wantErr error
+	}{
+		{
+			name: "should return the stored receipt with its items",
+			ctx:  context.Background(),
+
+			receiptID: storedRecord.ID,
+
+			want: storedRecord,
+		},
+		{
+			name: "should fail due unknown receipt id",
+			ctx:  context.Background(),
+
+			receiptID: "unknown",
+
+			wantErr: entity.ErrReceiptNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		repository := NewReceiptRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+		if err := repository.SaveReceipt(tc.ctx, storedRecord); err != nil {
+			t.Fatalf("SaveReceipt() = error %v", err)
+		}
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := repository.GetReceiptByID(tc.ctx, tc.receiptID)
+
+			if got.ID != tc.want.ID || got.Receipt.Total != tc.want.Receipt.Total ||
+				len(got.Receipt.Items) != len(tc.want.Receipt.Items) {
+				t.Errorf("GetReceiptByID() = %v, want %v", got, tc.want)
+			}
+
+			for i := range tc.want.Receipt.Items {
+				if got.Receipt.Items[i] != tc.want.Receipt.Items[i] {
+					t.Errorf("GetReceiptByID() item %d = %v, want %v", i, got.Receipt.Items[i], tc.want.Receipt.Items[i])
+				}
+			}
+
+			if err != tc.wantErr {
+				t.Errorf("GetReceiptByID() = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSaveReceiptKeepsSubmittedAt(t *testing.T) {
+	ctx := context.Background()
+	repository := NewReceiptRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+	submittedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	if err := repository.SaveReceipt(ctx, entity.ReceiptRecord{ID: "1234567890", SubmittedAt: submittedAt}); err != nil {
+		t.Fatalf("SaveReceipt() = error %v", err)
+	}
+
+	// Saving the receipt again replaces it, but it was still submitted first.
+	if err := repository.SaveReceipt(ctx, entity.ReceiptRecord{
+		ID:          "1234567890",
+		Receipt:     entity.Receipt{Retailer: "Target"},
+		SubmittedAt: submittedAt.Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("SaveReceipt() = error %v", err)
+	}
+
+	got, err := repository.GetReceiptByID(ctx, "1234567890")
+	if err != nil {
+		t.Fatalf("GetReceiptByID() = error %v", err)
+	}
+
+	if !got.SubmittedAt.Equal(submittedAt) || got.Receipt.Retailer != "Target" {
+		t.Errorf("GetReceiptByID() = %v, want Target submitted at %v", got, submittedAt)
+	}
+}
+
+func TestSaveReceiptWithAccount(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t, filepath.Join(t.TempDir(), "receipts.db"))
+	repository := NewReceiptRepository(db)
+
+	submittedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	if err := repository.SaveReceipt(ctx, entity.ReceiptRecord{ID: "1234567890", SubmittedAt: submittedAt, AccountID: "1"}); !errors.Is(err, entity.ErrAccountNotFound) {
+		t.Fatalf("SaveReceipt() error = %v, want %v", err, entity.ErrAccountNotFound)
+	}
+
+	if err := NewAccountRepository(db).SaveAccount(ctx, entity.Account{ID: "1", Name: "Jane", CreatedAt: submittedAt}); err != nil {
+		t.Fatalf("SaveAccount() = error %v", err)
+	}
+
+	if err := repository.SaveReceipt(ctx, entity.ReceiptRecord{ID: "1234567890", SubmittedAt: submittedAt, AccountID: "1"}); err != nil {
+		t.Fatalf("SaveReceipt() = error %v", err)
+	}
+
+	// Saving the receipt again replaces it, but it's still attached to the account.
+	if err := repository.SaveReceipt(ctx, entity.ReceiptRecord{ID: "1234567890", Receipt: entity.Receipt{Retailer: "Target"}}); err != nil {
+		t.Fatalf("SaveReceipt() = error %v", err)
+	}
+
+	got, err := repository.GetReceiptByID(ctx, "1234567890")
+	if err != nil {
+		t.Fatalf("GetReceiptByID() = error %v", err)
+	}
+
+	if got.AccountID != "1" || got.Receipt.Retailer != "Target" {
+		t.Errorf("GetReceiptByID() = %v, want Target submitted to account 1", got)
+	}
+
+	records, err := repository.ListReceipts(ctx)
+	if err != nil {
+		t.Fatalf("ListReceipts() = error %v", err)
+	}
+
+	if len(records) != 1 || records[0].AccountID != "1" {
+		t.Errorf("ListReceipts() = %v, want the receipt submitted to account 1", records)
+	}
+
+	page, err := repository.QueryReceipts(ctx, entity.ReceiptQuery{SortBy: entity.ReceiptSortSubmittedAt})
+	if err != nil {
+		t.Fatalf("QueryReceipts() = error %v", err)
+	}
+
+	if len(page.Entries) != 1 || page.Entries[0].Record.AccountID != "1" {
+		t.Errorf("QueryReceipts() = %v, want the receipt submitted to account 1", page.Entries)
+	}
+}
+
+func TestSaveReceiptWithItemProducts(t *testing.T) {
+	ctx := context.Background()
+	repository := NewReceiptRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+	record := entity.ReceiptRecord{
+		ID: "1234567890",
+		Receipt: entity.Receipt{
+			Retailer: "Target",
+			Items: []entity.Item{
+				{ShortDescription: "Gatorade 12PK", Price: entity.MustParseMoney("6.49")},
+				{ShortDescription: "Emils Cheese Pizza", Price: entity.MustParseMoney("12.25")},
+			},
+		},
+		ItemProductIDs: []string{"gatorade", ""},
+	}
+
+	if err := repository.SaveReceipt(ctx, record); err != nil {
+		t.Fatalf("SaveReceipt() = error %v", err)
+	}
+
+	got, err := repository.GetReceiptByID(ctx, record.ID)
+	if err != nil {
+		t.Fatalf("GetReceiptByID() = error %v", err)
+	}
+
+	if !reflect.DeepEqual(got.ItemProductIDs, record.ItemProductIDs) {
+		t.Errorf("GetReceiptByID() item products = %q, want %q", got.ItemProductIDs, record.ItemProductIDs)
+	}
+
+	page, err := repository.QueryReceipts(ctx, entity.ReceiptQuery{SortBy: entity.ReceiptSortSubmittedAt})
+	if err != nil {
+		t.Fatalf("QueryReceipts() = error %v", err)
+	}
+
+	if len(page.Entries) != 1 || !reflect.DeepEqual(page.Entries[0].Record.ItemProductIDs, record.ItemProductIDs) {
+		t.Errorf("QueryReceipts() = %v, want the item products %q", page.Entries, record.ItemProductIDs)
+	}
+
+	// Items that match no products have none.
+	record.ItemProductIDs = nil
+	if err := repository.SaveReceipt(ctx, record); err != nil {
+		t.Fatalf("SaveReceipt() = error %v", err)
+	}
+
+	records, err := repository.ListReceipts(ctx)
+	if err != nil {
+		t.Fatalf("ListReceipts() = error %v", err)
+	}
+
+	if len(records) != 1 || records[0].ItemProductIDs != nil {
+		t.Errorf("ListReceipts() = %v, want the receipt without item products", records)
+	}
+}
+
+func TestSaveReceiptWithItemQuantities(t *testing.T) {
+	ctx := context.Background()
+	repository := NewReceiptRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+	record := entity.ReceiptRecord{
+		ID: "1234567890",
+		Receipt: entity.Receipt{
+			Retailer: "Target",
+			Items: []entity.Item{
+				{
+					ShortDescription: "Klarbrunn 12-PK 12 FL OZ",
+					Price:            entity.MustParseMoney("35.00"),
+					Quantity:         3,
+					UnitPrice:        entity.MustParseMoney("12.00"),
+					Discount:         entity.MustParseMoney("1.00"),
+				},
+				{ShortDescription: "Emils Cheese Pizza", Price: entity.MustParseMoney("12.25")},
+			},
+		},
+	}
+
+	if err := repository.SaveReceipt(ctx, record); err != nil {
+		t.Fatalf("SaveReceipt() = error %v", err)
+	}
+
+	got, err := repository.GetReceiptByID(ctx, record.ID)
+	if err != nil {
+		t.Fatalf("GetReceiptByID() = error %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Receipt.Items, record.Receipt.Items) {
+		t.Errorf("GetReceiptByID() items = %v, want %v", got.Receipt.Items, record.Receipt.Items)
+	}
+}
+
+func TestDeleteReceipt(t *testing.T) {
+	ctx := context.Background()
+	repository := NewReceiptRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+	for _, receiptID := range []string{"a", "b", "c"} {
+		if err := repository.SaveReceipt(ctx, entity.ReceiptRecord{
+			ID:      receiptID,
+			Receipt: entity.Receipt{Items: []entity.Item{{ShortDescription: "Item", Price: entity.MustParseMoney("1.00")}}},
+		}); err != nil {
+			t.Fatalf("SaveReceipt() = error %v", err)
+		}
+	}
+
+	if err := repository.SaveReceiptPoints(ctx, "b", entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 10}); err != nil {
+		t.Fatalf("SaveReceiptPoints() = error %v", err)
+	}
+
+	// The cursor of a page must still work after deleting a receipt before it.
+	page, err := repository.QueryReceipts(ctx, entity.ReceiptQuery{Limit: 2})
+	if err != nil {
+		t.Fatalf("QueryReceipts() = error %v", err)
+	}
+
+	if err := repository.DeleteReceipt(ctx, "b"); err != nil {
+		t.Fatalf("DeleteReceipt() = error %v", err)
+	}
+
+	if _, err := repository.GetReceiptByID(ctx, "b"); err != entity.ErrReceiptNotFound {
+		t.Errorf("GetReceiptByID() = %v, want %v", err, entity.ErrReceiptNotFound)
+	}
+
+	if _, err := repository.GetReceiptPoints(ctx, "b"); err != entity.ErrReceiptNotFound {
+		t.Errorf("GetReceiptPoints() = %v, want %v", err, entity.ErrReceiptNotFound)
+	}
+
+	if err := repository.DeleteReceipt(ctx, "b"); err != entity.ErrReceiptNotFound {
+		t.Errorf("DeleteReceipt() = %v, want %v", err, entity.ErrReceiptNotFound)
+	}
+
+	nextPage, err := repository.QueryReceipts(ctx, entity.ReceiptQuery{Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("QueryReceipts() = error %v", err)
+	}
+
+	if len(nextPage.Entries) != 1 || nextPage.Entries[0].Record.ID != "c" {
+		t.Errorf("QueryReceipts() = %+v, want only c", nextPage.Entries)
+	}
+
+	records, err := repository.ListReceipts(ctx)
+	if err != nil {
+		t.Fatalf("ListReceipts() = error %v", err)
+	}
+
+	if len(records) != 2 || records[0].ID != "a" || records[1].ID != "c" {
+		t.Errorf("ListReceipts() = %+v, want a and c", records)
+	}
+}
+
+func TestGetReceiptPoints(t *testing.T) {
+	storedReceiptID := "1234567890"
+	scoredAt := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	breakdown := entity.PointsBreakdown{
+		Points:         28,
+		RuleSetVersion: "1",
+		Rules:          []entity.RulePoints{{Rule: "retailer_name", Points: 6, Reason: "retailer name has 6 alphanumeric characters"}},
+		Promotions:     []entity.PromotionPoints{{PromotionID: "1", Name: "Launch", Points: 22, Reason: "purchased during the launch"}},
+	}
+
+	testCases := []struct {
+		name string
+		ctx  context.Context
+
+		savedPoints *entity.ReceiptPoints
+		receiptID   string
+
+		want    entity.ReceiptPoints
+		wantErr error
+	}{
+		{
+			name: "should return scored points",
+			ctx:  context.Background(),
+
+			savedPoints: &entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1", ScoredAt: &scoredAt},
+			receiptID:   storedReceiptID,
+
+			want: entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1", ScoredAt: &scoredAt},
+		},
+		{
+			name: "should return scored points with their breakdown",
+			ctx:  context.Background(),
+
+			savedPoints: &entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1", ScoredAt: &scoredAt, Breakdown: &breakdown},
+			receiptID:   storedReceiptID,
+
+			want: entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1", ScoredAt: &scoredAt, Breakdown: &breakdown},
+		},
+		{
+			name: "should return scored zero points",
+			ctx:  context.Background(),
+
+			savedPoints: &entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 0, RuleSetVersion: "2", ScoredAt: &scoredAt},
+			receiptID:   storedReceiptID,
+
+			want: entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 0, RuleSetVersion: "2", ScoredAt: &scoredAt},
+		},
+		{
+			name: "should return expired points",
+			ctx:  context.Background(),
+
+			savedPoints: &entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1", ScoredAt: &scoredAt, ExpiredAt: &scoredAt},
+			receiptID:   storedReceiptID,
+
+			want: entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1", ScoredAt: &scoredAt, ExpiredAt: &scoredAt},
+		},
+		{
+			name: "should return failed scoring",
+			ctx:  context.Background(),
+
+			savedPoints: &entity.ReceiptPoints{Status: entity.ScoreStatusFailed, Error: "invalid total"},
+			receiptID:   storedReceiptID,
+
+			want: entity.ReceiptPoints{Status: entity.ScoreStatusFailed, Error: "invalid total"},
+		},
+		{
+			name: "should report pending points not calculated yet",
+			ctx:  context.Background(),
+
+			receiptID: storedReceiptID,
+
+			want: entity.ReceiptPoints{Status: entity.ScoreStatusPending},
+		},
+		{
+			name: "should fail due unknown receipt id",
+			ctx:  context.Background(),
+
+			receiptID: "unknown",
+
+			wantErr: entity.ErrReceiptNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		repository := NewReceiptRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+		if err := repository.SaveReceipt(tc.ctx, entity.ReceiptRecord{ID: storedReceiptID}); err != nil {
+			t.Fatalf("SaveReceipt() = error %v", err)
+		}
+
+		if tc.savedPoints != nil {
+			if err := repository.SaveReceiptPoints(tc.ctx, storedReceiptID, *tc.savedPoints); err != nil {
+				t.Fatalf("SaveReceiptPoints() = error %v", err)
+			}
+		}
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := repository.GetReceiptPoints(tc.ctx, tc.receiptID)
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("GetReceiptPoints() = %v, want %v", got, tc.want)
+			}
+
+			if err != tc.wantErr {
+				t.Errorf("GetReceiptPoints() = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestExpireReceiptPoints(t *testing.T) {
+	storedReceiptID := "1234567890"
+	scoredAt := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	rescoredAt := scoredAt.Add(time.Hour)
+	expiredAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	read := entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1", ScoredAt: &scoredAt}
+
+	testCases := []struct {
+		name string
+		ctx  context.Context
+
+		savedPoints entity.ReceiptPoints
+		receiptID   string
+
+		want       bool
+		wantPoints entity.ReceiptPoints
+		wantErr    error
+	}{
+		{
+			name: "should expire the points read",
+			ctx:  context.Background(),
+
+			savedPoints: read,
+			receiptID:   storedReceiptID,
+
+			want:       true,
+			wantPoints: entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1", ScoredAt: &scoredAt, ExpiredAt: &expiredAt},
+		},
+		{
+			name: "should not expire the points scored again since they were read",
+			ctx:  context.Background(),
+
+			savedPoints: entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1", ScoredAt: &rescoredAt},
+			receiptID:   storedReceiptID,
+
+			wantPoints: entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1", ScoredAt: &rescoredAt},
+		},
+		{
+			name: "should not expire the points changed since they were read",
+			ctx:  context.Background(),
+
+			savedPoints: entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 40, RuleSetVersion: "1", ScoredAt: &scoredAt},
+			receiptID:   storedReceiptID,
+
+			wantPoints: entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 40, RuleSetVersion: "1", ScoredAt: &scoredAt},
+		},
+		{
+			name: "should not expire the points failed to be scored again",
+			ctx:  context.Background(),
+
+			savedPoints: entity.ReceiptPoints{Status: entity.ScoreStatusFailed, Error: "invalid total"},
+			receiptID:   storedReceiptID,
+
+			wantPoints: entity.ReceiptPoints{Status: entity.ScoreStatusFailed, Error: "invalid total"},
+		},
+		{
+			name: "should not expire the points again",
+			ctx:  context.Background(),
+
+			savedPoints: entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1", ScoredAt: &scoredAt, ExpiredAt: &scoredAt},
+			receiptID:   storedReceiptID,
+
+			wantPoints: entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1", ScoredAt: &scoredAt, ExpiredAt: &scoredAt},
+		},
+		{
+			name: "should fail due unknown receipt id",
+			ctx:  context.Background(),
+
+			savedPoints: read,
+			receiptID:   "unknown",
+
+			wantPoints: read,
+			wantErr:    entity.ErrReceiptNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		repository := NewReceiptRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+		if err := repository.SaveReceipt(tc.ctx, entity.ReceiptRecord{ID: storedReceiptID}); err != nil {
+			t.Fatalf("SaveReceipt() = error %v", err)
+		}
+
+		if err := repository.SaveReceiptPoints(tc.ctx, storedReceiptID, tc.savedPoints); err != nil {
+			t.Fatalf("SaveReceiptPoints() = error %v", err)
+		}
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := repository.ExpireReceiptPoints(tc.ctx, tc.receiptID, read, expiredAt)
+
+			if got != tc.want {
+				t.Errorf("ExpireReceiptPoints() = %v, want %v", got, tc.want)
+			}
+
+			if err != tc.wantErr {
+				t.Errorf("ExpireReceiptPoints() = %v, want %v", err, tc.wantErr)
+			}
+
+			gotPoints, _ := repository.GetReceiptPoints(tc.ctx, storedReceiptID)
+			if !reflect.DeepEqual(gotPoints, tc.wantPoints) {
+				t.Errorf("GetReceiptPoints() = %v, want %v", gotPoints, tc.wantPoints)
+			}
+		})
+	}
+}
+
+func TestQueryReceipts(t *testing.T) {
+	ctx := context.Background()
+	repository := NewReceiptRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+	money := func(amount string) *entity.Money {
+		m := entity.MustParseMoney(amount)
+		return &m
+	}
+	points := func(p int64) *int64 { return &p }
+
+	expiredAt := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	stored := []struct {
+		record  entity.ReceiptRecord
+		points  *int64
+		expired bool
+	}{
+		{record: entity.ReceiptRecord{ID: "a", MerchantID: "target", Receipt: entity.Receipt{Retailer: "Target", PurchaseDate: "2022-01-01", Total: entity.MustParseMoney("10.00")}}, points: points(5)},
+		{record: entity.ReceiptRecord{ID: "b", Receipt: entity.Receipt{Retailer: "Target Store", PurchaseDate: "2022-01-05", Total: entity.MustParseMoney("20.00")}}},
+		{record: entity.ReceiptRecord{ID: "c", Receipt: entity.Receipt{Retailer: "Walgreens", PurchaseDate: "2022-02-01", Total: entity.MustParseMoney("30.00")}}, points: points(5), expired: true},
+		{record: entity.ReceiptRecord{ID: "d", MerchantID: "target", Receipt: entity.Receipt{Retailer: "Target", PurchaseDate: "2022-03-01", Total: entity.MustParseMoney("40.00")}}, points: points(0)},
+	}
+
+	for _, s := range stored {
+		if err := repository.SaveReceipt(ctx, s.record); err != nil {
+			t.Fatalf("SaveReceipt() = error %v", err)
+		}
+
+		if s.points != nil {
+			receiptPoints := entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: *s.points}
+			if s.expired {
+				receiptPoints.ExpiredAt = &expiredAt
+			}
+
+			if err := repository.SaveReceiptPoints(ctx, s.record.ID, receiptPoints); err != nil {
+				t.Fatalf("SaveReceiptPoints() = error %v", err)
+			}
+		}
+	}
+
+	testCases := []struct {
+		name string
+
+		query entity.ReceiptQuery
+
+		want []string
+	}{
+		{
+			name: "should list every receipt in submission order",
+
+			want: []string{"a", "b", "c", "d"},
+		},
+		{
+			name: "should filter by exact retailer",
+
+			query: entity.ReceiptQuery{Retailer: "Target"},
+
+			want: []string{"a", "d"},
+		},
+		{
+			name: "should filter by retailer prefix respecting the case",
+
+			query: entity.ReceiptQuery{RetailerPrefix: "Target"},
+
+			want: []string{"a", "b", "d"},
+		},
+		{
+			name: "should not match a retailer prefix with a different case",
+
+			query: entity.ReceiptQuery{RetailerPrefix: "target"},
+
+			want: []string{},
+		},
+		{
+			name: "should filter by merchant",
+
+			query: entity.ReceiptQuery{MerchantID: "target"},
+
+			want: []string{"a", "d"},
+		},
+		{
+			name: "should filter by purchase date range",
+
+			query: entity.ReceiptQuery{PurchaseDateFrom: "2022-01-05", PurchaseDateTo: "2022-02-01"},
+
+			want: []string{"b", "c"},
+		},
+		{
+			name: "should filter by total range",
+
+			query: entity.ReceiptQuery{TotalMin: money("20.00"), TotalMax: money("30.00")},
+
+			want: []string{"b", "c"},
+		},
+		{
+			name: "should filter by points range only scored receipts",
+
+			query: entity.ReceiptQuery{PointsMax: points(0)},
+
+			want: []string{"d"},
+		},
+		{
+			name: "should filter out receipts with expired points",
+
+			query: entity.ReceiptQuery{Unexpired: true},
+
+			want: []string{"a", "b", "d"},
+		},
+		{
+			name: "should sort by points with unscored receipts first",
+
+			query: entity.ReceiptQuery{SortBy: entity.ReceiptSortPoints},
+
+			want: []string{"b", "d", "a", "c"},
+		},
+		{
+			name: "should sort by points descending",
+
+			query: entity.ReceiptQuery{SortBy: entity.ReceiptSortPoints, Descending: true},
+
+			want: []string{"c", "a", "d", "b"},
+		},
+		{
+			name: "should sort by submission time descending",
+
+			query: entity.ReceiptQuery{SortBy: entity.ReceiptSortSubmittedAt, Descending: true},
+
+			want: []string{"d", "c", "b", "a"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			page, err := repository.QueryReceipts(ctx, tc.query)
+			if err != nil {
+				t.Fatalf("QueryReceipts() = error %v", err)
+			}
+
+			got := []string{}
+			for _, entry := range page.Entries {
+				got = append(got, entry.Record.ID)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("QueryReceipts() = %v, want %v", got, tc.want)
+			}
+
+			if page.NextCursor != "" {
+				t.Errorf("QueryReceipts() next cursor = %v, want none", page.NextCursor)
+			}
+		})
+	}
+
+	t.Run("should paginate with the cursor of each page", func(t *testing.T) {
+		query := entity.ReceiptQuery{SortBy: entity.ReceiptSortPoints, Descending: true, Limit: 3}
+
+		var got [][]string
+		for {
+			page, err := repository.QueryReceipts(ctx, query)
+			if err != nil {
+				t.Fatalf("QueryReceipts() = error %v", err)
+			}
+
+			var ids []string
+			for _, entry := range page.Entries {
+				ids = append(ids, entry.Record.ID)
+			}
+			got = append(got, ids)
+
+			if page.NextCursor == "" {
+				break
+			}
+			query.Cursor = page.NextCursor
+		}
+
+		want := [][]string{{"c", "a", "d"}, {"b"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("QueryReceipts() pages = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("should fail due cursor of a listing in another order", func(t *testing.T) {
+		page, err := repository.QueryReceipts(ctx, entity.ReceiptQuery{Limit: 1})
+		if err != nil {
+			t.Fatalf("QueryReceipts() = error %v", err)
+		}
+
+		for _, cursor := range []string{page.NextCursor, "not a cursor"} {
+			_, err := repository.QueryReceipts(ctx, entity.ReceiptQuery{SortBy: entity.ReceiptSortPoints, Cursor: cursor})
+			if err != entity.ErrInvalidCursor {
+				t.Errorf("QueryReceipts(%q) = %v, want %v", cursor, err, entity.ErrInvalidCursor)
+			}
+		}
+	})
+}
+
+func TestAssignMerchant(t *testing.T) {
+	ctx := context.Background()
+	repository := NewReceiptRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+	for _, record := range []entity.ReceiptRecord{
+		{ID: "a", Receipt: entity.Receipt{Retailer: "M&M Corner Market"}},
+		{ID: "b", Receipt: entity.Receipt{Retailer: "m & m CORNER market "}},
+		{ID: "c", Receipt: entity.Receipt{Retailer: "Target"}, MerchantID: "target"},
+	} {
+		if err := repository.SaveReceipt(ctx, record); err != nil {
+			t.Fatalf("SaveReceipt() = error %v", err)
+		}
+	}
+
+	steps := []struct {
+		name       string
+		merchantID string
+
+		wantAssigned   int64
+		wantMerchantID map[string]string
+	}{
+		{name: "assign", merchantID: "mm", wantAssigned: 2, wantMerchantID: map[string]string{"a": "mm", "b": "mm", "c": "target"}},
+		{name: "unassign", merchantID: "", wantAssigned: 2, wantMerchantID: map[string]string{"a": "", "b": "", "c": "target"}},
+	}
+
+	for _, step := range steps {
+		assigned, err := repository.AssignMerchant(ctx, "mmcornermarket", step.merchantID)
+		if err != nil {
+			t.Fatalf("AssignMerchant(%s) = error %v", step.name, err)
+		}
+
+		if assigned != step.wantAssigned {
+			t.Errorf("AssignMerchant(%s) = %d, want %d", step.name, assigned, step.wantAssigned)
+		}
+
+		for receiptID, want := range step.wantMerchantID {
+			record, err := repository.GetReceiptByID(ctx, receiptID)
+			if err != nil {
+				t.Fatalf("GetReceiptByID() = error %v", err)
+			}
+
+			if record.MerchantID != want {
+				t.Errorf("AssignMerchant(%s) receipt %s merchant = %q, want %q", step.name, receiptID, record.MerchantID, want)
+			}
+		}
+	}
+}
+
+func TestListReceipts(t *testing.T) {
+	ctx := context.Background()
+	repository := NewReceiptRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+	receiptIDs := []string{"c", "a", "b"}
+	for _, receiptID := range receiptIDs {
+		if err := repository.SaveReceipt(ctx, entity.ReceiptRecord{
+			ID: receiptID,
+			Receipt: entity.Receipt{
+				Items: []entity.Item{{ShortDescription: "Item " + receiptID, Price: entity.MustParseMoney("1.00")}},
+			},
+		}); err != nil {
+			t.Fatalf("SaveReceipt() = error %v", err)
+		}
+	}
+
+	// Saving an existing receipt again should not duplicate it nor its items.
+	if err := repository.SaveReceipt(ctx, entity.ReceiptRecord{
+		ID: "a",
+		Receipt: entity.Receipt{
+			Items: []entity.Item{{ShortDescription: "Item a", Price: entity.MustParseMoney("2.00")}},
+		},
+	}); err != nil {
+		t.Fatalf("SaveReceipt() = error %v", err)
+	}
+
+	got, err := repository.ListReceipts(ctx)
+	if err != nil {
+		t.Fatalf("ListReceipts() = error %v", err)
+	}
+
+	if len(got) != len(receiptIDs) {
+		t.Fatalf("ListReceipts() = %d receipts, want %d", len(got), len(receiptIDs))
+	}
+
+	for i, receiptID := range receiptIDs {
+		if got[i].ID != receiptID {
+			t.Errorf("ListReceipts()[%d] = %v, want %v", i, got[i].ID, receiptID)
+		}
+
+		if len(got[i].Receipt.Items) != 1 {
+			t.Errorf("ListReceipts()[%d] = %d items, want 1", i, len(got[i].Receipt.Items))
+		}
+	}
+
+	if got[1].Receipt.Items[0].Price != entity.MustParseMoney("2.00") {
+		t.Errorf("ListReceipts()[1] price = %v, want 2.00", got[1].Receipt.Items[0].Price)
+	}
+}
+
+func TestReceiptsPersistAcrossRestarts(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "receipts.db")
+
+	db, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() = error %v", err)
+	}
+
+	repository := NewReceiptRepository(db)
+
+	if err := repository.SaveReceipt(ctx, entity.ReceiptRecord{ID: "1234567890"}); err != nil {
+		t.Fatalf("SaveReceipt() = error %v", err)
+	}
+
+	if err := repository.SaveReceiptPoints(ctx, "1234567890", entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28, RuleSetVersion: "1"}); err != nil {
+		t.Fatalf("SaveReceiptPoints() = error %v", err)
+	}
+
+	db.Close()
+
+	// Opening the database again must not reapply the migrations.
+	repository = NewReceiptRepository(openTestDB(t, path))
+
+	points, err := repository.GetReceiptPoints(ctx, "1234567890")
+	if err != nil {
+		t.Fatalf("GetReceiptPoints() = error %v", err)
+	}
+
+	if !points.Scored() || points.Points != 28 || points.RuleSetVersion != "1" {
+		t.Errorf("GetReceiptPoints() = %v, want scored {28 1}", points)
+	}
+}
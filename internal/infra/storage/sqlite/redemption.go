@@ -0,0 +1,299 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// redemptionRepository keeps the redemptions in a SQLite database.
+type redemptionRepository struct {
+	db *sql.DB
+}
+
+// NewRedemptionRepository creates a new SQLite redemption repository.
+func NewRedemptionRepository(db *sql.DB) *redemptionRepository {
+	return &redemptionRepository{
+		db: db,
+	}
+}
+
+// ReserveReceiptPoints allocates the points of the redemption from the points
+// of the receipts not held by other redemptions, and stores it.
+func (rr *redemptionRepository) ReserveReceiptPoints(ctx context.Context, redemption entity.Redemption, receiptPoints []entity.RedemptionAllocation) (entity.Redemption, error) {
+	// Transactions take the write lock when they begin, so concurrent
+	// reservations see each other's allocations.
+	tx, err := rr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.Redemption{}, err
+	}
+	defer tx.Rollback()
+
+	held, err := heldPoints(ctx, tx, receiptPoints, redemption.CreatedAt)
+	if err != nil {
+		return entity.Redemption{}, err
+	}
+
+	available := make([]entity.RedemptionAllocation, len(receiptPoints))
+	for i, receipt := range receiptPoints {
+		available[i] = entity.RedemptionAllocation{
+			ReceiptID: receipt.ReceiptID,
+			Points:    receipt.Points - held[receipt.ReceiptID],
+		}
+	}
+
+	allocations, err := entity.AllocateRedemption(redemption.Points, available)
+	if err != nil {
+		return entity.Redemption{}, err
+	}
+
+	redemption.Allocations = allocations
+	redemption.Points = 0
+	for _, allocation := range allocations {
+		redemption.Points += allocation.Points
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO redemptions (id, reward, account_id, points, status, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		redemption.ID, redemption.Reward, redemption.AccountID, redemption.Points, redemption.Status,
+		redemption.CreatedAt.UnixNano(), redemption.ExpiresAt.UnixNano(),
+	); err != nil {
+		return entity.Redemption{}, err
+	}
+
+	for position, allocation := range allocations {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO redemption_allocations (redemption_id, position, receipt_id, points)
+			VALUES (?, ?, ?, ?)`,
+			redemption.ID, position, allocation.ReceiptID, allocation.Points,
+		); err != nil {
+			return entity.Redemption{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.Redemption{}, err
+	}
+
+	return redemption, nil
+}
+
+// heldPoints sums the points of each receipt held by the redemptions at the
+// given time.
+func heldPoints(ctx context.Context, q queryer, receiptPoints []entity.RedemptionAllocation, now time.Time) (map[string]int64, error) {
+	held := make(map[string]int64, len(receiptPoints))
+	if len(receiptPoints) == 0 {
+		return held, nil
+	}
+
+	placeholders := make([]string, len(receiptPoints))
+	args := []any{entity.RedemptionStatusConfirmed, entity.RedemptionStatusReserved, now.UnixNano()}
+	for i, receipt := range receiptPoints {
+		placeholders[i] = "?"
+		args = append(args, receipt.ReceiptID)
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT a.receipt_id, SUM(a.points)
+		FROM redemption_allocations a
+		JOIN redemptions r ON r.id = a.redemption_id
+		WHERE (r.status = ? OR (r.status = ? AND r.expires_at > ?))
+			AND a.receipt_id IN (`+strings.Join(placeholders, ", ")+`)
+		GROUP BY a.receipt_id`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var receiptID string
+		var points int64
+
+		if err := rows.Scan(&receiptID, &points); err != nil {
+			return nil, err
+		}
+
+		held[receiptID] = points
+	}
+
+	return held, rows.Err()
+}
+
+// GetRedemption gets a redemption by its ID.
+func (rr *redemptionRepository) GetRedemption(ctx context.Context, redemptionID string) (entity.Redemption, error) {
+	return getRedemption(ctx, rr.db, redemptionID)
+}
+
+// GetHeldPoints sums the points of the receipt held by the redemptions at the
+// given time.
+func (rr *redemptionRepository) GetHeldPoints(ctx context.Context, receiptID string, now time.Time) (int64, error) {
+	held, err := heldPoints(ctx, rr.db, []entity.RedemptionAllocation{{ReceiptID: receiptID}}, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return held[receiptID], nil
+}
+
+// ResolveRedemption changes a reserved redemption to the given status, or
+// marks it as expired if it expired at the given time.
+func (rr *redemptionRepository) ResolveRedemption(ctx context.Context, redemptionID, status string, at time.Time) (entity.Redemption, error) {
+	tx, err := rr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.Redemption{}, err
+	}
+	defer tx.Rollback()
+
+	redemption, err := getRedemption(ctx, tx, redemptionID)
+	if err != nil {
+		return entity.Redemption{}, err
+	}
+
+	if redemption.Status != entity.RedemptionStatusReserved {
+		return entity.Redemption{}, entity.ErrRedemptionResolved
+	}
+
+	if !redemption.Holds(at) {
+		if err := resolveRedemption(ctx, tx, redemptionID, entity.RedemptionStatusExpired, redemption.ExpiresAt); err != nil {
+			return entity.Redemption{}, err
+		}
+		if err := tx.Commit(); err != nil {
+			return entity.Redemption{}, err
+		}
+
+		return entity.Redemption{}, entity.ErrRedemptionResolved
+	}
+
+	if err := resolveRedemption(ctx, tx, redemptionID, status, at); err != nil {
+		return entity.Redemption{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.Redemption{}, err
+	}
+
+	redemption.Status = status
+	redemption.ResolvedAt = &at
+
+	return redemption, nil
+}
+
+// ReopenRedemption changes a confirmed redemption back to reserved.
+func (rr *redemptionRepository) ReopenRedemption(ctx context.Context, redemptionID string) error {
+	result, err := rr.db.ExecContext(ctx, `
+		UPDATE redemptions SET status = ?, resolved_at = NULL WHERE id = ? AND status = ?`,
+		entity.RedemptionStatusReserved, redemptionID, entity.RedemptionStatusConfirmed,
+	)
+	if err != nil {
+		return err
+	}
+
+	reopened, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if reopened == 0 {
+		if _, err := getRedemption(ctx, rr.db, redemptionID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExpireRedemptions marks the reserved redemptions expired at the given time.
+func (rr *redemptionRepository) ExpireRedemptions(ctx context.Context, now time.Time) (int64, error) {
+	result, err := rr.db.ExecContext(ctx, `
+		UPDATE redemptions
+		SET status = ?, resolved_at = expires_at
+		WHERE status = ? AND expires_at <= ?`,
+		entity.RedemptionStatusExpired, entity.RedemptionStatusReserved, now.UnixNano(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// resolveRedemption stores the status a redemption was resolved with.
+func resolveRedemption(ctx context.Context, tx *sql.Tx, redemptionID, status string, at time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE redemptions SET status = ?, resolved_at = ? WHERE id = ?`,
+		status, at.UnixNano(), redemptionID,
+	)
+
+	return err
+}
+
+// queryer is implemented by both sql.DB and sql.Tx.
+type queryer interface {
+	queryRower
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// getRedemption gets a redemption with its allocations by its ID.
+func getRedemption(ctx context.Context, q queryer, redemptionID string) (entity.Redemption, error) {
+	redemption := entity.Redemption{ID: redemptionID}
+	var createdAt, expiresAt int64
+	var resolvedAt sql.NullInt64
+
+	err := q.QueryRowContext(ctx, `
+		SELECT reward, account_id, points, status, created_at, expires_at, resolved_at
+		FROM redemptions
+		WHERE id = ?`,
+		redemptionID,
+	).Scan(
+		&redemption.Reward,
+		&redemption.AccountID,
+		&redemption.Points,
+		&redemption.Status,
+		&createdAt,
+		&expiresAt,
+		&resolvedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Redemption{}, entity.ErrRedemptionNotFound
+	}
+	if err != nil {
+		return entity.Redemption{}, err
+	}
+
+	redemption.CreatedAt = timeFromNull(sql.NullInt64{Int64: createdAt, Valid: true})
+	redemption.ExpiresAt = timeFromNull(sql.NullInt64{Int64: expiresAt, Valid: true})
+	if resolvedAt.Valid {
+		t := timeFromNull(resolvedAt)
+		redemption.ResolvedAt = &t
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT receipt_id, points
+		FROM redemption_allocations
+		WHERE redemption_id = ?
+		ORDER BY position`,
+		redemptionID,
+	)
+	if err != nil {
+		return entity.Redemption{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var allocation entity.RedemptionAllocation
+		if err := rows.Scan(&allocation.ReceiptID, &allocation.Points); err != nil {
+			return entity.Redemption{}, err
+		}
+
+		redemption.Allocations = append(redemption.Allocations, allocation)
+	}
+
+	return redemption, rows.Err()
+}
@@ -0,0 +1,381 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+func newReservation(id string, points int64, createdAt time.Time) entity.Redemption {
+	return entity.Redemption{
+		ID:        id,
+		Reward:    "coffee",
+		Points:    points,
+		Status:    entity.RedemptionStatusReserved,
+		CreatedAt: createdAt,
+		ExpiresAt: createdAt.Add(15 * time.Minute),
+	}
+}
+
+func TestReserveReceiptPoints(t *testing.T) {
+	ctx := context.Background()
+	repository := NewRedemptionRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	receiptPoints := []entity.RedemptionAllocation{{ReceiptID: "a", Points: 10}, {ReceiptID: "b", Points: 20}}
+
+	steps := []struct {
+		name       string
+		redemption entity.Redemption
+
+		wantAllocations []entity.RedemptionAllocation
+		wantErr         error
+	}{
+		{
+			name:       "first reservation",
+			redemption: newReservation("1", 15, createdAt),
+
+			wantAllocations: []entity.RedemptionAllocation{{ReceiptID: "a", Points: 10}, {ReceiptID: "b", Points: 5}},
+		},
+		{
+			name:       "reservation of the held points",
+			redemption: newReservation("2", 16, createdAt),
+
+			wantErr: entity.ErrInsufficientPoints,
+		},
+		{
+			name:       "reservation of the remaining points",
+			redemption: newReservation("3", 0, createdAt),
+
+			wantAllocations: []entity.RedemptionAllocation{{ReceiptID: "b", Points: 15}},
+		},
+		{
+			name:       "reservation after the others expired",
+			redemption: newReservation("4", 30, createdAt.Add(15*time.Minute)),
+
+			wantAllocations: []entity.RedemptionAllocation{{ReceiptID: "a", Points: 10}, {ReceiptID: "b", Points: 20}},
+		},
+	}
+
+	for _, step := range steps {
+		got, err := repository.ReserveReceiptPoints(ctx, step.redemption, receiptPoints)
+		if !errors.Is(err, step.wantErr) {
+			t.Fatalf("ReserveReceiptPoints(%s) error = %v, want %v", step.name, err, step.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+
+		if !reflect.DeepEqual(got.Allocations, step.wantAllocations) {
+			t.Errorf("ReserveReceiptPoints(%s) allocations = %v, want %v", step.name, got.Allocations, step.wantAllocations)
+		}
+
+		stored, err := repository.GetRedemption(ctx, step.redemption.ID)
+		if err != nil {
+			t.Fatalf("GetRedemption(%s) = error %v", step.name, err)
+		}
+
+		if !reflect.DeepEqual(stored, got) {
+			t.Errorf("GetRedemption(%s) = %v, want %v", step.name, stored, got)
+		}
+	}
+}
+
+func TestReserveReceiptPointsConcurrently(t *testing.T) {
+	ctx := context.Background()
+	repository := NewRedemptionRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	receiptPoints := []entity.RedemptionAllocation{{ReceiptID: "a", Points: 60}, {ReceiptID: "b", Points: 40}}
+
+	// Only ten of the concurrent reservations fit in the points of the receipts.
+	var wg sync.WaitGroup
+	errs := make([]error, 20)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = repository.ReserveReceiptPoints(ctx, newReservation(fmt.Sprint(i), 10, createdAt), receiptPoints)
+		}(i)
+	}
+	wg.Wait()
+
+	var reserved int
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			reserved++
+		case !errors.Is(err, entity.ErrInsufficientPoints):
+			t.Fatalf("ReserveReceiptPoints() = error %v", err)
+		}
+	}
+
+	if reserved != 10 {
+		t.Errorf("ReserveReceiptPoints() reserved %d times, want 10", reserved)
+	}
+}
+
+func TestGetHeldPoints(t *testing.T) {
+	ctx := context.Background()
+	repository := NewRedemptionRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	receiptPoints := []entity.RedemptionAllocation{{ReceiptID: "a", Points: 10}, {ReceiptID: "b", Points: 20}}
+
+	// The first reservation holds 10 points of a and 5 of b, and the second
+	// the other 15 of b until it's confirmed.
+	if _, err := repository.ReserveReceiptPoints(ctx, newReservation("1", 15, createdAt), receiptPoints); err != nil {
+		t.Fatalf("ReserveReceiptPoints() = error %v", err)
+	}
+	if _, err := repository.ReserveReceiptPoints(ctx, newReservation("2", 0, createdAt), receiptPoints); err != nil {
+		t.Fatalf("ReserveReceiptPoints() = error %v", err)
+	}
+	if _, err := repository.ResolveRedemption(ctx, "2", entity.RedemptionStatusConfirmed, createdAt); err != nil {
+		t.Fatalf("ResolveRedemption() = error %v", err)
+	}
+
+	testCases := []struct {
+		name string
+
+		receiptID string
+		at        time.Time
+
+		want int64
+	}{
+		{
+			name: "should sum the points held by a reservation",
+
+			receiptID: "a",
+			at:        createdAt,
+
+			want: 10,
+		},
+		{
+			name: "should sum the points held by reservations and confirmed redemptions",
+
+			receiptID: "b",
+			at:        createdAt,
+
+			want: 20,
+		},
+		{
+			name: "should not sum the points of the expired reservations",
+
+			receiptID: "b",
+			at:        createdAt.Add(15 * time.Minute),
+
+			want: 15,
+		},
+		{
+			name: "should return no points for a receipt without redemptions",
+
+			receiptID: "c",
+			at:        createdAt,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := repository.GetHeldPoints(ctx, tc.receiptID, tc.at)
+			if err != nil {
+				t.Fatalf("GetHeldPoints() = error %v", err)
+			}
+
+			if got != tc.want {
+				t.Errorf("GetHeldPoints() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResolveRedemption(t *testing.T) {
+	ctx := context.Background()
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	receiptPoints := []entity.RedemptionAllocation{{ReceiptID: "a", Points: 10}}
+
+	testCases := []struct {
+		name string
+
+		resolutions []string
+		at          time.Time
+
+		wantStatus string
+		wantErr    error
+	}{
+		{
+			name: "should confirm a reservation",
+
+			resolutions: []string{entity.RedemptionStatusConfirmed},
+			at:          createdAt.Add(time.Minute),
+
+			wantStatus: entity.RedemptionStatusConfirmed,
+		},
+		{
+			name: "should cancel a reservation",
+
+			resolutions: []string{entity.RedemptionStatusCancelled},
+			at:          createdAt.Add(time.Minute),
+
+			wantStatus: entity.RedemptionStatusCancelled,
+		},
+		{
+			name: "should fail due reservation already cancelled",
+
+			resolutions: []string{entity.RedemptionStatusCancelled, entity.RedemptionStatusConfirmed},
+			at:          createdAt.Add(time.Minute),
+
+			wantStatus: entity.RedemptionStatusCancelled,
+			wantErr:    entity.ErrRedemptionResolved,
+		},
+		{
+			name: "should expire a stale reservation",
+
+			resolutions: []string{entity.RedemptionStatusConfirmed},
+			at:          createdAt.Add(15 * time.Minute),
+
+			wantStatus: entity.RedemptionStatusExpired,
+			wantErr:    entity.ErrRedemptionResolved,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repository := NewRedemptionRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+			if _, err := repository.ReserveReceiptPoints(ctx, newReservation("1", 10, createdAt), receiptPoints); err != nil {
+				t.Fatalf("ReserveReceiptPoints() = error %v", err)
+			}
+
+			var err error
+			for _, status := range tc.resolutions {
+				_, err = repository.ResolveRedemption(ctx, "1", status, tc.at)
+			}
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("ResolveRedemption() error = %v, want %v", err, tc.wantErr)
+			}
+
+			got, err := repository.GetRedemption(ctx, "1")
+			if err != nil {
+				t.Fatalf("GetRedemption() = error %v", err)
+			}
+
+			if got.Status != tc.wantStatus || got.ResolvedAt == nil {
+				t.Errorf("GetRedemption() = %v, want %s", got, tc.wantStatus)
+			}
+		})
+	}
+
+	repository := NewRedemptionRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+	if _, err := repository.ResolveRedemption(ctx, "unknown", entity.RedemptionStatusConfirmed, createdAt); !errors.Is(err, entity.ErrRedemptionNotFound) {
+		t.Errorf("ResolveRedemption() error = %v, want %v", err, entity.ErrRedemptionNotFound)
+	}
+}
+
+func TestReopenRedemption(t *testing.T) {
+	ctx := context.Background()
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	receiptPoints := []entity.RedemptionAllocation{{ReceiptID: "a", Points: 10}}
+
+	testCases := []struct {
+		name string
+
+		resolution string
+
+		wantStatus string
+	}{
+		{
+			name: "should reserve again a confirmed redemption",
+
+			resolution: entity.RedemptionStatusConfirmed,
+
+			wantStatus: entity.RedemptionStatusReserved,
+		},
+		{
+			name: "should keep a cancelled redemption",
+
+			resolution: entity.RedemptionStatusCancelled,
+
+			wantStatus: entity.RedemptionStatusCancelled,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repository := NewRedemptionRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+
+			if _, err := repository.ReserveReceiptPoints(ctx, newReservation("1", 10, createdAt), receiptPoints); err != nil {
+				t.Fatalf("ReserveReceiptPoints() = error %v", err)
+			}
+
+			if _, err := repository.ResolveRedemption(ctx, "1", tc.resolution, createdAt.Add(time.Minute)); err != nil {
+				t.Fatalf("ResolveRedemption() = error %v", err)
+			}
+
+			if err := repository.ReopenRedemption(ctx, "1"); err != nil {
+				t.Fatalf("ReopenRedemption() = error %v", err)
+			}
+
+			got, err := repository.GetRedemption(ctx, "1")
+			if err != nil {
+				t.Fatalf("GetRedemption() = error %v", err)
+			}
+
+			if got.Status != tc.wantStatus || (got.ResolvedAt == nil) != (tc.wantStatus == entity.RedemptionStatusReserved) {
+				t.Errorf("ReopenRedemption() = %v, want %s", got, tc.wantStatus)
+			}
+		})
+	}
+
+	repository := NewRedemptionRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+	if err := repository.ReopenRedemption(ctx, "unknown"); !errors.Is(err, entity.ErrRedemptionNotFound) {
+		t.Errorf("ReopenRedemption() error = %v, want %v", err, entity.ErrRedemptionNotFound)
+	}
+}
+
+func TestExpireRedemptions(t *testing.T) {
+	ctx := context.Background()
+	repository := NewRedemptionRepository(openTestDB(t, filepath.Join(t.TempDir(), "receipts.db")))
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	receiptPoints := []entity.RedemptionAllocation{{ReceiptID: "a", Points: 100}}
+
+	for i, createdAt := range []time.Time{createdAt, createdAt, createdAt.Add(10 * time.Minute)} {
+		if _, err := repository.ReserveReceiptPoints(ctx, newReservation(fmt.Sprint(i), 10, createdAt), receiptPoints); err != nil {
+			t.Fatalf("ReserveReceiptPoints() = error %v", err)
+		}
+	}
+
+	if _, err := repository.ResolveRedemption(ctx, "1", entity.RedemptionStatusConfirmed, createdAt); err != nil {
+		t.Fatalf("ResolveRedemption() = error %v", err)
+	}
+
+	expired, err := repository.ExpireRedemptions(ctx, createdAt.Add(20*time.Minute))
+	if err != nil {
+		t.Fatalf("ExpireRedemptions() = error %v", err)
+	}
+
+	if expired != 1 {
+		t.Errorf("ExpireRedemptions() = %d, want 1", expired)
+	}
+
+	for id, wantStatus := range map[string]string{
+		"0": entity.RedemptionStatusExpired,
+		"1": entity.RedemptionStatusConfirmed,
+		"2": entity.RedemptionStatusReserved,
+	} {
+		got, err := repository.GetRedemption(ctx, id)
+		if err != nil {
+			t.Fatalf("GetRedemption(%s) = error %v", id, err)
+		}
+
+		if got.Status != wantStatus {
+			t.Errorf("GetRedemption(%s) status = %s, want %s", id, got.Status, wantStatus)
+		}
+	}
+}
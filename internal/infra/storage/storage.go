@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/infra/storage/memory"
+	"github.com/darcops/receipt-proccessor-challenge/internal/infra/storage/sqlite"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+)
+
+// Available storage backends.
+const (
+	BackendMemory = "memory"
+	BackendSQLite = "sqlite"
+)
+
+// Config selects the storage backend used by the application.
+type Config struct {
+	Backend    string `yaml:"backend"`
+	SQLitePath string `yaml:"sqlitePath"`
+}
+
+// Storage groups the repositories of the selected backend.
+type Storage struct {
+	ReceiptRepository     port.ReceiptRepository
+	IdempotencyRepository port.IdempotencyRepository
+	FraudRepository       port.FraudRepository
+	AuditRepository       port.ReceiptAuditRepository
+	AccountRepository     port.AccountRepository
+	RedemptionRepository  port.RedemptionRepository
+	MerchantRepository    port.MerchantRepository
+	PromotionRepository   port.PromotionRepository
+	ProductRepository     port.ProductRepository
+
+	close func() error
+}
+
+// New creates the repositories of the backend selected in the config.
+func New(config Config) (*Storage, error) {
+	switch config.Backend {
+	case BackendMemory:
+		receiptRepository := memory.NewReceiptRepository()
+
+		return &Storage{
+			ReceiptRepository:     receiptRepository,
+			IdempotencyRepository: memory.NewIdempotencyRepository(),
+			FraudRepository:       memory.NewFraudRepository(receiptRepository),
+			AuditRepository:       memory.NewReceiptAuditRepository(receiptRepository),
+			AccountRepository:     memory.NewAccountRepository(),
+			RedemptionRepository:  memory.NewRedemptionRepository(),
+			MerchantRepository:    memory.NewMerchantRepository(),
+			PromotionRepository:   memory.NewPromotionRepository(),
+			ProductRepository:     memory.NewProductRepository(),
+			close:                 func() error { return nil },
+		}, nil
+
+	case BackendSQLite:
+		db, err := sqlite.Open(config.SQLitePath)
+		if err != nil {
+			return nil, fmt.Errorf("opening sqlite database %q: %w", config.SQLitePath, err)
+		}
+
+		return &Storage{
+			ReceiptRepository:     sqlite.NewReceiptRepository(db),
+			IdempotencyRepository: sqlite.NewIdempotencyRepository(db),
+			FraudRepository:       sqlite.NewFraudRepository(db),
+			AuditRepository:       sqlite.NewReceiptAuditRepository(db),
+			AccountRepository:     sqlite.NewAccountRepository(db),
+			RedemptionRepository:  sqlite.NewRedemptionRepository(db),
+			MerchantRepository:    sqlite.NewMerchantRepository(db),
+			PromotionRepository:   sqlite.NewPromotionRepository(db),
+			ProductRepository:     sqlite.NewProductRepository(db),
+			close:                 db.Close,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", config.Backend)
+	}
+}
+
+// Close releases the resources held by the storage backend.
+func (s *Storage) Close() error {
+	return s.close()
+}
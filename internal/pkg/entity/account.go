@@ -0,0 +1,136 @@
+package entity
+
+import (
+	"encoding/base64"
+	"strconv"
+	"time"
+)
+
+// Account is a loyalty account the points of its receipts are credited to.
+type Account struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Ledger accounts of the points not held by the loyalty accounts: the points
+// issued to them for their receipts and the points they redeemed.
+const (
+	LedgerAccountIssued   = "system:issued"
+	LedgerAccountRedeemed = "system:redeemed"
+)
+
+// AccountLedgerAccount returns the ledger account of the points held by a
+// loyalty account.
+func AccountLedgerAccount(accountID string) string {
+	return "account:" + accountID
+}
+
+// Types of the ledger transactions. An earning credits the points of a
+// receipt, an adjustment corrects them when the receipt is scored again,
+// amended, rejected or deleted, and a redemption spends them.
+const (
+	TransactionTypeEarning    = "earning"
+	TransactionTypeAdjustment = "adjustment"
+	TransactionTypeRedemption = "redemption"
+)
+
+// LedgerEntry debits or credits points to a ledger account.
+type LedgerEntry struct {
+	LedgerAccount string `json:"ledgerAccount"`
+	Debit         int64  `json:"debit,omitempty"`
+	Credit        int64  `json:"credit,omitempty"`
+}
+
+// LedgerTransaction moves points between a loyalty account and a system
+// ledger account with balanced entries. Amount is the change of the balance
+// of the loyalty account, negative when points are taken from it.
+type LedgerTransaction struct {
+	ID          string        `json:"id"`
+	AccountID   string        `json:"accountId"`
+	Type        string        `json:"type"`
+	ReceiptID   string        `json:"receiptId,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Amount      int64         `json:"amount"`
+	Entries     []LedgerEntry `json:"entries"`
+	CreatedAt   time.Time     `json:"createdAt"`
+}
+
+// NewLedgerTransaction builds a transaction changing the balance of a loyalty
+// account by the amount. Earnings and adjustments move the points from or to
+// the issued points, and redemptions to the redeemed points.
+func NewLedgerTransaction(id, accountID, transactionType, receiptID string, amount int64, at time.Time) LedgerTransaction {
+	counterpart := LedgerAccountIssued
+	if transactionType == TransactionTypeRedemption {
+		counterpart = LedgerAccountRedeemed
+	}
+
+	account := AccountLedgerAccount(accountID)
+
+	var entries []LedgerEntry
+	if amount >= 0 {
+		entries = []LedgerEntry{
+			{LedgerAccount: counterpart, Debit: amount},
+			{LedgerAccount: account, Credit: amount},
+		}
+	} else {
+		entries = []LedgerEntry{
+			{LedgerAccount: account, Debit: -amount},
+			{LedgerAccount: counterpart, Credit: -amount},
+		}
+	}
+
+	return LedgerTransaction{
+		ID:        id,
+		AccountID: accountID,
+		Type:      transactionType,
+		ReceiptID: receiptID,
+		Amount:    amount,
+		Entries:   entries,
+		CreatedAt: at,
+	}
+}
+
+// Balanced reports whether the debits of the transaction equal its credits.
+func (t LedgerTransaction) Balanced() bool {
+	var balance int64
+	for _, entry := range t.Entries {
+		balance += entry.Credit - entry.Debit
+	}
+
+	return balance == 0
+}
+
+// AccountBalance is the balance of the points held by a loyalty account.
+type AccountBalance struct {
+	AccountID string `json:"accountId"`
+	Balance   int64  `json:"balance"`
+}
+
+// LedgerPage is a page of the transactions of a loyalty account, newest
+// first. NextCursor gets the next page, it's empty on the last one.
+type LedgerPage struct {
+	Transactions []LedgerTransaction
+	NextCursor   string
+}
+
+// EncodeSeqCursor returns an opaque cursor for the position of an item in a
+// listing ordered by the sequence it was stored in.
+func EncodeSeqCursor(seq int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(seq, 10)))
+}
+
+// DecodeSeqCursor decodes a cursor returned by EncodeSeqCursor.
+func DecodeSeqCursor(cursor string) (int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+
+	seq, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+
+	return seq, nil
+}
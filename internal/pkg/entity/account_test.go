@@ -0,0 +1,115 @@
+package entity
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewLedgerTransaction(t *testing.T) {
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+
+		transactionType string
+		amount          int64
+
+		wantEntries []LedgerEntry
+	}{
+		{
+			name: "should credit the account with the issued points of an earning",
+
+			transactionType: TransactionTypeEarning,
+			amount:          28,
+
+			wantEntries: []LedgerEntry{
+				{LedgerAccount: LedgerAccountIssued, Debit: 28},
+				{LedgerAccount: "account:1", Credit: 28},
+			},
+		},
+		{
+			name: "should return the points of a negative adjustment to the issued points",
+
+			transactionType: TransactionTypeAdjustment,
+			amount:          -18,
+
+			wantEntries: []LedgerEntry{
+				{LedgerAccount: "account:1", Debit: 18},
+				{LedgerAccount: LedgerAccountIssued, Credit: 18},
+			},
+		},
+		{
+			name: "should debit the account with the points of a redemption",
+
+			transactionType: TransactionTypeRedemption,
+			amount:          -10,
+
+			wantEntries: []LedgerEntry{
+				{LedgerAccount: "account:1", Debit: 10},
+				{LedgerAccount: LedgerAccountRedeemed, Credit: 10},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NewLedgerTransaction("tx", "1", tc.transactionType, "", tc.amount, at)
+
+			if !reflect.DeepEqual(got.Entries, tc.wantEntries) {
+				t.Errorf("NewLedgerTransaction() entries = %v, want %v", got.Entries, tc.wantEntries)
+			}
+
+			if !got.Balanced() {
+				t.Errorf("NewLedgerTransaction() = %v, want balanced entries", got)
+			}
+
+			if got.Amount != tc.amount {
+				t.Errorf("NewLedgerTransaction() amount = %d, want %d", got.Amount, tc.amount)
+			}
+		})
+	}
+}
+
+func TestDecodeSeqCursor(t *testing.T) {
+	testCases := []struct {
+		name   string
+		cursor string
+
+		want    int64
+		wantErr error
+	}{
+		{
+			name:   "should decode an encoded cursor",
+			cursor: EncodeSeqCursor(42),
+
+			want: 42,
+		},
+		{
+			name:   "should reject a cursor that isn't base64",
+			cursor: "not a cursor!",
+
+			wantErr: ErrInvalidCursor,
+		},
+		{
+			name:   "should reject a cursor without a number",
+			cursor: "YWJj",
+
+			wantErr: ErrInvalidCursor,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := DecodeSeqCursor(tc.cursor)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("DecodeSeqCursor() error = %v, want %v", err, tc.wantErr)
+			}
+
+			if got != tc.want {
+				t.Errorf("DecodeSeqCursor() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// Changes recorded in the audit log of a receipt.
+const (
+	AuditActionAmended = "amended"
+	AuditActionDeleted = "deleted"
+)
+
+// ReceiptAuditEntry records a change made to a stored receipt: who made it,
+// when and why, the receipt before and after it, which is nil once deleted,
+// and the points it had before and after. PointsDelta is the change in the
+// awarded points, counting receipts without points as zero.
+type ReceiptAuditEntry struct {
+	ReceiptID string    `json:"receiptId"`
+	Action    string    `json:"action"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason,omitempty"`
+	At        time.Time `json:"at"`
+
+	Previous *Receipt `json:"previous,omitempty"`
+	New      *Receipt `json:"new,omitempty"`
+
+	PreviousPoints *int64 `json:"previousPoints,omitempty"`
+	NewPoints      *int64 `json:"newPoints,omitempty"`
+	PointsDelta    int64  `json:"pointsDelta"`
+}
+
+// NewReceiptAuditEntry builds the audit entry of a change from the scoring
+// state of the receipt before and after it.
+func NewReceiptAuditEntry(receiptID, action, actor, reason string, at time.Time, previous, updated *Receipt, previousPoints, newPoints ReceiptPoints) ReceiptAuditEntry {
+	entry := ReceiptAuditEntry{
+		ReceiptID: receiptID,
+		Action:    action,
+		Actor:     actor,
+		Reason:    reason,
+		At:        at,
+		Previous:  previous,
+		New:       updated,
+	}
+
+	if previousPoints.Scored() {
+		entry.PreviousPoints = &previousPoints.Points
+		entry.PointsDelta -= previousPoints.Points
+	}
+
+	if newPoints.Scored() {
+		entry.NewPoints = &newPoints.Points
+		entry.PointsDelta += newPoints.Points
+	}
+
+	return entry
+}
@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewReceiptAuditEntry(t *testing.T) {
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+
+		previousPoints ReceiptPoints
+		newPoints      ReceiptPoints
+
+		wantPrevious *int64
+		wantNew      *int64
+		wantDelta    int64
+	}{
+		{
+			name: "should record the points of an amended receipt",
+
+			previousPoints: ReceiptPoints{Status: ScoreStatusScored, Points: 28},
+			newPoints:      ReceiptPoints{Status: ScoreStatusScored, Points: 10},
+
+			wantPrevious: pointsOf(28),
+			wantNew:      pointsOf(10),
+			wantDelta:    -18,
+		},
+		{
+			name: "should count a receipt that wasn't scored as zero points",
+
+			previousPoints: ReceiptPoints{Status: ScoreStatusPending},
+			newPoints:      ReceiptPoints{Status: ScoreStatusScored, Points: 10},
+
+			wantNew:   pointsOf(10),
+			wantDelta: 10,
+		},
+		{
+			name: "should remove the points of a deleted receipt",
+
+			previousPoints: ReceiptPoints{Status: ScoreStatusScored, Points: 28},
+
+			wantPrevious: pointsOf(28),
+			wantDelta:    -28,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NewReceiptAuditEntry("1", AuditActionAmended, "support", "", at, nil, nil, tc.previousPoints, tc.newPoints)
+
+			if !equalPoints(got.PreviousPoints, tc.wantPrevious) {
+				t.Errorf("NewReceiptAuditEntry() previous points = %v, want %v", got.PreviousPoints, tc.wantPrevious)
+			}
+
+			if !equalPoints(got.NewPoints, tc.wantNew) {
+				t.Errorf("NewReceiptAuditEntry() new points = %v, want %v", got.NewPoints, tc.wantNew)
+			}
+
+			if got.PointsDelta != tc.wantDelta {
+				t.Errorf("NewReceiptAuditEntry() delta = %v, want %v", got.PointsDelta, tc.wantDelta)
+			}
+		})
+	}
+}
+
+func pointsOf(points int64) *int64 {
+	return &points
+}
+
+func equalPoints(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
@@ -0,0 +1,61 @@
+package entity
+
+import "errors"
+
+var (
+	// ErrReceiptNotFound is returned when there is no receipt stored for the given ID.
+	ErrReceiptNotFound = errors.New("receipt not found")
+
+	// ErrRuleSetNotFound is returned when there is no rule set for the given version.
+	ErrRuleSetNotFound = errors.New("rule set not found")
+
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent again
+	// with a different receipt than the one it was first used with.
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused with a different receipt")
+
+	// ErrIdempotencyKeyInProgress is returned when a submission is retried
+	// while the receipt of the first one is still being stored.
+	ErrIdempotencyKeyInProgress = errors.New("idempotency key in use by a submission in progress")
+
+	// ErrScreeningNotFound is returned when a receipt wasn't screened for fraud.
+	ErrScreeningNotFound = errors.New("fraud screening not found")
+
+	// ErrAccountNotFound is returned when there is no loyalty account for the given ID.
+	ErrAccountNotFound = errors.New("account not found")
+
+	// ErrInsufficientPoints is returned when a loyalty account or the receipts
+	// of a redemption don't have enough points for it.
+	ErrInsufficientPoints = errors.New("insufficient points")
+
+	// ErrRedemptionNotFound is returned when there is no redemption for the given ID.
+	ErrRedemptionNotFound = errors.New("redemption not found")
+
+	// ErrRedemptionResolved is returned when a redemption can't be confirmed or
+	// cancelled because it already was, or it expired.
+	ErrRedemptionResolved = errors.New("redemption already resolved")
+
+	// ErrMixedAccounts is returned when the receipts of a redemption belong to
+	// different loyalty accounts.
+	ErrMixedAccounts = errors.New("receipts belong to different accounts")
+
+	// ErrReceiptNotScored is returned when the points of a receipt are needed
+	// but weren't calculated yet.
+	ErrReceiptNotScored = errors.New("receipt points not calculated yet")
+
+	// ErrMerchantNotFound is returned when there is no merchant for the given ID.
+	ErrMerchantNotFound = errors.New("merchant not found")
+
+	// ErrMerchantAliasTaken is returned when a retailer name is already an
+	// alias of another merchant.
+	ErrMerchantAliasTaken = errors.New("merchant alias taken")
+
+	// ErrMerchantAliasNotFound is returned when a retailer name isn't an alias
+	// of the merchant.
+	ErrMerchantAliasNotFound = errors.New("merchant alias not found")
+
+	// ErrPromotionNotFound is returned when there is no promotion for the given ID.
+	ErrPromotionNotFound = errors.New("promotion not found")
+
+	// ErrProductNotFound is returned when there is no product for the given ID.
+	ErrProductNotFound = errors.New("product not found")
+)
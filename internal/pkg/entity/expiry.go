@@ -0,0 +1,136 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/util"
+)
+
+// Policies for the expiry of the points of receipts.
+const (
+	// ExpiryPolicyNever keeps the points valid forever.
+	ExpiryPolicyNever = "never"
+	// ExpiryPolicyAfterPurchase expires the points a number of months after
+	// the purchase date of their receipt.
+	ExpiryPolicyAfterPurchase = "after_purchase"
+	// ExpiryPolicyEndOfYear expires the points at the end of the calendar year
+	// their receipt was purchased in, or a number of years after it.
+	ExpiryPolicyEndOfYear = "end_of_year"
+)
+
+// ExpiryPolicy decides when the points of a receipt expire from its purchase
+// date. Points are valid until the start of their expiry date in UTC, and an
+// empty type never expires them.
+type ExpiryPolicy struct {
+	Type   string `json:"type" yaml:"type"`
+	Months int    `json:"months,omitempty" yaml:"months"`
+	Years  int    `json:"years,omitempty" yaml:"years"`
+}
+
+// PointsValidity are the points of a receipt that are still valid at a given
+// time, along with when they expire, if ever.
+type PointsValidity struct {
+	ValidPoints int64      `json:"validPoints"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Validate checks the type and the period of the policy.
+func (p ExpiryPolicy) Validate() error {
+	switch p.Type {
+	case "", ExpiryPolicyNever:
+	case ExpiryPolicyAfterPurchase:
+		if p.Months <= 0 {
+			return fmt.Errorf("the months of the %s expiry policy must be greater than zero", p.Type)
+		}
+	case ExpiryPolicyEndOfYear:
+		if p.Years < 0 {
+			return fmt.Errorf("the years of the %s expiry policy can't be negative", p.Type)
+		}
+	default:
+		return fmt.Errorf("unknown expiry policy %q, it must be %s, %s or %s",
+			p.Type, ExpiryPolicyNever, ExpiryPolicyAfterPurchase, ExpiryPolicyEndOfYear)
+	}
+
+	return nil
+}
+
+// Expires reports whether the policy ever expires points.
+func (p ExpiryPolicy) Expires() bool {
+	return p.Type != "" && p.Type != ExpiryPolicyNever
+}
+
+// ExpiresAt returns when the points of a receipt purchased on the given date,
+// in the YYYY-MM-DD format, expire. It returns false if they never do.
+func (p ExpiryPolicy) ExpiresAt(purchaseDate string) (time.Time, bool, error) {
+	if !p.Expires() {
+		return time.Time{}, false, nil
+	}
+
+	date, err := util.ParseDate(purchaseDate)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return p.expiresAt(date), true, nil
+}
+
+func (p ExpiryPolicy) expiresAt(purchaseDate time.Time) time.Time {
+	if p.Type == ExpiryPolicyEndOfYear {
+		return util.StartOfYear(purchaseDate.Year()+p.Years+1, time.UTC)
+	}
+
+	return util.AddMonths(purchaseDate, p.Months)
+}
+
+// Validity evaluates the policy on the points of a receipt at the given time.
+// Receipts that aren't scored have no valid points.
+func (p ExpiryPolicy) Validity(receipt Receipt, points ReceiptPoints, at time.Time) (PointsValidity, error) {
+	expiresAt, expires, err := p.ExpiresAt(receipt.PurchaseDate)
+	if err != nil {
+		return PointsValidity{}, err
+	}
+
+	var validity PointsValidity
+	if expires {
+		validity.ExpiresAt = &expiresAt
+	}
+
+	if points.Scored() && (!expires || at.Before(expiresAt)) {
+		validity.ValidPoints = points.Points
+	}
+
+	return validity, nil
+}
+
+// LastExpiredPurchaseDate returns the latest purchase date, in the YYYY-MM-DD
+// format, whose points have expired at the given time. The points of receipts
+// purchased on it or before have expired, and those of the later ones haven't.
+// It returns false if the policy never expires points.
+func (p ExpiryPolicy) LastExpiredPurchaseDate(at time.Time) (string, bool) {
+	if !p.Expires() {
+		return "", false
+	}
+
+	at = at.UTC()
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+
+	// The expiry date only grows with the purchase date, so the estimate is
+	// moved to the last date that has expired. With the end of the months it
+	// can be off by a few days.
+	var date time.Time
+	if p.Type == ExpiryPolicyEndOfYear {
+		date = util.StartOfYear(at.Year()-p.Years, time.UTC).AddDate(0, 0, -1)
+	} else {
+		date = util.AddMonths(day, -p.Months)
+	}
+
+	for p.expiresAt(date).After(at) {
+		date = date.AddDate(0, 0, -1)
+	}
+	for next := date.AddDate(0, 0, 1); !p.expiresAt(next).After(at); next = next.AddDate(0, 0, 1) {
+		date = next
+	}
+
+	return util.FormatDate(date), true
+}
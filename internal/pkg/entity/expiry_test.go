@@ -0,0 +1,169 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExpiryPolicyValidity(t *testing.T) {
+	scored := ReceiptPoints{Status: ScoreStatusScored, Points: 28}
+	at := time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC)
+	date := func(year int, month time.Month, day int) *time.Time {
+		t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	testCases := []struct {
+		name string
+
+		policy       ExpiryPolicy
+		purchaseDate string
+		points       ReceiptPoints
+
+		want    PointsValidity
+		wantErr bool
+	}{
+		{
+			name: "should keep the points valid forever",
+
+			policy:       ExpiryPolicy{Type: ExpiryPolicyNever},
+			purchaseDate: "2010-01-01",
+			points:       scored,
+
+			want: PointsValidity{ValidPoints: 28},
+		},
+		{
+			name: "should keep the points valid until months after the purchase",
+
+			policy:       ExpiryPolicy{Type: ExpiryPolicyAfterPurchase, Months: 12},
+			purchaseDate: "2022-01-16",
+			points:       scored,
+
+			want: PointsValidity{ValidPoints: 28, ExpiresAt: date(2023, 1, 16)},
+		},
+		{
+			name: "should expire the points months after the purchase",
+
+			policy:       ExpiryPolicy{Type: ExpiryPolicyAfterPurchase, Months: 12},
+			purchaseDate: "2022-01-15",
+			points:       scored,
+
+			want: PointsValidity{ExpiresAt: date(2023, 1, 15)},
+		},
+		{
+			name: "should expire the points at the end of the purchase year",
+
+			policy:       ExpiryPolicy{Type: ExpiryPolicyEndOfYear},
+			purchaseDate: "2022-12-31",
+			points:       scored,
+
+			want: PointsValidity{ExpiresAt: date(2023, 1, 1)},
+		},
+		{
+			name: "should keep the points valid until the end of a later year",
+
+			policy:       ExpiryPolicy{Type: ExpiryPolicyEndOfYear, Years: 1},
+			purchaseDate: "2022-01-01",
+			points:       scored,
+
+			want: PointsValidity{ValidPoints: 28, ExpiresAt: date(2024, 1, 1)},
+		},
+		{
+			name: "should have no valid points if not scored",
+
+			policy:       ExpiryPolicy{Type: ExpiryPolicyEndOfYear},
+			purchaseDate: "2023-01-01",
+			points:       ReceiptPoints{Status: ScoreStatusPending},
+
+			want: PointsValidity{ExpiresAt: date(2024, 1, 1)},
+		},
+		{
+			name: "should fail due invalid purchase date",
+
+			policy:       ExpiryPolicy{Type: ExpiryPolicyEndOfYear},
+			purchaseDate: "2022-02-30",
+			points:       scored,
+
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.policy.Validity(Receipt{PurchaseDate: tc.purchaseDate}, tc.points, at)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Validity() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Validity() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLastExpiredPurchaseDate(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		policy ExpiryPolicy
+		at     time.Time
+
+		want   string
+		wantOK bool
+	}{
+		{
+			name: "should never expire points",
+
+			policy: ExpiryPolicy{Type: ExpiryPolicyNever},
+			at:     time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "should expire the purchases of months before",
+
+			policy: ExpiryPolicy{Type: ExpiryPolicyAfterPurchase, Months: 12},
+			at:     time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC),
+
+			want:   "2022-01-15",
+			wantOK: true,
+		},
+		{
+			name: "should expire the purchases moved to the end of a shorter month",
+
+			policy: ExpiryPolicy{Type: ExpiryPolicyAfterPurchase, Months: 1},
+			at:     time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC),
+
+			want:   "2023-01-31",
+			wantOK: true,
+		},
+		{
+			name: "should not expire the purchases of the day before midnight",
+
+			policy: ExpiryPolicy{Type: ExpiryPolicyAfterPurchase, Months: 1},
+			at:     time.Date(2023, 3, 31, 23, 59, 0, 0, time.UTC),
+
+			want:   "2023-02-28",
+			wantOK: true,
+		},
+		{
+			name: "should expire the purchases of the years before",
+
+			policy: ExpiryPolicy{Type: ExpiryPolicyEndOfYear, Years: 1},
+			at:     time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+
+			want:   "2021-12-31",
+			wantOK: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := tc.policy.LastExpiredPurchaseDate(tc.at)
+
+			if got != tc.want || ok != tc.wantOK {
+				t.Errorf("LastExpiredPurchaseDate() = %v, %v, want %v, %v", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
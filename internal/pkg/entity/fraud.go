@@ -0,0 +1,34 @@
+package entity
+
+// Screening statuses of a receipt. Receipts held for review or rejected aren't
+// awarded points.
+const (
+	ScreeningStatusClear    = "clear"
+	ScreeningStatusHeld     = "held_for_review"
+	ScreeningStatusRejected = "rejected"
+)
+
+// ReceiptFingerprint identifies a physical receipt regardless of small edits.
+// Exact covers every field of the normalized receipt, while Partial only
+// covers the retailer, purchase date and total, so receipts with edited items
+// are also matched.
+type ReceiptFingerprint struct {
+	ReceiptID string
+	Exact     string
+	Partial   string
+}
+
+// FraudScreening is the outcome of screening a receipt for duplicates.
+// DuplicateOf is the ID of the receipt it duplicates, if any, and Reviewed
+// whether the status was decided by a review.
+type FraudScreening struct {
+	Status      string `json:"status"`
+	Reason      string `json:"reason,omitempty"`
+	DuplicateOf string `json:"duplicateOf,omitempty"`
+	Reviewed    bool   `json:"reviewed,omitempty"`
+}
+
+// Awarded reports whether the receipt of the screening can be awarded points.
+func (s FraudScreening) Awarded() bool {
+	return s.Status == ScreeningStatusClear
+}
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// IdempotencyRecord links a submission to the receipt created by it, so
+// retries of the same submission return the same receipt. Key identifies the
+// submission and RequestHash is the canonical hash of the submitted receipt.
+// The record reserves the key while the receipt is stored, and is Completed
+// once it is, so retries never get the ID of a receipt that isn't stored.
+type IdempotencyRecord struct {
+	Key         string
+	RequestHash string
+	ReceiptID   string
+	Completed   bool
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// Expired reports whether the record is no longer retained at the given time.
+func (r IdempotencyRecord) Expired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
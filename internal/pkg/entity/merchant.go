@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Merchant is the canonical merchant of the receipts whose retailer is any of
+// its aliases. Retailers match an alias when their normalized names are equal,
+// and each normalized name is an alias of at most one merchant.
+type Merchant struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Aliases   []string  `json:"aliases"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// NormalizeRetailer returns the key of a retailer name, which is the same for
+// names that only differ in case, whitespace, punctuation or accents, e.g.
+// "M&M Corner Market" and "m & m corner márket". Compatibility characters,
+// such as full-width letters, are replaced by their plain equivalents.
+func NormalizeRetailer(name string) string {
+	var normalized strings.Builder
+	accented := false
+
+	// The decomposition splits the accents from their letters. Other scripts
+	// use marks to tell letters apart, so only the accents of the alphabets
+	// are removed. Casers can't be shared between goroutines.
+	for _, r := range cases.Fold().String(norm.NFKD.String(name)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			accented = unicode.In(r, unicode.Latin, unicode.Greek, unicode.Cyrillic)
+			normalized.WriteRune(r)
+		case unicode.IsMark(r) && !accented:
+			normalized.WriteRune(r)
+		}
+	}
+
+	return norm.NFC.String(normalized.String())
+}
@@ -0,0 +1,71 @@
+package entity
+
+import "testing"
+
+func TestNormalizeRetailer(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		retailer string
+
+		want string
+	}{
+		{
+			name: "should ignore the case, whitespace and punctuation",
+
+			retailer: " M & M CORNER Market ",
+
+			want: "mmcornermarket",
+		},
+		{
+			name: "should remove the accents",
+
+			retailer: "Café Ñandú",
+
+			want: "cafenandu",
+		},
+		{
+			name: "should fold the case beyond lowercase",
+
+			retailer: "Straße",
+
+			want: "strasse",
+		},
+		{
+			name: "should replace the compatibility characters",
+
+			retailer: "ＴＡＲＧＥＴ ２",
+
+			want: "target2",
+		},
+		{
+			name: "should keep the letters of other scripts",
+
+			retailer: "東京マート",
+
+			want: "東京マート",
+		},
+		{
+			name: "should keep the marks of other scripts",
+
+			retailer: "ガスト नमस्ते",
+
+			want: "ガストनमस्ते",
+		},
+		{
+			name: "should be empty without letters or digits",
+
+			retailer: "& - !",
+
+			want: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NormalizeRetailer(tc.retailer); got != tc.want {
+				t.Errorf("NormalizeRetailer() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// moneyPattern is the format of money amounts defined by the API spec.
+var moneyPattern = regexp.MustCompile(`^\d+\.\d{2}$`)
+
+// Money is an amount of money in cents. It is encoded in JSON as a string with
+// two decimals, e.g. "6.49", following the format of the API spec.
+type Money int64
+
+// ParseMoney parses an amount of money with the format of the API spec, digits
+// followed by a dot and exactly two decimals, e.g. "6.49".
+func ParseMoney(s string) (Money, error) {
+	if !moneyPattern.MatchString(s) {
+		return 0, fmt.Errorf("invalid money amount %q: must have the format %s", s, moneyPattern)
+	}
+
+	dollarsText, centsText, _ := strings.Cut(s, ".")
+
+	dollars, err := strconv.ParseInt(dollarsText, 10, 64)
+	if err != nil || dollars > (math.MaxInt64-99)/100 {
+		return 0, fmt.Errorf("invalid money amount %q: too large", s)
+	}
+
+	cents, _ := strconv.ParseInt(centsText, 10, 64)
+
+	return Money(dollars*100 + cents), nil
+}
+
+// MustParseMoney is like ParseMoney but panics if the amount can't be parsed.
+// It simplifies the initialization of amounts known to be valid.
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// Cents returns the amount in cents.
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// String formats the amount with two decimals, e.g. "6.49".
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// MarshalJSON encodes the amount as a string with two decimals.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON decodes an amount from a string with the format of the API spec.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid money amount %s: must be a string", data)
+	}
+
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		amount string
+
+		want    Money
+		wantErr bool
+	}{
+		{
+			name: "should parse zero",
+
+			amount: "0.00",
+
+			want: 0,
+		},
+		{
+			name: "should parse cents",
+
+			amount: "0.01",
+
+			want: 1,
+		},
+		{
+			name: "should parse dollars and cents",
+
+			amount: "35.35",
+
+			want: 3535,
+		},
+		{
+			name: "should parse leading zeros",
+
+			amount: "007.10",
+
+			want: 710,
+		},
+		{
+			name: "should parse the largest amount",
+
+			amount: "92233720368547757.99",
+
+			want: 9223372036854775799,
+		},
+		{
+			name: "should fail due amount too large",
+
+			amount: "92233720368547758.00",
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due amount overflowing the dollars",
+
+			amount: "99999999999999999999999.00",
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due missing cents",
+
+			amount: "1",
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due one decimal",
+
+			amount: "1.5",
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due three decimals",
+
+			amount: "1.505",
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due missing dollars",
+
+			amount: ".50",
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due negative amount",
+
+			amount: "-1.00",
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due currency symbol",
+
+			amount: "$1.00",
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due surrounding spaces",
+
+			amount: " 1.00 ",
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due thousands separator",
+
+			amount: "1,000.00",
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due empty amount",
+
+			amount: "",
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due invalid total",
+
+			amount: "invalid total",
+
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseMoney(tc.amount)
+
+			if got != tc.want {
+				t.Errorf("ParseMoney() = %v, want %v", got.Cents(), tc.want.Cents())
+			}
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ParseMoney() = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		amount Money
+
+		want string
+	}{
+		{
+			name: "should format zero",
+
+			amount: 0,
+
+			want: "0.00",
+		},
+		{
+			name: "should format cents",
+
+			amount: 5,
+
+			want: "0.05",
+		},
+		{
+			name: "should format dollars and cents",
+
+			amount: 3535,
+
+			want: "35.35",
+		},
+		{
+			name: "should format negative amounts",
+
+			amount: -125,
+
+			want: "-1.25",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.amount.String()
+
+			if got != tc.want {
+				t.Errorf("String() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		json string
+
+		want    Money
+		wantErr bool
+	}{
+		{
+			name: "should decode a string amount",
+
+			json: `"6.49"`,
+
+			want: 649,
+		},
+		{
+			name: "should fail due number instead of string",
+
+			json: `6.49`,
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due malformed amount",
+
+			json: `"6.4"`,
+
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got Money
+			err := json.Unmarshal([]byte(tc.json), &got)
+
+			if got != tc.want {
+				t.Errorf("UnmarshalJSON() = %v, want %v", got, tc.want)
+			}
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("UnmarshalJSON() = %v, want %v", err, tc.wantErr)
+			}
+
+			if tc.wantErr {
+				return
+			}
+
+			encoded, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("MarshalJSON() = error %v", err)
+			}
+
+			if string(encoded) != tc.json {
+				t.Errorf("MarshalJSON() = %s, want %s", encoded, tc.json)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// RulePoints are the points awarded to a receipt by a single rule.
+type RulePoints struct {
+	Rule   string `json:"rule"`
+	Points int64  `json:"points"`
+	Reason string `json:"reason"`
+}
+
+// PointsBreakdown explains how the points of a receipt were calculated. The
+// points are those of the rules plus those of the promotions that applied.
+type PointsBreakdown struct {
+	Points         int64             `json:"points"`
+	RuleSetVersion string            `json:"ruleSetVersion"`
+	Rules          []RulePoints      `json:"rules"`
+	Promotions     []PromotionPoints `json:"promotions,omitempty"`
+}
+
+// Scoring statuses of a receipt.
+const (
+	ScoreStatusPending = "pending"
+	ScoreStatusScored  = "scored"
+	ScoreStatusFailed  = "failed"
+)
+
+// ReceiptPoints are the scoring state of a receipt. Once scored, they hold the
+// calculated points, the version of the rule set used to calculate them and
+// when they were calculated. If the scoring failed Error describes why.
+// ExpiredAt is when the points were found expired by the expiry policy, they
+// are marked again if the receipt is scored again after it. Breakdown is how
+// the points were calculated when scored, and is nil for the receipts scored
+// before it was kept.
+type ReceiptPoints struct {
+	Status         string           `json:"status"`
+	Points         int64            `json:"points"`
+	RuleSetVersion string           `json:"ruleSetVersion,omitempty"`
+	Error          string           `json:"error,omitempty"`
+	ScoredAt       *time.Time       `json:"scoredAt,omitempty"`
+	ExpiredAt      *time.Time       `json:"expiredAt,omitempty"`
+	Breakdown      *PointsBreakdown `json:"-"`
+}
+
+// Scored reports whether the points of the receipt were calculated.
+func (p ReceiptPoints) Scored() bool {
+	return p.Status == ScoreStatusScored
+}
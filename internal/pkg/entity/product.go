@@ -0,0 +1,233 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Product is an entry of the catalogue the items of the receipts are matched
+// to by their short descriptions. An item matches a product when its
+// description has every word of the product name or of any of its keywords,
+// compared loosely so abbreviations and typos still match, e.g.
+// "MTN DEW 12PK" matches "Mountain Dew".
+type Product struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Brand     string    `json:"brand,omitempty"`
+	Category  string    `json:"category,omitempty"`
+	Keywords  []string  `json:"keywords"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Validate checks that the name and the keywords of the product have words to
+// match, returning every invalid field.
+func (p Product) Validate() []FieldError {
+	var fieldErrors []FieldError
+
+	if len(ProductWords(p.Name)) == 0 {
+		fieldErrors = append(fieldErrors, FieldError{Field: "name", Code: FieldErrorRequired, Message: "name must have letters or digits"})
+	}
+
+	for i, keyword := range p.Keywords {
+		if len(ProductWords(keyword)) == 0 {
+			field := fmt.Sprintf("keywords[%d]", i)
+			fieldErrors = append(fieldErrors, FieldError{Field: field, Code: FieldErrorRequired, Message: field + " must have letters or digits"})
+		}
+	}
+
+	return fieldErrors
+}
+
+// productAbbreviations are the abbreviations of the item descriptions too
+// short to be matched by their letters, mostly of sizes and packagings.
+var productAbbreviations = map[string]string{
+	"pk":  "pack",
+	"ct":  "count",
+	"oz":  "ounce",
+	"lb":  "pound",
+	"lbs": "pound",
+	"gal": "gallon",
+	"qt":  "quart",
+	"pt":  "pint",
+	"dz":  "dozen",
+	"bx":  "box",
+	"lg":  "large",
+	"sm":  "small",
+	"md":  "medium",
+	"xl":  "extra large",
+}
+
+// Qualities of the match of a word of a product, better matches are greater.
+const (
+	wordMatchTypo = iota + 1
+	wordMatchAbbreviation
+	wordMatchExact
+)
+
+// ProductWords splits a description or a product name in its words, which are
+// the runs of letters or digits, so "12PK" is "12" and "pack". The words are
+// folded, without accents, and their known abbreviations expanded.
+func ProductWords(description string) []string {
+	var words []string
+	var word strings.Builder
+	digits := false
+
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+
+		if expansion, ok := productAbbreviations[word.String()]; ok {
+			words = append(words, strings.Fields(expansion)...)
+		} else {
+			words = append(words, word.String())
+		}
+		word.Reset()
+	}
+
+	// As the retailer names, the accents are removed after decomposing the
+	// letters. Casers can't be shared between goroutines.
+	for _, r := range cases.Fold().String(norm.NFKD.String(description)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if word.Len() > 0 && unicode.IsDigit(r) != digits {
+				flush()
+			}
+			digits = unicode.IsDigit(r)
+			word.WriteRune(r)
+		case unicode.IsMark(r):
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return words
+}
+
+// Match returns how well the words of a description match the product, or
+// false if they don't. The score grows with the number of words matched and
+// how closely, so more specific products score higher.
+func (p Product) Match(descriptionWords []string) (int, bool) {
+	best, matched := 0, false
+
+	for _, term := range append([]string{p.Name}, p.Keywords...) {
+		if score, ok := matchWords(ProductWords(term), descriptionWords); ok && score > best {
+			best, matched = score, true
+		}
+	}
+
+	return best, matched
+}
+
+// matchWords returns the sum of the qualities of the matches of every term
+// word in the description, or false if any of them is missing.
+func matchWords(termWords, descriptionWords []string) (int, bool) {
+	if len(termWords) == 0 {
+		return 0, false
+	}
+
+	score := 0
+	for _, termWord := range termWords {
+		best := 0
+		for _, descriptionWord := range descriptionWords {
+			if quality := matchWord(termWord, descriptionWord); quality > best {
+				best = quality
+			}
+		}
+
+		if best == 0 {
+			return 0, false
+		}
+		score += best
+	}
+
+	return score, true
+}
+
+// matchWord returns the quality of the match of a word of a product by a word
+// of a description, or zero if they don't match. Descriptions abbreviate words
+// by truncating them or skipping letters, as "gatr" or "mtn", so a word with
+// the same first letter and the rest of its letters in order is an
+// abbreviation. Longer words match with a typo.
+func matchWord(productWord, descriptionWord string) int {
+	if productWord == descriptionWord {
+		return wordMatchExact
+	}
+
+	// Numbers, such as sizes, only match exactly.
+	if isNumber(productWord) || isNumber(descriptionWord) {
+		return 0
+	}
+
+	product, description := []rune(productWord), []rune(descriptionWord)
+
+	if len(description) >= 3 && len(description) < len(product) && description[0] == product[0] && isSubsequence(description, product) {
+		return wordMatchAbbreviation
+	}
+
+	if len(product) >= 5 && len(description) >= 5 && withinOneEdit(product, description) {
+		return wordMatchTypo
+	}
+
+	return 0
+}
+
+func isNumber(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isSubsequence reports whether the runes of a are in b in the same order.
+func isSubsequence(a, b []rune) bool {
+	i := 0
+	for _, r := range b {
+		if i < len(a) && a[i] == r {
+			i++
+		}
+	}
+
+	return i == len(a)
+}
+
+// withinOneEdit reports whether a and b differ in at most one inserted,
+// deleted or replaced rune.
+func withinOneEdit(a, b []rune) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(b)-len(a) > 1 {
+		return false
+	}
+
+	i, j, edits := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i] == b[j] {
+			i++
+			j++
+			continue
+		}
+
+		edits++
+		if edits > 1 {
+			return false
+		}
+
+		if len(a) == len(b) {
+			i++
+		}
+		j++
+	}
+
+	return edits+(len(b)-j)+(len(a)-i) <= 1
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProductWords(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		description string
+
+		want []string
+	}{
+		{name: "should split sizes and expand abbreviations", description: "Gatorade 12PK", want: []string{"gatorade", "12", "pack"}},
+		{name: "should ignore case and punctuation", description: "Doritos Nacho-Cheese 9.25oz", want: []string{"doritos", "nacho", "cheese", "9", "25", "ounce"}},
+		{name: "should remove accents", description: "Café Molído", want: []string{"cafe", "molido"}},
+		{name: "should expand to several words", description: "Tee XL", want: []string{"tee", "extra", "large"}},
+		{name: "should return no words", description: " - ", want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ProductWords(tc.description); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ProductWords() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestProductMatch(t *testing.T) {
+	gatorade := Product{Name: "Gatorade"}
+	mountainDew := Product{Name: "Mountain Dew", Keywords: []string{"Mtn Dew"}}
+	doritos := Product{Name: "Doritos Nacho Cheese 9.25 oz"}
+
+	testCases := []struct {
+		name string
+
+		product     Product
+		description string
+
+		want      int
+		wantMatch bool
+	}{
+		{name: "should match the exact name", product: gatorade, description: "Gatorade 12PK", want: 3, wantMatch: true},
+		{name: "should match a truncated word", product: gatorade, description: "GATORAD 12PK", want: 2, wantMatch: true},
+		{name: "should match a word without vowels", product: mountainDew, description: "MTN DEW 12PK", want: 6, wantMatch: true},
+		{name: "should match a typo", product: gatorade, description: "Gatorabe", want: 1, wantMatch: true},
+		{name: "should match the sizes exactly", product: doritos, description: "Doritos Nacho Chs 9.25oz", want: 17, wantMatch: true},
+		{name: "should not match another size", product: doritos, description: "Doritos Nacho Cheese 1oz"},
+		{name: "should not match a missing word", product: mountainDew, description: "Mountain Water"},
+		{name: "should not match a short word", product: gatorade, description: "GA 12PK"},
+		{name: "should not match another product", product: gatorade, description: "Pepsi 12PK"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := tc.product.Match(ProductWords(tc.description))
+
+			if got != tc.want || ok != tc.wantMatch {
+				t.Errorf("Match() = %v, %v, want %v, %v", got, ok, tc.want, tc.wantMatch)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/util"
+)
+
+// Stacking rules of the promotions that apply to the same receipt.
+const (
+	// PromotionStackingCombine adds the points of the promotion to those of
+	// the other combinable promotions.
+	PromotionStackingCombine = "combine"
+	// PromotionStackingExclusive applies the promotion alone, instead of the
+	// combinable ones, when it awards more points than all of them together.
+	PromotionStackingExclusive = "exclusive"
+)
+
+// maxPromotionMultiplier bounds the multipliers of the promotions, so the
+// points they award can't overflow.
+const maxPromotionMultiplier = 100
+
+// promotionMultiplierScale is the precision kept from the multipliers of the
+// promotions, so the points are multiplied with integer arithmetic.
+const promotionMultiplierScale = 1_000_000
+
+// Promotion awards bonus points to the receipts of a merchant, or of any
+// merchant if MerchantID is empty, purchased between StartDate and EndDate,
+// both included and in the YYYY-MM-DD format. An empty date leaves the window
+// open on that side. The promotion awards the base points of the rules
+// multiplied by Multiplier minus one, plus Bonus, up to MaxPoints if it isn't
+// zero.
+type Promotion struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	MerchantID string    `json:"merchantId,omitempty"`
+	StartDate  string    `json:"startDate,omitempty"`
+	EndDate    string    `json:"endDate,omitempty"`
+	Multiplier float64   `json:"multiplier,omitempty"`
+	Bonus      int64     `json:"bonus,omitempty"`
+	MaxPoints  int64     `json:"maxPoints,omitempty"`
+	Stacking   string    `json:"stacking"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// PromotionPoints are the points awarded to a receipt by a promotion.
+type PromotionPoints struct {
+	PromotionID string `json:"promotionId"`
+	Name        string `json:"name"`
+	Points      int64  `json:"points"`
+	Reason      string `json:"reason"`
+}
+
+// Validate checks the fields of the promotion, returning every invalid one.
+func (p Promotion) Validate() []FieldError {
+	var fieldErrors []FieldError
+
+	addError := func(field, code, message string) {
+		fieldErrors = append(fieldErrors, FieldError{Field: field, Code: code, Message: message})
+	}
+
+	if strings.TrimSpace(p.Name) == "" {
+		addError("name", FieldErrorRequired, "name is required")
+	}
+
+	for _, date := range []struct{ field, value string }{{"startDate", p.StartDate}, {"endDate", p.EndDate}} {
+		if date.value == "" {
+			continue
+		}
+		if _, err := util.ParseDate(date.value); err != nil {
+			addError(date.field, FieldErrorInvalidDate, date.field+" must be a valid date in the YYYY-MM-DD format")
+		}
+	}
+	if fieldErrors == nil && p.StartDate != "" && p.EndDate != "" && p.StartDate > p.EndDate {
+		addError("endDate", FieldErrorInvalidValue, "endDate must not be before startDate")
+	}
+
+	if p.Multiplier != 0 && (p.Multiplier < 1 || p.Multiplier > maxPromotionMultiplier) {
+		addError("multiplier", FieldErrorInvalidValue, fmt.Sprintf("multiplier must be between 1 and %d", maxPromotionMultiplier))
+	}
+	if p.Bonus < 0 {
+		addError("bonus", FieldErrorInvalidValue, "bonus must not be negative")
+	}
+	if p.Multiplier <= 1 && p.Bonus <= 0 {
+		addError("multiplier", FieldErrorRequired, "a multiplier greater than 1 or a bonus is required")
+	}
+	if p.MaxPoints < 0 {
+		addError("maxPoints", FieldErrorInvalidValue, "maxPoints must not be negative")
+	}
+
+	switch p.Stacking {
+	case "", PromotionStackingCombine, PromotionStackingExclusive:
+	default:
+		addError("stacking", FieldErrorInvalidValue, fmt.Sprintf("stacking must be %s or %s",
+			PromotionStackingCombine, PromotionStackingExclusive))
+	}
+
+	return fieldErrors
+}
+
+// Exclusive reports whether the promotion can't be combined with others.
+func (p Promotion) Exclusive() bool {
+	return p.Stacking == PromotionStackingExclusive
+}
+
+// ActiveOn reports whether a receipt purchased on the given date, in the
+// YYYY-MM-DD format, is within the window of the promotion.
+func (p Promotion) ActiveOn(purchaseDate string) bool {
+	return (p.StartDate == "" || p.StartDate <= purchaseDate) && (p.EndDate == "" || purchaseDate <= p.EndDate)
+}
+
+// Applies reports whether the promotion applies to a receipt of the merchant
+// purchased on the given date.
+func (p Promotion) Applies(merchantID, purchaseDate string) bool {
+	if p.MerchantID != "" && p.MerchantID != merchantID {
+		return false
+	}
+
+	return p.ActiveOn(purchaseDate)
+}
+
+// Points returns the points the promotion awards to a receipt with the given
+// base points. The multiplied points are rounded down.
+func (p Promotion) Points(basePoints int64) PromotionPoints {
+	var reasons []string
+	var points int64
+
+	if p.Multiplier > 1 {
+		scaledMultiplier := big.NewInt(int64(math.Round((p.Multiplier - 1) * promotionMultiplierScale)))
+
+		extra := new(big.Int).Mul(big.NewInt(basePoints), scaledMultiplier)
+		extra.Quo(extra, big.NewInt(promotionMultiplierScale))
+
+		points = math.MaxInt64
+		if extra.IsInt64() {
+			points = extra.Int64()
+		}
+
+		reasons = append(reasons, fmt.Sprintf("%gx the %d base points", p.Multiplier, basePoints))
+	}
+
+	if p.Bonus > 0 {
+		if points > math.MaxInt64-p.Bonus {
+			points = math.MaxInt64
+		} else {
+			points += p.Bonus
+		}
+
+		reasons = append(reasons, fmt.Sprintf("%d bonus points", p.Bonus))
+	}
+
+	reason := strings.Join(reasons, " plus ")
+	if p.MaxPoints > 0 && points > p.MaxPoints {
+		points = p.MaxPoints
+		reason += fmt.Sprintf(", capped at %d points", p.MaxPoints)
+	}
+
+	return PromotionPoints{
+		PromotionID: p.ID,
+		Name:        p.Name,
+		Points:      points,
+		Reason:      reason,
+	}
+}
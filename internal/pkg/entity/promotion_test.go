@@ -0,0 +1,191 @@
+package entity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPromotionPoints(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		promotion  Promotion
+		basePoints int64
+
+		want       int64
+		wantReason string
+	}{
+		{
+			name: "should multiply the base points",
+
+			promotion:  Promotion{Multiplier: 2},
+			basePoints: 28,
+
+			want:       28,
+			wantReason: "2x the 28 base points",
+		},
+		{
+			name: "should round the multiplied points down",
+
+			promotion:  Promotion{Multiplier: 1.5},
+			basePoints: 27,
+
+			want:       13,
+			wantReason: "1.5x the 27 base points",
+		},
+		{
+			name: "should add the bonus",
+
+			promotion:  Promotion{Bonus: 100},
+			basePoints: 28,
+
+			want:       100,
+			wantReason: "100 bonus points",
+		},
+		{
+			name: "should multiply and add the bonus",
+
+			promotion:  Promotion{Multiplier: 3, Bonus: 10},
+			basePoints: 5,
+
+			want:       20,
+			wantReason: "3x the 5 base points plus 10 bonus points",
+		},
+		{
+			name: "should cap the points",
+
+			promotion:  Promotion{Multiplier: 2, Bonus: 100, MaxPoints: 50},
+			basePoints: 28,
+
+			want:       50,
+			wantReason: "2x the 28 base points plus 100 bonus points, capped at 50 points",
+		},
+		{
+			name: "should not overflow",
+
+			promotion:  Promotion{Multiplier: 100, Bonus: 1},
+			basePoints: math.MaxInt64,
+
+			want:       math.MaxInt64,
+			wantReason: "100x the 9223372036854775807 base points plus 1 bonus points",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.promotion.Points(tc.basePoints)
+
+			if got.Points != tc.want {
+				t.Errorf("Points() = %v, want %v", got.Points, tc.want)
+			}
+
+			if got.Reason != tc.wantReason {
+				t.Errorf("Points() reason = %q, want %q", got.Reason, tc.wantReason)
+			}
+		})
+	}
+}
+
+func TestPromotionApplies(t *testing.T) {
+	promotion := Promotion{MerchantID: "target", StartDate: "2024-03-01", EndDate: "2024-03-07"}
+
+	testCases := []struct {
+		name string
+
+		promotion    Promotion
+		merchantID   string
+		purchaseDate string
+
+		want bool
+	}{
+		{name: "should apply on the first day", promotion: promotion, merchantID: "target", purchaseDate: "2024-03-01", want: true},
+		{name: "should apply on the last day", promotion: promotion, merchantID: "target", purchaseDate: "2024-03-07", want: true},
+		{name: "should not apply before the window", promotion: promotion, merchantID: "target", purchaseDate: "2024-02-29"},
+		{name: "should not apply after the window", promotion: promotion, merchantID: "target", purchaseDate: "2024-03-08"},
+		{name: "should not apply to another merchant", promotion: promotion, merchantID: "walmart", purchaseDate: "2024-03-02"},
+		{name: "should not apply without merchant", promotion: promotion, purchaseDate: "2024-03-02"},
+		{name: "should apply to any merchant", promotion: Promotion{StartDate: "2024-03-01"}, purchaseDate: "2030-01-01", want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.promotion.Applies(tc.merchantID, tc.purchaseDate); got != tc.want {
+				t.Errorf("Applies() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPromotionValidate(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		promotion Promotion
+
+		wantFields []string
+	}{
+		{
+			name: "should accept a multiplier",
+
+			promotion: Promotion{Name: "Double points", Multiplier: 2, StartDate: "2024-03-01", EndDate: "2024-03-01"},
+		},
+		{
+			name: "should accept a bonus",
+
+			promotion: Promotion{Name: "New partner", Bonus: 100, Stacking: PromotionStackingExclusive},
+		},
+		{
+			name: "should fail due missing name and award",
+
+			promotion: Promotion{Name: " "},
+
+			wantFields: []string{"name", "multiplier"},
+		},
+		{
+			name: "should fail due invalid dates",
+
+			promotion: Promotion{Name: "Double points", Multiplier: 2, StartDate: "2024-02-30", EndDate: "03/01/2024"},
+
+			wantFields: []string{"startDate", "endDate"},
+		},
+		{
+			name: "should fail due window ending before it starts",
+
+			promotion: Promotion{Name: "Double points", Multiplier: 2, StartDate: "2024-03-07", EndDate: "2024-03-01"},
+
+			wantFields: []string{"endDate"},
+		},
+		{
+			name: "should fail due invalid amounts",
+
+			promotion: Promotion{Name: "Double points", Multiplier: 0.5, Bonus: -1, MaxPoints: -1},
+
+			wantFields: []string{"multiplier", "bonus", "multiplier", "maxPoints"},
+		},
+		{
+			name: "should fail due unknown stacking",
+
+			promotion: Promotion{Name: "Double points", Multiplier: 2, Stacking: "best"},
+
+			wantFields: []string{"stacking"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, fieldError := range tc.promotion.Validate() {
+				got = append(got, fieldError.Field)
+			}
+
+			if len(got) != len(tc.wantFields) {
+				t.Fatalf("Validate() = %v, want %v", got, tc.wantFields)
+			}
+			for i := range got {
+				if got[i] != tc.wantFields[i] {
+					t.Errorf("Validate() = %v, want %v", got, tc.wantFields)
+				}
+			}
+		})
+	}
+}
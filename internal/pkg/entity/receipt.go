@@ -1,14 +1,85 @@
 package entity
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"time"
+)
+
+// Receipt fields are checked by the receipt service validation instead of
+// binding tags, so every invalid field is reported in the same way.
 type Receipt struct {
-	Retailer     string `json:"retailer" binding:"required"`
-	PurchaseDate string `json:"purchaseDate" binding:"required"`
-	PurchaseTime string `json:"purchaseTime" binding:"required"`
+	Retailer     string `json:"retailer"`
+	PurchaseDate string `json:"purchaseDate"`
+	PurchaseTime string `json:"purchaseTime"`
 	Items        []Item `json:"items"`
-	Total        string `json:"total" binding:"required"`
+	Total        Money  `json:"total"`
 }
 
+// Item is a line of a receipt. Price is what was paid for the whole line.
+// Quantity, UnitPrice and Discount are optional: a line without quantity is a
+// single unit, and when a unit price is given the price must be the quantity
+// times the unit price, minus the discount. They are omitted from the JSON when
+// zero, so the receipts without them are encoded as before.
 type Item struct {
-	ShortDescription string `json:"shortDescription" binding:"required"`
-	Price            string `json:"price" binding:"required"`
+	ShortDescription string `json:"shortDescription"`
+	Price            Money  `json:"price"`
+	Quantity         int64  `json:"quantity,omitempty"`
+	UnitPrice        Money  `json:"unitPrice,omitempty"`
+	Discount         Money  `json:"discount,omitempty"`
+}
+
+// Units returns the number of units of the item, one if it has no quantity.
+func (i Item) Units() int64 {
+	if i.Quantity == 0 {
+		return 1
+	}
+
+	return i.Quantity
+}
+
+// LinePrice returns the quantity times the unit price, minus the discount,
+// which the price must match. It returns false if the item has no unit price
+// or the amount overflows.
+func (i Item) LinePrice() (Money, bool) {
+	if i.UnitPrice == 0 {
+		return 0, false
+	}
+
+	units := i.Units()
+	if units < 0 || int64(i.UnitPrice) > math.MaxInt64/units {
+		return 0, false
+	}
+
+	return Money(units*int64(i.UnitPrice)) - i.Discount, true
+}
+
+// ReceiptRecord is a receipt as it is kept by the storage, identified by its
+// ID. SubmittedAt is when the receipt was first stored, it's zero for receipts
+// stored before it was recorded. AccountID is the loyalty account the receipt
+// was submitted to, if any, and MerchantID the merchant its retailer is an
+// alias of, if any. ItemProductIDs are the products of the catalogue the items
+// matched, in the same order and empty for the items that matched none, or
+// nil if none did.
+type ReceiptRecord struct {
+	ID             string    `json:"id"`
+	Receipt        Receipt   `json:"receipt"`
+	SubmittedAt    time.Time `json:"submittedAt"`
+	AccountID      string    `json:"accountId,omitempty"`
+	MerchantID     string    `json:"merchantId,omitempty"`
+	ItemProductIDs []string  `json:"itemProductIds,omitempty"`
+}
+
+// CanonicalHash returns the SHA-256 hash of the receipt encoded as JSON, in
+// hex. Receipts that only differ in the formatting of the submitted JSON, such
+// as the whitespace or the order of the keys, have the same hash.
+func (r Receipt) CanonicalHash() string {
+	// The fields of a struct are always encoded in the same order, and a
+	// receipt has no values that can fail to be encoded.
+	data, _ := json.Marshal(r)
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,172 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// receiptDocument is a receipt as it is sent in JSON. Its amounts and
+// quantities are pointers, as a missing amount or quantity would be decoded
+// as zero, which is a valid amount and a single unit.
+type receiptDocument struct {
+	Retailer     string         `json:"retailer"`
+	PurchaseDate string         `json:"purchaseDate"`
+	PurchaseTime string         `json:"purchaseTime"`
+	Items        []itemDocument `json:"items"`
+	Total        *Money         `json:"total"`
+}
+
+// itemDocument is an item as it is sent in JSON.
+type itemDocument struct {
+	ShortDescription string `json:"shortDescription"`
+	Price            *Money `json:"price"`
+	Quantity         *int64 `json:"quantity"`
+	UnitPrice        Money  `json:"unitPrice"`
+	Discount         Money  `json:"discount"`
+}
+
+// receiptAmounts holds the raw amounts of money of a receipt, to find which of
+// them are invalid when a receipt can't be decoded.
+type receiptAmounts struct {
+	Items []struct {
+		Price     *json.RawMessage `json:"price"`
+		UnitPrice *json.RawMessage `json:"unitPrice"`
+		Discount  *json.RawMessage `json:"discount"`
+	} `json:"items"`
+	Total *json.RawMessage `json:"total"`
+}
+
+// DecodeReceipt decodes a receipt from JSON. If it can't be decoded, its
+// amounts are missing or its quantities are given as zero, it returns the
+// reasons with the same field errors used by the receipt validation.
+func DecodeReceipt(data []byte) (Receipt, []FieldError) {
+	var document receiptDocument
+
+	err := json.Unmarshal(data, &document)
+	if err == nil {
+		return document.receipt()
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || !json.Valid(data) {
+		return Receipt{}, []FieldError{{
+			Code:    FieldErrorInvalidJSON,
+			Message: fmt.Sprintf("the receipt is not valid JSON: %s", err),
+		}}
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if typeErr.Field == "" {
+			return Receipt{}, []FieldError{{
+				Code:    FieldErrorInvalidJSON,
+				Message: "the receipt must be a JSON object",
+			}}
+		}
+
+		return Receipt{}, []FieldError{{
+			Field:   typeErr.Field,
+			Code:    FieldErrorInvalidFormat,
+			Message: fmt.Sprintf("%s has an invalid type, got %s", typeErr.Field, typeErr.Value),
+		}}
+	}
+
+	// The remaining errors come from amounts of money that can't be parsed,
+	// which are decoded again on their own to report all of them.
+	if fieldErrors := amountFieldErrors(data); len(fieldErrors) > 0 {
+		return Receipt{}, fieldErrors
+	}
+
+	return Receipt{}, []FieldError{{
+		Code:    FieldErrorInvalidJSON,
+		Message: fmt.Sprintf("the receipt can't be decoded: %s", err),
+	}}
+}
+
+// receipt returns the decoded receipt, or a required field error for the total
+// and for each item price missing, and an invalid value error for each item
+// quantity given as zero.
+func (d receiptDocument) receipt() (Receipt, []FieldError) {
+	var fieldErrors []FieldError
+
+	receipt := Receipt{
+		Retailer:     d.Retailer,
+		PurchaseDate: d.PurchaseDate,
+		PurchaseTime: d.PurchaseTime,
+	}
+
+	if d.Items != nil {
+		receipt.Items = make([]Item, 0, len(d.Items))
+	}
+
+	for i, document := range d.Items {
+		item := Item{
+			ShortDescription: document.ShortDescription,
+			UnitPrice:        document.UnitPrice,
+			Discount:         document.Discount,
+		}
+
+		if document.Price == nil {
+			field := fmt.Sprintf("items[%d].price", i)
+			fieldErrors = append(fieldErrors, FieldError{Field: field, Code: FieldErrorRequired, Message: field + " is required"})
+		} else {
+			item.Price = *document.Price
+		}
+
+		if document.Quantity != nil {
+			if *document.Quantity == 0 {
+				field := fmt.Sprintf("items[%d].quantity", i)
+				fieldErrors = append(fieldErrors, FieldError{Field: field, Code: FieldErrorInvalidValue, Message: field + " must be at least 1 when given"})
+			}
+			item.Quantity = *document.Quantity
+		}
+
+		receipt.Items = append(receipt.Items, item)
+	}
+
+	if d.Total == nil {
+		fieldErrors = append(fieldErrors, FieldError{Field: "total", Code: FieldErrorRequired, Message: "total is required"})
+	} else {
+		receipt.Total = *d.Total
+	}
+
+	if fieldErrors != nil {
+		return Receipt{}, fieldErrors
+	}
+
+	return receipt, nil
+}
+
+func amountFieldErrors(data []byte) []FieldError {
+	var amounts receiptAmounts
+	if err := json.Unmarshal(data, &amounts); err != nil {
+		return nil
+	}
+
+	var fieldErrors []FieldError
+
+	checkAmount := func(field string, raw *json.RawMessage) {
+		if raw == nil {
+			return
+		}
+
+		var amount Money
+		if err := json.Unmarshal(*raw, &amount); err != nil {
+			fieldErrors = append(fieldErrors, FieldError{
+				Field:   field,
+				Code:    FieldErrorInvalidFormat,
+				Message: fmt.Sprintf("%s must be a string with an amount with two decimals, e.g. \"6.49\", got %s", field, *raw),
+			})
+		}
+	}
+
+	for i, item := range amounts.Items {
+		checkAmount(fmt.Sprintf("items[%d].price", i), item.Price)
+		checkAmount(fmt.Sprintf("items[%d].unitPrice", i), item.UnitPrice)
+		checkAmount(fmt.Sprintf("items[%d].discount", i), item.Discount)
+	}
+	checkAmount("total", amounts.Total)
+
+	return fieldErrors
+}
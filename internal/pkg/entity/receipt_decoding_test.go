@@ -0,0 +1,111 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeReceipt(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		data string
+
+		want      Receipt
+		wantCodes []string
+	}{
+		{
+			name: "should decode a receipt",
+
+			data: `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01",
+				"items": [{"shortDescription": "Gatorade", "price": "2.25"},
+					{"shortDescription": "Klarbrunn", "price": "35.00", "quantity": 3, "unitPrice": "12.00", "discount": "1.00"}],
+				"total": "37.25"}`,
+
+			want: Receipt{
+				Retailer:     "Target",
+				PurchaseDate: "2022-01-01",
+				PurchaseTime: "13:01",
+				Items: []Item{
+					{ShortDescription: "Gatorade", Price: MustParseMoney("2.25")},
+					{
+						ShortDescription: "Klarbrunn",
+						Price:            MustParseMoney("35.00"),
+						Quantity:         3,
+						UnitPrice:        MustParseMoney("12.00"),
+						Discount:         MustParseMoney("1.00"),
+					},
+				},
+				Total: MustParseMoney("37.25"),
+			},
+		},
+		{
+			name: "should decode zero amounts",
+
+			data: `{"items": [{"shortDescription": "Bag", "price": "0.00"}], "total": "0.00"}`,
+
+			want: Receipt{Items: []Item{{ShortDescription: "Bag"}}},
+		},
+		{
+			name: "should fail due missing amounts",
+
+			data: `{"items": [{"shortDescription": "Gatorade"}]}`,
+
+			wantCodes: []string{FieldErrorRequired, FieldErrorRequired},
+		},
+		{
+			name: "should fail due quantity given as zero",
+
+			data: `{"items": [{"shortDescription": "Gatorade", "price": "2.25", "quantity": 0}], "total": "2.25"}`,
+
+			wantCodes: []string{FieldErrorInvalidValue},
+		},
+		{
+			name: "should fail due invalid amounts",
+
+			data: `{"items": [{"shortDescription": "Gatorade", "price": 2.25, "unitPrice": "2.2"}], "total": "2.25"}`,
+
+			wantCodes: []string{FieldErrorInvalidFormat, FieldErrorInvalidFormat},
+		},
+		{
+			name: "should fail due invalid type",
+
+			data: `{"retailer": 12, "total": "2.25"}`,
+
+			wantCodes: []string{FieldErrorInvalidFormat},
+		},
+		{
+			name: "should fail due JSON that isn't a receipt",
+
+			data: `[1, 2]`,
+
+			wantCodes: []string{FieldErrorInvalidJSON},
+		},
+		{
+			name: "should fail due malformed JSON",
+
+			data: `{"retailer": "Target",`,
+
+			wantCodes: []string{FieldErrorInvalidJSON},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, fieldErrors := DecodeReceipt([]byte(tc.data))
+
+			var gotCodes []string
+			for _, fieldError := range fieldErrors {
+				gotCodes = append(gotCodes, fieldError.Code)
+			}
+
+			if !reflect.DeepEqual(gotCodes, tc.wantCodes) {
+				t.Errorf("DecodeReceipt() error codes = %v, want %v", gotCodes, tc.wantCodes)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("DecodeReceipt() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
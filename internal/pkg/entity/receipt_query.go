@@ -0,0 +1,164 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Orders in which receipts can be listed.
+const (
+	ReceiptSortSubmittedAt = "submittedAt"
+	ReceiptSortPoints      = "points"
+)
+
+// ErrInvalidCursor is returned when a page cursor wasn't returned by the same
+// listing of receipts.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ReceiptQuery filters, sorts and paginates the stored receipts. Empty or nil
+// filters match every receipt, the ranges include their bounds and the points
+// range only matches scored receipts. When sorting by points, receipts that
+// aren't scored yet go before the scored ones.
+type ReceiptQuery struct {
+	Retailer       string
+	RetailerPrefix string
+	MerchantID     string
+
+	// Purchase dates in the YYYY-MM-DD format.
+	PurchaseDateFrom string
+	PurchaseDateTo   string
+
+	TotalMin  *Money
+	TotalMax  *Money
+	PointsMin *int64
+	PointsMax *int64
+
+	// Unexpired only matches the receipts whose points weren't marked expired.
+	Unexpired bool
+
+	SortBy     string
+	Descending bool
+
+	// Limit is the maximum number of receipts of a page, zero means no limit.
+	Limit  int
+	Cursor string
+}
+
+// ReceiptListEntry is a listed receipt along with its scoring state.
+type ReceiptListEntry struct {
+	Record ReceiptRecord
+	Points ReceiptPoints
+}
+
+// ReceiptPage is a page of listed receipts. NextCursor gets the next page, it's
+// empty on the last one.
+type ReceiptPage struct {
+	Entries    []ReceiptListEntry
+	NextCursor string
+}
+
+// ReceiptCursor is the position of the last receipt of a page: the value it
+// was sorted by and the order it was stored in, which breaks ties.
+type ReceiptCursor struct {
+	SortBy     string `json:"s"`
+	Descending bool   `json:"d,omitempty"`
+	SortKey    int64  `json:"k"`
+	Seq        int64  `json:"q"`
+}
+
+// Encode returns the cursor as an opaque string.
+func (c ReceiptCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeReceiptCursor decodes a cursor returned by Encode, checking it was
+// returned by a listing in the same order as the query.
+func DecodeReceiptCursor(query ReceiptQuery) (ReceiptCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(query.Cursor)
+	if err != nil {
+		return ReceiptCursor{}, ErrInvalidCursor
+	}
+
+	var cursor ReceiptCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return ReceiptCursor{}, ErrInvalidCursor
+	}
+
+	if cursor.SortBy != query.SortBy || cursor.Descending != query.Descending {
+		return ReceiptCursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// After reports whether a receipt with the given sort key and storage order
+// goes after the cursor.
+func (c ReceiptCursor) After(sortKey, seq int64) bool {
+	if c.Descending {
+		return sortKey < c.SortKey || (sortKey == c.SortKey && seq < c.Seq)
+	}
+
+	return sortKey > c.SortKey || (sortKey == c.SortKey && seq > c.Seq)
+}
+
+// ReceiptPointsSortKey is the value a receipt is sorted by when sorting by
+// points. Receipts that aren't scored yet have no points, so they go first.
+func ReceiptPointsSortKey(points ReceiptPoints) int64 {
+	if !points.Scored() {
+		return -1
+	}
+
+	return points.Points
+}
+
+// Matches reports whether a receipt with the given scoring state passes the
+// filters of the query.
+func (q ReceiptQuery) Matches(record ReceiptRecord, points ReceiptPoints) bool {
+	receipt := record.Receipt
+
+	if q.Retailer != "" && receipt.Retailer != q.Retailer {
+		return false
+	}
+	if q.RetailerPrefix != "" && !strings.HasPrefix(receipt.Retailer, q.RetailerPrefix) {
+		return false
+	}
+
+	if q.MerchantID != "" && record.MerchantID != q.MerchantID {
+		return false
+	}
+
+	if q.PurchaseDateFrom != "" && receipt.PurchaseDate < q.PurchaseDateFrom {
+		return false
+	}
+	if q.PurchaseDateTo != "" && receipt.PurchaseDate > q.PurchaseDateTo {
+		return false
+	}
+
+	if q.TotalMin != nil && receipt.Total < *q.TotalMin {
+		return false
+	}
+	if q.TotalMax != nil && receipt.Total > *q.TotalMax {
+		return false
+	}
+
+	if q.Unexpired && points.ExpiredAt != nil {
+		return false
+	}
+
+	if q.PointsMin != nil || q.PointsMax != nil {
+		if !points.Scored() {
+			return false
+		}
+		if q.PointsMin != nil && points.Points < *q.PointsMin {
+			return false
+		}
+		if q.PointsMax != nil && points.Points > *q.PointsMax {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestReceiptCanonicalHash(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		first  string
+		second string
+
+		wantEqual bool
+	}{
+		{
+			name: "should ignore the formatting of the JSON",
+
+			first: `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01",
+				"items": [{"shortDescription": "Pepsi - 12-oz", "price": "1.25"}], "total": "1.25"}`,
+			second: `{"total":"1.25","items":[{"price":"1.25","shortDescription":"Pepsi - 12-oz"}],` +
+				`"purchaseTime":"13:01","purchaseDate":"2022-01-01","retailer":"Target"}`,
+
+			wantEqual: true,
+		},
+		{
+			name: "should tell apart receipts with different values",
+
+			first: `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01",
+				"items": [{"shortDescription": "Pepsi - 12-oz", "price": "1.25"}], "total": "1.25"}`,
+			second: `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:02",
+				"items": [{"shortDescription": "Pepsi - 12-oz", "price": "1.25"}], "total": "1.25"}`,
+
+			wantEqual: false,
+		},
+		{
+			name: "should keep the hash of receipts without quantities",
+
+			first: `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01",
+				"items": [{"shortDescription": "Pepsi - 12-oz", "price": "1.25"}], "total": "1.25"}`,
+			second: `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01",
+				"items": [{"shortDescription": "Pepsi - 12-oz", "price": "1.25", "quantity": 0, "discount": "0.00"}], "total": "1.25"}`,
+
+			wantEqual: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var first, second Receipt
+
+			if err := json.Unmarshal([]byte(tc.first), &first); err != nil {
+				t.Fatalf("CanonicalHash() = Unmarshaling error %v", err)
+			}
+			if err := json.Unmarshal([]byte(tc.second), &second); err != nil {
+				t.Fatalf("CanonicalHash() = Unmarshaling error %v", err)
+			}
+
+			got := first.CanonicalHash() == second.CanonicalHash()
+
+			if got != tc.wantEqual {
+				t.Errorf("CanonicalHash() equal = %v, want %v", got, tc.wantEqual)
+			}
+		})
+	}
+}
+
+func TestItemLinePrice(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		item Item
+
+		want   Money
+		wantOK bool
+	}{
+		{
+			name: "should multiply the unit price by the quantity minus the discount",
+
+			item: Item{Quantity: 3, UnitPrice: MustParseMoney("12.00"), Discount: MustParseMoney("1.00")},
+
+			want:   MustParseMoney("35.00"),
+			wantOK: true,
+		},
+		{
+			name: "should take a single unit without quantity",
+
+			item: Item{UnitPrice: MustParseMoney("2.25")},
+
+			want:   MustParseMoney("2.25"),
+			wantOK: true,
+		},
+		{
+			name: "should fail without unit price",
+
+			item: Item{Quantity: 3, Price: MustParseMoney("6.75")},
+		},
+		{
+			name: "should fail due overflow",
+
+			item: Item{Quantity: 100_000, UnitPrice: Money(math.MaxInt64 / 1000)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := tc.item.LinePrice()
+
+			if got != tc.want || ok != tc.wantOK {
+				t.Errorf("LinePrice() = %v, %v, want %v, %v", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package entity
+
+import "time"
+
+// Statuses of a redemption. A reserved redemption holds the points of its
+// receipts until it's confirmed, cancelled or expires.
+const (
+	RedemptionStatusReserved  = "reserved"
+	RedemptionStatusConfirmed = "confirmed"
+	RedemptionStatusCancelled = "cancelled"
+	RedemptionStatusExpired   = "expired"
+)
+
+// RedemptionAllocation is the points of a receipt used by a redemption.
+type RedemptionAllocation struct {
+	ReceiptID string `json:"receiptId"`
+	Points    int64  `json:"points"`
+}
+
+// Redemption spends the points of one or more receipts on a reward. The
+// points are taken from the receipts in the order they were given. AccountID
+// is the loyalty account of the receipts, if any, which is debited the points
+// when the redemption is confirmed.
+type Redemption struct {
+	ID          string                 `json:"id"`
+	Reward      string                 `json:"reward"`
+	AccountID   string                 `json:"accountId,omitempty"`
+	Points      int64                  `json:"points"`
+	Allocations []RedemptionAllocation `json:"allocations"`
+	Status      string                 `json:"status"`
+	CreatedAt   time.Time              `json:"createdAt"`
+	ExpiresAt   time.Time              `json:"expiresAt"`
+	ResolvedAt  *time.Time             `json:"resolvedAt,omitempty"`
+}
+
+// Holds reports whether the redemption holds the points of its receipts at the
+// given time, as they are either spent or reserved and not expired yet.
+func (r Redemption) Holds(now time.Time) bool {
+	switch r.Status {
+	case RedemptionStatusConfirmed:
+		return true
+	case RedemptionStatusReserved:
+		return now.Before(r.ExpiresAt)
+	default:
+		return false
+	}
+}
+
+// AllocateRedemption takes the points from the receipts in order, given the
+// points still available in each of them. A redemption of zero points takes
+// all the available ones. It returns ErrInsufficientPoints if the receipts
+// don't have enough points together.
+func AllocateRedemption(points int64, available []RedemptionAllocation) ([]RedemptionAllocation, error) {
+	var total int64
+	for _, receipt := range available {
+		if receipt.Points > 0 {
+			total += receipt.Points
+		}
+	}
+
+	if points == 0 {
+		points = total
+	}
+	if points == 0 || points > total {
+		return nil, ErrInsufficientPoints
+	}
+
+	var allocations []RedemptionAllocation
+	for _, receipt := range available {
+		if points == 0 {
+			break
+		}
+		if receipt.Points <= 0 {
+			continue
+		}
+
+		allocated := min(receipt.Points, points)
+		allocations = append(allocations, RedemptionAllocation{ReceiptID: receipt.ReceiptID, Points: allocated})
+		points -= allocated
+	}
+
+	return allocations, nil
+}
@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAllocateRedemption(t *testing.T) {
+	available := []RedemptionAllocation{
+		{ReceiptID: "a", Points: 10},
+		{ReceiptID: "b", Points: 0},
+		{ReceiptID: "c", Points: -5},
+		{ReceiptID: "d", Points: 20},
+	}
+
+	testCases := []struct {
+		name   string
+		points int64
+
+		want    []RedemptionAllocation
+		wantErr error
+	}{
+		{
+			name:   "should take the points from the first receipt",
+			points: 6,
+
+			want: []RedemptionAllocation{{ReceiptID: "a", Points: 6}},
+		},
+		{
+			name:   "should combine the points of the receipts in order",
+			points: 25,
+
+			want: []RedemptionAllocation{{ReceiptID: "a", Points: 10}, {ReceiptID: "d", Points: 15}},
+		},
+		{
+			name:   "should take all the available points",
+			points: 0,
+
+			want: []RedemptionAllocation{{ReceiptID: "a", Points: 10}, {ReceiptID: "d", Points: 20}},
+		},
+		{
+			name:   "should fail due insufficient points",
+			points: 31,
+
+			wantErr: ErrInsufficientPoints,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := AllocateRedemption(tc.points, available)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("AllocateRedemption() error = %v, want %v", err, tc.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("AllocateRedemption() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := AllocateRedemption(0, []RedemptionAllocation{{ReceiptID: "a"}}); !errors.Is(err, ErrInsufficientPoints) {
+		t.Errorf("AllocateRedemption() of receipts without points error = %v, want %v", err, ErrInsufficientPoints)
+	}
+}
+
+func TestRedemptionHolds(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.Add(15 * time.Minute)
+
+	testCases := []struct {
+		name   string
+		status string
+		now    time.Time
+
+		want bool
+	}{
+		{name: "should hold the points of a reservation", status: RedemptionStatusReserved, now: createdAt, want: true},
+		{name: "should release the points of an expired reservation", status: RedemptionStatusReserved, now: expiresAt},
+		{name: "should hold the points of a confirmed redemption", status: RedemptionStatusConfirmed, now: expiresAt, want: true},
+		{name: "should release the points of a cancelled redemption", status: RedemptionStatusCancelled, now: createdAt},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			redemption := Redemption{Status: tc.status, CreatedAt: createdAt, ExpiresAt: expiresAt}
+
+			if got := redemption.Holds(tc.now); got != tc.want {
+				t.Errorf("Holds() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
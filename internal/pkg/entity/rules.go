@@ -0,0 +1,112 @@
+package entity
+
+import "strings"
+
+// RuleSet is a named and versioned set of rules. Receipts keep the version of
+// the rule set used to calculate their points.
+type RuleSet struct {
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+	Rules   Rules  `json:"rules" yaml:"rules"`
+}
+
+// RuleSetsConfig holds the available rule sets and the version of the one
+// used to calculate the points of new receipts.
+type RuleSetsConfig struct {
+	ActiveVersion string    `json:"activeVersion" yaml:"activeVersion"`
+	RuleSets      []RuleSet `json:"ruleSets" yaml:"ruleSets"`
+}
+
+// Rules are the parameters of the rules used to calculate the points of a receipt.
+type Rules struct {
+	RetailerName     RetailerNameRule     `json:"retailerName" yaml:"retailerName"`
+	TotalRounded     TotalRoundedRule     `json:"totalRounded" yaml:"totalRounded"`
+	TotalMultiple    TotalMultipleRule    `json:"totalMultiple" yaml:"totalMultiple"`
+	ItemPairs        ItemPairsRule        `json:"itemPairs" yaml:"itemPairs"`
+	ItemDescriptions ItemDescriptionsRule `json:"itemDescriptions" yaml:"itemDescriptions"`
+	PurchaseDate     PurchaseDateRule     `json:"purchaseDate" yaml:"purchaseDate"`
+	PurchaseTime     PurchaseTimeRule     `json:"purchaseTime" yaml:"purchaseTime"`
+	ProductBonus     ProductBonusRule     `json:"productBonus" yaml:"productBonus"`
+}
+
+// Definitions of the alphanumeric characters of a retailer name.
+const (
+	// RetailerCharactersASCII counts the ASCII letters and digits.
+	RetailerCharactersASCII = "ascii"
+	// RetailerCharactersUnicode counts the code points that are letters or
+	// numbers in any script, so the marks combined with them aren't counted.
+	RetailerCharactersUnicode = "unicode"
+	// RetailerCharactersGraphemes counts the user-perceived characters, such
+	// as a letter with its accents or an Indic conjunct, that start with a
+	// letter or number. Emoji aren't counted.
+	RetailerCharactersGraphemes = "graphemes"
+)
+
+// RetailerNameRule awards points for every alphanumeric character in the
+// retailer name, as defined by Characters, which are Unicode code points if
+// it's empty.
+type RetailerNameRule struct {
+	PointsPerCharacter int64  `json:"pointsPerCharacter" yaml:"pointsPerCharacter"`
+	Characters         string `json:"characters" yaml:"characters"`
+}
+
+// TotalRoundedRule awards points if the total is a round dollar amount with no cents.
+type TotalRoundedRule struct {
+	Points int64 `json:"points" yaml:"points"`
+}
+
+// TotalMultipleRule awards points if the total is a multiple of a given amount.
+type TotalMultipleRule struct {
+	Points   int64 `json:"points" yaml:"points"`
+	Multiple Money `json:"multiple" yaml:"multiple"`
+}
+
+// ItemPairsRule awards points for every two items on the receipt.
+type ItemPairsRule struct {
+	PointsPerPair int64 `json:"pointsPerPair" yaml:"pointsPerPair"`
+}
+
+// ItemDescriptionsRule awards, for every item whose trimmed description length
+// is a multiple of LengthMultiple, the item price multiplied by PriceMultiplier
+// and rounded up.
+type ItemDescriptionsRule struct {
+	LengthMultiple  int     `json:"lengthMultiple" yaml:"lengthMultiple"`
+	PriceMultiplier float64 `json:"priceMultiplier" yaml:"priceMultiplier"`
+}
+
+// PurchaseDateRule awards points if the day in the purchase date is odd.
+type PurchaseDateRule struct {
+	Points int64 `json:"points" yaml:"points"`
+}
+
+// PurchaseTimeRule awards points if the purchase time is between StartHour and EndHour, both included.
+type PurchaseTimeRule struct {
+	Points    int64 `json:"points" yaml:"points"`
+	StartHour int   `json:"startHour" yaml:"startHour"`
+	EndHour   int   `json:"endHour" yaml:"endHour"`
+}
+
+// ProductBonusRule awards, for every item matched to a product of the
+// catalogue, the points of the best bonus of its brand or category. The rule
+// is left out of the points breakdown if there are no bonuses.
+type ProductBonusRule struct {
+	Bonuses []ProductBonus `json:"bonuses" yaml:"bonuses"`
+}
+
+// ProductBonus awards points for the items of products of a brand, a
+// category or both, compared ignoring case.
+type ProductBonus struct {
+	Brand         string `json:"brand,omitempty" yaml:"brand"`
+	Category      string `json:"category,omitempty" yaml:"category"`
+	PointsPerItem int64  `json:"pointsPerItem" yaml:"pointsPerItem"`
+}
+
+// Applies reports whether the bonus is awarded to the items of the product.
+func (b ProductBonus) Applies(product Product) bool {
+	if product.ID == "" {
+		return false
+	}
+
+	return (b.Brand == "" || strings.EqualFold(b.Brand, product.Brand)) &&
+		(b.Category == "" || strings.EqualFold(b.Category, product.Category))
+}
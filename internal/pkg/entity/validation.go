@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Codes of the field errors, so clients can tell them apart without parsing the messages.
+const (
+	FieldErrorRequired      = "required"
+	FieldErrorInvalidJSON   = "invalid_json"
+	FieldErrorInvalidFormat = "invalid_format"
+	FieldErrorInvalidDate   = "invalid_date"
+	FieldErrorInvalidTime   = "invalid_time"
+	FieldErrorMinItems      = "min_items"
+	FieldErrorTotalMismatch = "total_mismatch"
+	FieldErrorInvalidValue  = "invalid_value"
+
+	FieldErrorLineTotalMismatch = "line_total_mismatch"
+)
+
+// FieldError describes why a field of a receipt is invalid. Field is the JSON
+// path of the field, e.g. "items[0].price".
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when a receipt is invalid, listing every invalid field.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldError := range e.Errors {
+		if fieldError.Field == "" {
+			messages = append(messages, fieldError.Message)
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("%s: %s", fieldError.Field, fieldError.Message))
+	}
+
+	return "invalid receipt: " + strings.Join(messages, "; ")
+}
@@ -0,0 +1,48 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// AccountService is the interface that wraps the methods of the loyalty
+// accounts and their points ledger.
+type AccountService interface {
+	CreateAccount(ctx context.Context, name string) (entity.Account, error)
+	GetAccount(ctx context.Context, accountID string) (entity.Account, error)
+	// SettleReceiptPoints records in the ledger the points awarded to a
+	// receipt of the account, adjusting the ones credited before if they
+	// changed.
+	SettleReceiptPoints(ctx context.Context, accountID, receiptID string, points int64) error
+	// Redeem spends points of the account, or returns
+	// entity.ErrInsufficientPoints if its balance isn't enough.
+	Redeem(ctx context.Context, accountID string, points int64, description string) (entity.LedgerTransaction, error)
+	GetBalance(ctx context.Context, accountID string) (entity.AccountBalance, error)
+	ListTransactions(ctx context.Context, accountID, cursor string, limit int) (entity.LedgerPage, error)
+}
+
+// AccountRepository is the interface that wraps the methods to store the
+// loyalty accounts and their points ledger. Transactions are never changed
+// once posted.
+type AccountRepository interface {
+	SaveAccount(ctx context.Context, account entity.Account) error
+	GetAccount(ctx context.Context, accountID string) (entity.Account, error)
+	// SettleReceiptPoints posts a transaction with the given ID bringing the
+	// points credited to the account for the receipt to the given points, as
+	// an earning the first time and as an adjustment afterwards. It returns
+	// false if they were already credited. It must be atomic, so concurrent
+	// settlements of the same receipt don't credit it twice.
+	SettleReceiptPoints(ctx context.Context, transactionID, accountID, receiptID string, points int64, at time.Time) (entity.LedgerTransaction, bool, error)
+	// PostRedemption posts a redemption transaction, or returns
+	// entity.ErrInsufficientPoints if the balance of the account isn't
+	// enough. It must be atomic, so concurrent redemptions can't overspend.
+	PostRedemption(ctx context.Context, transaction entity.LedgerTransaction) error
+	// GetBalance returns the points held by the account, the sum of the
+	// credits minus the debits of its ledger account.
+	GetBalance(ctx context.Context, accountID string) (int64, error)
+	// ListTransactions returns a page of the transactions of an account,
+	// newest first, or entity.ErrInvalidCursor if the cursor can't be used.
+	ListTransactions(ctx context.Context, accountID, cursor string, limit int) (entity.LedgerPage, error)
+}
@@ -0,0 +1,23 @@
+package port
+
+import (
+	"context"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// ReceiptAuditRepository is the interface that wraps the methods to keep the
+// audit log of the changes made to receipts. The log is append-only and
+// outlives the receipts, so the history of deleted ones can still be read.
+type ReceiptAuditRepository interface {
+	AppendReceiptAuditEntry(ctx context.Context, entry entity.ReceiptAuditEntry) error
+	// AmendReceipt stores an amended receipt with its points and appends the
+	// entry of the amendment, atomically, so neither is kept without the other.
+	AmendReceipt(ctx context.Context, record entity.ReceiptRecord, points entity.ReceiptPoints, entry entity.ReceiptAuditEntry) error
+	// DeleteReceipt deletes a receipt and appends the entry of the deletion,
+	// atomically.
+	DeleteReceipt(ctx context.Context, receiptID string, entry entity.ReceiptAuditEntry) error
+	// ListReceiptAuditEntries returns the audit entries of a receipt in the
+	// order they were appended.
+	ListReceiptAuditEntries(ctx context.Context, receiptID string) ([]entity.ReceiptAuditEntry, error)
+}
@@ -0,0 +1,22 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// ExpiryService is the interface that wraps the methods to expire the points
+// of the receipts with the expiry policy.
+type ExpiryService interface {
+	// GetPointsValidity evaluates the expiry policy on the points of a
+	// receipt at the given time.
+	GetPointsValidity(ctx context.Context, receipt entity.Receipt, points entity.ReceiptPoints, at time.Time) (entity.PointsValidity, error)
+	// ExpireReceipts marks the points of the receipts expired at the given
+	// time, returning how many were.
+	ExpireReceipts(ctx context.Context, now time.Time) (int64, error)
+	// ListExpiringReceipts lists a page of the scored receipts whose points
+	// are valid at the given time and expire within the window.
+	ListExpiringReceipts(ctx context.Context, now time.Time, within time.Duration, cursor string, limit int) (entity.ReceiptPage, error)
+}
@@ -0,0 +1,28 @@
+package port
+
+import (
+	"context"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// FraudService is the interface that wraps the methods to screen receipts for
+// duplicated submissions.
+type FraudService interface {
+	ScreenReceipt(ctx context.Context, record entity.ReceiptRecord) (entity.FraudScreening, error)
+	GetScreening(ctx context.Context, receiptID string) (entity.FraudScreening, bool, error)
+	ReviewReceipt(ctx context.Context, receiptID string, approved bool, reason string) (entity.FraudScreening, error)
+}
+
+// FraudRepository is the interface that wraps the methods to store the
+// fingerprints and screenings of the receipts.
+type FraudRepository interface {
+	// SaveFingerprint stores the fingerprint of a receipt and returns the IDs
+	// of the receipts saved before with the same exact and partial
+	// fingerprints, in the order they were saved. When it's saved again, only
+	// the receipts saved before its first fingerprint match. It must be
+	// atomic, so concurrent duplicates are matched with each other.
+	SaveFingerprint(ctx context.Context, fingerprint entity.ReceiptFingerprint) (exactMatches []string, partialMatches []string, err error)
+	SaveScreening(ctx context.Context, receiptID string, screening entity.FraudScreening) error
+	GetScreening(ctx context.Context, receiptID string) (entity.FraudScreening, bool, error)
+}
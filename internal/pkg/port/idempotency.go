@@ -0,0 +1,26 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// IdempotencyRepository is the interface that wraps the methods to store the
+// idempotency records of the receipt submissions.
+type IdempotencyRepository interface {
+	// SaveIdempotencyRecord saves the record unless there is an unexpired one
+	// with the same key at the record creation time, in which case it returns
+	// the existing record and false. It must be atomic, so only one of
+	// concurrent submissions with the same key saves its record.
+	SaveIdempotencyRecord(ctx context.Context, record entity.IdempotencyRecord) (entity.IdempotencyRecord, bool, error)
+	// CompleteIdempotencyRecord marks the record with the given key as
+	// completed, once the receipt of the submission is stored.
+	CompleteIdempotencyRecord(ctx context.Context, key string) error
+	DeleteIdempotencyRecord(ctx context.Context, key string) error
+	// DeleteReceiptIdempotencyRecord deletes the record with the given key
+	// only if it's of the given receipt.
+	DeleteReceiptIdempotencyRecord(ctx context.Context, key, receiptID string) error
+	DeleteExpiredIdempotencyRecords(ctx context.Context, now time.Time) (int64, error)
+}
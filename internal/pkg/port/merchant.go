@@ -0,0 +1,54 @@
+package port
+
+import (
+	"context"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// MerchantService is the interface that wraps the methods of the registry of
+// canonical merchants and the retailer names they are known by.
+type MerchantService interface {
+	// CreateMerchant registers a merchant with its name and the aliases as
+	// its retailer names, assigning it the receipts of any of them.
+	CreateMerchant(ctx context.Context, name string, aliases []string) (entity.Merchant, error)
+	GetMerchant(ctx context.Context, merchantID string) (entity.Merchant, error)
+	ListMerchants(ctx context.Context) ([]entity.Merchant, error)
+	RenameMerchant(ctx context.Context, merchantID, name string) (entity.Merchant, error)
+	// DeleteMerchant deletes a merchant with its aliases, unassigning its
+	// receipts.
+	DeleteMerchant(ctx context.Context, merchantID string) error
+	// AddAlias adds a retailer name to a merchant and assigns it its receipts,
+	// or returns entity.ErrMerchantAliasTaken if it's of another merchant.
+	AddAlias(ctx context.Context, merchantID, alias string) (entity.Merchant, error)
+	// DeleteAlias removes a retailer name from a merchant and unassigns its
+	// receipts.
+	DeleteAlias(ctx context.Context, merchantID, alias string) (entity.Merchant, error)
+	// ResolveRetailer returns the ID of the merchant a retailer name is an
+	// alias of, which is empty if there is none.
+	ResolveRetailer(ctx context.Context, retailer string) (string, error)
+}
+
+// MerchantRepository is the interface that wraps the methods to store the
+// merchants and their aliases, matched by their normalized retailer names.
+type MerchantRepository interface {
+	// SaveMerchant stores a new merchant with its aliases, or returns
+	// entity.ErrMerchantAliasTaken if any of them is of another merchant.
+	SaveMerchant(ctx context.Context, merchant entity.Merchant) error
+	GetMerchant(ctx context.Context, merchantID string) (entity.Merchant, error)
+	// ListMerchants lists the merchants in the order they were created.
+	ListMerchants(ctx context.Context) ([]entity.Merchant, error)
+	RenameMerchant(ctx context.Context, merchantID, name string) (entity.Merchant, error)
+	DeleteMerchant(ctx context.Context, merchantID string) error
+	// AddMerchantAlias adds an alias to a merchant, or returns
+	// entity.ErrMerchantAliasTaken if it's of another merchant. Aliases the
+	// merchant already has are left as they are.
+	AddMerchantAlias(ctx context.Context, merchantID, alias string) (entity.Merchant, error)
+	// DeleteMerchantAlias removes the alias of a merchant with the same
+	// normalized name, or returns entity.ErrMerchantAliasNotFound if it has
+	// none.
+	DeleteMerchantAlias(ctx context.Context, merchantID, alias string) (entity.Merchant, error)
+	// FindMerchantByRetailer returns the ID of the merchant with an alias of
+	// the normalized retailer name, or false if there is none.
+	FindMerchantByRetailer(ctx context.Context, retailerKey string) (string, bool, error)
+}
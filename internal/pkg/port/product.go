@@ -0,0 +1,35 @@
+package port
+
+import (
+	"context"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// ProductService is the interface that wraps the methods of the catalogue of
+// products the items of the receipts are matched to.
+type ProductService interface {
+	CreateProduct(ctx context.Context, product entity.Product) (entity.Product, error)
+	GetProduct(ctx context.Context, productID string) (entity.Product, error)
+	ListProducts(ctx context.Context) ([]entity.Product, error)
+	// UpdateProduct replaces the definition of a product, or returns
+	// entity.ErrProductNotFound if it doesn't exist.
+	UpdateProduct(ctx context.Context, productID string, product entity.Product) (entity.Product, error)
+	DeleteProduct(ctx context.Context, productID string) error
+	// MatchProducts returns the product each item matches by its short
+	// description, in the same order, which has no ID if it matches none.
+	MatchProducts(ctx context.Context, items []entity.Item) ([]entity.Product, error)
+}
+
+// ProductRepository is the interface that wraps the methods to store the
+// catalogue of products.
+type ProductRepository interface {
+	SaveProduct(ctx context.Context, product entity.Product) error
+	GetProduct(ctx context.Context, productID string) (entity.Product, error)
+	// ListProducts lists the products in the order they were created.
+	ListProducts(ctx context.Context) ([]entity.Product, error)
+	// UpdateProduct replaces a stored product, keeping its creation time, or
+	// returns entity.ErrProductNotFound if it isn't stored.
+	UpdateProduct(ctx context.Context, product entity.Product) (entity.Product, error)
+	DeleteProduct(ctx context.Context, productID string) error
+}
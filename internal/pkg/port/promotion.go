@@ -0,0 +1,40 @@
+package port
+
+import (
+	"context"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// PromotionService is the interface that wraps the methods to manage the
+// promotions and award their bonus points to the receipts.
+type PromotionService interface {
+	// CreatePromotion stores a new promotion, or returns
+	// entity.ErrMerchantNotFound if its merchant doesn't exist.
+	CreatePromotion(ctx context.Context, promotion entity.Promotion) (entity.Promotion, error)
+	GetPromotion(ctx context.Context, promotionID string) (entity.Promotion, error)
+	ListPromotions(ctx context.Context) ([]entity.Promotion, error)
+	// UpdatePromotion replaces the definition of a promotion, or returns
+	// entity.ErrMerchantNotFound if its merchant doesn't exist.
+	UpdatePromotion(ctx context.Context, promotionID string, promotion entity.Promotion) (entity.Promotion, error)
+	DeletePromotion(ctx context.Context, promotionID string) error
+	// GetPromotionPoints returns the points awarded by the promotions that
+	// apply to a receipt with the given base points.
+	GetPromotionPoints(ctx context.Context, receipt entity.Receipt, basePoints int64) ([]entity.PromotionPoints, error)
+}
+
+// PromotionRepository is the interface that wraps the methods to store the
+// promotions.
+type PromotionRepository interface {
+	SavePromotion(ctx context.Context, promotion entity.Promotion) error
+	GetPromotion(ctx context.Context, promotionID string) (entity.Promotion, error)
+	// ListPromotions lists the promotions in the order they were created.
+	ListPromotions(ctx context.Context) ([]entity.Promotion, error)
+	// ListActivePromotions lists the promotions whose window includes the
+	// purchase date, in the order they were created.
+	ListActivePromotions(ctx context.Context, purchaseDate string) ([]entity.Promotion, error)
+	// UpdatePromotion replaces a stored promotion, keeping its creation time,
+	// or returns entity.ErrPromotionNotFound if it isn't stored.
+	UpdatePromotion(ctx context.Context, promotion entity.Promotion) (entity.Promotion, error)
+	DeletePromotion(ctx context.Context, promotionID string) error
+}
@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
 )
@@ -9,5 +10,39 @@ import (
 // ReceiptService is the interface that wraps the basic methods for the receipt service.
 type ReceiptService interface {
 	CreateReceiptID(ctx context.Context) string
+	ValidateReceipt(ctx context.Context, receipt entity.Receipt) error
 	GetReceiptPoints(ctx context.Context, receipt entity.Receipt) (int64, error)
+	GetReceiptPointsBreakdown(ctx context.Context, receipt entity.Receipt) (entity.PointsBreakdown, error)
+	GetReceiptPointsBreakdownWithRuleSet(ctx context.Context, receipt entity.Receipt, ruleSetVersion string) (entity.PointsBreakdown, error)
+	// GetRecordPointsBreakdown gets the points breakdown of a stored receipt
+	// with the rule set of the given version, or the active one if empty,
+	// awarding its items the bonus of the products they were matched to when
+	// stored.
+	GetRecordPointsBreakdown(ctx context.Context, record entity.ReceiptRecord, ruleSetVersion string) (entity.PointsBreakdown, error)
+}
+
+// ReceiptRepository is the interface that wraps the basic methods for the receipt storage.
+type ReceiptRepository interface {
+	SaveReceipt(ctx context.Context, record entity.ReceiptRecord) error
+	GetReceiptByID(ctx context.Context, receiptID string) (entity.ReceiptRecord, error)
+	// DeleteReceipt deletes a receipt with its points, or returns
+	// entity.ErrReceiptNotFound if it isn't stored.
+	DeleteReceipt(ctx context.Context, receiptID string) error
+	SaveReceiptPoints(ctx context.Context, receiptID string, points entity.ReceiptPoints) error
+	// ExpireReceiptPoints marks the points of a receipt expired at the given
+	// time if they are still the given scored and unexpired ones, and reports
+	// whether they were. The check and the change must be atomic, so points
+	// scored again meanwhile aren't expired.
+	ExpireReceiptPoints(ctx context.Context, receiptID string, points entity.ReceiptPoints, expiredAt time.Time) (bool, error)
+	// GetReceiptPoints returns the scoring state of a receipt, which is pending
+	// if its points weren't saved yet.
+	GetReceiptPoints(ctx context.Context, receiptID string) (entity.ReceiptPoints, error)
+	ListReceipts(ctx context.Context) ([]entity.ReceiptRecord, error)
+	// QueryReceipts returns a page of the receipts matching the query, or
+	// entity.ErrInvalidCursor if its cursor can't be used.
+	QueryReceipts(ctx context.Context, query entity.ReceiptQuery) (entity.ReceiptPage, error)
+	// AssignMerchant sets the merchant of the receipts whose normalized
+	// retailer name is the given one, or unsets it if the merchant is empty,
+	// and returns how many were changed.
+	AssignMerchant(ctx context.Context, retailerKey, merchantID string) (int64, error)
 }
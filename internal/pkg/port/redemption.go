@@ -0,0 +1,51 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// RedemptionService is the interface that wraps the methods to spend the
+// points of the receipts on rewards.
+type RedemptionService interface {
+	// Reserve holds points of the receipts for a reward until the
+	// reservation is confirmed, cancelled or expires. Zero points reserve all
+	// the available ones.
+	Reserve(ctx context.Context, receiptIDs []string, points int64, reward string) (entity.Redemption, error)
+	Confirm(ctx context.Context, redemptionID string) (entity.Redemption, error)
+	Cancel(ctx context.Context, redemptionID string) (entity.Redemption, error)
+	GetRedemption(ctx context.Context, redemptionID string) (entity.Redemption, error)
+	// GetHeldPoints sums the points of a receipt held by the confirmed
+	// redemptions and the reservations that didn't expire.
+	GetHeldPoints(ctx context.Context, receiptID string) (int64, error)
+	// ExpireReservations marks the reservations expired at the given time,
+	// returning how many were.
+	ExpireReservations(ctx context.Context, now time.Time) (int64, error)
+}
+
+// RedemptionRepository is the interface that wraps the methods to store the
+// redemptions.
+type RedemptionRepository interface {
+	// ReserveReceiptPoints allocates the points of the redemption from the
+	// points of the receipts still available at its creation, those not held
+	// by other redemptions, and stores it with its allocations. It returns
+	// entity.ErrInsufficientPoints if they aren't enough. It must be atomic,
+	// so concurrent reservations can't allocate the same points.
+	ReserveReceiptPoints(ctx context.Context, redemption entity.Redemption, receiptPoints []entity.RedemptionAllocation) (entity.Redemption, error)
+	GetRedemption(ctx context.Context, redemptionID string) (entity.Redemption, error)
+	// GetHeldPoints sums the points of a receipt held by the redemptions at
+	// the given time.
+	GetHeldPoints(ctx context.Context, receiptID string, now time.Time) (int64, error)
+	// ResolveRedemption changes a reserved redemption to the given status. It
+	// returns entity.ErrRedemptionResolved if it isn't reserved anymore or it
+	// expired at the given time.
+	ResolveRedemption(ctx context.Context, redemptionID, status string, at time.Time) (entity.Redemption, error)
+	// ReopenRedemption changes a confirmed redemption back to reserved, when
+	// debiting its account fails after confirming it.
+	ReopenRedemption(ctx context.Context, redemptionID string) error
+	// ExpireRedemptions marks the reserved redemptions expired at the given
+	// time, returning how many were.
+	ExpireRedemptions(ctx context.Context, now time.Time) (int64, error)
+}
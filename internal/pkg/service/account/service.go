@@ -0,0 +1,84 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/google/uuid"
+)
+
+type accountService struct {
+	repository port.AccountRepository
+	now        func() time.Time
+}
+
+// NewAccountService creates a new loyalty account service.
+func NewAccountService(repository port.AccountRepository) *accountService {
+	return &accountService{
+		repository: repository,
+		now:        time.Now,
+	}
+}
+
+// CreateAccount creates a loyalty account without points.
+func (as *accountService) CreateAccount(ctx context.Context, name string) (entity.Account, error) {
+	account := entity.Account{
+		ID:        uuid.New().String(),
+		Name:      name,
+		CreatedAt: as.now().UTC(),
+	}
+
+	if err := as.repository.SaveAccount(ctx, account); err != nil {
+		return entity.Account{}, err
+	}
+
+	return account, nil
+}
+
+// GetAccount gets a loyalty account by its ID.
+func (as *accountService) GetAccount(ctx context.Context, accountID string) (entity.Account, error) {
+	return as.repository.GetAccount(ctx, accountID)
+}
+
+// SettleReceiptPoints records in the ledger the points awarded to a receipt of
+// the account. The points credited for it before are adjusted when it's scored
+// again, amended, rejected or deleted, which can leave the balance negative if
+// they were already redeemed.
+func (as *accountService) SettleReceiptPoints(ctx context.Context, accountID, receiptID string, points int64) error {
+	_, _, err := as.repository.SettleReceiptPoints(ctx, uuid.New().String(), accountID, receiptID, points, as.now().UTC())
+
+	return err
+}
+
+// Redeem spends points of the account, or returns entity.ErrInsufficientPoints
+// if its balance isn't enough. The points are expected to be positive.
+func (as *accountService) Redeem(ctx context.Context, accountID string, points int64, description string) (entity.LedgerTransaction, error) {
+	transaction := entity.NewLedgerTransaction(
+		uuid.New().String(), accountID, entity.TransactionTypeRedemption, "", -points, as.now().UTC(),
+	)
+	transaction.Description = description
+
+	if err := as.repository.PostRedemption(ctx, transaction); err != nil {
+		return entity.LedgerTransaction{}, err
+	}
+
+	return transaction, nil
+}
+
+// GetBalance gets the points held by the account.
+func (as *accountService) GetBalance(ctx context.Context, accountID string) (entity.AccountBalance, error) {
+	balance, err := as.repository.GetBalance(ctx, accountID)
+	if err != nil {
+		return entity.AccountBalance{}, err
+	}
+
+	return entity.AccountBalance{AccountID: accountID, Balance: balance}, nil
+}
+
+// ListTransactions lists a page of the transactions of the account, newest
+// first.
+func (as *accountService) ListTransactions(ctx context.Context, accountID, cursor string, limit int) (entity.LedgerPage, error) {
+	return as.repository.ListTransactions(ctx, accountID, cursor, limit)
+}
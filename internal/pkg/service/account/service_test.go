@@ -0,0 +1,116 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAccount(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	repository := &mocks.AccountRepository{}
+	service := NewAccountService(repository)
+	service.now = func() time.Time { return now }
+
+	repository.On(
+		"SaveAccount",
+		mock.Anything, /* context.Context */
+		mock.MatchedBy(func(account entity.Account) bool {
+			return account.ID != "" && account.Name == "Jane" && account.CreatedAt.Equal(now)
+		}),
+	).Return(nil).Once()
+
+	got, err := service.CreateAccount(context.Background(), "Jane")
+	if err != nil {
+		t.Fatalf("CreateAccount() = error %v", err)
+	}
+
+	if got.ID == "" || got.Name != "Jane" || !got.CreatedAt.Equal(now) {
+		t.Errorf("CreateAccount() = %v, want an account of Jane created at %v", got, now)
+	}
+
+	repository.AssertExpectations(t)
+}
+
+func TestRedeem(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+
+		repositoryErr error
+
+		wantErr error
+	}{
+		{
+			name: "should debit the redeemed points from the account",
+		},
+		{
+			name: "should fail when the balance isn't enough",
+
+			repositoryErr: entity.ErrInsufficientPoints,
+
+			wantErr: entity.ErrInsufficientPoints,
+		},
+	}
+
+	for _, tc := range testCases {
+		repository := &mocks.AccountRepository{}
+		service := NewAccountService(repository)
+		service.now = func() time.Time { return now }
+
+		repository.On(
+			"PostRedemption",
+			mock.Anything, /* context.Context */
+			mock.MatchedBy(func(transaction entity.LedgerTransaction) bool {
+				return transaction.AccountID == "1" &&
+					transaction.Type == entity.TransactionTypeRedemption &&
+					transaction.Amount == -10 &&
+					transaction.Description == "coffee" &&
+					transaction.Balanced()
+			}),
+		).Return(tc.repositoryErr).Once()
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := service.Redeem(context.Background(), "1", 10, "coffee")
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Redeem() error = %v, want %v", err, tc.wantErr)
+			}
+
+			if tc.wantErr == nil && (got.Amount != -10 || !got.CreatedAt.Equal(now)) {
+				t.Errorf("Redeem() = %v, want a redemption of 10 points at %v", got, now)
+			}
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetBalance(t *testing.T) {
+	repository := &mocks.AccountRepository{}
+	service := NewAccountService(repository)
+
+	repository.On(
+		"GetBalance",
+		mock.Anything, /* context.Context */
+		"1",
+	).Return(int64(28), nil).Once()
+
+	got, err := service.GetBalance(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("GetBalance() = error %v", err)
+	}
+
+	want := entity.AccountBalance{AccountID: "1", Balance: 28}
+	if got != want {
+		t.Errorf("GetBalance() = %v, want %v", got, want)
+	}
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,134 @@
+package expiry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/darcops/receipt-proccessor-challenge/util"
+)
+
+// expireBatchSize is the number of receipts read at a time when marking the
+// expired ones.
+const expireBatchSize = 100
+
+type expiryService struct {
+	policy            entity.ExpiryPolicy
+	receiptRepository port.ReceiptRepository
+	accountService    port.AccountService
+}
+
+// Option configures the expiry service.
+type Option func(*expiryService)
+
+// WithAccounts takes the expired points back from the loyalty accounts of
+// their receipts.
+func WithAccounts(accountService port.AccountService) Option {
+	return func(es *expiryService) {
+		es.accountService = accountService
+	}
+}
+
+// NewExpiryService creates a new expiry service with the given policy.
+func NewExpiryService(policy entity.ExpiryPolicy, receiptRepository port.ReceiptRepository, options ...Option) *expiryService {
+	es := &expiryService{
+		policy:            policy,
+		receiptRepository: receiptRepository,
+	}
+
+	for _, option := range options {
+		option(es)
+	}
+
+	return es
+}
+
+// GetPointsValidity evaluates the expiry policy on the points of a receipt at
+// the given time, so a change of the policy applies to the points already
+// calculated.
+func (es *expiryService) GetPointsValidity(ctx context.Context, receipt entity.Receipt, points entity.ReceiptPoints, at time.Time) (entity.PointsValidity, error) {
+	return es.policy.Validity(receipt, points, at)
+}
+
+// ExpireReceipts marks the points of the scored receipts expired at the given
+// time, and takes them back from their accounts.
+func (es *expiryService) ExpireReceipts(ctx context.Context, now time.Time) (int64, error) {
+	lastExpired, ok := es.policy.LastExpiredPurchaseDate(now)
+	if !ok {
+		return 0, nil
+	}
+
+	minPoints := int64(0)
+	query := entity.ReceiptQuery{
+		PurchaseDateTo: lastExpired,
+		PointsMin:      &minPoints,
+		Unexpired:      true,
+		Limit:          expireBatchSize,
+	}
+
+	var expired int64
+	for {
+		page, err := es.receiptRepository.QueryReceipts(ctx, query)
+		if err != nil {
+			return expired, err
+		}
+
+		for _, entry := range page.Entries {
+			// Receipts amended, scored again or deleted since they were read
+			// are left for the next run.
+			ok, err := es.receiptRepository.ExpireReceiptPoints(ctx, entry.Record.ID, entry.Points, now.UTC())
+			if errors.Is(err, entity.ErrReceiptNotFound) {
+				continue
+			}
+			if err != nil {
+				return expired, fmt.Errorf("receipt %s: %w", entry.Record.ID, err)
+			}
+			if !ok {
+				continue
+			}
+
+			if es.accountService != nil && entry.Record.AccountID != "" {
+				if err := es.accountService.SettleReceiptPoints(ctx, entry.Record.AccountID, entry.Record.ID, 0); err != nil {
+					return expired, fmt.Errorf("receipt %s: %w", entry.Record.ID, err)
+				}
+			}
+
+			expired++
+		}
+
+		if page.NextCursor == "" {
+			return expired, nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+// ListExpiringReceipts lists a page of the scored receipts whose points are
+// valid at the given time and expire within the window, in the order they
+// were submitted.
+func (es *expiryService) ListExpiringReceipts(ctx context.Context, now time.Time, within time.Duration, cursor string, limit int) (entity.ReceiptPage, error) {
+	lastExpired, ok := es.policy.LastExpiredPurchaseDate(now)
+	if !ok {
+		return entity.ReceiptPage{Entries: []entity.ReceiptListEntry{}}, nil
+	}
+	lastExpiring, _ := es.policy.LastExpiredPurchaseDate(now.Add(within))
+
+	// The receipts purchased after the last expired one are still valid.
+	date, err := util.ParseDate(lastExpired)
+	if err != nil {
+		return entity.ReceiptPage{}, err
+	}
+
+	minPoints := int64(0)
+
+	return es.receiptRepository.QueryReceipts(ctx, entity.ReceiptQuery{
+		PurchaseDateFrom: util.FormatDate(date.AddDate(0, 0, 1)),
+		PurchaseDateTo:   lastExpiring,
+		PointsMin:        &minPoints,
+		Limit:            limit,
+		Cursor:           cursor,
+	})
+}
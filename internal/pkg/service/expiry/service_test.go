@@ -0,0 +1,213 @@
+package expiry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExpireReceipts(t *testing.T) {
+	now := time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC)
+	policy := entity.ExpiryPolicy{Type: entity.ExpiryPolicyAfterPurchase, Months: 12}
+	scored := entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28}
+
+	testCases := []struct {
+		name string
+
+		policy  entity.ExpiryPolicy
+		pages   []entity.ReceiptPage
+		changed []entity.ReceiptRecord
+
+		wantExpired []entity.ReceiptRecord
+	}{
+		{
+			name: "should mark the expired receipts and take their points back from their accounts",
+
+			policy: policy,
+			pages: []entity.ReceiptPage{
+				{
+					Entries: []entity.ReceiptListEntry{
+						{Record: entity.ReceiptRecord{ID: "a", AccountID: "1"}, Points: scored},
+					},
+					NextCursor: "next",
+				},
+				{
+					Entries: []entity.ReceiptListEntry{
+						{Record: entity.ReceiptRecord{ID: "b"}, Points: scored},
+					},
+				},
+			},
+
+			wantExpired: []entity.ReceiptRecord{{ID: "a", AccountID: "1"}, {ID: "b"}},
+		},
+		{
+			name: "should not expire the receipts changed since they were read",
+
+			policy: policy,
+			pages: []entity.ReceiptPage{
+				{
+					Entries: []entity.ReceiptListEntry{
+						{Record: entity.ReceiptRecord{ID: "a", AccountID: "1"}, Points: scored},
+						{Record: entity.ReceiptRecord{ID: "b", AccountID: "1"}, Points: scored},
+					},
+				},
+			},
+			changed: []entity.ReceiptRecord{{ID: "a", AccountID: "1"}},
+
+			wantExpired: []entity.ReceiptRecord{{ID: "b", AccountID: "1"}},
+		},
+		{
+			name: "should not expire receipts if the policy never does",
+
+			policy: entity.ExpiryPolicy{Type: entity.ExpiryPolicyNever},
+		},
+	}
+
+	for _, tc := range testCases {
+		receiptRepository := &mocks.ReceiptRepository{}
+		accountService := &mocks.AccountService{}
+
+		service := NewExpiryService(tc.policy, receiptRepository, WithAccounts(accountService))
+
+		for i, page := range tc.pages {
+			cursor := ""
+			if i > 0 {
+				cursor = tc.pages[i-1].NextCursor
+			}
+
+			receiptRepository.On(
+				"QueryReceipts",
+				mock.Anything, /* context.Context */
+				mock.MatchedBy(func(query entity.ReceiptQuery) bool {
+					return query.PurchaseDateTo == "2022-01-15" &&
+						query.PointsMin != nil && *query.PointsMin == 0 &&
+						query.Unexpired &&
+						query.Cursor == cursor
+				}),
+			).Return(page, nil).Once()
+		}
+
+		for _, record := range tc.changed {
+			receiptRepository.On(
+				"ExpireReceiptPoints",
+				mock.Anything, /* context.Context */
+				record.ID,
+				scored,
+				now,
+			).Return(false, nil).Once()
+		}
+
+		for _, record := range tc.wantExpired {
+			receiptRepository.On(
+				"ExpireReceiptPoints",
+				mock.Anything, /* context.Context */
+				record.ID,
+				scored,
+				now,
+			).Return(true, nil).Once()
+
+			if record.AccountID != "" {
+				accountService.On(
+					"SettleReceiptPoints",
+					mock.Anything, /* context.Context */
+					record.AccountID,
+					record.ID,
+					int64(0),
+				).Return(nil).Once()
+			}
+		}
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := service.ExpireReceipts(context.Background(), now)
+			if err != nil {
+				t.Fatalf("ExpireReceipts() error = %v", err)
+			}
+
+			if got != int64(len(tc.wantExpired)) {
+				t.Errorf("ExpireReceipts() = %v, want %v", got, len(tc.wantExpired))
+			}
+
+			receiptRepository.AssertExpectations(t)
+			accountService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListExpiringReceipts(t *testing.T) {
+	now := time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+
+		policy entity.ExpiryPolicy
+		within time.Duration
+
+		wantQuery        bool
+		wantPurchaseFrom string
+		wantPurchaseTo   string
+	}{
+		{
+			name: "should list the receipts purchased months before the window",
+
+			policy: entity.ExpiryPolicy{Type: entity.ExpiryPolicyAfterPurchase, Months: 12},
+			within: 30 * 24 * time.Hour,
+
+			wantQuery:        true,
+			wantPurchaseFrom: "2022-01-16",
+			wantPurchaseTo:   "2022-02-14",
+		},
+		{
+			name: "should list the receipts of the year ending within the window",
+
+			policy: entity.ExpiryPolicy{Type: entity.ExpiryPolicyEndOfYear},
+			within: 365 * 24 * time.Hour,
+
+			wantQuery:        true,
+			wantPurchaseFrom: "2023-01-01",
+			wantPurchaseTo:   "2023-12-31",
+		},
+		{
+			name: "should list no receipts if the policy never expires points",
+
+			policy: entity.ExpiryPolicy{Type: entity.ExpiryPolicyNever},
+			within: 30 * 24 * time.Hour,
+		},
+	}
+
+	for _, tc := range testCases {
+		receiptRepository := &mocks.ReceiptRepository{}
+
+		service := NewExpiryService(tc.policy, receiptRepository)
+
+		if tc.wantQuery {
+			receiptRepository.On(
+				"QueryReceipts",
+				mock.Anything, /* context.Context */
+				mock.MatchedBy(func(query entity.ReceiptQuery) bool {
+					return query.PurchaseDateFrom == tc.wantPurchaseFrom &&
+						query.PurchaseDateTo == tc.wantPurchaseTo &&
+						query.PointsMin != nil && *query.PointsMin == 0 &&
+						query.Limit == 20 &&
+						query.Cursor == "cursor"
+				}),
+			).Return(entity.ReceiptPage{Entries: []entity.ReceiptListEntry{}}, nil).Once()
+		}
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := service.ListExpiringReceipts(context.Background(), now, tc.within, "cursor", 20)
+			if err != nil {
+				t.Fatalf("ListExpiringReceipts() error = %v", err)
+			}
+
+			if got.Entries == nil {
+				t.Errorf("ListExpiringReceipts() = nil entries, want empty")
+			}
+
+			receiptRepository.AssertExpectations(t)
+		})
+	}
+}
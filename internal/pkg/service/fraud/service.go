@@ -0,0 +1,204 @@
+package fraud
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+)
+
+// Actions taken on the receipts flagged as duplicates.
+const (
+	// ActionHold holds the receipts for review, without points until approved.
+	ActionHold = "hold"
+	// ActionZero rejects the receipts, so they are awarded zero points.
+	ActionZero = "zero"
+)
+
+type fraudService struct {
+	repository port.FraudRepository
+	action     string
+}
+
+// Option configures the fraud service.
+type Option func(*fraudService)
+
+// WithAction sets the action taken on the receipts flagged as duplicates.
+func WithAction(action string) Option {
+	return func(fs *fraudService) {
+		fs.action = action
+	}
+}
+
+// NewFraudService creates a new fraud service. Unless another action is set,
+// flagged receipts are held for review.
+func NewFraudService(repository port.FraudRepository, options ...Option) *fraudService {
+	fs := &fraudService{
+		repository: repository,
+		action:     ActionHold,
+	}
+
+	for _, option := range options {
+		option(fs)
+	}
+
+	return fs
+}
+
+// ScreenReceipt flags a receipt as a duplicate when a receipt with the same
+// fingerprint was screened before, and records the outcome. A screening
+// decided by a review is kept when the receipt is screened again, unless it
+// now duplicates another receipt.
+func (fs *fraudService) ScreenReceipt(ctx context.Context, record entity.ReceiptRecord) (entity.FraudScreening, error) {
+	exactMatches, partialMatches, err := fs.repository.SaveFingerprint(ctx, fingerprint(record))
+	if err != nil {
+		return entity.FraudScreening{}, err
+	}
+
+	flaggedStatus := entity.ScreeningStatusHeld
+	if fs.action == ActionZero {
+		flaggedStatus = entity.ScreeningStatusRejected
+	}
+
+	screening := entity.FraudScreening{Status: entity.ScreeningStatusClear}
+
+	switch {
+	case len(exactMatches) > 0:
+		screening = entity.FraudScreening{
+			Status: flaggedStatus,
+			Reason: fmt.Sprintf(
+				"same retailer, purchase date and time, total and items as receipt %s",
+				exactMatches[0],
+			),
+			DuplicateOf: exactMatches[0],
+		}
+	case len(partialMatches) > 0:
+		screening = entity.FraudScreening{
+			Status: flaggedStatus,
+			Reason: fmt.Sprintf(
+				"same retailer, purchase date and total as receipt %s, with a different purchase time or items",
+				partialMatches[0],
+			),
+			DuplicateOf: partialMatches[0],
+		}
+	}
+
+	// A review decided against the same receipt, or before the receipt was
+	// amended, still applies.
+	previous, ok, err := fs.repository.GetScreening(ctx, record.ID)
+	if err != nil {
+		return entity.FraudScreening{}, err
+	}
+	if ok && previous.Reviewed && (screening.DuplicateOf == "" || screening.DuplicateOf == previous.DuplicateOf) {
+		return previous, nil
+	}
+
+	if err := fs.repository.SaveScreening(ctx, record.ID, screening); err != nil {
+		return entity.FraudScreening{}, err
+	}
+
+	return screening, nil
+}
+
+// GetScreening gets the screening of a receipt, if it was screened.
+func (fs *fraudService) GetScreening(ctx context.Context, receiptID string) (entity.FraudScreening, bool, error) {
+	return fs.repository.GetScreening(ctx, receiptID)
+}
+
+// ReviewReceipt records the decision of a review of a screened receipt, which
+// is cleared to be awarded points if approved and rejected otherwise.
+func (fs *fraudService) ReviewReceipt(ctx context.Context, receiptID string, approved bool, reason string) (entity.FraudScreening, error) {
+	screening, ok, err := fs.repository.GetScreening(ctx, receiptID)
+	if err != nil {
+		return entity.FraudScreening{}, err
+	}
+	if !ok {
+		return entity.FraudScreening{}, entity.ErrScreeningNotFound
+	}
+
+	if approved {
+		screening.Status = entity.ScreeningStatusClear
+	} else {
+		screening.Status = entity.ScreeningStatusRejected
+	}
+	screening.Reviewed = true
+
+	if reason != "" {
+		screening.Reason = reason
+	}
+
+	if err := fs.repository.SaveScreening(ctx, receiptID, screening); err != nil {
+		return entity.FraudScreening{}, err
+	}
+
+	return screening, nil
+}
+
+// fingerprint normalizes a receipt so copies of the same physical receipt
+// with small edits, such as changes in case, whitespace or punctuation,
+// reordered items, or repeated lines given as a quantity, have the same
+// fingerprint.
+func fingerprint(record entity.ReceiptRecord) entity.ReceiptFingerprint {
+	receipt := record.Receipt
+
+	// Identical lines are counted instead of repeated, so a line of many
+	// units costs no more than a line of one.
+	counts := make(map[string]int64, len(receipt.Items))
+	for _, item := range receipt.Items {
+		description := normalizeText(item.ShortDescription)
+		units, price := item.Units(), item.Price.Cents()
+
+		// A line of units at the same price is the same as a line per unit,
+		// so it's counted as them. Lines whose price can't be split, such as
+		// the discounted ones, are kept whole along with their quantity.
+		if units <= 0 || price%units != 0 {
+			counts[fmt.Sprintf("%s=%dx%d", description, price, units)]++
+			continue
+		}
+
+		counts[fmt.Sprintf("%s=%d", description, price/units)] += units
+	}
+
+	// Items are compared as a multiset, regardless of their order.
+	items := make([]string, 0, len(counts))
+	for line, count := range counts {
+		items = append(items, fmt.Sprintf("%s*%d", line, count))
+	}
+	sort.Strings(items)
+
+	retailer := normalizeText(receipt.Retailer)
+	date := strings.TrimSpace(receipt.PurchaseDate)
+	total := fmt.Sprint(receipt.Total.Cents())
+
+	return entity.ReceiptFingerprint{
+		ReceiptID: record.ID,
+		Exact:     hashFields(retailer, date, strings.TrimSpace(receipt.PurchaseTime), total, strings.Join(items, ",")),
+		Partial:   hashFields(retailer, date, total),
+	}
+}
+
+// normalizeText lowercases the text and keeps only its letters and digits.
+func normalizeText(text string) string {
+	var normalized strings.Builder
+
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			normalized.WriteRune(r)
+		}
+	}
+
+	return normalized.String()
+}
+
+func hashFields(fields ...string) string {
+	// The unit separator can't be part of the normalized fields.
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+
+	return hex.EncodeToString(sum[:])
+}
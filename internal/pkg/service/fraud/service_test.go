@@ -0,0 +1,512 @@
+package fraud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScreenReceipt(t *testing.T) {
+	approvedScreening := entity.FraudScreening{
+		Status:      entity.ScreeningStatusClear,
+		Reason:      "customer sent two receipts from the same purchase",
+		DuplicateOf: "first",
+		Reviewed:    true,
+	}
+
+	record := entity.ReceiptRecord{
+		ID: "1234567890",
+		Receipt: entity.Receipt{
+			Retailer:     "M&M Corner Market",
+			PurchaseDate: "2022-03-20",
+			PurchaseTime: "14:33",
+			Items: []entity.Item{
+				{ShortDescription: "Gatorade", Price: entity.MustParseMoney("2.25")},
+			},
+			Total: entity.MustParseMoney("2.25"),
+		},
+	}
+
+	testCases := []struct {
+		name string
+		ctx  context.Context
+
+		action          string
+		exactMatches    []string
+		partialMatches  []string
+		storedScreening *entity.FraudScreening
+
+		want      entity.FraudScreening
+		wantSaved bool
+	}{
+		{
+			name: "should clear a receipt without duplicates",
+			ctx:  context.Background(),
+
+			action: ActionHold,
+
+			want:      entity.FraudScreening{Status: entity.ScreeningStatusClear},
+			wantSaved: true,
+		},
+		{
+			name: "should hold a receipt with the same fingerprint as another",
+			ctx:  context.Background(),
+
+			action:         ActionHold,
+			exactMatches:   []string{"first", "second"},
+			partialMatches: []string{"first", "second"},
+
+			want: entity.FraudScreening{
+				Status:      entity.ScreeningStatusHeld,
+				Reason:      "same retailer, purchase date and time, total and items as receipt first",
+				DuplicateOf: "first",
+			},
+			wantSaved: true,
+		},
+		{
+			name: "should reject a receipt with the same partial fingerprint as another",
+			ctx:  context.Background(),
+
+			action:         ActionZero,
+			partialMatches: []string{"first"},
+
+			want: entity.FraudScreening{
+				Status:      entity.ScreeningStatusRejected,
+				Reason:      "same retailer, purchase date and total as receipt first, with a different purchase time or items",
+				DuplicateOf: "first",
+			},
+			wantSaved: true,
+		},
+		{
+			name: "should keep an approved screening of a receipt duplicating the same receipt",
+			ctx:  context.Background(),
+
+			action:          ActionHold,
+			exactMatches:    []string{"first"},
+			partialMatches:  []string{"first"},
+			storedScreening: &approvedScreening,
+
+			want: approvedScreening,
+		},
+		{
+			name: "should keep a reviewed screening of a receipt no longer duplicating another",
+			ctx:  context.Background(),
+
+			action:          ActionHold,
+			storedScreening: &approvedScreening,
+
+			want: approvedScreening,
+		},
+		{
+			name: "should hold a reviewed receipt duplicating another receipt",
+			ctx:  context.Background(),
+
+			action:          ActionHold,
+			partialMatches:  []string{"second"},
+			storedScreening: &approvedScreening,
+
+			want: entity.FraudScreening{
+				Status:      entity.ScreeningStatusHeld,
+				Reason:      "same retailer, purchase date and total as receipt second, with a different purchase time or items",
+				DuplicateOf: "second",
+			},
+			wantSaved: true,
+		},
+		{
+			name: "should screen again a receipt not reviewed",
+			ctx:  context.Background(),
+
+			action: ActionHold,
+			storedScreening: &entity.FraudScreening{
+				Status:      entity.ScreeningStatusHeld,
+				Reason:      "same retailer, purchase date and time, total and items as receipt first",
+				DuplicateOf: "first",
+			},
+
+			want:      entity.FraudScreening{Status: entity.ScreeningStatusClear},
+			wantSaved: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		repository := &mocks.FraudRepository{}
+		service := NewFraudService(repository, WithAction(tc.action))
+
+		repository.On(
+			"SaveFingerprint",
+			mock.Anything, /* context.Context */
+			fingerprint(record),
+		).Return(tc.exactMatches, tc.partialMatches, nil).Once()
+
+		if tc.storedScreening != nil {
+			repository.On(
+				"GetScreening",
+				mock.Anything, /* context.Context */
+				record.ID,
+			).Return(*tc.storedScreening, true, nil).Once()
+		} else {
+			repository.On(
+				"GetScreening",
+				mock.Anything, /* context.Context */
+				record.ID,
+			).Return(entity.FraudScreening{}, false, nil).Once()
+		}
+
+		if tc.wantSaved {
+			repository.On(
+				"SaveScreening",
+				mock.Anything, /* context.Context */
+				record.ID,
+				tc.want,
+			).Return(nil).Once()
+		}
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := service.ScreenReceipt(tc.ctx, record)
+			if err != nil {
+				t.Errorf("ScreenReceipt() = error %v", err)
+			}
+
+			if got != tc.want {
+				t.Errorf("ScreenReceipt() = %v, want %v", got, tc.want)
+			}
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	original := entity.Receipt{
+		Retailer:     "M&M Corner Market",
+		PurchaseDate: "2022-03-20",
+		PurchaseTime: "14:33",
+		Items: []entity.Item{
+			{ShortDescription: "Gatorade", Price: entity.MustParseMoney("2.25")},
+			{ShortDescription: "Doritos Nacho Cheese", Price: entity.MustParseMoney("3.35")},
+		},
+		Total: entity.MustParseMoney("5.60"),
+	}
+
+	testCases := []struct {
+		name string
+
+		receipt entity.Receipt
+
+		wantExact   bool
+		wantPartial bool
+	}{
+		{
+			name: "should match a receipt with whitespace, case and reordered items edits",
+
+			receipt: entity.Receipt{
+				Retailer:     "  m & m  CORNER market ",
+				PurchaseDate: "2022-03-20",
+				PurchaseTime: "14:33",
+				Items: []entity.Item{
+					{ShortDescription: "doritos  nacho cheese ", Price: entity.MustParseMoney("3.35")},
+					{ShortDescription: "GATORADE", Price: entity.MustParseMoney("2.25")},
+				},
+				Total: entity.MustParseMoney("5.60"),
+			},
+
+			wantExact:   true,
+			wantPartial: true,
+		},
+		{
+			name: "should partially match a receipt with edited items",
+
+			receipt: entity.Receipt{
+				Retailer:     "M&M Corner Market",
+				PurchaseDate: "2022-03-20",
+				PurchaseTime: "14:35",
+				Items: []entity.Item{
+					{ShortDescription: "Gatorade Zero", Price: entity.MustParseMoney("5.60")},
+				},
+				Total: entity.MustParseMoney("5.60"),
+			},
+
+			wantExact:   false,
+			wantPartial: true,
+		},
+		{
+			name: "should not match a receipt with another total",
+
+			receipt: entity.Receipt{
+				Retailer:     "M&M Corner Market",
+				PurchaseDate: "2022-03-20",
+				PurchaseTime: "14:33",
+				Items: []entity.Item{
+					{ShortDescription: "Gatorade", Price: entity.MustParseMoney("2.25")},
+				},
+				Total: entity.MustParseMoney("2.25"),
+			},
+
+			wantExact:   false,
+			wantPartial: false,
+		},
+		{
+			name: "should partially match a receipt with a quantity",
+
+			receipt: entity.Receipt{
+				Retailer:     "M&M Corner Market",
+				PurchaseDate: "2022-03-20",
+				PurchaseTime: "14:33",
+				Items: []entity.Item{
+					{ShortDescription: "Gatorade", Price: entity.MustParseMoney("2.25"), Quantity: 2},
+					{ShortDescription: "Doritos Nacho Cheese", Price: entity.MustParseMoney("3.35")},
+				},
+				Total: entity.MustParseMoney("5.60"),
+			},
+
+			wantExact:   false,
+			wantPartial: true,
+		},
+		{
+			name: "should match a receipt with an item of a single unit",
+
+			receipt: entity.Receipt{
+				Retailer:     "M&M Corner Market",
+				PurchaseDate: "2022-03-20",
+				PurchaseTime: "14:33",
+				Items: []entity.Item{
+					{ShortDescription: "Gatorade", Price: entity.MustParseMoney("2.25"), Quantity: 1, UnitPrice: entity.MustParseMoney("2.25")},
+					{ShortDescription: "Doritos Nacho Cheese", Price: entity.MustParseMoney("3.35")},
+				},
+				Total: entity.MustParseMoney("5.60"),
+			},
+
+			wantExact:   true,
+			wantPartial: true,
+		},
+	}
+
+	want := fingerprint(entity.ReceiptRecord{Receipt: original})
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := fingerprint(entity.ReceiptRecord{Receipt: tc.receipt})
+
+			if (got.Exact == want.Exact) != tc.wantExact {
+				t.Errorf("fingerprint() exact match = %v, want %v", got.Exact == want.Exact, tc.wantExact)
+			}
+
+			if (got.Partial == want.Partial) != tc.wantPartial {
+				t.Errorf("fingerprint() partial match = %v, want %v", got.Partial == want.Partial, tc.wantPartial)
+			}
+		})
+	}
+}
+
+func TestFingerprintQuantities(t *testing.T) {
+	gatorade := entity.Item{ShortDescription: "Gatorade", Price: entity.MustParseMoney("2.25")}
+
+	repeated := entity.Receipt{
+		Retailer:     "M&M Corner Market",
+		PurchaseDate: "2022-03-20",
+		PurchaseTime: "14:33",
+		Items:        []entity.Item{gatorade, gatorade, gatorade},
+		Total:        entity.MustParseMoney("6.75"),
+	}
+
+	testCases := []struct {
+		name string
+
+		items []entity.Item
+
+		wantExact bool
+	}{
+		{
+			name: "should match the units of a line repeated as a quantity",
+
+			items: []entity.Item{
+				{ShortDescription: "Gatorade", Price: entity.MustParseMoney("6.75"), Quantity: 3, UnitPrice: entity.MustParseMoney("2.25")},
+			},
+
+			wantExact: true,
+		},
+		{
+			name: "should match the units of a line split in two quantities",
+
+			items: []entity.Item{
+				{ShortDescription: "Gatorade", Price: entity.MustParseMoney("4.50"), Quantity: 2},
+				gatorade,
+			},
+
+			wantExact: true,
+		},
+		{
+			name: "should not match a line of units at other prices",
+
+			items: []entity.Item{
+				{ShortDescription: "Gatorade", Price: entity.MustParseMoney("4.00"), Quantity: 2},
+				{ShortDescription: "Gatorade", Price: entity.MustParseMoney("2.75")},
+			},
+
+			wantExact: false,
+		},
+		{
+			name: "should not match a discounted line that can't be split",
+
+			items: []entity.Item{
+				{
+					ShortDescription: "Gatorade",
+					Price:            entity.MustParseMoney("6.75"),
+					Quantity:         4,
+					UnitPrice:        entity.MustParseMoney("2.25"),
+					Discount:         entity.MustParseMoney("2.25"),
+				},
+			},
+
+			wantExact: false,
+		},
+	}
+
+	want := fingerprint(entity.ReceiptRecord{Receipt: repeated})
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			receipt := repeated
+			receipt.Items = tc.items
+
+			got := fingerprint(entity.ReceiptRecord{Receipt: receipt})
+
+			if (got.Exact == want.Exact) != tc.wantExact {
+				t.Errorf("fingerprint() exact match = %v, want %v", got.Exact == want.Exact, tc.wantExact)
+			}
+		})
+	}
+}
+
+func TestFingerprintLargeQuantity(t *testing.T) {
+	const units = 100_000
+
+	gatorade := entity.Item{ShortDescription: "Gatorade", Price: entity.MustParseMoney("2.25")}
+
+	single := entity.Receipt{
+		Retailer:     "M&M Corner Market",
+		PurchaseDate: "2022-03-20",
+		PurchaseTime: "14:33",
+		Items:        make([]entity.Item, units),
+		Total:        gatorade.Price * units,
+	}
+	for i := range single.Items {
+		single.Items[i] = gatorade
+	}
+
+	quantity := single
+	quantity.Items = []entity.Item{
+		{ShortDescription: "Gatorade", Price: gatorade.Price * units, Quantity: units, UnitPrice: gatorade.Price},
+	}
+
+	if got, want := fingerprint(entity.ReceiptRecord{Receipt: quantity}), fingerprint(entity.ReceiptRecord{Receipt: single}); got.Exact != want.Exact {
+		t.Errorf("fingerprint() exact = %v, want %v", got.Exact, want.Exact)
+	}
+
+	// The units of a line are counted, not repeated, so its cost doesn't
+	// depend on the quantity.
+	allocs := testing.AllocsPerRun(10, func() {
+		fingerprint(entity.ReceiptRecord{Receipt: quantity})
+	})
+	if allocs > 100 {
+		t.Errorf("fingerprint() allocations = %v, want at most 100", allocs)
+	}
+}
+
+func TestReviewReceipt(t *testing.T) {
+	heldScreening := entity.FraudScreening{
+		Status:      entity.ScreeningStatusHeld,
+		Reason:      "same retailer, purchase date and time, total and items as receipt first",
+		DuplicateOf: "first",
+	}
+
+	testCases := []struct {
+		name string
+		ctx  context.Context
+
+		storedScreening *entity.FraudScreening
+
+		approved bool
+		reason   string
+
+		want    entity.FraudScreening
+		wantErr error
+	}{
+		{
+			name: "should clear an approved receipt",
+			ctx:  context.Background(),
+
+			storedScreening: &heldScreening,
+
+			approved: true,
+			reason:   "customer sent two receipts from the same purchase",
+
+			want: entity.FraudScreening{
+				Status:      entity.ScreeningStatusClear,
+				Reason:      "customer sent two receipts from the same purchase",
+				DuplicateOf: "first",
+				Reviewed:    true,
+			},
+		},
+		{
+			name: "should reject a receipt keeping the screening reason",
+			ctx:  context.Background(),
+
+			storedScreening: &heldScreening,
+
+			want: entity.FraudScreening{
+				Status:      entity.ScreeningStatusRejected,
+				Reason:      heldScreening.Reason,
+				DuplicateOf: "first",
+				Reviewed:    true,
+			},
+		},
+		{
+			name: "should fail due receipt not screened",
+			ctx:  context.Background(),
+
+			wantErr: entity.ErrScreeningNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		repository := &mocks.FraudRepository{}
+		service := NewFraudService(repository)
+
+		if tc.storedScreening != nil {
+			repository.On(
+				"GetScreening",
+				mock.Anything, /* context.Context */
+				"1234567890",
+			).Return(*tc.storedScreening, true, nil).Once()
+
+			repository.On(
+				"SaveScreening",
+				mock.Anything, /* context.Context */
+				"1234567890",
+				tc.want,
+			).Return(nil).Once()
+		} else {
+			repository.On(
+				"GetScreening",
+				mock.Anything, /* context.Context */
+				"1234567890",
+			).Return(entity.FraudScreening{}, false, nil).Once()
+		}
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := service.ReviewReceipt(tc.ctx, "1234567890", tc.approved, tc.reason)
+
+			if got != tc.want {
+				t.Errorf("ReviewReceipt() = %v, want %v", got, tc.want)
+			}
+
+			if err != tc.wantErr {
+				t.Errorf("ReviewReceipt() = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package merchant
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/google/uuid"
+)
+
+type merchantService struct {
+	repository        port.MerchantRepository
+	receiptRepository port.ReceiptRepository
+	now               func() time.Time
+}
+
+// NewMerchantService creates a new merchant registry service, which assigns
+// the stored receipts to the merchants of their retailers.
+func NewMerchantService(repository port.MerchantRepository, receiptRepository port.ReceiptRepository) *merchantService {
+	return &merchantService{
+		repository:        repository,
+		receiptRepository: receiptRepository,
+		now:               time.Now,
+	}
+}
+
+// CreateMerchant registers a merchant whose name is its first alias, followed
+// by the given ones, and assigns it the receipts of any of them. Aliases with
+// the same normalized name are only kept once.
+func (ms *merchantService) CreateMerchant(ctx context.Context, name string, aliases []string) (entity.Merchant, error) {
+	merchant := entity.Merchant{
+		ID:        uuid.New().String(),
+		Name:      strings.TrimSpace(name),
+		Aliases:   []string{},
+		CreatedAt: ms.now().UTC(),
+	}
+
+	keys := make(map[string]bool)
+	for _, alias := range append([]string{name}, aliases...) {
+		alias = strings.TrimSpace(alias)
+
+		key := entity.NormalizeRetailer(alias)
+		if !keys[key] {
+			keys[key] = true
+			merchant.Aliases = append(merchant.Aliases, alias)
+		}
+	}
+
+	if err := ms.repository.SaveMerchant(ctx, merchant); err != nil {
+		return entity.Merchant{}, err
+	}
+
+	for _, alias := range merchant.Aliases {
+		if _, err := ms.receiptRepository.AssignMerchant(ctx, entity.NormalizeRetailer(alias), merchant.ID); err != nil {
+			return entity.Merchant{}, err
+		}
+	}
+
+	return merchant, nil
+}
+
+// GetMerchant gets a merchant with its aliases by its ID.
+func (ms *merchantService) GetMerchant(ctx context.Context, merchantID string) (entity.Merchant, error) {
+	return ms.repository.GetMerchant(ctx, merchantID)
+}
+
+// ListMerchants lists the merchants in the order they were created.
+func (ms *merchantService) ListMerchants(ctx context.Context) ([]entity.Merchant, error) {
+	return ms.repository.ListMerchants(ctx)
+}
+
+// RenameMerchant changes the name of a merchant, its aliases are left as they
+// are.
+func (ms *merchantService) RenameMerchant(ctx context.Context, merchantID, name string) (entity.Merchant, error) {
+	return ms.repository.RenameMerchant(ctx, merchantID, strings.TrimSpace(name))
+}
+
+// DeleteMerchant deletes a merchant with its aliases and unassigns its
+// receipts.
+func (ms *merchantService) DeleteMerchant(ctx context.Context, merchantID string) error {
+	merchant, err := ms.repository.GetMerchant(ctx, merchantID)
+	if err != nil {
+		return err
+	}
+
+	if err := ms.repository.DeleteMerchant(ctx, merchantID); err != nil {
+		return err
+	}
+
+	for _, alias := range merchant.Aliases {
+		if _, err := ms.receiptRepository.AssignMerchant(ctx, entity.NormalizeRetailer(alias), ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddAlias adds a retailer name to a merchant and assigns it the receipts of
+// the retailer.
+func (ms *merchantService) AddAlias(ctx context.Context, merchantID, alias string) (entity.Merchant, error) {
+	alias = strings.TrimSpace(alias)
+
+	merchant, err := ms.repository.AddMerchantAlias(ctx, merchantID, alias)
+	if err != nil {
+		return entity.Merchant{}, err
+	}
+
+	if _, err := ms.receiptRepository.AssignMerchant(ctx, entity.NormalizeRetailer(alias), merchantID); err != nil {
+		return entity.Merchant{}, err
+	}
+
+	return merchant, nil
+}
+
+// DeleteAlias removes a retailer name from a merchant, matched by its
+// normalized name, and unassigns the receipts of the retailer.
+func (ms *merchantService) DeleteAlias(ctx context.Context, merchantID, alias string) (entity.Merchant, error) {
+	merchant, err := ms.repository.DeleteMerchantAlias(ctx, merchantID, alias)
+	if err != nil {
+		return entity.Merchant{}, err
+	}
+
+	if _, err := ms.receiptRepository.AssignMerchant(ctx, entity.NormalizeRetailer(alias), ""); err != nil {
+		return entity.Merchant{}, err
+	}
+
+	return merchant, nil
+}
+
+// ResolveRetailer returns the ID of the merchant a retailer name is an alias
+// of, which is empty if there is none.
+func (ms *merchantService) ResolveRetailer(ctx context.Context, retailer string) (string, error) {
+	key := entity.NormalizeRetailer(retailer)
+	if key == "" {
+		return "", nil
+	}
+
+	merchantID, _, err := ms.repository.FindMerchantByRetailer(ctx, key)
+
+	return merchantID, err
+}
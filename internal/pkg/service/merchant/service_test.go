@@ -0,0 +1,237 @@
+package merchant
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateMerchant(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+
+		aliases       []string
+		repositoryErr error
+
+		wantAliases []string
+		wantErr     error
+	}{
+		{
+			name: "should register the name and the aliases once",
+
+			aliases: []string{" m&m corner mkt ", "M & M CORNER MARKET"},
+
+			wantAliases: []string{"M&M Corner Market", "m&m corner mkt"},
+		},
+		{
+			name: "should fail due alias of another merchant",
+
+			aliases:       []string{"Target"},
+			repositoryErr: entity.ErrMerchantAliasTaken,
+
+			wantErr: entity.ErrMerchantAliasTaken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repository := &mocks.MerchantRepository{}
+			receiptRepository := &mocks.ReceiptRepository{}
+			service := NewMerchantService(repository, receiptRepository)
+			service.now = func() time.Time { return now }
+
+			repository.On(
+				"SaveMerchant",
+				mock.Anything, /* context.Context */
+				mock.MatchedBy(func(merchant entity.Merchant) bool {
+					return merchant.ID != "" && merchant.Name == "M&M Corner Market" && merchant.CreatedAt.Equal(now)
+				}),
+			).Return(tc.repositoryErr).Once()
+
+			if len(tc.wantAliases) > 0 {
+				receiptRepository.On(
+					"AssignMerchant",
+					mock.Anything, /* context.Context */
+					mock.Anything, /* retailerKey string */
+					mock.Anything, /* merchantID string */
+				).Return(int64(1), nil).Times(len(tc.wantAliases))
+			}
+
+			got, err := service.CreateMerchant(context.Background(), "M&M Corner Market", tc.aliases)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("CreateMerchant() error = %v, want %v", err, tc.wantErr)
+			}
+
+			if err == nil && !reflect.DeepEqual(got.Aliases, tc.wantAliases) {
+				t.Errorf("CreateMerchant() aliases = %v, want %v", got.Aliases, tc.wantAliases)
+			}
+
+			for _, alias := range tc.wantAliases {
+				receiptRepository.AssertCalled(t, "AssignMerchant", mock.Anything, entity.NormalizeRetailer(alias), got.ID)
+			}
+
+			repository.AssertExpectations(t)
+			receiptRepository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteMerchant(t *testing.T) {
+	repository := &mocks.MerchantRepository{}
+	receiptRepository := &mocks.ReceiptRepository{}
+	service := NewMerchantService(repository, receiptRepository)
+
+	repository.On(
+		"GetMerchant",
+		mock.Anything, /* context.Context */
+		"1",
+	).Return(entity.Merchant{ID: "1", Aliases: []string{"M&M Corner Market", "m&m corner mkt"}}, nil).Once()
+
+	repository.On(
+		"DeleteMerchant",
+		mock.Anything, /* context.Context */
+		"1",
+	).Return(nil).Once()
+
+	for _, key := range []string{"mmcornermarket", "mmcornermkt"} {
+		receiptRepository.On(
+			"AssignMerchant",
+			mock.Anything, /* context.Context */
+			key,
+			"",
+		).Return(int64(1), nil).Once()
+	}
+
+	if err := service.DeleteMerchant(context.Background(), "1"); err != nil {
+		t.Fatalf("DeleteMerchant() = error %v", err)
+	}
+
+	repository.AssertExpectations(t)
+	receiptRepository.AssertExpectations(t)
+}
+
+func TestAddAlias(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		repositoryErr error
+
+		wantAssigned bool
+		wantErr      error
+	}{
+		{
+			name: "should assign the receipts of the alias",
+
+			wantAssigned: true,
+		},
+		{
+			name: "should fail due alias of another merchant",
+
+			repositoryErr: entity.ErrMerchantAliasTaken,
+
+			wantErr: entity.ErrMerchantAliasTaken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repository := &mocks.MerchantRepository{}
+			receiptRepository := &mocks.ReceiptRepository{}
+			service := NewMerchantService(repository, receiptRepository)
+
+			repository.On(
+				"AddMerchantAlias",
+				mock.Anything, /* context.Context */
+				"1",
+				"m&m corner mkt",
+			).Return(entity.Merchant{ID: "1"}, tc.repositoryErr).Once()
+
+			if tc.wantAssigned {
+				receiptRepository.On(
+					"AssignMerchant",
+					mock.Anything, /* context.Context */
+					"mmcornermkt",
+					"1",
+				).Return(int64(2), nil).Once()
+			}
+
+			_, err := service.AddAlias(context.Background(), "1", " m&m corner mkt ")
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("AddAlias() error = %v, want %v", err, tc.wantErr)
+			}
+
+			repository.AssertExpectations(t)
+			receiptRepository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResolveRetailer(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		retailer string
+		found    bool
+
+		want string
+	}{
+		{
+			name: "should resolve the merchant of an alias",
+
+			retailer: "M & M CORNER MARKET ",
+			found:    true,
+
+			want: "1",
+		},
+		{
+			name: "should not resolve an unknown retailer",
+
+			retailer: "Target",
+		},
+		{
+			name: "should not resolve a retailer without letters or digits",
+
+			retailer: "&",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repository := &mocks.MerchantRepository{}
+			service := NewMerchantService(repository, &mocks.ReceiptRepository{})
+
+			key := entity.NormalizeRetailer(tc.retailer)
+			if key != "" {
+				merchantID := ""
+				if tc.found {
+					merchantID = "1"
+				}
+
+				repository.On(
+					"FindMerchantByRetailer",
+					mock.Anything, /* context.Context */
+					key,
+				).Return(merchantID, tc.found, nil).Once()
+			}
+
+			got, err := service.ResolveRetailer(context.Background(), tc.retailer)
+			if err != nil {
+				t.Fatalf("ResolveRetailer() = error %v", err)
+			}
+
+			if got != tc.want {
+				t.Errorf("ResolveRetailer() = %q, want %q", got, tc.want)
+			}
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
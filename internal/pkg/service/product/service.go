@@ -0,0 +1,106 @@
+package product
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/google/uuid"
+)
+
+type productService struct {
+	repository port.ProductRepository
+	now        func() time.Time
+}
+
+// NewProductService creates a new product catalogue service.
+func NewProductService(repository port.ProductRepository) *productService {
+	return &productService{
+		repository: repository,
+		now:        time.Now,
+	}
+}
+
+// CreateProduct adds a product to the catalogue. It is expected to be
+// validated.
+func (ps *productService) CreateProduct(ctx context.Context, product entity.Product) (entity.Product, error) {
+	product = prepare(product)
+	product.ID = uuid.New().String()
+	product.CreatedAt = ps.now().UTC()
+
+	if err := ps.repository.SaveProduct(ctx, product); err != nil {
+		return entity.Product{}, err
+	}
+
+	return product, nil
+}
+
+// GetProduct gets a product by its ID.
+func (ps *productService) GetProduct(ctx context.Context, productID string) (entity.Product, error) {
+	return ps.repository.GetProduct(ctx, productID)
+}
+
+// ListProducts lists the products in the order they were created.
+func (ps *productService) ListProducts(ctx context.Context) ([]entity.Product, error) {
+	return ps.repository.ListProducts(ctx)
+}
+
+// UpdateProduct replaces the definition of a product. The items already
+// stored keep the products they matched.
+func (ps *productService) UpdateProduct(ctx context.Context, productID string, product entity.Product) (entity.Product, error) {
+	product = prepare(product)
+	product.ID = productID
+
+	return ps.repository.UpdateProduct(ctx, product)
+}
+
+// DeleteProduct deletes a product.
+func (ps *productService) DeleteProduct(ctx context.Context, productID string) error {
+	return ps.repository.DeleteProduct(ctx, productID)
+}
+
+// MatchProducts returns the product each item matches by its short
+// description. When several products match an item the one matching more
+// words, and more closely, is chosen, and between equal matches the oldest.
+func (ps *productService) MatchProducts(ctx context.Context, items []entity.Item) ([]entity.Product, error) {
+	matches := make([]entity.Product, len(items))
+	if len(items) == 0 {
+		return matches, nil
+	}
+
+	products, err := ps.repository.ListProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range items {
+		words := entity.ProductWords(item.ShortDescription)
+
+		best := 0
+		for _, product := range products {
+			if score, ok := product.Match(words); ok && score > best {
+				best = score
+				matches[i] = product
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// prepare trims the fields of a product.
+func prepare(product entity.Product) entity.Product {
+	product.Name = strings.TrimSpace(product.Name)
+	product.Brand = strings.TrimSpace(product.Brand)
+	product.Category = strings.TrimSpace(product.Category)
+
+	keywords := make([]string, 0, len(product.Keywords))
+	for _, keyword := range product.Keywords {
+		keywords = append(keywords, strings.TrimSpace(keyword))
+	}
+	product.Keywords = keywords
+
+	return product
+}
@@ -0,0 +1,82 @@
+package product
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateProduct(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	repository := &mocks.ProductRepository{}
+	service := NewProductService(repository)
+	service.now = func() time.Time { return now }
+
+	repository.On(
+		"SaveProduct",
+		mock.Anything, /* context.Context */
+		mock.MatchedBy(func(product entity.Product) bool {
+			return product.ID != "" && product.CreatedAt.Equal(now)
+		}),
+	).Return(nil).Once()
+
+	got, err := service.CreateProduct(context.Background(), entity.Product{Name: " Gatorade ", Brand: "Gatorade ", Keywords: []string{" Thirst Quencher"}})
+	if err != nil {
+		t.Fatalf("CreateProduct() = error %v", err)
+	}
+
+	want := entity.Product{ID: got.ID, Name: "Gatorade", Brand: "Gatorade", Keywords: []string{"Thirst Quencher"}, CreatedAt: now}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CreateProduct() = %v, want %v", got, want)
+	}
+
+	repository.AssertExpectations(t)
+}
+
+func TestMatchProducts(t *testing.T) {
+	products := []entity.Product{
+		{ID: "gatorade", Name: "Gatorade"},
+		{ID: "gatorade-zero", Name: "Gatorade Zero"},
+		{ID: "mountain-dew", Name: "Mountain Dew"},
+		{ID: "dew", Name: "Mountain Dew", Keywords: []string{"Dew"}},
+	}
+
+	testCases := []struct {
+		name string
+
+		description string
+
+		want string
+	}{
+		{name: "should match a product", description: "GATORADE 12PK", want: "gatorade"},
+		{name: "should match the product matching more words", description: "Gatorade Zero 8pk", want: "gatorade-zero"},
+		{name: "should match the oldest of equal matches", description: "Mtn Dew 12PK", want: "mountain-dew"},
+		{name: "should match no product", description: "Emils Cheese Pizza", want: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repository := &mocks.ProductRepository{}
+			service := NewProductService(repository)
+
+			repository.On("ListProducts", mock.Anything /* context.Context */).Return(products, nil).Once()
+
+			got, err := service.MatchProducts(context.Background(), []entity.Item{{ShortDescription: tc.description}})
+			if err != nil {
+				t.Fatalf("MatchProducts() = error %v", err)
+			}
+
+			if len(got) != 1 || got[0].ID != tc.want {
+				t.Errorf("MatchProducts() = %v, want product %q", got, tc.want)
+			}
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
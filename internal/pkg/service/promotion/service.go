@@ -0,0 +1,145 @@
+package promotion
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/google/uuid"
+)
+
+type promotionService struct {
+	repository      port.PromotionRepository
+	merchantService port.MerchantService
+	now             func() time.Time
+}
+
+// NewPromotionService creates a new promotion service, which matches the
+// receipts to the merchants of the promotions with the merchant registry.
+func NewPromotionService(repository port.PromotionRepository, merchantService port.MerchantService) *promotionService {
+	return &promotionService{
+		repository:      repository,
+		merchantService: merchantService,
+		now:             time.Now,
+	}
+}
+
+// CreatePromotion stores a new promotion. It is expected to be validated.
+func (ps *promotionService) CreatePromotion(ctx context.Context, promotion entity.Promotion) (entity.Promotion, error) {
+	promotion, err := ps.prepare(ctx, promotion)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+
+	promotion.ID = uuid.New().String()
+	promotion.CreatedAt = ps.now().UTC()
+
+	if err := ps.repository.SavePromotion(ctx, promotion); err != nil {
+		return entity.Promotion{}, err
+	}
+
+	return promotion, nil
+}
+
+// GetPromotion gets a promotion by its ID.
+func (ps *promotionService) GetPromotion(ctx context.Context, promotionID string) (entity.Promotion, error) {
+	return ps.repository.GetPromotion(ctx, promotionID)
+}
+
+// ListPromotions lists the promotions in the order they were created.
+func (ps *promotionService) ListPromotions(ctx context.Context) ([]entity.Promotion, error) {
+	return ps.repository.ListPromotions(ctx)
+}
+
+// UpdatePromotion replaces the definition of a promotion. The points already
+// calculated aren't changed, only those of the receipts scored from then on.
+func (ps *promotionService) UpdatePromotion(ctx context.Context, promotionID string, promotion entity.Promotion) (entity.Promotion, error) {
+	promotion, err := ps.prepare(ctx, promotion)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+
+	promotion.ID = promotionID
+
+	return ps.repository.UpdatePromotion(ctx, promotion)
+}
+
+// DeletePromotion deletes a promotion.
+func (ps *promotionService) DeletePromotion(ctx context.Context, promotionID string) error {
+	return ps.repository.DeletePromotion(ctx, promotionID)
+}
+
+// prepare fills the defaults of a promotion and checks that its merchant
+// exists.
+func (ps *promotionService) prepare(ctx context.Context, promotion entity.Promotion) (entity.Promotion, error) {
+	promotion.Name = strings.TrimSpace(promotion.Name)
+	if promotion.Stacking == "" {
+		promotion.Stacking = entity.PromotionStackingCombine
+	}
+
+	if promotion.MerchantID != "" {
+		if _, err := ps.merchantService.GetMerchant(ctx, promotion.MerchantID); err != nil {
+			return entity.Promotion{}, err
+		}
+	}
+
+	return promotion, nil
+}
+
+// GetPromotionPoints returns the points awarded by the promotions of the
+// merchant of the receipt, or of any merchant, whose window includes its
+// purchase date. The combinable promotions add up, and an exclusive one is
+// applied alone instead when it awards more points than all of them together.
+// Between exclusive promotions awarding the same points the oldest is applied.
+func (ps *promotionService) GetPromotionPoints(ctx context.Context, receipt entity.Receipt, basePoints int64) ([]entity.PromotionPoints, error) {
+	promotions, err := ps.repository.ListActivePromotions(ctx, receipt.PurchaseDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(promotions) == 0 {
+		return nil, nil
+	}
+
+	// The merchant is only resolved if a promotion needs it.
+	var merchantID string
+	for _, promotion := range promotions {
+		if promotion.MerchantID != "" {
+			merchantID, err = ps.merchantService.ResolveRetailer(ctx, receipt.Retailer)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	var combined []entity.PromotionPoints
+	var combinedPoints int64
+	var exclusive *entity.PromotionPoints
+
+	for _, promotion := range promotions {
+		if !promotion.Applies(merchantID, receipt.PurchaseDate) {
+			continue
+		}
+
+		points := promotion.Points(basePoints)
+
+		if promotion.Exclusive() {
+			if exclusive == nil || points.Points > exclusive.Points {
+				exclusive = &points
+			}
+			continue
+		}
+
+		combined = append(combined, points)
+		combinedPoints += points.Points
+	}
+
+	if exclusive != nil && (combined == nil || exclusive.Points > combinedPoints) {
+		return []entity.PromotionPoints{*exclusive}, nil
+	}
+
+	return combined, nil
+}
@@ -0,0 +1,183 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreatePromotion(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+
+		promotion   entity.Promotion
+		merchantErr error
+
+		wantStacking string
+		wantErr      error
+	}{
+		{
+			name: "should store a promotion combinable by default",
+
+			promotion: entity.Promotion{Name: " New partner ", Bonus: 100},
+
+			wantStacking: entity.PromotionStackingCombine,
+		},
+		{
+			name: "should store a promotion of a merchant",
+
+			promotion: entity.Promotion{Name: "New partner", MerchantID: "1", Multiplier: 2, Stacking: entity.PromotionStackingExclusive},
+
+			wantStacking: entity.PromotionStackingExclusive,
+		},
+		{
+			name: "should fail due unknown merchant",
+
+			promotion:   entity.Promotion{Name: "New partner", MerchantID: "1", Multiplier: 2},
+			merchantErr: entity.ErrMerchantNotFound,
+
+			wantErr: entity.ErrMerchantNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repository := &mocks.PromotionRepository{}
+			merchantService := &mocks.MerchantService{}
+			service := NewPromotionService(repository, merchantService)
+			service.now = func() time.Time { return now }
+
+			if tc.promotion.MerchantID != "" {
+				merchantService.On(
+					"GetMerchant",
+					mock.Anything, /* context.Context */
+					tc.promotion.MerchantID,
+				).Return(entity.Merchant{ID: tc.promotion.MerchantID}, tc.merchantErr).Once()
+			}
+
+			if tc.wantErr == nil {
+				repository.On(
+					"SavePromotion",
+					mock.Anything, /* context.Context */
+					mock.MatchedBy(func(promotion entity.Promotion) bool {
+						return promotion.ID != "" && promotion.Name == "New partner" && promotion.CreatedAt.Equal(now)
+					}),
+				).Return(nil).Once()
+			}
+
+			got, err := service.CreatePromotion(context.Background(), tc.promotion)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("CreatePromotion() error = %v, want %v", err, tc.wantErr)
+			}
+
+			if got.Stacking != tc.wantStacking {
+				t.Errorf("CreatePromotion() stacking = %q, want %q", got.Stacking, tc.wantStacking)
+			}
+
+			repository.AssertExpectations(t)
+			merchantService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetPromotionPoints(t *testing.T) {
+	receipt := entity.Receipt{Retailer: "Target", PurchaseDate: "2024-03-02"}
+
+	double := entity.Promotion{ID: "double", Name: "Double points at Target", MerchantID: "target", Multiplier: 2, Stacking: entity.PromotionStackingCombine}
+	bonus := entity.Promotion{ID: "bonus", Name: "Bonus", Bonus: 10, Stacking: entity.PromotionStackingCombine}
+	walmart := entity.Promotion{ID: "walmart", Name: "Walmart", MerchantID: "walmart", Bonus: 1000, Stacking: entity.PromotionStackingCombine}
+	partner := entity.Promotion{ID: "partner", Name: "New partner", Bonus: 100, Stacking: entity.PromotionStackingExclusive}
+	halfMore := entity.Promotion{ID: "half", Name: "Half more points", Multiplier: 1.5, Stacking: entity.PromotionStackingExclusive}
+
+	testCases := []struct {
+		name string
+
+		promotions []entity.Promotion
+
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "should award nothing without promotions",
+		},
+		{
+			name: "should combine the promotions of the merchant and of any merchant",
+
+			promotions: []entity.Promotion{double, walmart, bonus},
+
+			want: []string{"double", "bonus"},
+		},
+		{
+			name: "should apply an exclusive promotion awarding more points alone",
+
+			promotions: []entity.Promotion{double, bonus, partner},
+
+			want: []string{"partner"},
+		},
+		{
+			name: "should keep the combined promotions awarding more points",
+
+			promotions: []entity.Promotion{double, bonus, halfMore},
+
+			want: []string{"double", "bonus"},
+		},
+		{
+			name: "should apply the exclusive promotion awarding most points",
+
+			promotions: []entity.Promotion{halfMore, partner},
+
+			want: []string{"partner"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repository := &mocks.PromotionRepository{}
+			merchantService := &mocks.MerchantService{}
+			service := NewPromotionService(repository, merchantService)
+
+			repository.On(
+				"ListActivePromotions",
+				mock.Anything, /* context.Context */
+				receipt.PurchaseDate,
+			).Return(tc.promotions, nil).Once()
+
+			for _, promotion := range tc.promotions {
+				if promotion.MerchantID != "" {
+					merchantService.On(
+						"ResolveRetailer",
+						mock.Anything, /* context.Context */
+						receipt.Retailer,
+					).Return("target", nil).Once()
+					break
+				}
+			}
+
+			// Double points award 28, half more points 14.
+			got, err := service.GetPromotionPoints(context.Background(), receipt, 28)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("GetPromotionPoints() error = %v, want %v", err, tc.wantErr)
+			}
+
+			var gotIDs []string
+			for _, points := range got {
+				gotIDs = append(gotIDs, points.PromotionID)
+			}
+
+			if !reflect.DeepEqual(gotIDs, tc.want) {
+				t.Errorf("GetPromotionPoints() = %v, want %v", gotIDs, tc.want)
+			}
+
+			repository.AssertExpectations(t)
+			merchantService.AssertExpectations(t)
+		})
+	}
+}
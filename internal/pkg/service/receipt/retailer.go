@@ -0,0 +1,131 @@
+package receipt
+
+import (
+	"unicode"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Code points that join or change the look of the characters around them.
+const (
+	zeroWidthJoiner       = '\u200d'
+	emojiPresentation     = '\ufe0f'
+	combiningEnclosingKey = '\u20e3'
+)
+
+// viramaCombiningClass is the canonical combining class of the viramas, which
+// join the consonants around them in a conjunct in the Indic scripts.
+const viramaCombiningClass = 9
+
+var (
+	variationSelectors = &unicode.RangeTable{
+		R16: []unicode.Range16{{Lo: 0xfe00, Hi: 0xfe0f, Stride: 1}},
+		R32: []unicode.Range32{{Lo: 0xe0100, Hi: 0xe01ef, Stride: 1}},
+	}
+	emojiModifiers = &unicode.RangeTable{
+		R32: []unicode.Range32{{Lo: 0x1f3fb, Hi: 0x1f3ff, Stride: 1}},
+	}
+	emojiTags = &unicode.RangeTable{
+		R32: []unicode.Range32{{Lo: 0xe0020, Hi: 0xe007f, Stride: 1}},
+	}
+	regionalIndicators = &unicode.RangeTable{
+		R32: []unicode.Range32{{Lo: 0x1f1e6, Hi: 0x1f1ff, Stride: 1}},
+	}
+)
+
+// countAlphanumerics counts the alphanumeric characters of a retailer name as
+// defined by the characters of the rule.
+func countAlphanumerics(retailer, characters string) int64 {
+	var count int64
+
+	switch characters {
+	case entity.RetailerCharactersASCII:
+		for i := 0; i < len(retailer); i++ {
+			if c := retailer[i]; 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' {
+				count++
+			}
+		}
+
+	case entity.RetailerCharactersGraphemes:
+		for _, grapheme := range graphemes(retailer) {
+			if isAlphanumericGrapheme(grapheme) {
+				count++
+			}
+		}
+
+	default:
+		for _, r := range retailer {
+			if isAlphanumeric(r) {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+func isAlphanumeric(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
+
+// isAlphanumericGrapheme reports whether a grapheme is a letter or number,
+// along with its marks, and not shown as an emoji, as the keycaps.
+func isAlphanumericGrapheme(grapheme []rune) bool {
+	if !isAlphanumeric(grapheme[0]) {
+		return false
+	}
+
+	for _, r := range grapheme[1:] {
+		if r == emojiPresentation || r == combiningEnclosingKey {
+			return false
+		}
+	}
+
+	return true
+}
+
+// graphemes splits a string in user-perceived characters. It approximates the
+// extended grapheme clusters of Unicode for the cases that matter when
+// counting letters: the marks, the conjuncts joined by a virama, and the emoji
+// with their modifiers, joined sequences and flags. The string is composed
+// first, so decomposed letters and Hangul syllables are single characters.
+func graphemes(s string) [][]rune {
+	var clusters [][]rune
+
+	for _, r := range norm.NFC.String(s) {
+		if len(clusters) > 0 && extendsGrapheme(clusters[len(clusters)-1], r) {
+			clusters[len(clusters)-1] = append(clusters[len(clusters)-1], r)
+			continue
+		}
+
+		clusters = append(clusters, []rune{r})
+	}
+
+	return clusters
+}
+
+// extendsGrapheme reports whether the rune belongs to the grapheme before it.
+func extendsGrapheme(grapheme []rune, r rune) bool {
+	previous := grapheme[len(grapheme)-1]
+
+	switch {
+	case unicode.IsMark(r), r == zeroWidthJoiner, unicode.In(r, variationSelectors, emojiModifiers, emojiTags):
+		return true
+	case previous == zeroWidthJoiner:
+		return true
+	case unicode.IsLetter(r) && norm.NFC.PropertiesString(string(previous)).CCC() == viramaCombiningClass:
+		return true
+	case unicode.Is(regionalIndicators, r):
+		// Flags are pairs of regional indicators.
+		indicators := 0
+		for _, g := range grapheme {
+			if unicode.Is(regionalIndicators, g) {
+				indicators++
+			}
+		}
+		return len(grapheme) == indicators && indicators%2 == 1
+	default:
+		return false
+	}
+}
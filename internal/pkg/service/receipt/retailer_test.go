@@ -0,0 +1,103 @@
+package receipt
+
+import (
+	"testing"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+func TestCountAlphanumerics(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		retailer string
+
+		wantASCII     int64
+		wantUnicode   int64
+		wantGraphemes int64
+	}{
+		{name: "ascii", retailer: "Target", wantASCII: 6, wantUnicode: 6, wantGraphemes: 6},
+		{name: "ascii with punctuation", retailer: "M&M Corner Market", wantASCII: 14, wantUnicode: 14, wantGraphemes: 14},
+		{name: "ascii with spaces and digits", retailer: "  retailer name with spaces 1  ", wantASCII: 23, wantUnicode: 23, wantGraphemes: 23},
+		{name: "no alphanumerics", retailer: "%#&$ -", wantASCII: 0, wantUnicode: 0, wantGraphemes: 0},
+		{name: "empty", retailer: "", wantASCII: 0, wantUnicode: 0, wantGraphemes: 0},
+		{name: "latin precomposed accents", retailer: "Café Ñandú", wantASCII: 6, wantUnicode: 9, wantGraphemes: 9},
+		{name: "latin decomposed accents", retailer: "Café", wantASCII: 4, wantUnicode: 4, wantGraphemes: 4},
+		{name: "latin stacked accents", retailer: "Phở Việt", wantASCII: 5, wantUnicode: 7, wantGraphemes: 7},
+		{name: "german sharp s", retailer: "Bäckerei Straße", wantASCII: 12, wantUnicode: 14, wantGraphemes: 14},
+		{name: "greek", retailer: "Αθήνα", wantASCII: 0, wantUnicode: 5, wantGraphemes: 5},
+		{name: "cyrillic with digits", retailer: "Москва 24", wantASCII: 2, wantUnicode: 8, wantGraphemes: 8},
+		{name: "hebrew", retailer: "שלום", wantASCII: 0, wantUnicode: 4, wantGraphemes: 4},
+		{name: "arabic", retailer: "مكتبة جرير", wantASCII: 0, wantUnicode: 9, wantGraphemes: 9},
+		{name: "arabic with vowel marks", retailer: "كِتَاب", wantASCII: 0, wantUnicode: 4, wantGraphemes: 4},
+		{name: "devanagari conjunct", retailer: "नमस्ते", wantASCII: 0, wantUnicode: 4, wantGraphemes: 3},
+		{name: "tamil", retailer: "தமிழ்", wantASCII: 0, wantUnicode: 3, wantGraphemes: 3},
+		{name: "thai", retailer: "ไทยมาร์ท", wantASCII: 0, wantUnicode: 7, wantGraphemes: 7},
+		{name: "chinese", retailer: "北京超市", wantASCII: 0, wantUnicode: 4, wantGraphemes: 4},
+		{name: "japanese kana and kanji", retailer: "東京マート", wantASCII: 0, wantUnicode: 5, wantGraphemes: 5},
+		{name: "japanese voiced kana", retailer: "ガスト", wantASCII: 0, wantUnicode: 3, wantGraphemes: 3},
+		{name: "japanese decomposed voiced kana", retailer: "ガスト", wantASCII: 0, wantUnicode: 3, wantGraphemes: 3},
+		{name: "hangul syllables", retailer: "서울 마트", wantASCII: 0, wantUnicode: 4, wantGraphemes: 4},
+		{name: "hangul conjoining jamo", retailer: "서울", wantASCII: 0, wantUnicode: 5, wantGraphemes: 2},
+		{name: "full-width latin", retailer: "ＡＢＣ", wantASCII: 0, wantUnicode: 3, wantGraphemes: 3},
+		{name: "roman numeral", retailer: "Ⅻ Market", wantASCII: 6, wantUnicode: 7, wantGraphemes: 7},
+		{name: "vulgar fraction", retailer: "½ Price", wantASCII: 5, wantUnicode: 6, wantGraphemes: 6},
+		{name: "arabic-indic digits", retailer: "متجر ٢٤", wantASCII: 0, wantUnicode: 6, wantGraphemes: 6},
+		{name: "emoji", retailer: "Pizza 🍕", wantASCII: 5, wantUnicode: 5, wantGraphemes: 5},
+		{name: "emoji with skin tone", retailer: "👍🏽 Shop", wantASCII: 4, wantUnicode: 4, wantGraphemes: 4},
+		{name: "emoji joined sequence", retailer: "👨‍👩‍👧 Family", wantASCII: 6, wantUnicode: 6, wantGraphemes: 6},
+		{name: "emoji flag", retailer: "🇺🇸🇨🇦 Store", wantASCII: 5, wantUnicode: 5, wantGraphemes: 5},
+		{name: "emoji keycap", retailer: "1️⃣ Dollar", wantASCII: 7, wantUnicode: 7, wantGraphemes: 6},
+		{name: "emoji letter after joiner", retailer: "🏳️‍🌈 Bar", wantASCII: 3, wantUnicode: 3, wantGraphemes: 3},
+		{name: "mixed scripts", retailer: "Café 東京 24/7 🍕", wantASCII: 6, wantUnicode: 9, wantGraphemes: 9},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for characters, want := range map[string]int64{
+				entity.RetailerCharactersASCII:     tc.wantASCII,
+				entity.RetailerCharactersUnicode:   tc.wantUnicode,
+				entity.RetailerCharactersGraphemes: tc.wantGraphemes,
+			} {
+				if got := countAlphanumerics(tc.retailer, characters); got != want {
+					t.Errorf("countAlphanumerics(%q, %s) = %d, want %d", tc.retailer, characters, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGraphemes(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		s string
+
+		want []string
+	}{
+		{name: "letters", s: "ab", want: []string{"a", "b"}},
+		{name: "composed accent", s: "éx", want: []string{"é", "x"}},
+		{name: "conjunct", s: "स्ते", want: []string{"स्ते"}},
+		{name: "joined emoji", s: "👨‍👩‍👧!", want: []string{"👨‍👩‍👧", "!"}},
+		{name: "flags", s: "🇺🇸🇨🇦", want: []string{"🇺🇸", "🇨🇦"}},
+		{name: "skin tone", s: "👍🏽a", want: []string{"👍🏽", "a"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, grapheme := range graphemes(tc.s) {
+				got = append(got, string(grapheme))
+			}
+
+			if len(got) != len(tc.want) {
+				t.Fatalf("graphemes() = %q, want %q", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("graphemes() = %q, want %q", got, tc.want)
+				}
+			}
+		})
+	}
+}
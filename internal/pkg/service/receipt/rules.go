@@ -0,0 +1,182 @@
+package receipt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+/*
+In order to make the code policy-less I defined a series of constants
+to make the methods agnostic to the values of the rules. These constants
+are the default values of the rules, they can be overridden with a rules
+file loaded at start-up so the points can be tuned without changing the
+code. In this way we can have different versions of the rules for
+different environments.
+*/
+const (
+	pointsForAlphanumericCharacter = 1
+	pointsForTotalRounded          = 50
+	pointsForTotalMultiple         = 25
+	pointsForPurchaseTimeInBetween = 10
+	pointsForItemPairs             = 5
+	pointsForDayOdd                = 6
+)
+
+const (
+	divisibilityFactorForTotalRounded     = 25 // In cents.
+	multpliyingFactorForItemsDescriptions = 0.2
+	lengthMultipleForItemsDescriptions    = 3
+)
+
+const (
+	startTimeHourForTimeCheck = 14
+	endTimeHourForTimeCheck   = 16
+)
+
+// Name and version of the rule set used when no rules file is provided.
+const (
+	defaultRuleSetName    = "default"
+	defaultRuleSetVersion = "1"
+)
+
+// DefaultRuleSets returns the rule sets used when no rules file is provided,
+// which only contain the default rules.
+func DefaultRuleSets() entity.RuleSetsConfig {
+	return SingleRuleSet(DefaultRules())
+}
+
+// SingleRuleSet returns a config with only the given rules as the default
+// rule set, which is the active one.
+func SingleRuleSet(rules entity.Rules) entity.RuleSetsConfig {
+	return entity.RuleSetsConfig{
+		ActiveVersion: defaultRuleSetVersion,
+		RuleSets: []entity.RuleSet{
+			{
+				Name:    defaultRuleSetName,
+				Version: defaultRuleSetVersion,
+				Rules:   rules,
+			},
+		},
+	}
+}
+
+// DefaultRules returns the rules used when no rules file is provided.
+func DefaultRules() entity.Rules {
+	return entity.Rules{
+		RetailerName: entity.RetailerNameRule{
+			PointsPerCharacter: pointsForAlphanumericCharacter,
+			Characters:         entity.RetailerCharactersUnicode,
+		},
+		TotalRounded: entity.TotalRoundedRule{
+			Points: pointsForTotalRounded,
+		},
+		TotalMultiple: entity.TotalMultipleRule{
+			Points:   pointsForTotalMultiple,
+			Multiple: divisibilityFactorForTotalRounded,
+		},
+		ItemPairs: entity.ItemPairsRule{
+			PointsPerPair: pointsForItemPairs,
+		},
+		ItemDescriptions: entity.ItemDescriptionsRule{
+			LengthMultiple:  lengthMultipleForItemsDescriptions,
+			PriceMultiplier: multpliyingFactorForItemsDescriptions,
+		},
+		PurchaseDate: entity.PurchaseDateRule{
+			Points: pointsForDayOdd,
+		},
+		PurchaseTime: entity.PurchaseTimeRule{
+			Points:    pointsForPurchaseTimeInBetween,
+			StartHour: startTimeHourForTimeCheck,
+			EndHour:   endTimeHourForTimeCheck,
+		},
+	}
+}
+
+// ValidateRuleSets checks that the rule sets have unique versions, that the
+// active version is one of them and that their rules are valid.
+func ValidateRuleSets(config entity.RuleSetsConfig) error {
+	var errs []error
+
+	if len(config.RuleSets) == 0 {
+		errs = append(errs, errors.New("at least one rule set is required"))
+	}
+
+	versions := make(map[string]bool, len(config.RuleSets))
+	for i, ruleSet := range config.RuleSets {
+		if ruleSet.Version == "" {
+			errs = append(errs, fmt.Errorf("ruleSets[%d].version is required", i))
+			continue
+		}
+
+		if versions[ruleSet.Version] {
+			errs = append(errs, fmt.Errorf("ruleSets[%d].version %q is duplicated", i, ruleSet.Version))
+		}
+		versions[ruleSet.Version] = true
+
+		if err := ValidateRules(ruleSet.Rules); err != nil {
+			errs = append(errs, fmt.Errorf("rule set version %q: %w", ruleSet.Version, err))
+		}
+	}
+
+	if len(config.RuleSets) > 0 && !versions[config.ActiveVersion] {
+		errs = append(errs, fmt.Errorf("activeVersion %q is not the version of any rule set", config.ActiveVersion))
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateRules checks that the parameters of the rules can be used to calculate points.
+func ValidateRules(rules entity.Rules) error {
+	var errs []error
+
+	checkNotNegative := func(field string, value int64) {
+		if value < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative, got %d", field, value))
+		}
+	}
+
+	checkNotNegative("retailerName.pointsPerCharacter", rules.RetailerName.PointsPerCharacter)
+	checkNotNegative("totalRounded.points", rules.TotalRounded.Points)
+	checkNotNegative("totalMultiple.points", rules.TotalMultiple.Points)
+	checkNotNegative("itemPairs.pointsPerPair", rules.ItemPairs.PointsPerPair)
+	checkNotNegative("purchaseDate.points", rules.PurchaseDate.Points)
+	checkNotNegative("purchaseTime.points", rules.PurchaseTime.Points)
+
+	switch rules.RetailerName.Characters {
+	case "", entity.RetailerCharactersASCII, entity.RetailerCharactersUnicode, entity.RetailerCharactersGraphemes:
+	default:
+		errs = append(errs, fmt.Errorf("retailerName.characters must be %s, %s or %s, got %q",
+			entity.RetailerCharactersASCII, entity.RetailerCharactersUnicode, entity.RetailerCharactersGraphemes,
+			rules.RetailerName.Characters))
+	}
+
+	for i, bonus := range rules.ProductBonus.Bonuses {
+		if bonus.Brand == "" && bonus.Category == "" {
+			errs = append(errs, fmt.Errorf("productBonus.bonuses[%d] must have a brand or a category", i))
+		}
+		checkNotNegative(fmt.Sprintf("productBonus.bonuses[%d].pointsPerItem", i), bonus.PointsPerItem)
+	}
+
+	if rules.TotalMultiple.Multiple <= 0 {
+		errs = append(errs, fmt.Errorf("totalMultiple.multiple must be greater than zero, got %s", rules.TotalMultiple.Multiple))
+	}
+
+	if rules.ItemDescriptions.LengthMultiple <= 0 {
+		errs = append(errs, fmt.Errorf("itemDescriptions.lengthMultiple must be greater than zero, got %d", rules.ItemDescriptions.LengthMultiple))
+	}
+
+	if rules.ItemDescriptions.PriceMultiplier < 0 {
+		errs = append(errs, fmt.Errorf("itemDescriptions.priceMultiplier must not be negative, got %v", rules.ItemDescriptions.PriceMultiplier))
+	}
+
+	timeRule := rules.PurchaseTime
+	if timeRule.StartHour < 0 || timeRule.StartHour > 23 || timeRule.EndHour < 0 || timeRule.EndHour > 23 {
+		errs = append(errs, fmt.Errorf("purchaseTime hours must be between 0 and 23, got %d and %d", timeRule.StartHour, timeRule.EndHour))
+	} else if timeRule.StartHour > timeRule.EndHour {
+		errs = append(errs, fmt.Errorf("purchaseTime.startHour %d must not be after purchaseTime.endHour %d", timeRule.StartHour, timeRule.EndHour))
+	}
+
+	return errors.Join(errs...)
+}
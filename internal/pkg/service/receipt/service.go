@@ -2,49 +2,83 @@ package receipt
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math"
-	"strconv"
+	"math/big"
 	"strings"
-	"unicode"
 
 	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
 	"github.com/darcops/receipt-proccessor-challenge/util"
 	"github.com/google/uuid"
 	"golang.org/x/sync/errgroup"
 )
 
-/*
-In order to make the code policy-less I defined a series of constants
-to make the methods agnostic to the values of the rules. This way, if
-the rules change, only the constants need to be changed and not the
-methods themselves. Also these constants can be provided by a config
-file or environment variables. In this way we can have different versions
-of the rules for different environments.
-*/
+// Names of the rules used to calculate the points of a receipt.
 const (
-	pointsForAlphanumericCharacter = 1
-	pointsForTotalRounded          = 50
-	pointsForTotalMultiple         = 25
-	pointsForPurchaseTimeInBetween = 10
-	pointsForItemPairs             = 5
-	pointsForDayOdd                = 6
+	ruleRetailerName     = "retailer_name"
+	ruleTotalRounded     = "total_rounded"
+	ruleTotalMultiple    = "total_multiple"
+	ruleItemPairs        = "item_pairs"
+	ruleItemDescriptions = "item_descriptions"
+	rulePurchaseDate     = "purchase_date"
+	rulePurchaseTime     = "purchase_time"
+	ruleProductBonus     = "product_bonus"
 )
 
-const (
-	divisibilityFactorForTotalRounded     = 0.25
-	multpliyingFactorForItemsDescriptions = 0.2
-)
+type receiptService struct {
+	ruleSetByVersion map[string]entity.RuleSet
+	activeVersion    string
+	promotionService port.PromotionService
+	productService   port.ProductService
+}
 
-const (
-	startTimeHourForTimeCheck = 14
-	endTimeHourForTimeCheck   = 16
-)
+// Option configures the receipt service.
+type Option func(*receiptService)
+
+// WithRuleSets sets the rule sets available to calculate the points and the
+// version of the one used for new receipts. They are expected to be validated
+// with ValidateRuleSets.
+func WithRuleSets(config entity.RuleSetsConfig) Option {
+	return func(rs *receiptService) {
+		rs.ruleSetByVersion = make(map[string]entity.RuleSet, len(config.RuleSets))
+		for _, ruleSet := range config.RuleSets {
+			rs.ruleSetByVersion[ruleSet.Version] = ruleSet
+		}
+
+		rs.activeVersion = config.ActiveVersion
+	}
+}
+
+// WithPromotions adds the points of the promotions that apply to a receipt to
+// those of the rules.
+func WithPromotions(promotionService port.PromotionService) Option {
+	return func(rs *receiptService) {
+		rs.promotionService = promotionService
+	}
+}
+
+// WithProducts matches the items to the products of the catalogue, so the
+// product bonuses of the rules can be awarded.
+func WithProducts(productService port.ProductService) Option {
+	return func(rs *receiptService) {
+		rs.productService = productService
+	}
+}
 
-type receiptService struct{}
+// NewReceiptService creates a new receipt service. Unless other rule sets are
+// provided the points are calculated with DefaultRuleSets.
+func NewReceiptService(options ...Option) *receiptService {
+	rs := &receiptService{}
 
-// NewReceiptService creates a new receipt service.
-func NewReceiptService() *receiptService {
-	return &receiptService{}
+	WithRuleSets(DefaultRuleSets())(rs)
+
+	for _, option := range options {
+		option(rs)
+	}
+
+	return rs
 }
 
 // CreateReceiptID creates an ID for receipt.
@@ -52,20 +86,88 @@ func (rs *receiptService) CreateReceiptID(ctx context.Context) string {
 	return uuid.New().String()
 }
 
-// GetReceiptPoints gets the points of a receipt.
+// GetReceiptPoints gets the points of a receipt with the active rule set.
 func (rs *receiptService) GetReceiptPoints(ctx context.Context, receipt entity.Receipt) (int64, error) {
-	ruleFunctions := []func() (int64, error){
-		func() (int64, error) { return rs.getPointsForRetailerName(receipt.Retailer), nil },
-		func() (int64, error) { return rs.getPointsForTotalRounded(receipt.Total) },
-		func() (int64, error) { return rs.getPointsForTotalMultiple(receipt.Total) },
-		func() (int64, error) { return rs.getPointsForItemsCount(receipt.Items), nil },
-		func() (int64, error) { return rs.getPointsForItemsDescriptions(receipt.Items), nil },
-		func() (int64, error) { return rs.getPointsForPurchaseDate(receipt.PurchaseDate) },
-		func() (int64, error) { return rs.getPointsForPurchaseHour(receipt.PurchaseTime) },
+	breakdown, err := rs.GetReceiptPointsBreakdown(ctx, receipt)
+	if err != nil {
+		return 0, err
+	}
+
+	return breakdown.Points, nil
+}
+
+// GetReceiptPointsBreakdown gets the points of a receipt with the active rule
+// set along with the points awarded by each rule and the reason for them.
+func (rs *receiptService) GetReceiptPointsBreakdown(ctx context.Context, receipt entity.Receipt) (entity.PointsBreakdown, error) {
+	return rs.GetReceiptPointsBreakdownWithRuleSet(ctx, receipt, rs.activeVersion)
+}
+
+// GetReceiptPointsBreakdownWithRuleSet gets the points breakdown of a receipt
+// with the rule set of the given version.
+func (rs *receiptService) GetReceiptPointsBreakdownWithRuleSet(ctx context.Context, receipt entity.Receipt, ruleSetVersion string) (entity.PointsBreakdown, error) {
+	return rs.getPointsBreakdown(ctx, receipt, ruleSetVersion, func() ([]entity.Product, error) {
+		return rs.productService.MatchProducts(ctx, receipt.Items)
+	})
+}
+
+// GetRecordPointsBreakdown gets the points breakdown of a stored receipt with
+// the rule set of the given version, or the active one if empty. Its items
+// are awarded the product bonus of the products they were matched to when
+// the receipt was stored, instead of being matched again.
+func (rs *receiptService) GetRecordPointsBreakdown(ctx context.Context, record entity.ReceiptRecord, ruleSetVersion string) (entity.PointsBreakdown, error) {
+	if ruleSetVersion == "" {
+		ruleSetVersion = rs.activeVersion
+	}
+
+	return rs.getPointsBreakdown(ctx, record.Receipt, ruleSetVersion, func() ([]entity.Product, error) {
+		return rs.getStoredProducts(ctx, record)
+	})
+}
+
+// getPointsBreakdown gets the points breakdown of a receipt with the rule set
+// of the given version, getting the products of its items with the given
+// function if the rule set awards a product bonus.
+func (rs *receiptService) getPointsBreakdown(ctx context.Context, receipt entity.Receipt, ruleSetVersion string, getProducts func() ([]entity.Product, error)) (entity.PointsBreakdown, error) {
+	ruleSet, ok := rs.ruleSetByVersion[ruleSetVersion]
+	if !ok {
+		return entity.PointsBreakdown{}, entity.ErrRuleSetNotFound
+	}
+
+	rules := ruleSet.Rules
+
+	ruleFunctions := []func() (entity.RulePoints, error){
+		func() (entity.RulePoints, error) {
+			return rs.getPointsForRetailerName(rules.RetailerName, receipt.Retailer), nil
+		},
+		func() (entity.RulePoints, error) {
+			return rs.getPointsForTotalRounded(rules.TotalRounded, receipt.Total), nil
+		},
+		func() (entity.RulePoints, error) {
+			return rs.getPointsForTotalMultiple(rules.TotalMultiple, receipt.Total), nil
+		},
+		func() (entity.RulePoints, error) {
+			return rs.getPointsForItemsCount(rules.ItemPairs, receipt.Items), nil
+		},
+		func() (entity.RulePoints, error) {
+			return rs.getPointsForItemsDescriptions(rules.ItemDescriptions, receipt.Items), nil
+		},
+		func() (entity.RulePoints, error) {
+			return rs.getPointsForPurchaseDate(rules.PurchaseDate, receipt.PurchaseDate)
+		},
+		func() (entity.RulePoints, error) {
+			return rs.getPointsForPurchaseHour(rules.PurchaseTime, receipt.PurchaseTime)
+		},
+	}
+
+	// The rule is only listed in the breakdown of the rule sets that use it.
+	if len(rules.ProductBonus.Bonuses) > 0 {
+		ruleFunctions = append(ruleFunctions, func() (entity.RulePoints, error) {
+			return rs.getPointsForProductBonus(rules.ProductBonus, receipt.Items, getProducts)
+		})
 	}
 
 	errGroup, _ := errgroup.WithContext(ctx)
-	partialPoints := make([]int64, len(ruleFunctions))
+	partialPoints := make([]entity.RulePoints, len(ruleFunctions))
 
 	// Execute rule functions concurrently.
 	for i, ruleFunc := range ruleFunctions {
@@ -81,95 +183,259 @@ func (rs *receiptService) GetReceiptPoints(ctx context.Context, receipt entity.R
 	}
 
 	if err := errGroup.Wait(); err != nil {
-		return 0, err
+		return entity.PointsBreakdown{}, err
 	}
 
 	// Calculate total points.
 	totalPoints := int64(0)
 	for _, points := range partialPoints {
-		totalPoints += points
+		totalPoints += points.Points
 	}
 
-	return totalPoints, nil
+	breakdown := entity.PointsBreakdown{
+		Points:         totalPoints,
+		RuleSetVersion: ruleSet.Version,
+		Rules:          partialPoints,
+	}
+
+	if rs.promotionService == nil {
+		return breakdown, nil
+	}
+
+	// Promotions multiply the points of the rules, so they are applied after them.
+	promotionPoints, err := rs.promotionService.GetPromotionPoints(ctx, receipt, totalPoints)
+	if err != nil {
+		return entity.PointsBreakdown{}, err
+	}
+
+	for _, points := range promotionPoints {
+		breakdown.Points += points.Points
+	}
+	breakdown.Promotions = promotionPoints
+
+	return breakdown, nil
+}
+
+func (rs *receiptService) getPointsForRetailerName(rule entity.RetailerNameRule, retailer string) entity.RulePoints {
+	characters := countAlphanumerics(retailer, rule.Characters)
+
+	return entity.RulePoints{
+		Rule:   ruleRetailerName,
+		Points: characters * rule.PointsPerCharacter,
+		Reason: fmt.Sprintf("%d alphanumeric characters in the retailer name", characters),
+	}
 }
 
-func (rs *receiptService) getPointsForRetailerName(retailer string) int64 {
+func (rs *receiptService) getPointsForTotalRounded(rule entity.TotalRoundedRule, total entity.Money) entity.RulePoints {
+	if total > 0 && total.Cents()%100 == 0 {
+		return entity.RulePoints{
+			Rule:   ruleTotalRounded,
+			Points: rule.Points,
+			Reason: fmt.Sprintf("total %s is a round dollar amount with no cents", total),
+		}
+	}
+
+	return entity.RulePoints{
+		Rule:   ruleTotalRounded,
+		Reason: fmt.Sprintf("total %s is not a round dollar amount", total),
+	}
+}
+
+func (rs *receiptService) getPointsForTotalMultiple(rule entity.TotalMultipleRule, total entity.Money) entity.RulePoints {
+	multiple := rule.Multiple
+
+	if total > 0 && total.Cents()%multiple.Cents() == 0 {
+		return entity.RulePoints{
+			Rule:   ruleTotalMultiple,
+			Points: rule.Points,
+			Reason: fmt.Sprintf("total %s is a multiple of %s", total, multiple),
+		}
+	}
+
+	return entity.RulePoints{
+		Rule:   ruleTotalMultiple,
+		Reason: fmt.Sprintf("total %s is not a multiple of %s", total, multiple),
+	}
+}
+
+// getPointsForItemsCount counts the units of the items, so a line with a
+// quantity counts as many times as if it was repeated.
+func (rs *receiptService) getPointsForItemsCount(rule entity.ItemPairsRule, items []entity.Item) entity.RulePoints {
+	var units int64
+	for _, item := range items {
+		units += item.Units()
+	}
+	pairs := units / 2
+
+	return entity.RulePoints{
+		Rule:   ruleItemPairs,
+		Points: pairs * rule.PointsPerPair,
+		Reason: fmt.Sprintf("%d pairs of items", pairs),
+	}
+}
+
+// getPointsForItemsDescriptions awards the points of each line once, on the
+// price paid for the whole line whatever its quantity.
+func (rs *receiptService) getPointsForItemsDescriptions(rule entity.ItemDescriptionsRule, items []entity.Item) entity.RulePoints {
 	var points int64
+	var matchingItems int
+
+	lengthMultiple := rule.LengthMultiple
 
-	for i := 0; i < len(retailer); i++ {
-		if unicode.IsLetter(rune(retailer[i])) || unicode.IsNumber(rune(retailer[i])) {
-			points += pointsForAlphanumericCharacter
+	for i := 0; i < len(items); i++ {
+		description := strings.TrimSpace(items[i].ShortDescription)
+
+		if len(description) > 0 && len(description)%lengthMultiple == 0 {
+			points += multiplyPriceRoundingUp(items[i].Price, rule.PriceMultiplier)
+			matchingItems++
 		}
 	}
 
-	return points
+	return entity.RulePoints{
+		Rule:   ruleItemDescriptions,
+		Points: points,
+		Reason: fmt.Sprintf("%d items with description length divisible by %d", matchingItems, lengthMultiple),
+	}
 }
 
-func (rs *receiptService) getPointsForTotalRounded(total string) (int64, error) {
-	value, err := strconv.ParseFloat(total, 64)
+func (rs *receiptService) getPointsForPurchaseDate(rule entity.PurchaseDateRule, purchaseDate string) (entity.RulePoints, error) {
+	isOdd, err := util.IsDayOdd(purchaseDate)
 	if err != nil {
-		return 0, err
+		return entity.RulePoints{}, err
 	}
 
-	if value > 0 && value == math.Round(value) {
-		return pointsForTotalRounded, nil
+	if !isOdd {
+		return entity.RulePoints{
+			Rule:   rulePurchaseDate,
+			Reason: fmt.Sprintf("purchase day of %s is even", purchaseDate),
+		}, nil
 	}
 
-	return 0, nil
+	return entity.RulePoints{
+		Rule:   rulePurchaseDate,
+		Points: rule.Points,
+		Reason: fmt.Sprintf("purchase day of %s is odd", purchaseDate),
+	}, nil
 }
 
-func (rs *receiptService) getPointsForTotalMultiple(total string) (int64, error) {
-	value, err := strconv.ParseFloat(total, 64)
+func (rs *receiptService) getPointsForPurchaseHour(rule entity.PurchaseTimeRule, purchaseTime string) (entity.RulePoints, error) {
+	isHourBetween, err := util.IsTimeBetween(
+		purchaseTime,
+		rule.StartHour,
+		rule.EndHour,
+	)
 	if err != nil {
-		return 0, err
+		return entity.RulePoints{}, err
 	}
 
-	if value > 0 && math.Mod(value, divisibilityFactorForTotalRounded) == 0 {
-		return pointsForTotalMultiple, nil
+	if !isHourBetween {
+		return entity.RulePoints{
+			Rule: rulePurchaseTime,
+			Reason: fmt.Sprintf(
+				"purchase time %s is not between %02d:00 and %02d:00",
+				purchaseTime, rule.StartHour, rule.EndHour,
+			),
+		}, nil
 	}
 
-	return 0, nil
+	return entity.RulePoints{
+		Rule:   rulePurchaseTime,
+		Points: rule.Points,
+		Reason: fmt.Sprintf(
+			"purchase time %s is between %02d:00 and %02d:00",
+			purchaseTime, rule.StartHour, rule.EndHour,
+		),
+	}, nil
 }
 
-func (rs *receiptService) getPointsForItemsCount(items []entity.Item) int64 {
-	return int64((len(items) / 2) * pointsForItemPairs)
-}
+func (rs *receiptService) getPointsForProductBonus(rule entity.ProductBonusRule, items []entity.Item, getProducts func() ([]entity.Product, error)) (entity.RulePoints, error) {
+	if rs.productService == nil {
+		return entity.RulePoints{
+			Rule:   ruleProductBonus,
+			Reason: "items are not matched to products",
+		}, nil
+	}
 
-func (rs *receiptService) getPointsForItemsDescriptions(items []entity.Item) int64 {
-	var points int64
+	products, err := getProducts()
+	if err != nil {
+		return entity.RulePoints{}, err
+	}
 
-	for i := 0; i < len(items); i++ {
-		description := strings.TrimSpace(items[i].ShortDescription)
+	var points, matchingItems int64
+
+	// Every item is awarded its best bonus only, for each of its units.
+	for i, product := range products {
+		var best int64
+		matched := false
+
+		for _, bonus := range rule.Bonuses {
+			if bonus.Applies(product) && (!matched || bonus.PointsPerItem > best) {
+				best = bonus.PointsPerItem
+				matched = true
+			}
+		}
 
-		if len(description) > 0 && len(description)%3 == 0 {
-			itemPrice, _ := strconv.ParseFloat(items[i].Price, 64)
-			pricePoints := int64(math.Ceil(itemPrice * multpliyingFactorForItemsDescriptions))
-			points += pricePoints
+		if matched {
+			points += best * items[i].Units()
+			matchingItems += items[i].Units()
 		}
 	}
 
-	return points
+	return entity.RulePoints{
+		Rule:   ruleProductBonus,
+		Points: points,
+		Reason: fmt.Sprintf("%d items of products with a bonus", matchingItems),
+	}, nil
 }
 
-func (rs *receiptService) getPointsForPurchaseDate(purchaseDate string) (int64, error) {
-	if isOdd, err := util.IsDayOdd(purchaseDate); err != nil || !isOdd {
-		return 0, err
+// getStoredProducts gets the products the items of a stored receipt were
+// matched to, in the order of the items. Items that matched none, or whose
+// product was deleted since, get a product without ID.
+func (rs *receiptService) getStoredProducts(ctx context.Context, record entity.ReceiptRecord) ([]entity.Product, error) {
+	products := make([]entity.Product, len(record.Receipt.Items))
+	productByID := make(map[string]entity.Product)
+
+	for i, productID := range record.ItemProductIDs {
+		if productID == "" || i >= len(products) {
+			continue
+		}
+
+		product, ok := productByID[productID]
+		if !ok {
+			var err error
+			product, err = rs.productService.GetProduct(ctx, productID)
+			if err != nil && !errors.Is(err, entity.ErrProductNotFound) {
+				return nil, err
+			}
+			productByID[productID] = product
+		}
+
+		products[i] = product
 	}
 
-	return pointsForDayOdd, nil
+	return products, nil
 }
 
-func (rs *receiptService) getPointsForPurchaseHour(purchaseTime string) (int64, error) {
-	var isHourBetween bool
-	var err error
+// priceMultiplierScale is the precision kept from the price multipliers, so
+// prices are multiplied with integer arithmetic and without floating point errors.
+const priceMultiplierScale = 1_000_000
 
-	if isHourBetween, err = util.IsTimeBetween(
-		purchaseTime,
-		startTimeHourForTimeCheck,
-		endTimeHourForTimeCheck,
-	); err != nil || !isHourBetween {
-		return 0, err
+// multiplyPriceRoundingUp multiplies a price, in dollars, by the multiplier and
+// rounds the result up to the nearest integer.
+func multiplyPriceRoundingUp(price entity.Money, multiplier float64) int64 {
+	scaledMultiplier := big.NewInt(int64(math.Round(multiplier * priceMultiplierScale)))
+
+	numerator := new(big.Int).Mul(big.NewInt(price.Cents()), scaledMultiplier)
+	denominator := big.NewInt(100 * priceMultiplierScale)
+
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if remainder.Sign() > 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+
+	if !quotient.IsInt64() {
+		return math.MaxInt64
 	}
 
-	return pointsForPurchaseTimeInBetween, nil
+	return quotient.Int64()
 }
@@ -2,9 +2,13 @@ package receipt
 
 import (
 	"context"
+	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetReceiptPoints(t *testing.T) {
@@ -30,18 +34,18 @@ func TestGetReceiptPoints(t *testing.T) {
 				Items: []entity.Item{
 					{
 						ShortDescription: "Item 1",
-						Price:            "10.00",
+						Price:            entity.MustParseMoney("10.00"),
 					},
 					{
 						ShortDescription: "Item 2",
-						Price:            "10.87",
+						Price:            entity.MustParseMoney("10.87"),
 					},
 					{
 						ShortDescription: "Item 3",
-						Price:            "1.00",
+						Price:            entity.MustParseMoney("1.00"),
 					},
 				},
-				Total: "100.00",
+				Total: entity.MustParseMoney("100.00"),
 			},
 
 			want: 109,
@@ -55,7 +59,7 @@ func TestGetReceiptPoints(t *testing.T) {
 				Retailer:     " retailer name example-2 ",
 				PurchaseDate: "2020-01-02",
 				PurchaseTime: "12:00",
-				Total:        "100.01",
+				Total:        entity.MustParseMoney("100.01"),
 			},
 
 			want: 20,
@@ -77,6 +81,342 @@ func TestGetReceiptPoints(t *testing.T) {
 	}
 }
 
+func TestGetReceiptPointsBreakdown(t *testing.T) {
+	service := NewReceiptService()
+
+	receipt := entity.Receipt{
+		Retailer:     "$Walmart/   ",
+		PurchaseDate: "2020-01-01",
+		PurchaseTime: "15:00",
+		Items: []entity.Item{
+			{
+				ShortDescription: "Item 1",
+				Price:            entity.MustParseMoney("10.00"),
+			},
+			{
+				ShortDescription: "Item 2",
+				Price:            entity.MustParseMoney("10.87"),
+			},
+			{
+				ShortDescription: "Item 3",
+				Price:            entity.MustParseMoney("1.00"),
+			},
+		},
+		Total: entity.MustParseMoney("100.00"),
+	}
+
+	want := []entity.RulePoints{
+		{Rule: ruleRetailerName, Points: 7, Reason: "7 alphanumeric characters in the retailer name"},
+		{Rule: ruleTotalRounded, Points: 50, Reason: "total 100.00 is a round dollar amount with no cents"},
+		{Rule: ruleTotalMultiple, Points: 25, Reason: "total 100.00 is a multiple of 0.25"},
+		{Rule: ruleItemPairs, Points: 5, Reason: "1 pairs of items"},
+		{Rule: ruleItemDescriptions, Points: 6, Reason: "3 items with description length divisible by 3"},
+		{Rule: rulePurchaseDate, Points: 6, Reason: "purchase day of 2020-01-01 is odd"},
+		{Rule: rulePurchaseTime, Points: 10, Reason: "purchase time 15:00 is between 14:00 and 16:00"},
+	}
+
+	got, err := service.GetReceiptPointsBreakdown(context.Background(), receipt)
+	if err != nil {
+		t.Fatalf("GetReceiptPointsBreakdown() = error %v", err)
+	}
+
+	if got.Points != 109 || got.RuleSetVersion != defaultRuleSetVersion {
+		t.Errorf("GetReceiptPointsBreakdown() = %v, %v, want %v, %v", got.Points, got.RuleSetVersion, 109, defaultRuleSetVersion)
+	}
+
+	if len(got.Rules) != len(want) {
+		t.Fatalf("GetReceiptPointsBreakdown() = %d rules, want %d", len(got.Rules), len(want))
+	}
+
+	for i := range want {
+		if got.Rules[i] != want[i] {
+			t.Errorf("GetReceiptPointsBreakdown() rule %d = %v, want %v", i, got.Rules[i], want[i])
+		}
+	}
+}
+
+func TestGetReceiptPointsBreakdownWithPromotions(t *testing.T) {
+	receipt := entity.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-02",
+		PurchaseTime: "13:01",
+		Items: []entity.Item{
+			{
+				ShortDescription: "Mountain Dew 12PK",
+				Price:            entity.MustParseMoney("6.49"),
+			},
+		},
+		Total: entity.MustParseMoney("6.49"),
+	}
+
+	promotionErr := errors.New("promotion error")
+
+	testCases := []struct {
+		name string
+
+		promotionPoints []entity.PromotionPoints
+		promotionErr    error
+
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "should add the points of the promotions to those of the rules",
+
+			promotionPoints: []entity.PromotionPoints{
+				{PromotionID: "1", Points: 6},
+				{PromotionID: "2", Points: 100},
+			},
+
+			want: 112,
+		},
+		{
+			name: "should return the points of the rules without promotions",
+
+			want: 6,
+		},
+		{
+			name: "should fail due promotion error",
+
+			promotionErr: promotionErr,
+
+			wantErr: promotionErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockPromotionService := &mocks.PromotionService{}
+
+			// Only the retailer name awards points, 6.
+			mockPromotionService.On(
+				"GetPromotionPoints",
+				mock.Anything, /* context.Context */
+				receipt,
+				int64(6),
+			).Return(tc.promotionPoints, tc.promotionErr).Once()
+
+			service := NewReceiptService(WithPromotions(mockPromotionService))
+
+			got, err := service.GetReceiptPointsBreakdown(context.Background(), receipt)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("GetReceiptPointsBreakdown() error = %v, want %v", err, tc.wantErr)
+			}
+
+			if got.Points != tc.want {
+				t.Errorf("GetReceiptPointsBreakdown() = %v, want %v", got.Points, tc.want)
+			}
+
+			if tc.wantErr == nil && !reflect.DeepEqual(got.Promotions, tc.promotionPoints) {
+				t.Errorf("GetReceiptPointsBreakdown() promotions = %v, want %v", got.Promotions, tc.promotionPoints)
+			}
+
+			mockPromotionService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetPointsForProductBonus(t *testing.T) {
+	items := []entity.Item{
+		{ShortDescription: "Gatorade 12PK", Price: entity.MustParseMoney("6.49")},
+		{ShortDescription: "Doritos Nacho Cheese", Price: entity.MustParseMoney("3.35")},
+		{ShortDescription: "Emils Cheese Pizza", Price: entity.MustParseMoney("12.25")},
+	}
+
+	products := []entity.Product{
+		{ID: "1", Brand: "Gatorade", Category: "Drinks"},
+		{ID: "2", Brand: "Doritos", Category: "Snacks"},
+		{},
+	}
+
+	testCases := []struct {
+		name string
+
+		items   []entity.Item
+		bonuses []entity.ProductBonus
+
+		want int64
+	}{
+		{
+			name: "should award the bonus of a brand",
+
+			bonuses: []entity.ProductBonus{{Brand: "gatorade", PointsPerItem: 10}},
+
+			want: 10,
+		},
+		{
+			name: "should award the bonus of a category",
+
+			bonuses: []entity.ProductBonus{{Category: "Snacks", PointsPerItem: 5}},
+
+			want: 5,
+		},
+		{
+			name: "should award only the best bonus of every item",
+
+			bonuses: []entity.ProductBonus{
+				{Category: "Drinks", PointsPerItem: 5},
+				{Brand: "Gatorade", Category: "Drinks", PointsPerItem: 20},
+				{Category: "Snacks", PointsPerItem: 5},
+			},
+
+			want: 25,
+		},
+		{
+			name: "should award nothing to other brands",
+
+			bonuses: []entity.ProductBonus{{Brand: "Pepsi", PointsPerItem: 10}},
+
+			want: 0,
+		},
+		{
+			name: "should award the bonus for every unit of an item",
+
+			items: []entity.Item{
+				{ShortDescription: "Gatorade 12PK", Price: entity.MustParseMoney("19.47"), Quantity: 3},
+				items[1],
+				items[2],
+			},
+			bonuses: []entity.ProductBonus{{Brand: "Gatorade", PointsPerItem: 10}},
+
+			want: 30,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			items := items
+			if tc.items != nil {
+				items = tc.items
+			}
+
+			service := NewReceiptService(WithProducts(&mocks.ProductService{}))
+
+			got, err := service.getPointsForProductBonus(entity.ProductBonusRule{Bonuses: tc.bonuses}, items, func() ([]entity.Product, error) {
+				return products, nil
+			})
+			if err != nil {
+				t.Fatalf("getPointsForProductBonus() = error %v", err)
+			}
+
+			if got.Points != tc.want {
+				t.Errorf("getPointsForProductBonus() = %v, want %v", got.Points, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetRecordPointsBreakdown(t *testing.T) {
+	rules := DefaultRules()
+	rules.ProductBonus.Bonuses = []entity.ProductBonus{{Brand: "Gatorade", PointsPerItem: 10}}
+
+	ruleSets := entity.RuleSetsConfig{
+		ActiveVersion: "1",
+		RuleSets:      []entity.RuleSet{{Name: "bonus", Version: "1", Rules: rules}},
+	}
+
+	record := entity.ReceiptRecord{
+		ID: "receipt-1",
+		Receipt: entity.Receipt{
+			Retailer:     "Target",
+			PurchaseDate: "2022-01-01",
+			PurchaseTime: "13:01",
+			Items: []entity.Item{
+				{ShortDescription: "Gatorade 12PK", Price: entity.MustParseMoney("6.49")},
+				{ShortDescription: "G2 Lemon Lime", Price: entity.MustParseMoney("3.35")},
+				{ShortDescription: "Emils Cheese Pizza", Price: entity.MustParseMoney("12.25")},
+			},
+			Total: entity.MustParseMoney("22.09"),
+		},
+	}
+
+	gatorade := entity.Product{ID: "1", Brand: "Gatorade", Category: "Drinks"}
+
+	testCases := []struct {
+		name string
+
+		itemProductIDs []string
+		products       map[string]entity.Product
+		productErr     error
+
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "should award the bonus of the stored matches",
+
+			itemProductIDs: []string{"1", "1", ""},
+			products:       map[string]entity.Product{"1": gatorade},
+
+			want: 20,
+		},
+		{
+			name: "should award nothing to items without matches",
+
+			want: 0,
+		},
+		{
+			name: "should award nothing to products deleted since",
+
+			itemProductIDs: []string{"2", "", ""},
+
+			want: 0,
+		},
+		{
+			name: "should fail due error getting a product",
+
+			itemProductIDs: []string{"1", "", ""},
+			productErr:     errors.New("some error"),
+
+			wantErr: errors.New("some error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockProductService := &mocks.ProductService{}
+			for _, productID := range tc.itemProductIDs {
+				if productID == "" {
+					continue
+				}
+
+				product, ok := tc.products[productID]
+				err := tc.productErr
+				if !ok && err == nil {
+					err = entity.ErrProductNotFound
+				}
+				mockProductService.On("GetProduct", mock.Anything /* context.Context */, productID).Return(product, err).Once()
+				break
+			}
+
+			service := NewReceiptService(WithRuleSets(ruleSets), WithProducts(mockProductService))
+
+			record := record
+			record.ItemProductIDs = tc.itemProductIDs
+
+			got, err := service.GetRecordPointsBreakdown(context.Background(), record, "")
+			if !reflect.DeepEqual(err, tc.wantErr) {
+				t.Fatalf("GetRecordPointsBreakdown() error = %v, want %v", err, tc.wantErr)
+			}
+
+			if tc.wantErr == nil {
+				var bonus int64
+				for _, rulePoints := range got.Rules {
+					if rulePoints.Rule == ruleProductBonus {
+						bonus = rulePoints.Points
+					}
+				}
+
+				if bonus != tc.want {
+					t.Errorf("GetRecordPointsBreakdown() product bonus = %v, want %v", bonus, tc.want)
+				}
+			}
+
+			mockProductService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestGetPointsForRetailerName(t *testing.T) {
 	testCases := []struct {
 		name    string
@@ -110,6 +450,14 @@ func TestGetPointsForRetailerName(t *testing.T) {
 
 			want: 1,
 		},
+		{
+			name:    "should count letters of any script",
+			service: NewReceiptService(),
+
+			retailerName: "Café 東京",
+
+			want: 6,
+		},
 		{
 			name:    "should ommit spaces",
 			service: NewReceiptService(),
@@ -122,10 +470,10 @@ func TestGetPointsForRetailerName(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := tc.service.getPointsForRetailerName(tc.retailerName)
+			got := tc.service.getPointsForRetailerName(DefaultRules().RetailerName, tc.retailerName)
 
-			if got != tc.want {
-				t.Errorf("getPointsForRetailerName() = %v, want %v", got, tc.want)
+			if got.Points != tc.want {
+				t.Errorf("getPointsForRetailerName() = %v, want %v", got.Points, tc.want)
 			}
 		})
 	}
@@ -136,16 +484,15 @@ func TestGetPointsForTotalRounded(t *testing.T) {
 		name    string
 		service *receiptService
 
-		total string
+		total entity.Money
 
-		want    int64
-		wantErr bool
+		want int64
 	}{
 		{
 			name:    "should return zero points for total",
 			service: NewReceiptService(),
 
-			total: "0.00",
+			total: entity.MustParseMoney("0.00"),
 
 			want: 0,
 		},
@@ -153,7 +500,7 @@ func TestGetPointsForTotalRounded(t *testing.T) {
 			name:    "should return zero points for total with cents",
 			service: NewReceiptService(),
 
-			total: "0.01",
+			total: entity.MustParseMoney("0.01"),
 
 			want: 0,
 		},
@@ -161,30 +508,18 @@ func TestGetPointsForTotalRounded(t *testing.T) {
 			name:    "should return 50 points for total with no cents",
 			service: NewReceiptService(),
 
-			total: "1.00",
+			total: entity.MustParseMoney("1.00"),
 
 			want: pointsForTotalRounded,
 		},
-		{
-			name:    "should fail due invalid total",
-			service: NewReceiptService(),
-
-			total: "invalid total",
-
-			wantErr: true,
-		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			got, err := tc.service.getPointsForTotalRounded(tc.total)
-
-			if got != tc.want {
-				t.Errorf("getPointsForTotalRounded() = %v, want %v", got, tc.want)
-			}
+			got := tc.service.getPointsForTotalRounded(DefaultRules().TotalRounded, tc.total)
 
-			if (err != nil) != tc.wantErr {
-				t.Errorf("getPointsForTotalRounded() = %v, want %v", err, tc.wantErr)
+			if got.Points != tc.want {
+				t.Errorf("getPointsForTotalRounded() = %v, want %v", got.Points, tc.want)
 			}
 		})
 	}
@@ -195,16 +530,15 @@ func TestGetPointsForTotalMultiple(t *testing.T) {
 		name    string
 		service *receiptService
 
-		total string
+		total entity.Money
 
-		want    int64
-		wantErr bool
+		want int64
 	}{
 		{
 			name:    "should return zero points for total",
 			service: NewReceiptService(),
 
-			total: "0.00",
+			total: entity.MustParseMoney("0.00"),
 
 			want: 0,
 		},
@@ -212,7 +546,7 @@ func TestGetPointsForTotalMultiple(t *testing.T) {
 			name:    "should return zero points for total with cents",
 			service: NewReceiptService(),
 
-			total: "0.01",
+			total: entity.MustParseMoney("0.01"),
 
 			want: 0,
 		},
@@ -220,30 +554,18 @@ func TestGetPointsForTotalMultiple(t *testing.T) {
 			name:    "should return 25 points for total if is multiple of 0.25",
 			service: NewReceiptService(),
 
-			total: "1.00",
+			total: entity.MustParseMoney("1.00"),
 
 			want: pointsForTotalMultiple,
 		},
-		{
-			name:    "should fail due invalid total",
-			service: NewReceiptService(),
-
-			total: "invalid total",
-
-			wantErr: true,
-		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			got, err := tc.service.getPointsForTotalMultiple(tc.total)
+			got := tc.service.getPointsForTotalMultiple(DefaultRules().TotalMultiple, tc.total)
 
-			if got != tc.want {
-				t.Errorf("getPointsForTotalMultiple() = %v, want %v", got, tc.want)
-			}
-
-			if (err != nil) != tc.wantErr {
-				t.Errorf("getPointsForTotalMultiple() = %v, want %v", err, tc.wantErr)
+			if got.Points != tc.want {
+				t.Errorf("getPointsForTotalMultiple() = %v, want %v", got.Points, tc.want)
 			}
 		})
 	}
@@ -273,11 +595,11 @@ func TestGetPointsForItemsCount(t *testing.T) {
 			items: []entity.Item{
 				{
 					ShortDescription: "Item 1",
-					Price:            "10.00",
+					Price:            entity.MustParseMoney("10.00"),
 				},
 				{
 					ShortDescription: "Item 2",
-					Price:            "10.00",
+					Price:            entity.MustParseMoney("10.00"),
 				},
 			},
 
@@ -290,15 +612,15 @@ func TestGetPointsForItemsCount(t *testing.T) {
 			items: []entity.Item{
 				{
 					ShortDescription: "Item 1",
-					Price:            "10.00",
+					Price:            entity.MustParseMoney("10.00"),
 				},
 				{
 					ShortDescription: "Item 2",
-					Price:            "10.00",
+					Price:            entity.MustParseMoney("10.00"),
 				},
 				{
 					ShortDescription: "Item 3",
-					Price:            "10.00",
+					Price:            entity.MustParseMoney("10.00"),
 				},
 			},
 
@@ -311,40 +633,141 @@ func TestGetPointsForItemsCount(t *testing.T) {
 			items: []entity.Item{
 				{
 					ShortDescription: "Item 1",
-					Price:            "10.00",
+					Price:            entity.MustParseMoney("10.00"),
 				},
 				{
 					ShortDescription: "Item 2",
-					Price:            "10.00",
+					Price:            entity.MustParseMoney("10.00"),
 				},
 				{
 					ShortDescription: "Item 3",
-					Price:            "10.00",
+					Price:            entity.MustParseMoney("10.00"),
 				},
 				{
 					ShortDescription: "Item 4",
-					Price:            "10.00",
+					Price:            entity.MustParseMoney("10.00"),
 				},
 				{
 					ShortDescription: "Item 5",
-					Price:            "10.00",
+					Price:            entity.MustParseMoney("10.00"),
 				},
 				{
 					ShortDescription: "Item 6",
-					Price:            "10.00",
+					Price:            entity.MustParseMoney("10.00"),
 				},
 			},
 
 			want: pointsForItemPairs * 3,
 		},
+		{
+			name:    "should count the units of items with a quantity",
+			service: NewReceiptService(),
+
+			items: []entity.Item{
+				{
+					ShortDescription: "Klarbrunn 12-PK 12 FL OZ",
+					Price:            entity.MustParseMoney("36.00"),
+					Quantity:         3,
+					UnitPrice:        entity.MustParseMoney("12.00"),
+				},
+				{
+					ShortDescription: "Item 2",
+					Price:            entity.MustParseMoney("10.00"),
+				},
+			},
+
+			want: pointsForItemPairs * 2,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := tc.service.getPointsForItemsCount(tc.items)
+			got := tc.service.getPointsForItemsCount(DefaultRules().ItemPairs, tc.items)
 
-			if got != tc.want {
-				t.Errorf("getPointsForItemsCount() = %v, want %v", got, tc.want)
+			if got.Points != tc.want {
+				t.Errorf("getPointsForItemsCount() = %v, want %v", got.Points, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetPointsForItemsDescriptions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		service *receiptService
+
+		items []entity.Item
+
+		want int64
+	}{
+		{
+			name:    "should return zero points for descriptions not multiple of 3",
+			service: NewReceiptService(),
+
+			items: []entity.Item{
+				{
+					ShortDescription: "Mountain Dew 12PK",
+					Price:            entity.MustParseMoney("6.49"),
+				},
+			},
+
+			want: 0,
+		},
+		{
+			name:    "should return price points rounded up for trimmed descriptions multiple of 3",
+			service: NewReceiptService(),
+
+			items: []entity.Item{
+				{
+					ShortDescription: "Emils Cheese Pizza",
+					Price:            entity.MustParseMoney("12.25"),
+				},
+				{
+					ShortDescription: "   Klarbrunn 12-PK 12 FL OZ  ",
+					Price:            entity.MustParseMoney("12.00"),
+				},
+			},
+
+			want: 6,
+		},
+		{
+			name:    "should not round up exact price points",
+			service: NewReceiptService(),
+
+			items: []entity.Item{
+				{
+					ShortDescription: "Gatorade",
+					Price:            entity.MustParseMoney("2.25"),
+				},
+				{
+					ShortDescription: "Pizza!",
+					Price:            entity.MustParseMoney("5.00"),
+				},
+			},
+
+			want: 1,
+		},
+		{
+			name:    "should return points for very large prices",
+			service: NewReceiptService(),
+
+			items: []entity.Item{
+				{
+					ShortDescription: "Car",
+					Price:            entity.MustParseMoney("90000000000000000.00"),
+				},
+			},
+
+			want: 18000000000000000,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.service.getPointsForItemsDescriptions(DefaultRules().ItemDescriptions, tc.items)
+
+			if got.Points != tc.want {
+				t.Errorf("getPointsForItemsDescriptions() = %v, want %v", got.Points, tc.want)
 			}
 		})
 	}
@@ -388,10 +811,10 @@ func TestGetPointsForPurchaseDate(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			got, err := tc.service.getPointsForPurchaseDate(tc.purchaseDate)
+			got, err := tc.service.getPointsForPurchaseDate(DefaultRules().PurchaseDate, tc.purchaseDate)
 
-			if got != tc.want {
-				t.Errorf("getPointsForPurchaseDate() = %v, want %v", got, tc.want)
+			if got.Points != tc.want {
+				t.Errorf("getPointsForPurchaseDate() = %v, want %v", got.Points, tc.want)
 			}
 
 			if (err != nil) != tc.wantErr {
@@ -463,10 +886,10 @@ func TestGetPointsForPurchaseHour(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			got, err := tc.service.getPointsForPurchaseHour(tc.purchaseHour)
+			got, err := tc.service.getPointsForPurchaseHour(DefaultRules().PurchaseTime, tc.purchaseHour)
 
-			if got != tc.want {
-				t.Errorf("getPointsForPurchaseHour() = %v, want %v", got, tc.want)
+			if got.Points != tc.want {
+				t.Errorf("getPointsForPurchaseHour() = %v, want %v", got.Points, tc.want)
 			}
 
 			if (err != nil) != tc.wantErr {
@@ -475,3 +898,180 @@ func TestGetPointsForPurchaseHour(t *testing.T) {
 		})
 	}
 }
+
+func TestGetReceiptPointsWithRuleSets(t *testing.T) {
+	tunedRules := DefaultRules()
+	tunedRules.RetailerName.PointsPerCharacter = 2
+	tunedRules.TotalRounded.Points = 0
+	tunedRules.PurchaseTime = entity.PurchaseTimeRule{Points: 100, StartHour: 12, EndHour: 12}
+
+	ruleSets := entity.RuleSetsConfig{
+		ActiveVersion: "2",
+		RuleSets: []entity.RuleSet{
+			{Name: "default", Version: "1", Rules: DefaultRules()},
+			{Name: "tuned", Version: "2", Rules: tunedRules},
+		},
+	}
+
+	receipt := entity.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2020-01-02",
+		PurchaseTime: "12:00",
+		Total:        entity.MustParseMoney("100.00"),
+	}
+
+	testCases := []struct {
+		name    string
+		ctx     context.Context
+		service *receiptService
+
+		ruleSetVersion string
+
+		want    entity.PointsBreakdown
+		wantErr error
+	}{
+		{
+			name:    "should calculate points with the active rule set",
+			ctx:     context.Background(),
+			service: NewReceiptService(WithRuleSets(ruleSets)),
+
+			ruleSetVersion: ruleSets.ActiveVersion,
+
+			// 12 points for the retailer name, 25 for the total multiple and 100 for the purchase time.
+			want: entity.PointsBreakdown{Points: 137, RuleSetVersion: "2"},
+		},
+		{
+			name:    "should calculate points with a previous rule set",
+			ctx:     context.Background(),
+			service: NewReceiptService(WithRuleSets(ruleSets)),
+
+			ruleSetVersion: "1",
+
+			want: entity.PointsBreakdown{Points: 81, RuleSetVersion: "1"},
+		},
+		{
+			name:    "should fail due unknown rule set version",
+			ctx:     context.Background(),
+			service: NewReceiptService(WithRuleSets(ruleSets)),
+
+			ruleSetVersion: "3",
+
+			wantErr: entity.ErrRuleSetNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.service.GetReceiptPointsBreakdownWithRuleSet(tc.ctx, receipt, tc.ruleSetVersion)
+
+			if got.Points != tc.want.Points || got.RuleSetVersion != tc.want.RuleSetVersion {
+				t.Errorf("GetReceiptPointsBreakdownWithRuleSet() = %v, want %v", got, tc.want)
+			}
+
+			if err != tc.wantErr {
+				t.Errorf("GetReceiptPointsBreakdownWithRuleSet() = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		rules func() entity.Rules
+
+		wantErr bool
+	}{
+		{
+			name: "should accept default rules",
+
+			rules: DefaultRules,
+		},
+		{
+			name: "should fail due negative points",
+
+			rules: func() entity.Rules {
+				rules := DefaultRules()
+				rules.TotalRounded.Points = -1
+				return rules
+			},
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due zero total multiple",
+
+			rules: func() entity.Rules {
+				rules := DefaultRules()
+				rules.TotalMultiple.Multiple = 0
+				return rules
+			},
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due start hour after end hour",
+
+			rules: func() entity.Rules {
+				rules := DefaultRules()
+				rules.PurchaseTime.StartHour = 17
+				return rules
+			},
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due product bonus without brand or category",
+
+			rules: func() entity.Rules {
+				rules := DefaultRules()
+				rules.ProductBonus.Bonuses = []entity.ProductBonus{{PointsPerItem: 10}}
+				return rules
+			},
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due unknown retailer characters",
+
+			rules: func() entity.Rules {
+				rules := DefaultRules()
+				rules.RetailerName.Characters = "emoji"
+				return rules
+			},
+
+			wantErr: true,
+		},
+		{
+			name: "should accept empty retailer characters",
+
+			rules: func() entity.Rules {
+				rules := DefaultRules()
+				rules.RetailerName.Characters = ""
+				return rules
+			},
+		},
+		{
+			name: "should fail due hour out of range",
+
+			rules: func() entity.Rules {
+				rules := DefaultRules()
+				rules.PurchaseTime.EndHour = 24
+				return rules
+			},
+
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateRules(tc.rules())
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ValidateRules() = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package receipt
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+// Formats of the receipt fields defined by the API spec. The retailer names
+// may also have letters and digits of any script, apostrophes and periods, as
+// "Café Ñandú" or "Trader Joe's", which the spec's ASCII-only \w rejects.
+var (
+	retailerPattern         = regexp.MustCompile(`^[\p{L}\p{M}\p{N}_\s\-&'.]+$`)
+	shortDescriptionPattern = regexp.MustCompile(`^[\w\s\-]+$`)
+	purchaseDatePattern     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	purchaseTimePattern     = regexp.MustCompile(`^\d{2}:\d{2}$`)
+)
+
+const (
+	purchaseDateLayout = "2006-01-02"
+	purchaseTimeLayout = "15:04"
+)
+
+// maxItemQuantity is the maximum quantity of an item, so the units of a
+// receipt can be counted without overflowing.
+const maxItemQuantity = 100_000
+
+// ValidateReceipt checks the receipt fields against the formats of the API
+// spec, that the purchase date and time exist, that there is at least one item,
+// that the price of each item matches its quantity, unit price and discount
+// when given, and that the total matches the sum of the item prices. If the receipt is
+// invalid it returns an *entity.ValidationError listing every invalid field.
+func (rs *receiptService) ValidateReceipt(ctx context.Context, receipt entity.Receipt) error {
+	var fieldErrors []entity.FieldError
+
+	addError := func(field, code, message string) {
+		fieldErrors = append(fieldErrors, entity.FieldError{
+			Field:   field,
+			Code:    code,
+			Message: message,
+		})
+	}
+
+	switch {
+	case receipt.Retailer == "":
+		addError("retailer", entity.FieldErrorRequired, "retailer is required")
+	case !retailerPattern.MatchString(receipt.Retailer):
+		addError("retailer", entity.FieldErrorInvalidFormat,
+			"retailer may only contain letters, digits, spaces, hyphens, ampersands, apostrophes and periods")
+	}
+
+	switch {
+	case receipt.PurchaseDate == "":
+		addError("purchaseDate", entity.FieldErrorRequired, "purchaseDate is required")
+	case !purchaseDatePattern.MatchString(receipt.PurchaseDate):
+		addError("purchaseDate", entity.FieldErrorInvalidFormat, "purchaseDate must have the format YYYY-MM-DD")
+	default:
+		if _, err := time.Parse(purchaseDateLayout, receipt.PurchaseDate); err != nil {
+			addError("purchaseDate", entity.FieldErrorInvalidDate,
+				fmt.Sprintf("purchaseDate %s is not a valid date", receipt.PurchaseDate))
+		}
+	}
+
+	switch {
+	case receipt.PurchaseTime == "":
+		addError("purchaseTime", entity.FieldErrorRequired, "purchaseTime is required")
+	case !purchaseTimePattern.MatchString(receipt.PurchaseTime):
+		addError("purchaseTime", entity.FieldErrorInvalidFormat, "purchaseTime must have the 24-hour format HH:MM")
+	default:
+		if _, err := time.Parse(purchaseTimeLayout, receipt.PurchaseTime); err != nil {
+			addError("purchaseTime", entity.FieldErrorInvalidTime,
+				fmt.Sprintf("purchaseTime %s is not a valid time", receipt.PurchaseTime))
+		}
+	}
+
+	if len(receipt.Items) == 0 {
+		addError("items", entity.FieldErrorMinItems, "the receipt must have at least one item")
+	}
+
+	var itemsTotal entity.Money
+	for i, item := range receipt.Items {
+		field := fmt.Sprintf("items[%d].shortDescription", i)
+
+		switch {
+		case item.ShortDescription == "":
+			addError(field, entity.FieldErrorRequired, "shortDescription is required")
+		case !shortDescriptionPattern.MatchString(item.ShortDescription):
+			addError(field, entity.FieldErrorInvalidFormat,
+				"shortDescription may only contain letters, digits, spaces and hyphens")
+		}
+
+		validateItemLine(i, item, addError)
+
+		itemsTotal += item.Price
+	}
+
+	if len(receipt.Items) > 0 && itemsTotal != receipt.Total {
+		addError("total", entity.FieldErrorTotalMismatch,
+			fmt.Sprintf("total %s does not match the sum of the item prices %s", receipt.Total, itemsTotal))
+	}
+
+	if len(fieldErrors) > 0 {
+		return &entity.ValidationError{Errors: fieldErrors}
+	}
+
+	return nil
+}
+
+// validateItemLine checks the quantity of an item and that its price is the
+// quantity times the unit price, minus the discount. The discount can't be
+// checked without a unit price, so it requires one.
+func validateItemLine(i int, item entity.Item, addError func(field, code, message string)) {
+	if item.Quantity < 0 || item.Quantity > maxItemQuantity {
+		addError(fmt.Sprintf("items[%d].quantity", i), entity.FieldErrorInvalidValue,
+			fmt.Sprintf("quantity must be between 1 and %d", maxItemQuantity))
+		return
+	}
+
+	if item.UnitPrice == 0 {
+		if item.Discount > 0 {
+			addError(fmt.Sprintf("items[%d].unitPrice", i), entity.FieldErrorRequired, "unitPrice is required with a discount")
+		}
+		return
+	}
+
+	if linePrice, ok := item.LinePrice(); !ok || linePrice != item.Price {
+		addError(fmt.Sprintf("items[%d].price", i), entity.FieldErrorLineTotalMismatch,
+			fmt.Sprintf("price %s does not match %d units of %s minus a discount of %s",
+				item.Price, item.Units(), item.UnitPrice, item.Discount))
+	}
+}
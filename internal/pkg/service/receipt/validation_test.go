@@ -0,0 +1,199 @@
+package receipt
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+)
+
+func TestValidateReceipt(t *testing.T) {
+	validReceipt := func() entity.Receipt {
+		return entity.Receipt{
+			Retailer:     "M&M Corner Market",
+			PurchaseDate: "2022-03-20",
+			PurchaseTime: "14:33",
+			Items: []entity.Item{
+				{
+					ShortDescription: "Gatorade",
+					Price:            entity.MustParseMoney("2.25"),
+				},
+				{
+					ShortDescription: "Gatorade",
+					Price:            entity.MustParseMoney("2.25"),
+				},
+			},
+			Total: entity.MustParseMoney("4.50"),
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		ctx     context.Context
+		service *receiptService
+
+		receipt func() entity.Receipt
+
+		wantFields []string
+		wantCodes  []string
+	}{
+		{
+			name:    "should accept a valid receipt",
+			ctx:     context.Background(),
+			service: NewReceiptService(),
+
+			receipt: validReceipt,
+		},
+		{
+			name:    "should fail due missing fields",
+			ctx:     context.Background(),
+			service: NewReceiptService(),
+
+			receipt: func() entity.Receipt {
+				return entity.Receipt{}
+			},
+
+			wantFields: []string{"retailer", "purchaseDate", "purchaseTime", "items"},
+			wantCodes: []string{
+				entity.FieldErrorRequired,
+				entity.FieldErrorRequired,
+				entity.FieldErrorRequired,
+				entity.FieldErrorMinItems,
+			},
+		},
+		{
+			name:    "should fail due invalid formats",
+			ctx:     context.Background(),
+			service: NewReceiptService(),
+
+			receipt: func() entity.Receipt {
+				receipt := validReceipt()
+				receipt.Retailer = "$Walmart/"
+				receipt.PurchaseDate = "03/20/2022"
+				receipt.PurchaseTime = "2:33 PM"
+				receipt.Items[1].ShortDescription = "Gatorade 1.5L"
+				return receipt
+			},
+
+			wantFields: []string{"retailer", "purchaseDate", "purchaseTime", "items[1].shortDescription"},
+			wantCodes: []string{
+				entity.FieldErrorInvalidFormat,
+				entity.FieldErrorInvalidFormat,
+				entity.FieldErrorInvalidFormat,
+				entity.FieldErrorInvalidFormat,
+			},
+		},
+		{
+			name:    "should fail due date and time that don't exist",
+			ctx:     context.Background(),
+			service: NewReceiptService(),
+
+			receipt: func() entity.Receipt {
+				receipt := validReceipt()
+				receipt.PurchaseDate = "2022-02-30"
+				receipt.PurchaseTime = "25:99"
+				return receipt
+			},
+
+			wantFields: []string{"purchaseDate", "purchaseTime"},
+			wantCodes:  []string{entity.FieldErrorInvalidDate, entity.FieldErrorInvalidTime},
+		},
+		{
+			name:    "should fail due total that doesn't match the items",
+			ctx:     context.Background(),
+			service: NewReceiptService(),
+
+			receipt: func() entity.Receipt {
+				receipt := validReceipt()
+				receipt.Total = entity.MustParseMoney("4.51")
+				return receipt
+			},
+
+			wantFields: []string{"total"},
+			wantCodes:  []string{entity.FieldErrorTotalMismatch},
+		},
+		{
+			name:    "should accept items with quantities, unit prices and discounts",
+			ctx:     context.Background(),
+			service: NewReceiptService(),
+
+			receipt: func() entity.Receipt {
+				receipt := validReceipt()
+				receipt.Items[0] = entity.Item{
+					ShortDescription: "Klarbrunn 12-PK 12 FL OZ",
+					Price:            entity.MustParseMoney("35.00"),
+					Quantity:         3,
+					UnitPrice:        entity.MustParseMoney("12.00"),
+					Discount:         entity.MustParseMoney("1.00"),
+				}
+				receipt.Items[1].Quantity = 2
+				receipt.Total = entity.MustParseMoney("37.25")
+				return receipt
+			},
+		},
+		{
+			name:    "should fail due line prices that don't reconcile",
+			ctx:     context.Background(),
+			service: NewReceiptService(),
+
+			receipt: func() entity.Receipt {
+				receipt := validReceipt()
+				receipt.Items[0].Quantity = 2
+				receipt.Items[0].UnitPrice = entity.MustParseMoney("2.25")
+				receipt.Items[1].Quantity = -1
+				return receipt
+			},
+
+			wantFields: []string{"items[0].price", "items[1].quantity"},
+			wantCodes:  []string{entity.FieldErrorLineTotalMismatch, entity.FieldErrorInvalidValue},
+		},
+		{
+			name:    "should fail due discount without unit price",
+			ctx:     context.Background(),
+			service: NewReceiptService(),
+
+			receipt: func() entity.Receipt {
+				receipt := validReceipt()
+				receipt.Items[0].Discount = entity.MustParseMoney("0.25")
+				return receipt
+			},
+
+			wantFields: []string{"items[0].unitPrice"},
+			wantCodes:  []string{entity.FieldErrorRequired},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.service.ValidateReceipt(tc.ctx, tc.receipt())
+
+			if tc.wantCodes == nil {
+				if err != nil {
+					t.Errorf("ValidateReceipt() = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *entity.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("ValidateReceipt() = %v, want *entity.ValidationError", err)
+			}
+
+			var gotFields, gotCodes []string
+			for _, fieldError := range validationErr.Errors {
+				gotFields = append(gotFields, fieldError.Field)
+				gotCodes = append(gotCodes, fieldError.Code)
+			}
+
+			if !reflect.DeepEqual(gotFields, tc.wantFields) {
+				t.Errorf("ValidateReceipt() fields = %v, want %v", gotFields, tc.wantFields)
+			}
+
+			if !reflect.DeepEqual(gotCodes, tc.wantCodes) {
+				t.Errorf("ValidateReceipt() codes = %v, want %v", gotCodes, tc.wantCodes)
+			}
+		})
+	}
+}
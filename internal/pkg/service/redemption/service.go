@@ -0,0 +1,224 @@
+package redemption
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/port"
+	"github.com/google/uuid"
+)
+
+// DefaultReservationTTL is how long the reservations hold the points of their
+// receipts unless another duration is set with WithReservationTTL.
+const DefaultReservationTTL = 15 * time.Minute
+
+type redemptionService struct {
+	repository        port.RedemptionRepository
+	receiptRepository port.ReceiptRepository
+	fraudService      port.FraudService
+	accountService    port.AccountService
+	expiryService     port.ExpiryService
+	reservationTTL    time.Duration
+	now               func() time.Time
+
+	// resolutions serializes the confirmations, cancellations and expirations,
+	// so a reservation can't expire or be cancelled while its account is
+	// debited.
+	resolutions sync.Mutex
+}
+
+// Option configures the redemption service.
+type Option func(*redemptionService)
+
+// WithReservationTTL sets how long the reservations hold the points of their
+// receipts.
+func WithReservationTTL(ttl time.Duration) Option {
+	return func(rs *redemptionService) {
+		rs.reservationTTL = ttl
+	}
+}
+
+// WithFraudScreening makes the receipts that aren't awarded points due to
+// their fraud screening have no points to redeem.
+func WithFraudScreening(fraudService port.FraudService) Option {
+	return func(rs *redemptionService) {
+		rs.fraudService = fraudService
+	}
+}
+
+// WithAccounts debits the confirmed redemptions from the loyalty account of
+// their receipts.
+func WithAccounts(accountService port.AccountService) Option {
+	return func(rs *redemptionService) {
+		rs.accountService = accountService
+	}
+}
+
+// WithPointsExpiry makes the receipts whose points expired have no points to
+// redeem.
+func WithPointsExpiry(expiryService port.ExpiryService) Option {
+	return func(rs *redemptionService) {
+		rs.expiryService = expiryService
+	}
+}
+
+// NewRedemptionService creates a new redemption service.
+func NewRedemptionService(repository port.RedemptionRepository, receiptRepository port.ReceiptRepository, options ...Option) *redemptionService {
+	rs := &redemptionService{
+		repository:        repository,
+		receiptRepository: receiptRepository,
+		reservationTTL:    DefaultReservationTTL,
+		now:               time.Now,
+	}
+
+	for _, option := range options {
+		option(rs)
+	}
+
+	return rs
+}
+
+// Reserve holds points of the receipts for a reward, taken from the receipts
+// in the given order. Zero points reserve all the available ones. The
+// receipts must be scored and belong to the same loyalty account, if any.
+func (rs *redemptionService) Reserve(ctx context.Context, receiptIDs []string, points int64, reward string) (entity.Redemption, error) {
+	now := rs.now().UTC()
+
+	redemption := entity.Redemption{
+		ID:        uuid.New().String(),
+		Reward:    reward,
+		Points:    points,
+		Status:    entity.RedemptionStatusReserved,
+		CreatedAt: now,
+		ExpiresAt: now.Add(rs.reservationTTL),
+	}
+
+	seen := make(map[string]bool, len(receiptIDs))
+	receiptPoints := make([]entity.RedemptionAllocation, 0, len(receiptIDs))
+
+	for _, receiptID := range receiptIDs {
+		if seen[receiptID] {
+			continue
+		}
+		seen[receiptID] = true
+
+		record, err := rs.receiptRepository.GetReceiptByID(ctx, receiptID)
+		if err != nil {
+			return entity.Redemption{}, fmt.Errorf("receipt %s: %w", receiptID, err)
+		}
+
+		if len(receiptPoints) == 0 {
+			redemption.AccountID = record.AccountID
+		} else if record.AccountID != redemption.AccountID {
+			return entity.Redemption{}, entity.ErrMixedAccounts
+		}
+
+		awarded, err := rs.awardedPoints(ctx, record, now)
+		if err != nil {
+			return entity.Redemption{}, fmt.Errorf("receipt %s: %w", receiptID, err)
+		}
+
+		receiptPoints = append(receiptPoints, entity.RedemptionAllocation{ReceiptID: receiptID, Points: awarded})
+	}
+
+	return rs.repository.ReserveReceiptPoints(ctx, redemption, receiptPoints)
+}
+
+// awardedPoints gets the points awarded to a receipt still valid at the given
+// time, which are none if its fraud screening doesn't award them.
+func (rs *redemptionService) awardedPoints(ctx context.Context, record entity.ReceiptRecord, now time.Time) (int64, error) {
+	points, err := rs.receiptRepository.GetReceiptPoints(ctx, record.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	if !points.Scored() {
+		return 0, entity.ErrReceiptNotScored
+	}
+
+	if rs.expiryService != nil {
+		validity, err := rs.expiryService.GetPointsValidity(ctx, record.Receipt, points, now)
+		if err != nil {
+			return 0, err
+		}
+		points.Points = validity.ValidPoints
+	}
+
+	if rs.fraudService != nil {
+		screening, ok, err := rs.fraudService.GetScreening(ctx, record.ID)
+		if err != nil {
+			return 0, err
+		}
+		if ok && !screening.Awarded() {
+			return 0, nil
+		}
+	}
+
+	return points.Points, nil
+}
+
+// Confirm spends the points held by a reservation, debiting them from the
+// account of its receipts. It returns entity.ErrRedemptionResolved if the
+// reservation was already confirmed or cancelled, or it expired. The
+// reservation is confirmed before debiting the account, and reserved again if
+// the debit fails, so the account is never debited for a redemption that
+// isn't confirmed.
+func (rs *redemptionService) Confirm(ctx context.Context, redemptionID string) (entity.Redemption, error) {
+	rs.resolutions.Lock()
+	defer rs.resolutions.Unlock()
+
+	// Resolving it marks it as expired if it is.
+	redemption, err := rs.repository.ResolveRedemption(ctx, redemptionID, entity.RedemptionStatusConfirmed, rs.now().UTC())
+	if err != nil {
+		return entity.Redemption{}, err
+	}
+
+	if rs.accountService == nil || redemption.AccountID == "" {
+		return redemption, nil
+	}
+
+	description := fmt.Sprintf("%s (redemption %s)", redemption.Reward, redemption.ID)
+	if _, err := rs.accountService.Redeem(ctx, redemption.AccountID, redemption.Points, description); err != nil {
+		if reopenErr := rs.repository.ReopenRedemption(ctx, redemptionID); reopenErr != nil {
+			return entity.Redemption{}, errors.Join(err, fmt.Errorf("reserving redemption %s again: %w", redemptionID, reopenErr))
+		}
+
+		return entity.Redemption{}, err
+	}
+
+	return redemption, nil
+}
+
+// Cancel releases the points held by a reservation. It returns
+// entity.ErrRedemptionResolved if the reservation was already confirmed or
+// cancelled, or it expired.
+func (rs *redemptionService) Cancel(ctx context.Context, redemptionID string) (entity.Redemption, error) {
+	rs.resolutions.Lock()
+	defer rs.resolutions.Unlock()
+
+	return rs.repository.ResolveRedemption(ctx, redemptionID, entity.RedemptionStatusCancelled, rs.now().UTC())
+}
+
+// GetRedemption gets a redemption by its ID.
+func (rs *redemptionService) GetRedemption(ctx context.Context, redemptionID string) (entity.Redemption, error) {
+	return rs.repository.GetRedemption(ctx, redemptionID)
+}
+
+// GetHeldPoints sums the points of a receipt held by the confirmed redemptions
+// and the reservations that didn't expire.
+func (rs *redemptionService) GetHeldPoints(ctx context.Context, receiptID string) (int64, error) {
+	return rs.repository.GetHeldPoints(ctx, receiptID, rs.now().UTC())
+}
+
+// ExpireReservations marks the reservations expired at the given time. Their
+// points are already available again once they expire, so this only records it.
+func (rs *redemptionService) ExpireReservations(ctx context.Context, now time.Time) (int64, error) {
+	rs.resolutions.Lock()
+	defer rs.resolutions.Unlock()
+
+	return rs.repository.ExpireRedemptions(ctx, now)
+}
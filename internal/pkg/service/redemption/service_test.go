@@ -0,0 +1,263 @@
+package redemption
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	"github.com/darcops/receipt-proccessor-challenge/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReserve(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	scored := entity.ReceiptPoints{Status: entity.ScoreStatusScored, Points: 28}
+
+	testCases := []struct {
+		name string
+
+		accountIDs []string
+		points     []entity.ReceiptPoints
+		screenings []entity.FraudScreening
+		expired    []bool
+
+		wantReceiptPoints []entity.RedemptionAllocation
+		wantErr           error
+	}{
+		{
+			name: "should reserve the points of the receipts",
+
+			accountIDs: []string{"1", "1"},
+			points:     []entity.ReceiptPoints{scored, scored},
+			screenings: []entity.FraudScreening{{Status: entity.ScreeningStatusClear}, {Status: entity.ScreeningStatusClear}},
+
+			wantReceiptPoints: []entity.RedemptionAllocation{{ReceiptID: "a", Points: 28}, {ReceiptID: "b", Points: 28}},
+		},
+		{
+			name: "should count no points for a rejected receipt",
+
+			accountIDs: []string{"", ""},
+			points:     []entity.ReceiptPoints{scored, scored},
+			screenings: []entity.FraudScreening{{Status: entity.ScreeningStatusClear}, {Status: entity.ScreeningStatusRejected}},
+
+			wantReceiptPoints: []entity.RedemptionAllocation{{ReceiptID: "a", Points: 28}, {ReceiptID: "b", Points: 0}},
+		},
+		{
+			name: "should count no points for a receipt whose points expired",
+
+			accountIDs: []string{"1", "1"},
+			points:     []entity.ReceiptPoints{scored, scored},
+			screenings: []entity.FraudScreening{{Status: entity.ScreeningStatusClear}, {Status: entity.ScreeningStatusClear}},
+			expired:    []bool{true, false},
+
+			wantReceiptPoints: []entity.RedemptionAllocation{{ReceiptID: "a", Points: 0}, {ReceiptID: "b", Points: 28}},
+		},
+		{
+			name: "should fail due receipts of different accounts",
+
+			accountIDs: []string{"1", "2"},
+			points:     []entity.ReceiptPoints{scored, scored},
+			screenings: []entity.FraudScreening{{Status: entity.ScreeningStatusClear}, {Status: entity.ScreeningStatusClear}},
+
+			wantErr: entity.ErrMixedAccounts,
+		},
+		{
+			name: "should fail due receipt not scored",
+
+			accountIDs: []string{"1", "1"},
+			points:     []entity.ReceiptPoints{scored, {Status: entity.ScoreStatusPending}},
+			screenings: []entity.FraudScreening{{Status: entity.ScreeningStatusClear}, {Status: entity.ScreeningStatusClear}},
+
+			wantErr: entity.ErrReceiptNotScored,
+		},
+	}
+
+	for _, tc := range testCases {
+		repository := &mocks.RedemptionRepository{}
+		receiptRepository := &mocks.ReceiptRepository{}
+		fraudService := &mocks.FraudService{}
+		expiryService := &mocks.ExpiryService{}
+
+		service := NewRedemptionService(
+			repository, receiptRepository, WithFraudScreening(fraudService), WithPointsExpiry(expiryService),
+		)
+		service.now = func() time.Time { return now }
+
+		for i, receiptID := range []string{"a", "b"} {
+			receiptRepository.On(
+				"GetReceiptByID",
+				mock.Anything, /* context.Context */
+				receiptID,
+			).Return(entity.ReceiptRecord{ID: receiptID, Receipt: entity.Receipt{Retailer: receiptID}, AccountID: tc.accountIDs[i]}, nil).Maybe()
+
+			receiptRepository.On(
+				"GetReceiptPoints",
+				mock.Anything, /* context.Context */
+				receiptID,
+			).Return(tc.points[i], nil).Maybe()
+
+			fraudService.On(
+				"GetScreening",
+				mock.Anything, /* context.Context */
+				receiptID,
+			).Return(tc.screenings[i], true, nil).Maybe()
+
+			validity := entity.PointsValidity{ValidPoints: tc.points[i].Points}
+			if tc.expired != nil && tc.expired[i] {
+				validity.ValidPoints = 0
+			}
+
+			expiryService.On(
+				"GetPointsValidity",
+				mock.Anything, /* context.Context */
+				entity.Receipt{Retailer: receiptID},
+				tc.points[i],
+				now,
+			).Return(validity, nil).Maybe()
+		}
+
+		if tc.wantErr == nil {
+			repository.On(
+				"ReserveReceiptPoints",
+				mock.Anything, /* context.Context */
+				mock.MatchedBy(func(redemption entity.Redemption) bool {
+					return redemption.Status == entity.RedemptionStatusReserved &&
+						redemption.Points == 30 &&
+						redemption.AccountID == tc.accountIDs[0] &&
+						redemption.CreatedAt.Equal(now) &&
+						redemption.ExpiresAt.Equal(now.Add(DefaultReservationTTL))
+				}),
+				tc.wantReceiptPoints,
+			).Return(entity.Redemption{ID: "1"}, nil).Once()
+		}
+
+		t.Run(tc.name, func(t *testing.T) {
+			// The same receipt given twice only counts once.
+			_, err := service.Reserve(context.Background(), []string{"a", "b", "a"}, 30, "coffee")
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Reserve() error = %v, want %v", err, tc.wantErr)
+			}
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	confirmed := entity.Redemption{
+		ID:         "1",
+		Reward:     "coffee",
+		AccountID:  "account",
+		Points:     10,
+		Status:     entity.RedemptionStatusConfirmed,
+		CreatedAt:  now.Add(-time.Minute),
+		ExpiresAt:  now.Add(time.Minute),
+		ResolvedAt: &now,
+	}
+
+	testCases := []struct {
+		name string
+
+		resolveErr error
+		redeemErr  error
+		reopenErr  error
+
+		wantRedeem bool
+		wantReopen bool
+		wantErr    error
+	}{
+		{
+			name: "should confirm the reservation and debit the account",
+
+			wantRedeem: true,
+		},
+		{
+			name: "should reserve again due insufficient points in the account",
+
+			redeemErr: entity.ErrInsufficientPoints,
+
+			wantRedeem: true,
+			wantReopen: true,
+			wantErr:    entity.ErrInsufficientPoints,
+		},
+		{
+			name: "should fail due error reserving again after the debit fails",
+
+			redeemErr: entity.ErrInsufficientPoints,
+			reopenErr: errors.New("database is locked"),
+
+			wantRedeem: true,
+			wantReopen: true,
+			wantErr:    entity.ErrInsufficientPoints,
+		},
+		{
+			name: "should fail without debiting the account due expired reservation",
+
+			resolveErr: entity.ErrRedemptionResolved,
+
+			wantErr: entity.ErrRedemptionResolved,
+		},
+	}
+
+	for _, tc := range testCases {
+		repository := &mocks.RedemptionRepository{}
+		accountService := &mocks.AccountService{}
+
+		service := NewRedemptionService(repository, &mocks.ReceiptRepository{}, WithAccounts(accountService))
+		service.now = func() time.Time { return now }
+
+		resolved := confirmed
+		if tc.resolveErr != nil {
+			resolved = entity.Redemption{}
+		}
+
+		repository.On(
+			"ResolveRedemption",
+			mock.Anything, /* context.Context */
+			"1",
+			entity.RedemptionStatusConfirmed,
+			now,
+		).Return(resolved, tc.resolveErr).Once()
+
+		if tc.wantRedeem {
+			accountService.On(
+				"Redeem",
+				mock.Anything, /* context.Context */
+				"account",
+				int64(10),
+				"coffee (redemption 1)",
+			).Return(entity.LedgerTransaction{}, tc.redeemErr).Once()
+		}
+
+		if tc.wantReopen {
+			repository.On(
+				"ReopenRedemption",
+				mock.Anything, /* context.Context */
+				"1",
+			).Return(tc.reopenErr).Once()
+		}
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := service.Confirm(context.Background(), "1")
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Confirm() error = %v, want %v", err, tc.wantErr)
+			}
+
+			if tc.reopenErr != nil && !errors.Is(err, tc.reopenErr) {
+				t.Errorf("Confirm() error = %v, want %v", err, tc.reopenErr)
+			}
+
+			if tc.wantErr == nil && !reflect.DeepEqual(got, confirmed) {
+				t.Errorf("Confirm() = %+v, want %+v", got, confirmed)
+			}
+
+			repository.AssertExpectations(t)
+			accountService.AssertExpectations(t)
+		})
+	}
+}
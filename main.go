@@ -1,7 +1,29 @@
 package main
 
-import "github.com/darcops/receipt-proccessor-challenge/internal/infra/api"
+import (
+	"errors"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/darcops/receipt-proccessor-challenge/internal/infra/api"
+	"github.com/darcops/receipt-proccessor-challenge/internal/infra/cli"
+	"github.com/darcops/receipt-proccessor-challenge/internal/infra/config"
+)
 
 func main() {
-	api.RunServer()
+	// The score subcommand scores receipt files without starting the server.
+	if len(os.Args) > 1 && os.Args[1] == "score" {
+		os.Exit(cli.RunScore(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
+	cfg, err := config.Load(os.Args[1:], os.Getenv, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Error loading the config: %v", err)
+	}
+
+	api.RunServer(cfg)
 }
@@ -0,0 +1,162 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// AccountRepository is an autogenerated mock type for the AccountRepository type
+type AccountRepository struct {
+	mock.Mock
+}
+
+// GetAccount provides a mock function with given fields: ctx, accountID
+func (_m *AccountRepository) GetAccount(ctx context.Context, accountID string) (entity.Account, error) {
+	ret := _m.Called(ctx, accountID)
+
+	var r0 entity.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.Account, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.Account); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		r0 = ret.Get(0).(entity.Account)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBalance provides a mock function with given fields: ctx, accountID
+func (_m *AccountRepository) GetBalance(ctx context.Context, accountID string) (int64, error) {
+	ret := _m.Called(ctx, accountID)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTransactions provides a mock function with given fields: ctx, accountID, cursor, limit
+func (_m *AccountRepository) ListTransactions(ctx context.Context, accountID string, cursor string, limit int) (entity.LedgerPage, error) {
+	ret := _m.Called(ctx, accountID, cursor, limit)
+
+	var r0 entity.LedgerPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) (entity.LedgerPage, error)); ok {
+		return rf(ctx, accountID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) entity.LedgerPage); ok {
+		r0 = rf(ctx, accountID, cursor, limit)
+	} else {
+		r0 = ret.Get(0).(entity.LedgerPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, accountID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PostRedemption provides a mock function with given fields: ctx, transaction
+func (_m *AccountRepository) PostRedemption(ctx context.Context, transaction entity.LedgerTransaction) error {
+	ret := _m.Called(ctx, transaction)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.LedgerTransaction) error); ok {
+		r0 = rf(ctx, transaction)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveAccount provides a mock function with given fields: ctx, account
+func (_m *AccountRepository) SaveAccount(ctx context.Context, account entity.Account) error {
+	ret := _m.Called(ctx, account)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Account) error); ok {
+		r0 = rf(ctx, account)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SettleReceiptPoints provides a mock function with given fields: ctx, transactionID, accountID, receiptID, points, at
+func (_m *AccountRepository) SettleReceiptPoints(ctx context.Context, transactionID string, accountID string, receiptID string, points int64, at time.Time) (entity.LedgerTransaction, bool, error) {
+	ret := _m.Called(ctx, transactionID, accountID, receiptID, points, at)
+
+	var r0 entity.LedgerTransaction
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int64, time.Time) (entity.LedgerTransaction, bool, error)); ok {
+		return rf(ctx, transactionID, accountID, receiptID, points, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int64, time.Time) entity.LedgerTransaction); ok {
+		r0 = rf(ctx, transactionID, accountID, receiptID, points, at)
+	} else {
+		r0 = ret.Get(0).(entity.LedgerTransaction)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, int64, time.Time) bool); ok {
+		r1 = rf(ctx, transactionID, accountID, receiptID, points, at)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, string, int64, time.Time) error); ok {
+		r2 = rf(ctx, transactionID, accountID, receiptID, points, at)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewAccountRepository creates a new instance of AccountRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAccountRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AccountRepository {
+	mock := &AccountRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
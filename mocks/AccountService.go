@@ -0,0 +1,163 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AccountService is an autogenerated mock type for the AccountService type
+type AccountService struct {
+	mock.Mock
+}
+
+// CreateAccount provides a mock function with given fields: ctx, name
+func (_m *AccountService) CreateAccount(ctx context.Context, name string) (entity.Account, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 entity.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.Account, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.Account); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Get(0).(entity.Account)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccount provides a mock function with given fields: ctx, accountID
+func (_m *AccountService) GetAccount(ctx context.Context, accountID string) (entity.Account, error) {
+	ret := _m.Called(ctx, accountID)
+
+	var r0 entity.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.Account, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.Account); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		r0 = ret.Get(0).(entity.Account)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBalance provides a mock function with given fields: ctx, accountID
+func (_m *AccountService) GetBalance(ctx context.Context, accountID string) (entity.AccountBalance, error) {
+	ret := _m.Called(ctx, accountID)
+
+	var r0 entity.AccountBalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.AccountBalance, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.AccountBalance); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		r0 = ret.Get(0).(entity.AccountBalance)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTransactions provides a mock function with given fields: ctx, accountID, cursor, limit
+func (_m *AccountService) ListTransactions(ctx context.Context, accountID string, cursor string, limit int) (entity.LedgerPage, error) {
+	ret := _m.Called(ctx, accountID, cursor, limit)
+
+	var r0 entity.LedgerPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) (entity.LedgerPage, error)); ok {
+		return rf(ctx, accountID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) entity.LedgerPage); ok {
+		r0 = rf(ctx, accountID, cursor, limit)
+	} else {
+		r0 = ret.Get(0).(entity.LedgerPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, accountID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Redeem provides a mock function with given fields: ctx, accountID, points, description
+func (_m *AccountService) Redeem(ctx context.Context, accountID string, points int64, description string) (entity.LedgerTransaction, error) {
+	ret := _m.Called(ctx, accountID, points, description)
+
+	var r0 entity.LedgerTransaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string) (entity.LedgerTransaction, error)); ok {
+		return rf(ctx, accountID, points, description)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string) entity.LedgerTransaction); ok {
+		r0 = rf(ctx, accountID, points, description)
+	} else {
+		r0 = ret.Get(0).(entity.LedgerTransaction)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, string) error); ok {
+		r1 = rf(ctx, accountID, points, description)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SettleReceiptPoints provides a mock function with given fields: ctx, accountID, receiptID, points
+func (_m *AccountService) SettleReceiptPoints(ctx context.Context, accountID string, receiptID string, points int64) error {
+	ret := _m.Called(ctx, accountID, receiptID, points)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) error); ok {
+		r0 = rf(ctx, accountID, receiptID, points)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAccountService creates a new instance of AccountService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAccountService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AccountService {
+	mock := &AccountService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
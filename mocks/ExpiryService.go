@@ -0,0 +1,103 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ExpiryService is an autogenerated mock type for the ExpiryService type
+type ExpiryService struct {
+	mock.Mock
+}
+
+// ExpireReceipts provides a mock function with given fields: ctx, now
+func (_m *ExpiryService) ExpireReceipts(ctx context.Context, now time.Time) (int64, error) {
+	ret := _m.Called(ctx, now)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPointsValidity provides a mock function with given fields: ctx, receipt, points, at
+func (_m *ExpiryService) GetPointsValidity(ctx context.Context, receipt entity.Receipt, points entity.ReceiptPoints, at time.Time) (entity.PointsValidity, error) {
+	ret := _m.Called(ctx, receipt, points, at)
+
+	var r0 entity.PointsValidity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Receipt, entity.ReceiptPoints, time.Time) (entity.PointsValidity, error)); ok {
+		return rf(ctx, receipt, points, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Receipt, entity.ReceiptPoints, time.Time) entity.PointsValidity); ok {
+		r0 = rf(ctx, receipt, points, at)
+	} else {
+		r0 = ret.Get(0).(entity.PointsValidity)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.Receipt, entity.ReceiptPoints, time.Time) error); ok {
+		r1 = rf(ctx, receipt, points, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListExpiringReceipts provides a mock function with given fields: ctx, now, within, cursor, limit
+func (_m *ExpiryService) ListExpiringReceipts(ctx context.Context, now time.Time, within time.Duration, cursor string, limit int) (entity.ReceiptPage, error) {
+	ret := _m.Called(ctx, now, within, cursor, limit)
+
+	var r0 entity.ReceiptPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Duration, string, int) (entity.ReceiptPage, error)); ok {
+		return rf(ctx, now, within, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Duration, string, int) entity.ReceiptPage); ok {
+		r0 = rf(ctx, now, within, cursor, limit)
+	} else {
+		r0 = ret.Get(0).(entity.ReceiptPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Duration, string, int) error); ok {
+		r1 = rf(ctx, now, within, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewExpiryService creates a new instance of ExpiryService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExpiryService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExpiryService {
+	mock := &ExpiryService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,109 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FraudRepository is an autogenerated mock type for the FraudRepository type
+type FraudRepository struct {
+	mock.Mock
+}
+
+// GetScreening provides a mock function with given fields: ctx, receiptID
+func (_m *FraudRepository) GetScreening(ctx context.Context, receiptID string) (entity.FraudScreening, bool, error) {
+	ret := _m.Called(ctx, receiptID)
+
+	var r0 entity.FraudScreening
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.FraudScreening, bool, error)); ok {
+		return rf(ctx, receiptID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.FraudScreening); ok {
+		r0 = rf(ctx, receiptID)
+	} else {
+		r0 = ret.Get(0).(entity.FraudScreening)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) bool); ok {
+		r1 = rf(ctx, receiptID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, receiptID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SaveFingerprint provides a mock function with given fields: ctx, fingerprint
+func (_m *FraudRepository) SaveFingerprint(ctx context.Context, fingerprint entity.ReceiptFingerprint) ([]string, []string, error) {
+	ret := _m.Called(ctx, fingerprint)
+
+	var r0 []string
+	var r1 []string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReceiptFingerprint) ([]string, []string, error)); ok {
+		return rf(ctx, fingerprint)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReceiptFingerprint) []string); ok {
+		r0 = rf(ctx, fingerprint)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ReceiptFingerprint) []string); ok {
+		r1 = rf(ctx, fingerprint)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.ReceiptFingerprint) error); ok {
+		r2 = rf(ctx, fingerprint)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SaveScreening provides a mock function with given fields: ctx, receiptID, screening
+func (_m *FraudRepository) SaveScreening(ctx context.Context, receiptID string, screening entity.FraudScreening) error {
+	ret := _m.Called(ctx, receiptID, screening)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.FraudScreening) error); ok {
+		r0 = rf(ctx, receiptID, screening)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewFraudRepository creates a new instance of FraudRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFraudRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FraudRepository {
+	mock := &FraudRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,108 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FraudService is an autogenerated mock type for the FraudService type
+type FraudService struct {
+	mock.Mock
+}
+
+// GetScreening provides a mock function with given fields: ctx, receiptID
+func (_m *FraudService) GetScreening(ctx context.Context, receiptID string) (entity.FraudScreening, bool, error) {
+	ret := _m.Called(ctx, receiptID)
+
+	var r0 entity.FraudScreening
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.FraudScreening, bool, error)); ok {
+		return rf(ctx, receiptID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.FraudScreening); ok {
+		r0 = rf(ctx, receiptID)
+	} else {
+		r0 = ret.Get(0).(entity.FraudScreening)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) bool); ok {
+		r1 = rf(ctx, receiptID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, receiptID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ReviewReceipt provides a mock function with given fields: ctx, receiptID, approved, reason
+func (_m *FraudService) ReviewReceipt(ctx context.Context, receiptID string, approved bool, reason string) (entity.FraudScreening, error) {
+	ret := _m.Called(ctx, receiptID, approved, reason)
+
+	var r0 entity.FraudScreening
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, string) (entity.FraudScreening, error)); ok {
+		return rf(ctx, receiptID, approved, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, string) entity.FraudScreening); ok {
+		r0 = rf(ctx, receiptID, approved, reason)
+	} else {
+		r0 = ret.Get(0).(entity.FraudScreening)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool, string) error); ok {
+		r1 = rf(ctx, receiptID, approved, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ScreenReceipt provides a mock function with given fields: ctx, record
+func (_m *FraudService) ScreenReceipt(ctx context.Context, record entity.ReceiptRecord) (entity.FraudScreening, error) {
+	ret := _m.Called(ctx, record)
+
+	var r0 entity.FraudScreening
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReceiptRecord) (entity.FraudScreening, error)); ok {
+		return rf(ctx, record)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReceiptRecord) entity.FraudScreening); ok {
+		r0 = rf(ctx, record)
+	} else {
+		r0 = ret.Get(0).(entity.FraudScreening)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ReceiptRecord) error); ok {
+		r1 = rf(ctx, record)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewFraudService creates a new instance of FraudService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFraudService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FraudService {
+	mock := &FraudService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,210 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MerchantRepository is an autogenerated mock type for the MerchantRepository type
+type MerchantRepository struct {
+	mock.Mock
+}
+
+// AddMerchantAlias provides a mock function with given fields: ctx, merchantID, alias
+func (_m *MerchantRepository) AddMerchantAlias(ctx context.Context, merchantID string, alias string) (entity.Merchant, error) {
+	ret := _m.Called(ctx, merchantID, alias)
+
+	var r0 entity.Merchant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (entity.Merchant, error)); ok {
+		return rf(ctx, merchantID, alias)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) entity.Merchant); ok {
+		r0 = rf(ctx, merchantID, alias)
+	} else {
+		r0 = ret.Get(0).(entity.Merchant)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, merchantID, alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteMerchant provides a mock function with given fields: ctx, merchantID
+func (_m *MerchantRepository) DeleteMerchant(ctx context.Context, merchantID string) error {
+	ret := _m.Called(ctx, merchantID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, merchantID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteMerchantAlias provides a mock function with given fields: ctx, merchantID, alias
+func (_m *MerchantRepository) DeleteMerchantAlias(ctx context.Context, merchantID string, alias string) (entity.Merchant, error) {
+	ret := _m.Called(ctx, merchantID, alias)
+
+	var r0 entity.Merchant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (entity.Merchant, error)); ok {
+		return rf(ctx, merchantID, alias)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) entity.Merchant); ok {
+		r0 = rf(ctx, merchantID, alias)
+	} else {
+		r0 = ret.Get(0).(entity.Merchant)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, merchantID, alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindMerchantByRetailer provides a mock function with given fields: ctx, retailerKey
+func (_m *MerchantRepository) FindMerchantByRetailer(ctx context.Context, retailerKey string) (string, bool, error) {
+	ret := _m.Called(ctx, retailerKey)
+
+	var r0 string
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, bool, error)); ok {
+		return rf(ctx, retailerKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, retailerKey)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) bool); ok {
+		r1 = rf(ctx, retailerKey)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, retailerKey)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetMerchant provides a mock function with given fields: ctx, merchantID
+func (_m *MerchantRepository) GetMerchant(ctx context.Context, merchantID string) (entity.Merchant, error) {
+	ret := _m.Called(ctx, merchantID)
+
+	var r0 entity.Merchant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.Merchant, error)); ok {
+		return rf(ctx, merchantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.Merchant); ok {
+		r0 = rf(ctx, merchantID)
+	} else {
+		r0 = ret.Get(0).(entity.Merchant)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, merchantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListMerchants provides a mock function with given fields: ctx
+func (_m *MerchantRepository) ListMerchants(ctx context.Context) ([]entity.Merchant, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entity.Merchant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.Merchant, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.Merchant); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Merchant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RenameMerchant provides a mock function with given fields: ctx, merchantID, name
+func (_m *MerchantRepository) RenameMerchant(ctx context.Context, merchantID string, name string) (entity.Merchant, error) {
+	ret := _m.Called(ctx, merchantID, name)
+
+	var r0 entity.Merchant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (entity.Merchant, error)); ok {
+		return rf(ctx, merchantID, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) entity.Merchant); ok {
+		r0 = rf(ctx, merchantID, name)
+	} else {
+		r0 = ret.Get(0).(entity.Merchant)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, merchantID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveMerchant provides a mock function with given fields: ctx, merchant
+func (_m *MerchantRepository) SaveMerchant(ctx context.Context, merchant entity.Merchant) error {
+	ret := _m.Called(ctx, merchant)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Merchant) error); ok {
+		r0 = rf(ctx, merchant)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMerchantRepository creates a new instance of MerchantRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMerchantRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MerchantRepository {
+	mock := &MerchantRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
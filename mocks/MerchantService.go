@@ -0,0 +1,213 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MerchantService is an autogenerated mock type for the MerchantService type
+type MerchantService struct {
+	mock.Mock
+}
+
+// AddAlias provides a mock function with given fields: ctx, merchantID, alias
+func (_m *MerchantService) AddAlias(ctx context.Context, merchantID string, alias string) (entity.Merchant, error) {
+	ret := _m.Called(ctx, merchantID, alias)
+
+	var r0 entity.Merchant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (entity.Merchant, error)); ok {
+		return rf(ctx, merchantID, alias)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) entity.Merchant); ok {
+		r0 = rf(ctx, merchantID, alias)
+	} else {
+		r0 = ret.Get(0).(entity.Merchant)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, merchantID, alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateMerchant provides a mock function with given fields: ctx, name, aliases
+func (_m *MerchantService) CreateMerchant(ctx context.Context, name string, aliases []string) (entity.Merchant, error) {
+	ret := _m.Called(ctx, name, aliases)
+
+	var r0 entity.Merchant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (entity.Merchant, error)); ok {
+		return rf(ctx, name, aliases)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) entity.Merchant); ok {
+		r0 = rf(ctx, name, aliases)
+	} else {
+		r0 = ret.Get(0).(entity.Merchant)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, name, aliases)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteAlias provides a mock function with given fields: ctx, merchantID, alias
+func (_m *MerchantService) DeleteAlias(ctx context.Context, merchantID string, alias string) (entity.Merchant, error) {
+	ret := _m.Called(ctx, merchantID, alias)
+
+	var r0 entity.Merchant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (entity.Merchant, error)); ok {
+		return rf(ctx, merchantID, alias)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) entity.Merchant); ok {
+		r0 = rf(ctx, merchantID, alias)
+	} else {
+		r0 = ret.Get(0).(entity.Merchant)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, merchantID, alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteMerchant provides a mock function with given fields: ctx, merchantID
+func (_m *MerchantService) DeleteMerchant(ctx context.Context, merchantID string) error {
+	ret := _m.Called(ctx, merchantID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, merchantID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetMerchant provides a mock function with given fields: ctx, merchantID
+func (_m *MerchantService) GetMerchant(ctx context.Context, merchantID string) (entity.Merchant, error) {
+	ret := _m.Called(ctx, merchantID)
+
+	var r0 entity.Merchant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.Merchant, error)); ok {
+		return rf(ctx, merchantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.Merchant); ok {
+		r0 = rf(ctx, merchantID)
+	} else {
+		r0 = ret.Get(0).(entity.Merchant)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, merchantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListMerchants provides a mock function with given fields: ctx
+func (_m *MerchantService) ListMerchants(ctx context.Context) ([]entity.Merchant, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entity.Merchant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.Merchant, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.Merchant); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Merchant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RenameMerchant provides a mock function with given fields: ctx, merchantID, name
+func (_m *MerchantService) RenameMerchant(ctx context.Context, merchantID string, name string) (entity.Merchant, error) {
+	ret := _m.Called(ctx, merchantID, name)
+
+	var r0 entity.Merchant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (entity.Merchant, error)); ok {
+		return rf(ctx, merchantID, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) entity.Merchant); ok {
+		r0 = rf(ctx, merchantID, name)
+	} else {
+		r0 = ret.Get(0).(entity.Merchant)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, merchantID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResolveRetailer provides a mock function with given fields: ctx, retailer
+func (_m *MerchantService) ResolveRetailer(ctx context.Context, retailer string) (string, error) {
+	ret := _m.Called(ctx, retailer)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, retailer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, retailer)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, retailer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMerchantService creates a new instance of MerchantService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMerchantService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MerchantService {
+	mock := &MerchantService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
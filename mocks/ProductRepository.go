@@ -0,0 +1,131 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ProductRepository is an autogenerated mock type for the ProductRepository type
+type ProductRepository struct {
+	mock.Mock
+}
+
+// DeleteProduct provides a mock function with given fields: ctx, productID
+func (_m *ProductRepository) DeleteProduct(ctx context.Context, productID string) error {
+	ret := _m.Called(ctx, productID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, productID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetProduct provides a mock function with given fields: ctx, productID
+func (_m *ProductRepository) GetProduct(ctx context.Context, productID string) (entity.Product, error) {
+	ret := _m.Called(ctx, productID)
+
+	var r0 entity.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.Product, error)); ok {
+		return rf(ctx, productID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.Product); ok {
+		r0 = rf(ctx, productID)
+	} else {
+		r0 = ret.Get(0).(entity.Product)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, productID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListProducts provides a mock function with given fields: ctx
+func (_m *ProductRepository) ListProducts(ctx context.Context) ([]entity.Product, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entity.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.Product, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.Product); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveProduct provides a mock function with given fields: ctx, product
+func (_m *ProductRepository) SaveProduct(ctx context.Context, product entity.Product) error {
+	ret := _m.Called(ctx, product)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Product) error); ok {
+		r0 = rf(ctx, product)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProduct provides a mock function with given fields: ctx, product
+func (_m *ProductRepository) UpdateProduct(ctx context.Context, product entity.Product) (entity.Product, error) {
+	ret := _m.Called(ctx, product)
+
+	var r0 entity.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Product) (entity.Product, error)); ok {
+		return rf(ctx, product)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Product) entity.Product); ok {
+		r0 = rf(ctx, product)
+	} else {
+		r0 = ret.Get(0).(entity.Product)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.Product) error); ok {
+		r1 = rf(ctx, product)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewProductRepository creates a new instance of ProductRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewProductRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ProductRepository {
+	mock := &ProductRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,157 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PromotionRepository is an autogenerated mock type for the PromotionRepository type
+type PromotionRepository struct {
+	mock.Mock
+}
+
+// DeletePromotion provides a mock function with given fields: ctx, promotionID
+func (_m *PromotionRepository) DeletePromotion(ctx context.Context, promotionID string) error {
+	ret := _m.Called(ctx, promotionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, promotionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPromotion provides a mock function with given fields: ctx, promotionID
+func (_m *PromotionRepository) GetPromotion(ctx context.Context, promotionID string) (entity.Promotion, error) {
+	ret := _m.Called(ctx, promotionID)
+
+	var r0 entity.Promotion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.Promotion, error)); ok {
+		return rf(ctx, promotionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.Promotion); ok {
+		r0 = rf(ctx, promotionID)
+	} else {
+		r0 = ret.Get(0).(entity.Promotion)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, promotionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListActivePromotions provides a mock function with given fields: ctx, purchaseDate
+func (_m *PromotionRepository) ListActivePromotions(ctx context.Context, purchaseDate string) ([]entity.Promotion, error) {
+	ret := _m.Called(ctx, purchaseDate)
+
+	var r0 []entity.Promotion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entity.Promotion, error)); ok {
+		return rf(ctx, purchaseDate)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.Promotion); ok {
+		r0 = rf(ctx, purchaseDate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Promotion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, purchaseDate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPromotions provides a mock function with given fields: ctx
+func (_m *PromotionRepository) ListPromotions(ctx context.Context) ([]entity.Promotion, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entity.Promotion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.Promotion, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.Promotion); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Promotion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SavePromotion provides a mock function with given fields: ctx, promotion
+func (_m *PromotionRepository) SavePromotion(ctx context.Context, promotion entity.Promotion) error {
+	ret := _m.Called(ctx, promotion)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Promotion) error); ok {
+		r0 = rf(ctx, promotion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePromotion provides a mock function with given fields: ctx, promotion
+func (_m *PromotionRepository) UpdatePromotion(ctx context.Context, promotion entity.Promotion) (entity.Promotion, error) {
+	ret := _m.Called(ctx, promotion)
+
+	var r0 entity.Promotion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Promotion) (entity.Promotion, error)); ok {
+		return rf(ctx, promotion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Promotion) entity.Promotion); ok {
+		r0 = rf(ctx, promotion)
+	} else {
+		r0 = ret.Get(0).(entity.Promotion)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.Promotion) error); ok {
+		r1 = rf(ctx, promotion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPromotionRepository creates a new instance of PromotionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPromotionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PromotionRepository {
+	mock := &PromotionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,167 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PromotionService is an autogenerated mock type for the PromotionService type
+type PromotionService struct {
+	mock.Mock
+}
+
+// CreatePromotion provides a mock function with given fields: ctx, promotion
+func (_m *PromotionService) CreatePromotion(ctx context.Context, promotion entity.Promotion) (entity.Promotion, error) {
+	ret := _m.Called(ctx, promotion)
+
+	var r0 entity.Promotion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Promotion) (entity.Promotion, error)); ok {
+		return rf(ctx, promotion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Promotion) entity.Promotion); ok {
+		r0 = rf(ctx, promotion)
+	} else {
+		r0 = ret.Get(0).(entity.Promotion)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.Promotion) error); ok {
+		r1 = rf(ctx, promotion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeletePromotion provides a mock function with given fields: ctx, promotionID
+func (_m *PromotionService) DeletePromotion(ctx context.Context, promotionID string) error {
+	ret := _m.Called(ctx, promotionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, promotionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPromotion provides a mock function with given fields: ctx, promotionID
+func (_m *PromotionService) GetPromotion(ctx context.Context, promotionID string) (entity.Promotion, error) {
+	ret := _m.Called(ctx, promotionID)
+
+	var r0 entity.Promotion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.Promotion, error)); ok {
+		return rf(ctx, promotionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.Promotion); ok {
+		r0 = rf(ctx, promotionID)
+	} else {
+		r0 = ret.Get(0).(entity.Promotion)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, promotionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPromotionPoints provides a mock function with given fields: ctx, receipt, basePoints
+func (_m *PromotionService) GetPromotionPoints(ctx context.Context, receipt entity.Receipt, basePoints int64) ([]entity.PromotionPoints, error) {
+	ret := _m.Called(ctx, receipt, basePoints)
+
+	var r0 []entity.PromotionPoints
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Receipt, int64) ([]entity.PromotionPoints, error)); ok {
+		return rf(ctx, receipt, basePoints)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Receipt, int64) []entity.PromotionPoints); ok {
+		r0 = rf(ctx, receipt, basePoints)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PromotionPoints)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.Receipt, int64) error); ok {
+		r1 = rf(ctx, receipt, basePoints)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPromotions provides a mock function with given fields: ctx
+func (_m *PromotionService) ListPromotions(ctx context.Context) ([]entity.Promotion, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entity.Promotion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.Promotion, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.Promotion); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Promotion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePromotion provides a mock function with given fields: ctx, promotionID, promotion
+func (_m *PromotionService) UpdatePromotion(ctx context.Context, promotionID string, promotion entity.Promotion) (entity.Promotion, error) {
+	ret := _m.Called(ctx, promotionID, promotion)
+
+	var r0 entity.Promotion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.Promotion) (entity.Promotion, error)); ok {
+		return rf(ctx, promotionID, promotion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.Promotion) entity.Promotion); ok {
+		r0 = rf(ctx, promotionID, promotion)
+	} else {
+		r0 = ret.Get(0).(entity.Promotion)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, entity.Promotion) error); ok {
+		r1 = rf(ctx, promotionID, promotion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPromotionService creates a new instance of PromotionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPromotionService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PromotionService {
+	mock := &PromotionService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,97 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReceiptAuditRepository is an autogenerated mock type for the ReceiptAuditRepository type
+type ReceiptAuditRepository struct {
+	mock.Mock
+}
+
+// AmendReceipt provides a mock function with given fields: ctx, record, points, entry
+func (_m *ReceiptAuditRepository) AmendReceipt(ctx context.Context, record entity.ReceiptRecord, points entity.ReceiptPoints, entry entity.ReceiptAuditEntry) error {
+	ret := _m.Called(ctx, record, points, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReceiptRecord, entity.ReceiptPoints, entity.ReceiptAuditEntry) error); ok {
+		r0 = rf(ctx, record, points, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AppendReceiptAuditEntry provides a mock function with given fields: ctx, entry
+func (_m *ReceiptAuditRepository) AppendReceiptAuditEntry(ctx context.Context, entry entity.ReceiptAuditEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReceiptAuditEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteReceipt provides a mock function with given fields: ctx, receiptID, entry
+func (_m *ReceiptAuditRepository) DeleteReceipt(ctx context.Context, receiptID string, entry entity.ReceiptAuditEntry) error {
+	ret := _m.Called(ctx, receiptID, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.ReceiptAuditEntry) error); ok {
+		r0 = rf(ctx, receiptID, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListReceiptAuditEntries provides a mock function with given fields: ctx, receiptID
+func (_m *ReceiptAuditRepository) ListReceiptAuditEntries(ctx context.Context, receiptID string) ([]entity.ReceiptAuditEntry, error) {
+	ret := _m.Called(ctx, receiptID)
+
+	var r0 []entity.ReceiptAuditEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entity.ReceiptAuditEntry, error)); ok {
+		return rf(ctx, receiptID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.ReceiptAuditEntry); ok {
+		r0 = rf(ctx, receiptID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ReceiptAuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, receiptID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewReceiptAuditRepository creates a new instance of ReceiptAuditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReceiptAuditRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReceiptAuditRepository {
+	mock := &ReceiptAuditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
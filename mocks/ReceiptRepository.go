@@ -0,0 +1,219 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ReceiptRepository is an autogenerated mock type for the ReceiptRepository type
+type ReceiptRepository struct {
+	mock.Mock
+}
+
+// AssignMerchant provides a mock function with given fields: ctx, retailerKey, merchantID
+func (_m *ReceiptRepository) AssignMerchant(ctx context.Context, retailerKey string, merchantID string) (int64, error) {
+	ret := _m.Called(ctx, retailerKey, merchantID)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (int64, error)); ok {
+		return rf(ctx, retailerKey, merchantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int64); ok {
+		r0 = rf(ctx, retailerKey, merchantID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, retailerKey, merchantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteReceipt provides a mock function with given fields: ctx, receiptID
+func (_m *ReceiptRepository) DeleteReceipt(ctx context.Context, receiptID string) error {
+	ret := _m.Called(ctx, receiptID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, receiptID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExpireReceiptPoints provides a mock function with given fields: ctx, receiptID, points, expiredAt
+func (_m *ReceiptRepository) ExpireReceiptPoints(ctx context.Context, receiptID string, points entity.ReceiptPoints, expiredAt time.Time) (bool, error) {
+	ret := _m.Called(ctx, receiptID, points, expiredAt)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.ReceiptPoints, time.Time) (bool, error)); ok {
+		return rf(ctx, receiptID, points, expiredAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.ReceiptPoints, time.Time) bool); ok {
+		r0 = rf(ctx, receiptID, points, expiredAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, entity.ReceiptPoints, time.Time) error); ok {
+		r1 = rf(ctx, receiptID, points, expiredAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReceiptByID provides a mock function with given fields: ctx, receiptID
+func (_m *ReceiptRepository) GetReceiptByID(ctx context.Context, receiptID string) (entity.ReceiptRecord, error) {
+	ret := _m.Called(ctx, receiptID)
+
+	var r0 entity.ReceiptRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.ReceiptRecord, error)); ok {
+		return rf(ctx, receiptID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.ReceiptRecord); ok {
+		r0 = rf(ctx, receiptID)
+	} else {
+		r0 = ret.Get(0).(entity.ReceiptRecord)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, receiptID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReceiptPoints provides a mock function with given fields: ctx, receiptID
+func (_m *ReceiptRepository) GetReceiptPoints(ctx context.Context, receiptID string) (entity.ReceiptPoints, error) {
+	ret := _m.Called(ctx, receiptID)
+
+	var r0 entity.ReceiptPoints
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.ReceiptPoints, error)); ok {
+		return rf(ctx, receiptID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.ReceiptPoints); ok {
+		r0 = rf(ctx, receiptID)
+	} else {
+		r0 = ret.Get(0).(entity.ReceiptPoints)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, receiptID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListReceipts provides a mock function with given fields: ctx
+func (_m *ReceiptRepository) ListReceipts(ctx context.Context) ([]entity.ReceiptRecord, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entity.ReceiptRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.ReceiptRecord, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.ReceiptRecord); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ReceiptRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueryReceipts provides a mock function with given fields: ctx, query
+func (_m *ReceiptRepository) QueryReceipts(ctx context.Context, query entity.ReceiptQuery) (entity.ReceiptPage, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 entity.ReceiptPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReceiptQuery) (entity.ReceiptPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReceiptQuery) entity.ReceiptPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		r0 = ret.Get(0).(entity.ReceiptPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ReceiptQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveReceipt provides a mock function with given fields: ctx, record
+func (_m *ReceiptRepository) SaveReceipt(ctx context.Context, record entity.ReceiptRecord) error {
+	ret := _m.Called(ctx, record)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReceiptRecord) error); ok {
+		r0 = rf(ctx, record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveReceiptPoints provides a mock function with given fields: ctx, receiptID, points
+func (_m *ReceiptRepository) SaveReceiptPoints(ctx context.Context, receiptID string, points entity.ReceiptPoints) error {
+	ret := _m.Called(ctx, receiptID, points)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.ReceiptPoints) error); ok {
+		r0 = rf(ctx, receiptID, points)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewReceiptRepository creates a new instance of ReceiptRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReceiptRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReceiptRepository {
+	mock := &ReceiptRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
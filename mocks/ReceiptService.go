@@ -52,6 +52,92 @@ func (_m *ReceiptService) GetReceiptPoints(ctx context.Context, receipt entity.R
 	return r0, r1
 }
 
+// GetReceiptPointsBreakdown provides a mock function with given fields: ctx, receipt
+func (_m *ReceiptService) GetReceiptPointsBreakdown(ctx context.Context, receipt entity.Receipt) (entity.PointsBreakdown, error) {
+	ret := _m.Called(ctx, receipt)
+
+	var r0 entity.PointsBreakdown
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Receipt) (entity.PointsBreakdown, error)); ok {
+		return rf(ctx, receipt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Receipt) entity.PointsBreakdown); ok {
+		r0 = rf(ctx, receipt)
+	} else {
+		r0 = ret.Get(0).(entity.PointsBreakdown)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.Receipt) error); ok {
+		r1 = rf(ctx, receipt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReceiptPointsBreakdownWithRuleSet provides a mock function with given fields: ctx, receipt, ruleSetVersion
+func (_m *ReceiptService) GetReceiptPointsBreakdownWithRuleSet(ctx context.Context, receipt entity.Receipt, ruleSetVersion string) (entity.PointsBreakdown, error) {
+	ret := _m.Called(ctx, receipt, ruleSetVersion)
+
+	var r0 entity.PointsBreakdown
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Receipt, string) (entity.PointsBreakdown, error)); ok {
+		return rf(ctx, receipt, ruleSetVersion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Receipt, string) entity.PointsBreakdown); ok {
+		r0 = rf(ctx, receipt, ruleSetVersion)
+	} else {
+		r0 = ret.Get(0).(entity.PointsBreakdown)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.Receipt, string) error); ok {
+		r1 = rf(ctx, receipt, ruleSetVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRecordPointsBreakdown provides a mock function with given fields: ctx, record, ruleSetVersion
+func (_m *ReceiptService) GetRecordPointsBreakdown(ctx context.Context, record entity.ReceiptRecord, ruleSetVersion string) (entity.PointsBreakdown, error) {
+	ret := _m.Called(ctx, record, ruleSetVersion)
+
+	var r0 entity.PointsBreakdown
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReceiptRecord, string) (entity.PointsBreakdown, error)); ok {
+		return rf(ctx, record, ruleSetVersion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReceiptRecord, string) entity.PointsBreakdown); ok {
+		r0 = rf(ctx, record, ruleSetVersion)
+	} else {
+		r0 = ret.Get(0).(entity.PointsBreakdown)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ReceiptRecord, string) error); ok {
+		r1 = rf(ctx, record, ruleSetVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ValidateReceipt provides a mock function with given fields: ctx, receipt
+func (_m *ReceiptService) ValidateReceipt(ctx context.Context, receipt entity.Receipt) error {
+	ret := _m.Called(ctx, receipt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Receipt) error); ok {
+		r0 = rf(ctx, receipt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewReceiptService creates a new instance of ReceiptService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewReceiptService(t interface {
@@ -0,0 +1,165 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RedemptionRepository is an autogenerated mock type for the RedemptionRepository type
+type RedemptionRepository struct {
+	mock.Mock
+}
+
+// ExpireRedemptions provides a mock function with given fields: ctx, now
+func (_m *RedemptionRepository) ExpireRedemptions(ctx context.Context, now time.Time) (int64, error) {
+	ret := _m.Called(ctx, now)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHeldPoints provides a mock function with given fields: ctx, receiptID, now
+func (_m *RedemptionRepository) GetHeldPoints(ctx context.Context, receiptID string, now time.Time) (int64, error) {
+	ret := _m.Called(ctx, receiptID, now)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (int64, error)); ok {
+		return rf(ctx, receiptID, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) int64); ok {
+		r0 = rf(ctx, receiptID, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, receiptID, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRedemption provides a mock function with given fields: ctx, redemptionID
+func (_m *RedemptionRepository) GetRedemption(ctx context.Context, redemptionID string) (entity.Redemption, error) {
+	ret := _m.Called(ctx, redemptionID)
+
+	var r0 entity.Redemption
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.Redemption, error)); ok {
+		return rf(ctx, redemptionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.Redemption); ok {
+		r0 = rf(ctx, redemptionID)
+	} else {
+		r0 = ret.Get(0).(entity.Redemption)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, redemptionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReopenRedemption provides a mock function with given fields: ctx, redemptionID
+func (_m *RedemptionRepository) ReopenRedemption(ctx context.Context, redemptionID string) error {
+	ret := _m.Called(ctx, redemptionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, redemptionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReserveReceiptPoints provides a mock function with given fields: ctx, redemption, receiptPoints
+func (_m *RedemptionRepository) ReserveReceiptPoints(ctx context.Context, redemption entity.Redemption, receiptPoints []entity.RedemptionAllocation) (entity.Redemption, error) {
+	ret := _m.Called(ctx, redemption, receiptPoints)
+
+	var r0 entity.Redemption
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Redemption, []entity.RedemptionAllocation) (entity.Redemption, error)); ok {
+		return rf(ctx, redemption, receiptPoints)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Redemption, []entity.RedemptionAllocation) entity.Redemption); ok {
+		r0 = rf(ctx, redemption, receiptPoints)
+	} else {
+		r0 = ret.Get(0).(entity.Redemption)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.Redemption, []entity.RedemptionAllocation) error); ok {
+		r1 = rf(ctx, redemption, receiptPoints)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResolveRedemption provides a mock function with given fields: ctx, redemptionID, status, at
+func (_m *RedemptionRepository) ResolveRedemption(ctx context.Context, redemptionID string, status string, at time.Time) (entity.Redemption, error) {
+	ret := _m.Called(ctx, redemptionID, status, at)
+
+	var r0 entity.Redemption
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (entity.Redemption, error)); ok {
+		return rf(ctx, redemptionID, status, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) entity.Redemption); ok {
+		r0 = rf(ctx, redemptionID, status, at)
+	} else {
+		r0 = ret.Get(0).(entity.Redemption)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(ctx, redemptionID, status, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRedemptionRepository creates a new instance of RedemptionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRedemptionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RedemptionRepository {
+	mock := &RedemptionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,175 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/darcops/receipt-proccessor-challenge/internal/pkg/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RedemptionService is an autogenerated mock type for the RedemptionService type
+type RedemptionService struct {
+	mock.Mock
+}
+
+// Cancel provides a mock function with given fields: ctx, redemptionID
+func (_m *RedemptionService) Cancel(ctx context.Context, redemptionID string) (entity.Redemption, error) {
+	ret := _m.Called(ctx, redemptionID)
+
+	var r0 entity.Redemption
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.Redemption, error)); ok {
+		return rf(ctx, redemptionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.Redemption); ok {
+		r0 = rf(ctx, redemptionID)
+	} else {
+		r0 = ret.Get(0).(entity.Redemption)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, redemptionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Confirm provides a mock function with given fields: ctx, redemptionID
+func (_m *RedemptionService) Confirm(ctx context.Context, redemptionID string) (entity.Redemption, error) {
+	ret := _m.Called(ctx, redemptionID)
+
+	var r0 entity.Redemption
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.Redemption, error)); ok {
+		return rf(ctx, redemptionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.Redemption); ok {
+		r0 = rf(ctx, redemptionID)
+	} else {
+		r0 = ret.Get(0).(entity.Redemption)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, redemptionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpireReservations provides a mock function with given fields: ctx, now
+func (_m *RedemptionService) ExpireReservations(ctx context.Context, now time.Time) (int64, error) {
+	ret := _m.Called(ctx, now)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHeldPoints provides a mock function with given fields: ctx, receiptID
+func (_m *RedemptionService) GetHeldPoints(ctx context.Context, receiptID string) (int64, error) {
+	ret := _m.Called(ctx, receiptID)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, receiptID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, receiptID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, receiptID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRedemption provides a mock function with given fields: ctx, redemptionID
+func (_m *RedemptionService) GetRedemption(ctx context.Context, redemptionID string) (entity.Redemption, error) {
+	ret := _m.Called(ctx, redemptionID)
+
+	var r0 entity.Redemption
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.Redemption, error)); ok {
+		return rf(ctx, redemptionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.Redemption); ok {
+		r0 = rf(ctx, redemptionID)
+	} else {
+		r0 = ret.Get(0).(entity.Redemption)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, redemptionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reserve provides a mock function with given fields: ctx, receiptIDs, points, reward
+func (_m *RedemptionService) Reserve(ctx context.Context, receiptIDs []string, points int64, reward string) (entity.Redemption, error) {
+	ret := _m.Called(ctx, receiptIDs, points, reward)
+
+	var r0 entity.Redemption
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int64, string) (entity.Redemption, error)); ok {
+		return rf(ctx, receiptIDs, points, reward)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int64, string) entity.Redemption); ok {
+		r0 = rf(ctx, receiptIDs, points, reward)
+	} else {
+		r0 = ret.Get(0).(entity.Redemption)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, int64, string) error); ok {
+		r1 = rf(ctx, receiptIDs, points, reward)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRedemptionService creates a new instance of RedemptionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRedemptionService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RedemptionService {
+	mock := &RedemptionService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -50,3 +50,38 @@ func IsDayOdd(date string) (bool, error) {
 
 	return day%2 != 0, nil
 }
+
+// ParseDate parses a date with the YYYY-MM-DD format.
+func ParseDate(date string) (time.Time, error) {
+	return time.Parse(dateLayout, date)
+}
+
+// FormatDate formats a date with the YYYY-MM-DD format.
+func FormatDate(date time.Time) string {
+	return date.Format(dateLayout)
+}
+
+// AddMonths adds a number of months to a date, which can be negative. Unlike
+// time.AddDate, days past the end of the resulting month are moved to its last
+// day instead of overflowing into the next one, e.g. 2024-01-31 plus one month
+// is 2024-02-29.
+func AddMonths(date time.Time, months int) time.Time {
+	firstOfMonth := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, date.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+
+	return time.Date(
+		firstOfMonth.Year(),
+		firstOfMonth.Month(),
+		min(date.Day(), lastDay),
+		date.Hour(),
+		date.Minute(),
+		date.Second(),
+		date.Nanosecond(),
+		date.Location(),
+	)
+}
+
+// StartOfYear returns the first instant of a year in the given location.
+func StartOfYear(year int, location *time.Location) time.Time {
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, location)
+}
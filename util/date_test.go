@@ -105,3 +105,100 @@ func TestIsDayOdd(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDate(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		date string
+
+		wantErr bool
+	}{
+		{
+			name: "should parse a valid date",
+
+			date: "2024-02-29",
+		},
+		{
+			name: "should fail due date that doesn't exist",
+
+			date: "2022-02-30",
+
+			wantErr: true,
+		},
+		{
+			name: "should fail due invalid date format",
+
+			date: "01-01-2021",
+
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseDate(tc.date)
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ParseDate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		date   string
+		months int
+
+		want string
+	}{
+		{
+			name: "should add months within the same year",
+
+			date:   "2024-03-15",
+			months: 2,
+
+			want: "2024-05-15",
+		},
+		{
+			name: "should add months into the next year",
+
+			date:   "2023-11-30",
+			months: 12,
+
+			want: "2024-11-30",
+		},
+		{
+			name: "should move the day to the end of a shorter month",
+
+			date:   "2024-01-31",
+			months: 1,
+
+			want: "2024-02-29",
+		},
+		{
+			name: "should subtract months",
+
+			date:   "2024-03-31",
+			months: -13,
+
+			want: "2023-02-28",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			date, err := ParseDate(tc.date)
+			if err != nil {
+				t.Fatalf("ParseDate() error = %v", err)
+			}
+
+			if got := FormatDate(AddMonths(date, tc.months)); got != tc.want {
+				t.Errorf("AddMonths() got = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}